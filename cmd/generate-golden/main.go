@@ -7,6 +7,9 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/selftest"
 )
 
 // GoldenData represents a single test case in the golden file
@@ -15,10 +18,25 @@ type GoldenData struct {
 	Result string `json:"result"`
 }
 
+// knownAnswerTargets are the indices hashed into the known-answer file. They
+// span the Karatsuba and FFT multiplication ranges.
+var knownAnswerTargets = []uint64{
+	10_000, 100_000, 1_000_000, 5_000_000,
+}
+
 func main() {
 	outputDir := flag.String("out", "internal/fibonacci/testdata", "Output directory for the golden file")
+	knownAnswers := flag.String("known-answers", "", "Also write the self-test known answers (hashes of large F(n)) to this file, e.g. internal/selftest/testdata/known_answers.json")
 	flag.Parse()
 
+	if *knownAnswers != "" {
+		if err := writeKnownAnswers(*knownAnswers); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing known answers: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Successfully generated known answers at %s\n", *knownAnswers)
+	}
+
 	if err := os.MkdirAll(*outputDir, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
 		os.Exit(1)
@@ -88,3 +106,50 @@ func fibBig(n uint64) *big.Int {
 	}
 	return b
 }
+
+// fibDoubling calculates the nth Fibonacci number by fast doubling with
+// math/big only. It is the oracle for indices too large for fibBig.
+func fibDoubling(n uint64) *big.Int {
+	a := big.NewInt(0) // F(k)
+	b := big.NewInt(1) // F(k+1)
+	t := new(big.Int)
+	for i := 63; i >= 0; i-- {
+		// F(2k) = F(k) * (2*F(k+1) - F(k)), F(2k+1) = F(k)^2 + F(k+1)^2
+		t.Lsh(b, 1)
+		t.Sub(t, a)
+		t.Mul(a, t)
+		a.Mul(a, a)
+		b.Mul(b, b)
+		b.Add(a, b)
+		a, t = t, a
+		if n>>uint(i)&1 == 1 {
+			a.Add(a, b)
+			a, b = b, a
+		}
+	}
+	return a
+}
+
+// knownAnswer computes the known answer for F(n), so that results too large
+// to ship in full can still be checked by `fibcalc selftest`.
+func knownAnswer(n uint64) selftest.KnownAnswer {
+	f := fibDoubling(n)
+	return selftest.KnownAnswer{N: n, Bits: f.BitLen(), SHA256: orchestration.Digest(f)}
+}
+
+// writeKnownAnswers writes the known answers of knownAnswerTargets to path.
+func writeKnownAnswers(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	answers := make([]selftest.KnownAnswer, 0, len(knownAnswerTargets))
+	for _, n := range knownAnswerTargets {
+		answers = append(answers, knownAnswer(n))
+		fmt.Printf("Hashed F(%d)\n", n)
+	}
+	data, err := json.MarshalIndent(answers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -94,3 +94,26 @@ func TestFibBig_LargeValues(t *testing.T) {
 		})
 	}
 }
+
+// TestFibDoubling checks the fast doubling oracle against fibBig.
+func TestFibDoubling(t *testing.T) {
+	for _, n := range []uint64{0, 1, 2, 3, 93, 94, 255, 256, 1000, 10000} {
+		if got, want := fibDoubling(n), fibBig(n); got.Cmp(want) != 0 {
+			t.Errorf("fibDoubling(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+// TestKnownAnswer checks the size and hash of a known answer.
+func TestKnownAnswer(t *testing.T) {
+	ka := knownAnswer(100)
+	if ka.N != 100 || ka.Bits != 69 {
+		t.Errorf("knownAnswer(100) = %+v, want N=100 and Bits=69", ka)
+	}
+	if len(ka.SHA256) != 64 {
+		t.Errorf("SHA256 = %q, want 64 hex digits", ka.SHA256)
+	}
+	if knownAnswer(101).SHA256 == ka.SHA256 {
+		t.Error("F(100) and F(101) have the same hash")
+	}
+}
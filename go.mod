@@ -2,24 +2,32 @@ module github.com/agbru/fibcalc
 
 go 1.25.0
 
-require golang.org/x/sync v0.17.0
+require (
+	golang.org/x/sync v0.17.0
+	golang.org/x/term v0.36.0
+)
 
 require (
+	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // TUI: Clipboard copy with OSC 52 escape sequences
 	github.com/briandowns/spinner v1.23.2
 	github.com/charmbracelet/bubbles v0.21.0 // TUI: Common UI components (help, key bindings)
 	github.com/charmbracelet/bubbletea v1.3.10 // TUI: Elm-inspired TUI framework
 	github.com/charmbracelet/lipgloss v1.1.0 // TUI: Style definitions and rendering
 	github.com/golang/mock v1.6.0
+	github.com/klauspost/compress v1.18.0 // Binary result files: zstd compression
 	github.com/leanovate/gopter v0.2.11
 	github.com/ncw/gmp v1.0.5
 	github.com/prometheus/client_golang v1.23.2
 	github.com/rs/zerolog v1.34.0
 	go.opentelemetry.io/otel v1.39.0
-	golang.org/x/sys v0.38.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.39.0
+	go.opentelemetry.io/otel/sdk v1.39.0
+	go.opentelemetry.io/otel/trace v1.39.0
+	golang.org/x/sys v0.39.0
 )
 
 require (
-	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
+	github.com/atotto/clipboard v0.1.4 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/charmbracelet/colorprofile v0.2.3-0.20250311203215-f60798e515dc // indirect
@@ -30,6 +38,7 @@ require (
 	github.com/fatih/color v1.18.0 // indirect
 	github.com/go-logr/logr v1.4.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -46,9 +55,7 @@ require (
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/otel/metric v1.39.0 // indirect
-	go.opentelemetry.io/otel/trace v1.39.0 // indirect
 	go.yaml.in/yaml/v2 v2.4.2 // indirect
-	golang.org/x/term v0.36.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
 	google.golang.org/protobuf v1.36.8 // indirect
 )
@@ -9,18 +9,29 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"runtime/pprof"
 	"syscall"
+	"time"
 
+	"github.com/agbru/fibcalc/internal/bench"
 	"github.com/agbru/fibcalc/internal/calibration"
 	"github.com/agbru/fibcalc/internal/cli"
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/profiling"
+	"github.com/agbru/fibcalc/internal/report"
+	"github.com/agbru/fibcalc/internal/resultfile"
+	"github.com/agbru/fibcalc/internal/selftest"
 	"github.com/agbru/fibcalc/internal/server"
+	"github.com/agbru/fibcalc/internal/session"
+	"github.com/agbru/fibcalc/internal/telemetry"
 	"github.com/agbru/fibcalc/internal/tui"
 	"github.com/agbru/fibcalc/internal/ui"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
 )
 
 // Application represents the fibcalc application instance.
@@ -34,6 +45,9 @@ type Application struct {
 	Factory fibonacci.CalculatorFactory
 	// ErrWriter is the writer for error output (typically os.Stderr).
 	ErrWriter io.Writer
+	// calibration identifies the profile the thresholds come from, for the
+	// manifest of the JSON output and the HTML report.
+	calibration CalibrationIdentity
 }
 
 // New creates a new Application instance by parsing command-line arguments.
@@ -65,8 +79,10 @@ func New(args []string, errWriter io.Writer) (*Application, error) {
 
 	// Try to load cached calibration profile first
 	// This allows the application to use optimal thresholds found in previous runs
+	calibrationID := CalibrationIdentity{Source: CalibrationNone}
 	if cfgWithProfile, loaded := calibration.LoadCachedCalibration(cfg, cfg.CalibrationProfile); loaded {
 		cfg = cfgWithProfile
+		calibrationID = profileIdentity(CalibrationProfile, cfg.CalibrationProfile)
 	} else {
 		// Fallback to adaptive thresholds based on hardware characteristics
 		// This provides automatic optimization without requiring --auto-calibrate
@@ -74,9 +90,10 @@ func New(args []string, errWriter io.Writer) (*Application, error) {
 	}
 
 	return &Application{
-		Config:    cfg,
-		Factory:   factory,
-		ErrWriter: errWriter,
+		Config:      cfg,
+		Factory:     factory,
+		ErrWriter:   errWriter,
+		calibration: calibrationID,
 	}, nil
 }
 
@@ -130,6 +147,36 @@ func (a *Application) Run(ctx context.Context, out io.Writer) int {
 		return a.runCompletion(out)
 	}
 
+	// Export OpenTelemetry spans when requested
+	if a.Config.TraceOut != "" {
+		shutdown, err := telemetry.Setup(a.Config.TraceOut, out, a.ErrWriter)
+		if err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				fmt.Fprintf(a.ErrWriter, "Warning: failed to flush traces: %v\n", err)
+			}
+		}()
+	}
+	// Capture CPU/heap/block profiles and execution traces when requested
+	if opts := a.profilingOptions(); opts.Enabled() {
+		session, err := profiling.Start(opts)
+		if err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		defer func() {
+			if err := session.Stop(); err != nil {
+				fmt.Fprintf(a.ErrWriter, "Warning: failed to write profiles: %v\n", err)
+			}
+		}()
+	}
+
+	ctx, span := otel.Tracer("app").Start(ctx, "fibcalc")
+	defer span.End()
+
 	// Disable trace-level logging by default to avoid polluting CLI output.
 	// Server mode may override this to enable more verbose logging.
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
@@ -137,6 +184,9 @@ func (a *Application) Run(ctx context.Context, out io.Writer) int {
 	// Initialize CLI theme (respects --no-color flag and NO_COLOR env var)
 	ui.InitTheme(a.Config.NoColor)
 
+	// The pools and FFT cache are shared by every calculation of the process
+	fibonacci.ConfigureMemoryMode(a.Config.LowMemory)
+
 	// Server mode
 	if a.Config.ServerMode {
 		return a.runServer()
@@ -147,11 +197,41 @@ func (a *Application) Run(ctx context.Context, out io.Writer) int {
 		return a.runTUI()
 	}
 
+	// Replay a recorded session instead of calculating
+	if a.Config.Replay != "" {
+		return a.runReplay(ctx, out)
+	}
+
+	// Script mode: REPL commands and assertions, run non-interactively
+	if a.Config.Script != "" {
+		return a.runScript(out)
+	}
+
 	// Interactive REPL mode
 	if a.Config.Interactive {
 		return a.runREPL()
 	}
 
+	// Explain mode: print the execution plan without computing
+	if a.Config.Explain {
+		return a.runExplain(out)
+	}
+
+	// Inspect: print the header of a result file and verify its checksum
+	if a.Config.InspectFile != "" {
+		return a.runInspect(out)
+	}
+
+	// Self-test: known-answer checks of the arithmetic on this machine
+	if a.Config.SelfTest {
+		return a.runSelfTest(ctx, out)
+	}
+
+	// Benchmark mode: sequential, repeated runs with statistics
+	if a.Config.Bench {
+		return a.runBench(ctx, out)
+	}
+
 	// Calibration mode
 	if a.Config.Calibrate {
 		return a.runCalibration(ctx, out)
@@ -164,6 +244,16 @@ func (a *Application) Run(ctx context.Context, out io.Writer) int {
 	return a.runCalculate(ctx, out)
 }
 
+// profilingOptions returns the profiles requested on the command line.
+func (a *Application) profilingOptions() profiling.Options {
+	return profiling.Options{
+		CPUProfile:   a.Config.CPUProfile,
+		MemProfile:   a.Config.MemProfile,
+		BlockProfile: a.Config.BlockProfile,
+		ExecTrace:    a.Config.ExecTrace,
+	}
+}
+
 // runCompletion generates shell completion scripts.
 func (a *Application) runCompletion(out io.Writer) int {
 	availableAlgos := a.Factory.List()
@@ -186,14 +276,260 @@ func (a *Application) runServer() int {
 
 // runREPL starts the interactive REPL mode.
 func (a *Application) runREPL() int {
-	repl := cli.NewREPL(a.Factory.GetAll(), cli.REPLConfig{
-		DefaultAlgo:  a.Config.Algo,
-		Timeout:      a.Config.Timeout,
-		Threshold:    a.Config.Threshold,
-		FFTThreshold: a.Config.FFTThreshold,
-		HexOutput:    a.Config.HexOutput,
+	a.newREPL(cli.DefaultHistoryPath()).Start()
+	return apperrors.ExitSuccess
+}
+
+// runScript runs the REPL commands of the --script file, or of standard
+// input for "-", and reports the failed commands and assertions. It exits
+// with ExitErrorMismatch if only assertions failed, and ExitErrorGeneric if
+// a command failed.
+func (a *Application) runScript(out io.Writer) int {
+	in, name := io.Reader(os.Stdin), "stdin"
+	if a.Config.Script != "-" {
+		f, err := os.Open(a.Config.Script)
+		if err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		defer f.Close()
+		in, name = f, a.Config.Script
+	}
+
+	repl := a.newREPL("")
+	repl.SetInput(in)
+	repl.SetOutput(out)
+	err := repl.RunScript(name)
+	if err == nil {
+		return apperrors.ExitSuccess
+	}
+	fmt.Fprintln(a.ErrWriter, err)
+	var scriptErr *cli.ScriptError
+	if errors.As(err, &scriptErr) && !scriptErr.Aborted() {
+		return apperrors.ExitErrorMismatch
+	}
+	return apperrors.ExitErrorGeneric
+}
+
+// newREPL returns a REPL configured from the command line, persisting its
+// history to historyFile.
+func (a *Application) newREPL(historyFile string) *cli.REPL {
+	profile := a.loadProfile()
+	return cli.NewREPL(a.Factory.GetAll(), cli.REPLConfig{
+		DefaultAlgo:       a.Config.Algo,
+		Timeout:           a.Config.Timeout,
+		Threshold:         a.Config.Threshold,
+		FFTThreshold:      a.Config.FFTThreshold,
+		StrassenThreshold: a.Config.StrassenThreshold,
+		HexOutput:         a.Config.HexOutput,
+		Estimator: func(n uint64, algo string) (time.Duration, string, bool) {
+			return calibration.EstimateDuration(n, profile, algo)
+		},
+		HistoryFile: historyFile,
 	})
-	repl.Start()
+}
+
+// runExplain prints the execution plan of F(N) for each selected algorithm
+// without performing the calculation. Duration estimates come from the cost
+// models of the calibration profile, or the built-in defaults.
+func (a *Application) runExplain(out io.Writer) int {
+	profile := a.loadProfile()
+
+	runCfg := a.Config
+	var selection *calibration.AlgorithmSelection
+	if runCfg.Algo == config.AutoAlgo {
+		sel := calibration.SelectAlgorithm(runCfg.N, profile, a.Factory.List())
+		selection = &sel
+		runCfg = applySelection(runCfg, sel)
+	}
+	algorithms := a.algorithmKeys(runCfg.Algo)
+
+	plans := make([]fibonacci.ExecutionPlan, 0, len(algorithms))
+	for _, algo := range algorithms {
+		if _, err := a.Factory.Get(algo); err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error: no calculator available for algorithm '%s'\n", algo)
+			return apperrors.ExitErrorConfig
+		}
+		plan := fibonacci.PlanCalculation(runCfg.N, algo, runCfg.ToCalculationOptions())
+		if d, source, ok := calibration.EstimateDuration(runCfg.N, profile, algo); ok {
+			plan.EstimatedDuration, plan.EstimateSource = d, source
+		}
+		plans = append(plans, plan)
+	}
+
+	if runCfg.JSONOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(explainOutput{Selection: selection, Plans: plans}); err != nil {
+			return apperrors.ExitErrorGeneric
+		}
+		return apperrors.ExitSuccess
+	}
+
+	if selection != nil {
+		fmt.Fprint(out, selection.Explain())
+	}
+	for _, plan := range plans {
+		cli.DisplayExecutionPlan(out, plan)
+	}
+	return apperrors.ExitSuccess
+}
+
+// explainOutput is the JSON document written by --explain --json.
+type explainOutput struct {
+	Selection *calibration.AlgorithmSelection `json:"selection,omitempty"`
+	Plans     []fibonacci.ExecutionPlan       `json:"plans"`
+}
+
+// runBench runs the statistical benchmark of the selected calculators,
+// prints the results, exports the report if requested and compares it with
+// the baseline. A regression against the baseline exits with
+// ExitErrorRegress.
+func (a *Application) runBench(ctx context.Context, out io.Writer) int {
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	procs, err := bench.ParseProcs(a.Config.BenchProcs)
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+		return apperrors.ExitErrorConfig
+	}
+	var baseline *bench.Report
+	if a.Config.BenchBaseline != "" {
+		if baseline, err = bench.LoadReport(a.Config.BenchBaseline); err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+	}
+
+	runCfg := a.Config
+	if runCfg.Algo == config.AutoAlgo {
+		runCfg = applySelection(runCfg, a.selectAlgorithm())
+	}
+	calculators := cli.GetCalculatorsToRun(runCfg, a.Factory)
+	if len(calculators) == 0 {
+		fmt.Fprintf(a.ErrWriter, "Error: no calculator available for algorithm '%s'\n", runCfg.Algo)
+		return apperrors.ExitErrorConfig
+	}
+
+	if !runCfg.Quiet && !runCfg.JSONOutput {
+		fmt.Fprintf(out, "Benchmarking %d calculator(s) sequentially for F(%d): %d warm-up + %d measured runs each...\n",
+			len(calculators), runCfg.N, runCfg.BenchWarmup, runCfg.BenchRuns)
+	}
+	report, err := bench.Run(ctx, calculators, bench.Options{
+		N:       runCfg.N,
+		Runs:    runCfg.BenchRuns,
+		Warmup:  runCfg.BenchWarmup,
+		Procs:   procs,
+		Timeout: runCfg.Timeout,
+		Calc:    runCfg.ToCalculationOptions(),
+	})
+	if err != nil {
+		return apperrors.HandleCalculationError(err, 0, a.ErrWriter, cli.CLIColorProvider{})
+	}
+
+	if a.Config.BenchOut != "" {
+		if err := bench.SaveReport(report, a.Config.BenchOut); err != nil {
+			fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+			return apperrors.ExitErrorGeneric
+		}
+	}
+
+	var comparisons []bench.Comparison
+	if baseline != nil {
+		comparisons = bench.Compare(baseline, report, a.Config.BenchTolerance)
+	}
+
+	if a.Config.JSONOutput {
+		if err := report.WriteJSON(out); err != nil {
+			return apperrors.ExitErrorGeneric
+		}
+	} else {
+		cli.DisplayBenchReport(out, report)
+		if baseline != nil {
+			cli.DisplayBenchComparison(out, comparisons, a.Config.BenchTolerance)
+		}
+		if a.Config.BenchOut != "" {
+			fmt.Fprintf(out, "\nReport saved to: %s\n", a.Config.BenchOut)
+		}
+	}
+
+	if bench.HasRegression(comparisons) {
+		fmt.Fprintf(a.ErrWriter, "Benchmark regression detected against %s\n", a.Config.BenchBaseline)
+		return apperrors.ExitErrorRegress
+	}
+	return apperrors.ExitSuccess
+}
+
+// runSelfTest runs the known-answer self-test of the arithmetic and prints
+// its report, as JSON with --json and not at all with --quiet. A failed check
+// exits with ExitErrorMismatch, which makes the command usable as a container
+// health check.
+func (a *Application) runSelfTest(ctx context.Context, out io.Writer) int {
+	ctx, cancelTimeout := context.WithTimeout(ctx, a.Config.Timeout)
+	defer cancelTimeout()
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	if !a.Config.Quiet && !a.Config.JSONOutput {
+		fmt.Fprintln(out, "Running the arithmetic self-test (SIMD levels x strategies x allocators)...")
+	}
+	report, err := selftest.Run(ctx, selftest.Options{})
+	if err != nil && report == nil {
+		fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+		return apperrors.ExitErrorGeneric
+	}
+
+	switch {
+	case a.Config.JSONOutput:
+		if err := report.WriteJSON(out); err != nil {
+			return apperrors.ExitErrorGeneric
+		}
+	case !a.Config.Quiet:
+		selftest.PrintReport(out, report)
+	}
+
+	if err != nil {
+		return apperrors.HandleCalculationError(err, 0, a.ErrWriter, cli.CLIColorProvider{})
+	}
+	if !report.Passed() {
+		fmt.Fprintf(a.ErrWriter, "Self-test failed: %d of %d checks\n", report.Failures(), len(report.Checks))
+		return apperrors.ExitErrorMismatch
+	}
+	return apperrors.ExitSuccess
+}
+
+// runInspect loads the result file given to the inspect subcommand and
+// prints its metadata, as JSON with --json. A binary file whose checksum does
+// not match exits with ExitErrorMismatch.
+func (a *Application) runInspect(out io.Writer) int {
+	loaded, err := resultfile.Load(a.Config.InspectFile)
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Error: cannot inspect %s: %v\n", a.Config.InspectFile, err)
+		if errors.Is(err, resultfile.ErrChecksum) {
+			return apperrors.ExitErrorMismatch
+		}
+		return apperrors.ExitErrorGeneric
+	}
+
+	if a.Config.JSONOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Path      string           `json:"path"`
+			Format    string           `json:"format"`
+			N         uint64           `json:"n"`
+			Algorithm string           `json:"algorithm,omitempty"`
+			Bits      int              `json:"bits"`
+			File      *resultfile.Info `json:"file,omitempty"`
+		}{a.Config.InspectFile, loaded.Format, loaded.N, loaded.Algorithm, loaded.Value.BitLen(), loaded.Info}); err != nil {
+			return apperrors.ExitErrorGeneric
+		}
+		return apperrors.ExitSuccess
+	}
+	if !a.Config.Quiet {
+		cli.DisplayResultFileInfo(out, a.Config.InspectFile, loaded)
+	}
 	return apperrors.ExitSuccess
 }
 
@@ -212,6 +548,7 @@ func (a *Application) runCalibration(ctx context.Context, out io.Writer) int {
 func (a *Application) runAutoCalibrationIfEnabled(ctx context.Context, out io.Writer) config.AppConfig {
 	if a.Config.AutoCalibrate {
 		if updated, ok := calibration.AutoCalibrate(ctx, a.Config, out, a.Factory.GetAll()); ok {
+			a.calibration = profileIdentity(CalibrationAuto, a.Config.CalibrationProfile)
 			return updated
 		}
 	}
@@ -226,45 +563,246 @@ func (a *Application) runCalculate(ctx context.Context, out io.Writer) int {
 	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
 	defer stopSignals()
 
+	// Resolve the "auto" pseudo-algorithm into a concrete calculator
+	runCfg := a.Config
+	var selection *calibration.AlgorithmSelection
+	if runCfg.Algo == config.AutoAlgo {
+		sel := a.selectAlgorithm()
+		selection = &sel
+		runCfg = applySelection(runCfg, sel)
+	}
+
 	// Get calculators to run
-	calculatorsToRun := cli.GetCalculatorsToRun(a.Config, a.Factory)
+	calculatorsToRun := cli.GetCalculatorsToRun(runCfg, a.Factory)
+	if len(calculatorsToRun) == 0 {
+		fmt.Fprintf(a.ErrWriter, "Error: no calculator available for algorithm '%s'\n", runCfg.Algo)
+		return apperrors.ExitErrorConfig
+	}
+
+	// Pre-flight the estimated peak memory against the budget
+	runCfg, ok := a.checkMemoryBudget(runCfg)
+	if !ok {
+		return apperrors.ExitErrorConfig
+	}
+	// A downgrade to low-memory mode applies to the whole run
+	fibonacci.ConfigureMemoryMode(runCfg.LowMemory)
 
 	// Skip verbose output in quiet mode
-	if !a.Config.JSONOutput && !a.Config.Quiet {
-		cli.PrintExecutionConfig(a.Config, out)
-		cli.PrintExecutionMode(calculatorsToRun, out)
+	if !runCfg.JSONOutput && !runCfg.Quiet {
+		cli.PrintExecutionConfig(runCfg, out)
+		if selection != nil && runCfg.Details {
+			fmt.Fprint(out, selection.Explain())
+		}
+		if runCfg.Race && len(calculatorsToRun) > 1 {
+			cli.PrintRaceMode(calculatorsToRun, out)
+		} else {
+			cli.PrintExecutionMode(calculatorsToRun, out)
+		}
 	}
 
-	// Choose progress reporter based on quiet mode
-	var progressReporter orchestration.ProgressReporter
-	progressOut := out
-	if a.Config.Quiet {
-		progressOut = io.Discard
-		progressReporter = orchestration.NullProgressReporter{}
-	} else {
-		progressReporter = cli.CLIProgressReporter{}
+	// Choose progress reporter based on the progress format and quiet mode
+	names := make([]string, len(calculatorsToRun))
+	for i, calc := range calculatorsToRun {
+		names[i] = calc.Name()
+	}
+	progressReporter, progressOut, closeProgress := a.progressOutput(names, out)
+
+	// Record the progress for the HTML report
+	var recorder *report.ProgressRecorder
+	if runCfg.Report != "" {
+		recorder = report.NewProgressRecorder(progressReporter)
+		progressReporter = recorder
+	}
+
+	// Record the session for --replay
+	var sessionRecorder *session.Recorder
+	if runCfg.Record != "" {
+		f, err := os.Create(runCfg.Record)
+		if err != nil {
+			closeProgress()
+			fmt.Fprintf(a.ErrWriter, "Error: cannot create session file: %v\n", err)
+			return apperrors.ExitErrorConfig
+		}
+		defer f.Close()
+		sessionRecorder = session.NewRecorder(f, session.NewHeader(runCfg, names))
+		ctx = orchestration.WithProgressObserver(ctx, sessionRecorder)
 	}
 
 	// Execute calculations
-	results := orchestration.ExecuteCalculations(ctx, calculatorsToRun, a.Config, progressReporter, progressOut)
+	results := orchestration.ExecuteCalculations(ctx, calculatorsToRun, runCfg, progressReporter, progressOut)
+	closeProgress()
+	if sessionRecorder != nil {
+		if err := sessionRecorder.Finish(results); err != nil {
+			fmt.Fprintf(a.ErrWriter, "Warning: failed to write session %s: %v\n", runCfg.Record, err)
+		}
+	}
 
+	// Presenting the results is dominated by the decimal conversion of F(n),
+	// which is labelled as its own phase in CPU profiles.
+	exitCode := apperrors.ExitSuccess
+	pprof.Do(ctx, pprof.Labels(fibonacci.LabelPhase, fibonacci.PhaseDecimalConversion), func(context.Context) {
+		exitCode = a.presentResults(runCfg, results, selection, out)
+	})
+	if runCfg.Report != "" {
+		exitCode = reportExitCode(exitCode, a.writeReport(runCfg, calculatorsToRun, results, recorder), a.ErrWriter)
+	}
+	return exitCode
+}
+
+// presentResults writes the results as JSON or through the CLI presenter,
+// honouring the output file, hexadecimal and quiet options. runCfg is the
+// configuration the calculations ran with.
+func (a *Application) presentResults(runCfg config.AppConfig, results []orchestration.CalculationResult, selection *calibration.AlgorithmSelection, out io.Writer) int {
 	// Handle JSON output
-	if a.Config.JSONOutput {
-		return printJSONResults(results, out)
+	if runCfg.JSONOutput {
+		return printJSONResults(results, selection, buildManifest(runCfg, a.calibration), out)
 	}
 
 	// Build output config for the CLI options
 	outputCfg := cli.OutputConfig{
-		OutputFile: a.Config.OutputFile,
-		HexOutput:  a.Config.HexOutput,
-		Quiet:      a.Config.Quiet,
-		Verbose:    a.Config.Verbose,
-		Concise:    a.Config.Concise,
+		OutputFile: runCfg.OutputFile,
+		HexOutput:  runCfg.HexOutput,
+		Quiet:      runCfg.Quiet,
+		Verbose:    runCfg.Verbose,
+		Concise:    runCfg.Concise,
+		Format:     runCfg.OutputFormat,
+		Compress:   runCfg.OutputCompress,
+		Thresholds: resultfile.Thresholds{
+			Parallel: runCfg.Threshold,
+			FFT:      runCfg.FFTThreshold,
+			Strassen: runCfg.StrassenThreshold,
+		},
 	}
 
 	return a.analyzeResultsWithOutput(results, outputCfg, out)
 }
 
+// progressOutput selects the progress reporter and its writer: NDJSON records
+// on the progress file descriptor for --progress-format json, nothing in quiet
+// mode, and the progress bar otherwise. names are the calculator names, by
+// calculator index.
+//
+// The returned function is called once the progress display is done: it
+// reports the first failed write of the NDJSON records, and closes the
+// progress file descriptor.
+func (a *Application) progressOutput(names []string, out io.Writer) (orchestration.ProgressReporter, io.Writer, func()) {
+	if a.Config.ProgressFormat == config.ProgressFormatJSON {
+		reporter := &orchestration.JSONProgressReporter{Names: names}
+		w, closeWriter := a.progressWriter(out)
+		return reporter, w, func() {
+			if err := reporter.Err(); err != nil {
+				fmt.Fprintf(a.ErrWriter, "Warning: failed to write progress records: %v\n", err)
+			}
+			if err := closeWriter(); err != nil {
+				fmt.Fprintf(a.ErrWriter, "Warning: failed to close progress file descriptor %d: %v\n", a.Config.ProgressFD, err)
+			}
+		}
+	}
+	if a.Config.Quiet {
+		return orchestration.NullProgressReporter{}, io.Discard, func() {}
+	}
+	return cli.CLIProgressReporter{}, out, func() {}
+}
+
+// progressWriter returns the writer for the configured progress file
+// descriptor and the function closing it. Descriptors 1 and 2 map to the
+// application's output and error writers, which are left open; any other
+// descriptor is expected to be inherited from the parent.
+func (a *Application) progressWriter(out io.Writer) (io.Writer, func() error) {
+	switch a.Config.ProgressFD {
+	case 1:
+		return out, func() error { return nil }
+	case 2:
+		return a.ErrWriter, func() error { return nil }
+	default:
+		f := os.NewFile(uintptr(a.Config.ProgressFD), fmt.Sprintf("progress-fd-%d", a.Config.ProgressFD))
+		return f, f.Close
+	}
+}
+
+// algorithmKeys returns the registered algorithm names selected by algo,
+// expanding "all" to every registered calculator.
+func (a *Application) algorithmKeys(algo string) []string {
+	if algo == "all" {
+		return a.Factory.List()
+	}
+	return []string{algo}
+}
+
+// checkMemoryBudget compares the estimated peak memory of the calculations
+// with the memory budget (--max-memory or GOMEMLIMIT). When the estimate
+// exceeds the budget, it switches to low-memory mode if that fits, or
+// reports an error. --max-memory is also applied as the runtime soft memory
+// limit so the GC works to stay within it.
+//
+// Returns:
+//   - config.AppConfig: The configuration to run, possibly in low-memory mode.
+//   - bool: False if the calculation must be refused.
+func (a *Application) checkMemoryBudget(cfg config.AppConfig) (config.AppConfig, bool) {
+	budget := cfg.MemoryBudget()
+	if budget == 0 {
+		return cfg, true
+	}
+	check := fibonacci.CheckMemoryBudget(cfg.N, a.algorithmKeys(cfg.Algo), cfg.ToCalculationOptions(), budget)
+	switch {
+	case check.Fits:
+	case check.Downgrade:
+		if !cfg.Quiet {
+			fmt.Fprintf(a.ErrWriter, "Warning: estimated peak memory %s exceeds the budget of %s; switching to low-memory mode (estimated %s)\n",
+				cli.FormatBytes(check.Estimate), cli.FormatBytes(budget), cli.FormatBytes(check.LowMemoryEstimate))
+		}
+		cfg.LowMemory = true
+	default:
+		fmt.Fprintf(a.ErrWriter, "Error: estimated peak memory %s (%s in low-memory mode) exceeds the budget of %s\n",
+			cli.FormatBytes(check.Estimate), cli.FormatBytes(check.LowMemoryEstimate), cli.FormatBytes(budget))
+		return cfg, false
+	}
+
+	if cfg.MaxMemory != "" {
+		debug.SetMemoryLimit(int64(budget))
+	}
+	return cfg, true
+}
+
+// selectAlgorithm runs the automatic algorithm selection for the configured N,
+// using the calibration profile (if any) and the registered calculators.
+func (a *Application) selectAlgorithm() calibration.AlgorithmSelection {
+	return calibration.SelectAlgorithm(a.Config.N, a.loadProfile(), a.Factory.List())
+}
+
+// loadProfile loads the calibration profile of the cost models. A missing
+// profile selects the built-in defaults silently, but an unreadable or
+// corrupt one is reported before falling back to them.
+func (a *Application) loadProfile() *calibration.CalibrationProfile {
+	profile, err := calibration.LoadProfile(a.Config.CalibrationProfile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(a.ErrWriter, "Warning: ignoring calibration profile: %v\n", err)
+		}
+		return nil
+	}
+	return profile
+}
+
+// applySelection returns a copy of cfg configured to run the selected
+// algorithm, with the range-specific thresholds from the profile if any.
+// Thresholds set explicitly by the user are kept.
+func applySelection(cfg config.AppConfig, sel calibration.AlgorithmSelection) config.AppConfig {
+	cfg.Algo = sel.Algorithm
+	if t := sel.Thresholds; t != nil {
+		if t.Parallel > 0 && !cfg.ThresholdsSet.Parallel {
+			cfg.Threshold = t.Parallel
+		}
+		if t.FFT > 0 && !cfg.ThresholdsSet.FFT {
+			cfg.FFTThreshold = t.FFT
+		}
+		if t.Strassen > 0 && !cfg.ThresholdsSet.Strassen {
+			cfg.StrassenThreshold = t.Strassen
+		}
+	}
+	return cfg
+}
+
 func (a *Application) analyzeResultsWithOutput(results []orchestration.CalculationResult, outputCfg cli.OutputConfig, out io.Writer) int {
 	bestResult := findBestResult(results)
 
@@ -280,8 +818,13 @@ func (a *Application) analyzeResultsWithOutput(results []orchestration.Calculati
 		return apperrors.ExitSuccess
 	}
 
-	// Use standard analysis for non-quiet mode
-	exitCode := orchestration.AnalyzeComparisonResults(results, a.Config, cli.CLIResultPresenter{}, out)
+	// Use race or standard analysis for non-quiet mode
+	var exitCode int
+	if a.Config.Race {
+		exitCode = orchestration.AnalyzeRaceResults(results, a.Config, cli.CLIResultPresenter{}, out)
+	} else {
+		exitCode = orchestration.AnalyzeComparisonResults(results, a.Config, cli.CLIResultPresenter{}, out)
+	}
 
 	// Handle file output and hex display for non-quiet mode
 	if bestResult != nil && exitCode == apperrors.ExitSuccess {
@@ -346,32 +889,57 @@ func (a *Application) displayHexIfNeeded(res *orchestration.CalculationResult, c
 
 // jsonResult represents a single calculation result in JSON format.
 type jsonResult struct {
-	Algorithm string `json:"algorithm"`
-	Duration  string `json:"duration"`
-	Result    string `json:"result,omitempty"`
-	Error     string `json:"error,omitempty"`
+	Algorithm string                          `json:"algorithm"`
+	Duration  string                          `json:"duration"`
+	Result    string                          `json:"result,omitempty"`
+	Bits      int                             `json:"bits,omitempty"`
+	SHA256    string                          `json:"sha256,omitempty"`
+	Error     string                          `json:"error,omitempty"`
+	Selection *calibration.AlgorithmSelection `json:"selection,omitempty"`
+	Resources *orchestration.ResourceUsage    `json:"resources,omitempty"`
+	// DynamicThresholds are the final thresholds with --dynamic-thresholds.
+	DynamicThresholds *DynamicThresholds `json:"dynamic_thresholds,omitempty"`
 }
 
-// printJSONResults formats the calculation results as a JSON array and writes
-// them to the output. This is useful for programmatic consumption of the results.
-func printJSONResults(results []orchestration.CalculationResult, out io.Writer) int {
+// jsonReport is the document written by --json: the results and the
+// manifest of the run, under a versioned schema.
+type jsonReport struct {
+	SchemaVersion int          `json:"schema_version"`
+	Manifest      Manifest     `json:"manifest"`
+	Results       []jsonResult `json:"results"`
+}
+
+// printJSONResults formats the calculation results and the manifest of the
+// run as a JSON document and writes it to the output. This is useful for
+// programmatic consumption of the results. When the algorithm was chosen
+// automatically, the selection is attached to each result to explain the
+// choice. Each result carries the SHA-256 of its big-endian magnitude, so
+// that results can be compared without their digits.
+func printJSONResults(results []orchestration.CalculationResult, selection *calibration.AlgorithmSelection, manifest Manifest, out io.Writer) int {
 	output := make([]jsonResult, len(results))
 	for i, res := range results {
 		jr := jsonResult{
-			Algorithm: res.Name,
-			Duration:  res.Duration.String(),
+			Algorithm:         res.Name,
+			Duration:          res.Duration.String(),
+			Selection:         selection,
+			DynamicThresholds: dynamicThresholds(res.Thresholds),
+		}
+		if res.Resources.Available() {
+			jr.Resources = &res.Resources
 		}
 		if res.Err != nil {
 			jr.Error = res.Err.Error()
 		} else {
 			jr.Result = res.Result.String()
+			jr.Bits = res.Result.BitLen()
+			jr.SHA256 = orchestration.Digest(res.Result)
 		}
 		output[i] = jr
 	}
 
 	enc := json.NewEncoder(out)
 	enc.SetIndent("", "  ")
-	if err := enc.Encode(output); err != nil {
+	if err := enc.Encode(jsonReport{SchemaVersion: ManifestSchemaVersion, Manifest: manifest, Results: output}); err != nil {
 		return apperrors.ExitErrorGeneric
 	}
 	return apperrors.ExitSuccess
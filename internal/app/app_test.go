@@ -4,21 +4,29 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
+	"path/filepath"
 	"runtime"
+	"runtime/debug"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/bench"
 	"github.com/agbru/fibcalc/internal/calibration"
 	"github.com/agbru/fibcalc/internal/cli"
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/resultfile"
+	"github.com/agbru/fibcalc/internal/selftest"
 	"github.com/agbru/fibcalc/internal/testutil"
+	"go.opentelemetry.io/otel"
 )
 
 // Helper to create a test factory with mocked calculator
@@ -394,6 +402,240 @@ func TestPrintJSONResults(t *testing.T) {
 	}
 }
 
+// TestAutoAlgorithmSelection tests the "auto" pseudo-algorithm in JSON and details mode.
+func TestAutoAlgorithmSelection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("JSON output includes selection", func(t *testing.T) {
+		t.Parallel()
+		var outBuf bytes.Buffer
+		app := &Application{
+			Config: config.AppConfig{
+				N:                  1_000_000,
+				Algo:               config.AutoAlgo,
+				Timeout:            1 * time.Minute,
+				JSONOutput:         true,
+				CalibrationProfile: filepath.Join(t.TempDir(), "missing.json"),
+			},
+			Factory:   createMockFactory(big.NewInt(5), nil),
+			ErrWriter: &bytes.Buffer{},
+		}
+
+		if exitCode := app.Run(context.Background(), &outBuf); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+		}
+		output := outBuf.String()
+		for _, want := range []string{`"selection"`, `"candidates"`, `"reason"`} {
+			if !strings.Contains(output, want) {
+				t.Errorf("JSON output should contain %s. Got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("Details explain the choice", func(t *testing.T) {
+		t.Parallel()
+		var outBuf bytes.Buffer
+		app := &Application{
+			Config: config.AppConfig{
+				N:                  1_000_000,
+				Algo:               config.AutoAlgo,
+				Timeout:            1 * time.Minute,
+				Details:            true,
+				CalibrationProfile: filepath.Join(t.TempDir(), "missing.json"),
+			},
+			Factory:   createMockFactory(big.NewInt(5), nil),
+			ErrWriter: &bytes.Buffer{},
+		}
+
+		if exitCode := app.Run(context.Background(), &outBuf); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+		}
+		if !strings.Contains(outBuf.String(), "Auto-selected algorithm:") {
+			t.Errorf("Expected selection explanation in output. Got:\n%s", outBuf.String())
+		}
+	})
+}
+
+// TestApplySelection tests that the range thresholds of the profile do not
+// override thresholds set by the user.
+func TestApplySelection(t *testing.T) {
+	t.Parallel()
+	sel := calibration.AlgorithmSelection{
+		Algorithm:  "fast",
+		Thresholds: &calibration.SelectedThresholds{Parallel: 2048, FFT: 800_000, Strassen: 1024},
+	}
+	cfg := config.AppConfig{Algo: config.AutoAlgo, Threshold: 4096, FFTThreshold: 500_000, StrassenThreshold: 3072}
+
+	got := applySelection(cfg, sel)
+	if got.Algo != "fast" || got.Threshold != 2048 || got.FFTThreshold != 800_000 || got.StrassenThreshold != 1024 {
+		t.Errorf("Expected the range thresholds, got %+v", got)
+	}
+
+	cfg.ThresholdsSet = config.ThresholdsSet{Parallel: true, FFT: true}
+	got = applySelection(cfg, sel)
+	if got.Threshold != 4096 || got.FFTThreshold != 500_000 || got.StrassenThreshold != 1024 {
+		t.Errorf("Expected the explicit thresholds kept, got %+v", got)
+	}
+}
+
+// TestLoadProfile tests that a corrupt calibration profile is reported.
+func TestLoadProfile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		path, want string
+	}{
+		{filepath.Join(dir, "missing.json"), ""},
+		{corrupt, "Warning: ignoring calibration profile: failed to parse profile"},
+	} {
+		var errBuf bytes.Buffer
+		app := &Application{Config: config.AppConfig{CalibrationProfile: tt.path}, ErrWriter: &errBuf}
+		if app.loadProfile() != nil {
+			t.Errorf("%s: expected no profile", tt.path)
+		}
+		if got := errBuf.String(); tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: unexpected warning %q", tt.path, got)
+		}
+	}
+}
+
+// TestExplainMode tests that --explain prints plans without calculating.
+func TestExplainMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Text output", func(t *testing.T) {
+		t.Parallel()
+		var outBuf bytes.Buffer
+		factory := createMockFactory(nil, errors.New("calculation must not run"))
+		app := &Application{
+			Config: config.AppConfig{
+				N:                  1_000_000,
+				Algo:               "all",
+				Timeout:            1 * time.Minute,
+				Explain:            true,
+				CalibrationProfile: filepath.Join(t.TempDir(), "missing.json"),
+			},
+			Factory:   factory,
+			ErrWriter: &bytes.Buffer{},
+		}
+
+		if exitCode := app.Run(context.Background(), &outBuf); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+		}
+		output := outBuf.String()
+		if got := strings.Count(output, "Execution Plan"); got != 3 {
+			t.Errorf("Expected 3 plans, got %d. Output:\n%s", got, output)
+		}
+		if !strings.Contains(output, "Estimated duration:") {
+			t.Errorf("Expected duration estimate in output. Got:\n%s", output)
+		}
+	})
+
+	t.Run("JSON output with auto", func(t *testing.T) {
+		t.Parallel()
+		var outBuf bytes.Buffer
+		app := &Application{
+			Config: config.AppConfig{
+				N:                  1_000_000,
+				Algo:               config.AutoAlgo,
+				Timeout:            1 * time.Minute,
+				Explain:            true,
+				JSONOutput:         true,
+				CalibrationProfile: filepath.Join(t.TempDir(), "missing.json"),
+			},
+			Factory:   createMockFactory(big.NewInt(5), nil),
+			ErrWriter: &bytes.Buffer{},
+		}
+
+		if exitCode := app.Run(context.Background(), &outBuf); exitCode != apperrors.ExitSuccess {
+			t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+		}
+		var doc explainOutput
+		if err := json.Unmarshal(outBuf.Bytes(), &doc); err != nil {
+			t.Fatalf("Invalid JSON output: %v\n%s", err, outBuf.String())
+		}
+		if doc.Selection == nil || len(doc.Plans) != 1 {
+			t.Fatalf("Expected a selection and one plan, got %+v", doc)
+		}
+		if doc.Plans[0].Algorithm != doc.Selection.Algorithm || len(doc.Plans[0].Steps) == 0 {
+			t.Errorf("Unexpected plan: %+v", doc.Plans[0])
+		}
+	})
+
+	t.Run("Unknown algorithm", func(t *testing.T) {
+		t.Parallel()
+		app := &Application{
+			Config:    config.AppConfig{N: 1000, Algo: "unknown", Timeout: time.Minute, Explain: true},
+			Factory:   createMockFactory(big.NewInt(5), nil),
+			ErrWriter: &bytes.Buffer{},
+		}
+		if exitCode := app.Run(context.Background(), io.Discard); exitCode != apperrors.ExitErrorConfig {
+			t.Errorf("Expected exit code %d, got %d", apperrors.ExitErrorConfig, exitCode)
+		}
+	})
+}
+
+// TestMemoryBudget tests the memory budget pre-flight of the CLI calculation.
+func TestMemoryBudget(t *testing.T) {
+	// Not parallel: --max-memory changes the runtime memory limit.
+	previous := debug.SetMemoryLimit(-1)
+	defer debug.SetMemoryLimit(previous)
+
+	const n = 10_000_000
+	opts := fibonacci.Options{}
+	normal := fibonacci.EstimatePeakMemory(n, "fast", opts)
+	low := fibonacci.EstimatePeakMemory(n, "fast", fibonacci.Options{LowMemory: true})
+
+	t.Run("Downgrade to low-memory mode", func(t *testing.T) {
+		var errBuf bytes.Buffer
+		app := &Application{
+			Config:    config.AppConfig{N: n, Algo: "fast", Timeout: time.Minute, MaxMemory: fmt.Sprint(low)},
+			Factory:   createMockFactory(big.NewInt(5), nil),
+			ErrWriter: &errBuf,
+		}
+		cfg, ok := app.checkMemoryBudget(app.Config)
+		if !ok || !cfg.LowMemory {
+			t.Fatalf("expected a downgrade, got ok=%v low=%v", ok, cfg.LowMemory)
+		}
+		if !strings.Contains(errBuf.String(), "switching to low-memory mode") {
+			t.Errorf("expected a warning, got %q", errBuf.String())
+		}
+		if limit := debug.SetMemoryLimit(-1); uint64(limit) != low {
+			t.Errorf("runtime memory limit = %d, want %d", limit, low)
+		}
+	})
+
+	t.Run("Refuse", func(t *testing.T) {
+		var errBuf bytes.Buffer
+		app := &Application{
+			Config:    config.AppConfig{N: n, Algo: "fast", Timeout: time.Minute, MaxMemory: fmt.Sprint(low - 1)},
+			Factory:   createMockFactory(big.NewInt(5), nil),
+			ErrWriter: &errBuf,
+		}
+		if exitCode := app.runCalculate(context.Background(), io.Discard); exitCode != apperrors.ExitErrorConfig {
+			t.Errorf("Expected exit code %d, got %d", apperrors.ExitErrorConfig, exitCode)
+		}
+		if !strings.Contains(errBuf.String(), "exceeds the budget") {
+			t.Errorf("expected an error message, got %q", errBuf.String())
+		}
+	})
+
+	t.Run("Fits", func(t *testing.T) {
+		app := &Application{
+			Config:    config.AppConfig{N: n, Algo: "fast", Timeout: time.Minute, MaxMemory: fmt.Sprint(normal)},
+			Factory:   createMockFactory(big.NewInt(5), nil),
+			ErrWriter: &bytes.Buffer{},
+		}
+		if cfg, ok := app.checkMemoryBudget(app.Config); !ok || cfg.LowMemory {
+			t.Errorf("expected the calculation to fit, got ok=%v low=%v", ok, cfg.LowMemory)
+		}
+	})
+}
+
 // TestHexOutput tests hexadecimal output mode.
 func TestHexOutput(t *testing.T) {
 	t.Parallel()
@@ -636,7 +878,7 @@ func TestPrintJSONResultsError(t *testing.T) {
 		},
 	}
 	var outBuf bytes.Buffer
-	exitCode := printJSONResults(results, &outBuf)
+	exitCode := printJSONResults(results, nil, Manifest{}, &outBuf)
 	if exitCode != apperrors.ExitSuccess {
 		t.Errorf("Expected success, got %d", exitCode)
 	}
@@ -645,6 +887,67 @@ func TestPrintJSONResultsError(t *testing.T) {
 	}
 }
 
+// TestPrintJSONResultsResources verifies that resource usage is part of the
+// JSON output.
+func TestPrintJSONResultsResources(t *testing.T) {
+	t.Parallel()
+	results := []orchestration.CalculationResult{
+		{
+			Name:      "fast",
+			Result:    big.NewInt(55),
+			Resources: orchestration.ResourceUsage{GCCycles: 7, ParallelEfficiency: 0.25},
+		},
+	}
+	var outBuf bytes.Buffer
+	if exitCode := printJSONResults(results, nil, Manifest{}, &outBuf); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected success, got %d", exitCode)
+	}
+	var decoded struct {
+		Results []struct {
+			Resources orchestration.ResourceUsage `json:"resources"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(outBuf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(decoded.Results) != 1 || decoded.Results[0].Resources.GCCycles != 7 || decoded.Results[0].Resources.ParallelEfficiency != 0.25 {
+		t.Errorf("Unexpected resources: %+v", decoded)
+	}
+}
+
+func TestPrintJSONResultsDynamicThresholds(t *testing.T) {
+	t.Parallel()
+	results := []orchestration.CalculationResult{
+		{
+			Name:   "fast",
+			Result: big.NewInt(55),
+			Thresholds: &fibonacci.ThresholdStats{
+				OriginalFFT: 500000, CurrentFFT: 250000, OriginalParallel: 4096, CurrentParallel: 8192, IterationsProcessed: 17,
+			},
+		},
+		{Name: "matrix", Result: big.NewInt(55)},
+	}
+	var outBuf bytes.Buffer
+	if exitCode := printJSONResults(results, nil, Manifest{}, &outBuf); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected success, got %d", exitCode)
+	}
+	var decoded struct {
+		Results []struct {
+			DynamicThresholds *DynamicThresholds `json:"dynamic_thresholds"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(outBuf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	want := DynamicThresholds{InitialFFT: 500000, InitialParallel: 4096, FinalFFT: 250000, FinalParallel: 8192, Iterations: 17}
+	if len(decoded.Results) != 2 || decoded.Results[0].DynamicThresholds == nil || *decoded.Results[0].DynamicThresholds != want {
+		t.Errorf("Unexpected dynamic thresholds: %+v", decoded.Results)
+	}
+	if decoded.Results[1].DynamicThresholds != nil {
+		t.Errorf("Expected no dynamic thresholds without adjustments, got %+v", decoded.Results[1].DynamicThresholds)
+	}
+}
+
 // TestRunServer tests the runServer method.
 func TestRunServer(t *testing.T) {
 	t.Parallel()
@@ -991,3 +1294,361 @@ func TestRunAllModes(t *testing.T) {
 		}
 	})
 }
+
+// TestJSONProgressFormat verifies that --progress-format json writes NDJSON
+// progress records to stderr, even in quiet mode.
+func TestJSONProgressFormat(t *testing.T) {
+	t.Parallel()
+	var outBuf, errBuf bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:              1000,
+			Algo:           "fast",
+			Timeout:        1 * time.Minute,
+			Quiet:          true,
+			ProgressFormat: config.ProgressFormatJSON,
+			ProgressFD:     config.DefaultProgressFD,
+		},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &errBuf,
+	}
+
+	if exitCode := app.Run(context.Background(), &outBuf); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+
+	lines := strings.Split(strings.TrimSpace(errBuf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 NDJSON records, got %d:\n%s", len(lines), errBuf.String())
+	}
+	var rec orchestration.ProgressRecord
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("Invalid NDJSON record %q: %v", lines[0], err)
+	}
+	if rec.Type != "progress" || rec.Progress != 1.0 || rec.Algorithm == "" {
+		t.Errorf("Unexpected progress record: %+v", rec)
+	}
+	if !strings.Contains(lines[1], `"type":"done"`) {
+		t.Errorf("Expected final done record, got %s", lines[1])
+	}
+	if strings.Contains(outBuf.String(), "{") {
+		t.Errorf("Progress records must not be written to stdout, got:\n%s", outBuf.String())
+	}
+}
+
+// TestJSONProgressWriteError verifies that a failed write of the NDJSON
+// progress records is reported.
+func TestJSONProgressWriteError(t *testing.T) {
+	t.Parallel()
+	var errBuf bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:              1000,
+			Algo:           "fast",
+			Timeout:        1 * time.Minute,
+			Quiet:          true,
+			ProgressFormat: config.ProgressFormatJSON,
+			ProgressFD:     1 << 20, // not open
+		},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &errBuf,
+	}
+
+	if exitCode := app.Run(context.Background(), io.Discard); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+	if !strings.Contains(errBuf.String(), "Warning: failed to write progress records:") {
+		t.Errorf("Expected the write error reported, got:\n%s", errBuf.String())
+	}
+}
+
+// TestTraceOut verifies that --trace-out writes the spans of a run as JSON.
+// It is not parallel because it replaces the global tracer provider.
+func TestTraceOut(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	tracePath := filepath.Join(t.TempDir(), "trace.json")
+	app := &Application{
+		Config: config.AppConfig{
+			N:        1000,
+			Algo:     "fast",
+			Timeout:  1 * time.Minute,
+			Quiet:    true,
+			TraceOut: tracePath,
+		},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	if exitCode := app.Run(context.Background(), io.Discard); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+
+	data, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatalf("Trace file not written: %v", err)
+	}
+	if !strings.Contains(string(data), `"Name":"fibcalc"`) {
+		t.Errorf("Expected root span in trace output, got:\n%s", data)
+	}
+}
+
+// TestProfilingFlags verifies that the profiling flags write their files.
+// It is not parallel because CPU profiling is process-wide.
+func TestProfilingFlags(t *testing.T) {
+	dir := t.TempDir()
+	app := &Application{
+		Config: config.AppConfig{
+			N:          1000,
+			Algo:       "fast",
+			Timeout:    1 * time.Minute,
+			Quiet:      true,
+			CPUProfile: filepath.Join(dir, "cpu.pprof"),
+			MemProfile: filepath.Join(dir, "mem.pprof"),
+		},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	if exitCode := app.Run(context.Background(), io.Discard); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+	for _, path := range []string{app.Config.CPUProfile, app.Config.MemProfile} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("Profile %s not written: %v", filepath.Base(path), err)
+		}
+	}
+
+	app.Config.CPUProfile = filepath.Join(dir, "missing", "cpu.pprof")
+	if exitCode := app.Run(context.Background(), io.Discard); exitCode != apperrors.ExitErrorConfig {
+		t.Errorf("Expected exit code %d for unwritable profile, got %d", apperrors.ExitErrorConfig, exitCode)
+	}
+}
+
+// TestBenchMode verifies the benchmark mode, its export and the regression
+// exit code against a baseline.
+// It is not parallel because the benchmark changes GOMAXPROCS.
+func TestBenchMode(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "bench.json")
+	app := &Application{
+		Config: config.AppConfig{
+			N:              1000,
+			Algo:           "fast",
+			Timeout:        1 * time.Minute,
+			Bench:          true,
+			BenchRuns:      3,
+			BenchWarmup:    1,
+			BenchProcs:     "1,2",
+			BenchOut:       jsonPath,
+			BenchTolerance: config.DefaultBenchTolerance,
+		},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	var out bytes.Buffer
+	if exitCode := app.Run(context.Background(), &out); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+	if !strings.Contains(out.String(), "Median") || !strings.Contains(out.String(), "95% CI") {
+		t.Errorf("Expected statistics table, got:\n%s", out.String())
+	}
+	report, err := bench.LoadReport(jsonPath)
+	if err != nil {
+		t.Fatalf("Report not exported: %v", err)
+	}
+	if len(report.Results) != 2 || report.Results[0].Stats.Count != 3 {
+		t.Fatalf("Unexpected report: %+v", report.Results)
+	}
+
+	// An impossibly fast baseline must be reported as a regression. The
+	// calculator sleeps so that the confidence interval of the new runs
+	// cannot reach down to the 1ns baseline.
+	app.Factory = fibonacci.NewTestFactory(map[string]fibonacci.Calculator{
+		"fast": &fibonacci.MockCalculator{Fn: func(context.Context, uint64) (*big.Int, error) {
+			time.Sleep(2 * time.Millisecond)
+			return big.NewInt(42), nil
+		}},
+	})
+	for i := range report.Results {
+		report.Results[i].Stats = bench.ComputeStats([]time.Duration{1, 1, 1})
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := bench.SaveReport(report, baselinePath); err != nil {
+		t.Fatal(err)
+	}
+	app.Config.BenchOut = filepath.Join(dir, "bench.csv")
+	app.Config.BenchBaseline = baselinePath
+	out.Reset()
+	if exitCode := app.Run(context.Background(), &out); exitCode != apperrors.ExitErrorRegress {
+		t.Errorf("Expected exit code %d, got %d:\n%s", apperrors.ExitErrorRegress, exitCode, out.String())
+	}
+	if !strings.Contains(out.String(), "REGRESSION") {
+		t.Errorf("Expected regression in comparison, got:\n%s", out.String())
+	}
+	if data, err := os.ReadFile(app.Config.BenchOut); err != nil || !strings.HasPrefix(string(data), "algorithm,procs") {
+		t.Errorf("CSV report not written: %v", err)
+	}
+
+	app.Config.BenchProcs = "0"
+	if exitCode := app.Run(context.Background(), io.Discard); exitCode != apperrors.ExitErrorConfig {
+		t.Errorf("Expected exit code %d for invalid procs, got %d", apperrors.ExitErrorConfig, exitCode)
+	}
+}
+
+// TestRaceMode verifies that --race reports the winner of the race.
+func TestRaceMode(t *testing.T) {
+	t.Parallel()
+	app := &Application{
+		Config: config.AppConfig{
+			N:       1000,
+			Algo:    "all",
+			Timeout: 1 * time.Minute,
+			Race:    true,
+		},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	var out bytes.Buffer
+	if exitCode := app.Run(context.Background(), &out); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+	for _, want := range []string{"Race between 3 algorithms", "Race Summary", "Winner:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "Comparison Summary") {
+		t.Error("Race mode should not print the comparison table")
+	}
+}
+
+func TestSelfTestMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the full self-test in short mode")
+	}
+	app := &Application{
+		Config: config.AppConfig{
+			Timeout:    1 * time.Minute,
+			SelfTest:   true,
+			JSONOutput: true,
+		},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+
+	var out bytes.Buffer
+	if exitCode := app.Run(context.Background(), &out); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d:\n%s", apperrors.ExitSuccess, exitCode, out.String())
+	}
+	var report selftest.Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid JSON report: %v", err)
+	}
+	if len(report.Checks) == 0 || !report.Passed() {
+		t.Errorf("Expected passing checks, got %+v", report.Checks)
+	}
+}
+
+func TestInspectMode(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "f300.fib")
+	f300, _ := new(big.Int).SetString("222232244629420445529739893461909967206666939096499764990979600", 10)
+
+	writer := &Application{
+		Config: config.AppConfig{
+			N:              300,
+			OutputFile:     path,
+			OutputFormat:   resultfile.FormatBinary,
+			OutputCompress: resultfile.CompressionGzip,
+			FFTThreshold:   500000,
+		},
+		ErrWriter: &bytes.Buffer{},
+	}
+	results := []orchestration.CalculationResult{{Name: "fast", Result: f300, Duration: time.Millisecond}}
+	if exitCode := writer.presentResults(writer.Config, results, nil, io.Discard); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d writing the file, got %d", apperrors.ExitSuccess, exitCode)
+	}
+
+	inspect := func(jsonOutput bool) (int, string) {
+		app := &Application{
+			Config:    config.AppConfig{InspectFile: path, JSONOutput: jsonOutput},
+			ErrWriter: &bytes.Buffer{},
+		}
+		var out bytes.Buffer
+		return app.Run(context.Background(), &out), out.String()
+	}
+
+	exitCode, out := inspect(false)
+	if exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+	for _, want := range []string{"bin (version 1, gzip compression)", "F(300)", "fast", "FFT 500000", "(verified)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Inspect output lacks %q:\n%s", want, out)
+		}
+	}
+
+	exitCode, out = inspect(true)
+	var report struct {
+		Format string
+		N      uint64
+		Bits   int
+	}
+	if err := json.Unmarshal([]byte(out), &report); exitCode != apperrors.ExitSuccess || err != nil {
+		t.Fatalf("Expected a JSON report, got exit code %d, %v:\n%s", exitCode, err, out)
+	}
+	if report.Format != resultfile.FormatBinary || report.N != 300 || report.Bits != f300.BitLen() {
+		t.Errorf("Unexpected JSON report %+v", report)
+	}
+
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 1
+	os.WriteFile(path, data, 0644)
+	if exitCode, _ := inspect(false); exitCode != apperrors.ExitErrorMismatch {
+		t.Errorf("Expected exit code %d for a corrupted file, got %d", apperrors.ExitErrorMismatch, exitCode)
+	}
+}
+
+func TestScriptMode(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	run := func(name, script string) (int, string) {
+		path := filepath.Join(dir, name)
+		if script != "" {
+			if err := os.WriteFile(path, []byte(script), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var errBuf bytes.Buffer
+		app := &Application{
+			Config:    config.AppConfig{Script: path, Algo: "fast", Timeout: time.Minute},
+			Factory:   createMockFactory(big.NewInt(55), nil),
+			ErrWriter: &errBuf,
+		}
+		return app.Run(context.Background(), io.Discard), errBuf.String()
+	}
+
+	if exitCode, stderr := run("pass.fib", "# F(10)\ncalc 10\nassert last == 55\n"); exitCode != apperrors.ExitSuccess {
+		t.Errorf("Expected exit code %d, got %d: %s", apperrors.ExitSuccess, exitCode, stderr)
+	}
+
+	exitCode, stderr := run("assert.fib", "calc 10\nassert digits(last) == 3\nassert last_n == 10\n")
+	if exitCode != apperrors.ExitErrorMismatch {
+		t.Errorf("Expected exit code %d for a failed assertion, got %d", apperrors.ExitErrorMismatch, exitCode)
+	}
+	if !strings.Contains(stderr, "assert.fib:2: assert digits(last) == 3: assertion failed: got 2 == 3") {
+		t.Errorf("Unexpected report %q", stderr)
+	}
+
+	if exitCode, _ := run("command.fib", "calc 10\nfrobnicate\nassert last == 55\n"); exitCode != apperrors.ExitErrorGeneric {
+		t.Errorf("Expected exit code %d for a failed command, got %d", apperrors.ExitErrorGeneric, exitCode)
+	}
+	if exitCode, _ := run("missing.fib", ""); exitCode != apperrors.ExitErrorConfig {
+		t.Errorf("Expected exit code %d for a missing script, got %d", apperrors.ExitErrorConfig, exitCode)
+	}
+}
@@ -0,0 +1,187 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// ManifestSchemaVersion is the version of the JSON output schema. It is
+// incremented whenever a field is removed or changes meaning; new fields
+// do not change the version.
+const ManifestSchemaVersion = 1
+
+// Calibration sources recorded in the manifest.
+const (
+	// CalibrationNone means the thresholds are the hardware-adaptive
+	// defaults or the values given on the command line.
+	CalibrationNone = "none"
+	// CalibrationProfile means the thresholds come from a cached profile.
+	CalibrationProfile = "profile"
+	// CalibrationAuto means the thresholds come from --auto-calibrate.
+	CalibrationAuto = "auto-calibration"
+)
+
+// Manifest describes how a set of results was produced, so that results
+// obtained on different machines can be compared and reproduced.
+type Manifest struct {
+	// Build identifies the binary.
+	Build VersionData `json:"build"`
+	// CPU describes the SIMD features of the CPU and the active
+	// implementation of the FFT arithmetic.
+	CPU CPUInfo `json:"cpu"`
+	// GOMAXPROCS and NumCPU describe the available parallelism.
+	GOMAXPROCS int `json:"gomaxprocs"`
+	NumCPU     int `json:"num_cpu"`
+	// N is the index of the calculated Fibonacci number.
+	N uint64 `json:"n"`
+	// Strategy is the algorithm run ("all" or a calculator name, after
+	// resolving "auto"), and Race whether the first result won.
+	Strategy string `json:"strategy"`
+	Race     bool   `json:"race,omitempty"`
+	// Thresholds are the thresholds the calculators used.
+	Thresholds ManifestThresholds `json:"thresholds"`
+	// Calibration identifies the source of the thresholds.
+	Calibration CalibrationIdentity `json:"calibration"`
+	// LowMemory and the integrity settings change the code paths taken.
+	LowMemory         bool `json:"low_memory"`
+	IntegrityCheck    bool `json:"integrity_check"`
+	IntegrityInterval int  `json:"integrity_interval,omitempty"`
+	// Timestamp is the time the manifest was produced.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ManifestThresholds are the effective thresholds of a calculation, in bits,
+// with the defaults filled in for zero values.
+type ManifestThresholds struct {
+	Parallel  int `json:"parallel"`
+	FFT       int `json:"fft"`
+	Karatsuba int `json:"karatsuba"`
+	Strassen  int `json:"strassen"`
+	// Dynamic reports whether the thresholds could be adjusted during the
+	// calculation; the values above are then the initial ones, and each
+	// result carries its final ones in DynamicThresholds.
+	Dynamic bool `json:"dynamic"`
+}
+
+// DynamicThresholds are the FFT and parallel thresholds of a calculation
+// with dynamic thresholds, in bits, before and after the adjustments of the
+// doubling loop.
+type DynamicThresholds struct {
+	InitialFFT      int `json:"initial_fft"`
+	InitialParallel int `json:"initial_parallel"`
+	FinalFFT        int `json:"final_fft"`
+	FinalParallel   int `json:"final_parallel"`
+	// Iterations is the number of doubling steps measured.
+	Iterations int `json:"iterations"`
+}
+
+// dynamicThresholds converts the statistics of a calculation, or returns
+// nil if it did not adjust its thresholds.
+func dynamicThresholds(stats *fibonacci.ThresholdStats) *DynamicThresholds {
+	if stats == nil {
+		return nil
+	}
+	return &DynamicThresholds{
+		InitialFFT:      stats.OriginalFFT,
+		InitialParallel: stats.OriginalParallel,
+		FinalFFT:        stats.CurrentFFT,
+		FinalParallel:   stats.CurrentParallel,
+		Iterations:      stats.IterationsProcessed,
+	}
+}
+
+// CPUInfo describes the CPU features relevant to the arithmetic.
+type CPUInfo struct {
+	// Features lists the detected instruction set extensions.
+	Features []string `json:"features"`
+	// SIMDLevel is the highest SIMD level supported by the CPU.
+	SIMDLevel string `json:"simd_level"`
+	// ActiveImplementation is the SIMD level of the FFT arithmetic in use.
+	ActiveImplementation string `json:"active_implementation"`
+}
+
+// CalibrationIdentity identifies the calibration profile behind the
+// thresholds of a calculation.
+type CalibrationIdentity struct {
+	// Source is CalibrationNone, CalibrationProfile or CalibrationAuto.
+	Source string `json:"source"`
+	// Path is the profile file.
+	Path string `json:"path,omitempty"`
+	// SHA256 is the digest of the profile file.
+	SHA256 string `json:"sha256,omitempty"`
+	// CPUModel and CalibratedAt are read from the profile.
+	CPUModel     string     `json:"cpu_model,omitempty"`
+	CalibratedAt *time.Time `json:"calibrated_at,omitempty"`
+}
+
+// profileIdentity identifies the calibration profile at path (the default
+// profile if empty). The digest and profile fields are left empty if the
+// file cannot be read.
+func profileIdentity(source, path string) CalibrationIdentity {
+	if path == "" {
+		path = calibration.GetDefaultProfilePath()
+	}
+	id := CalibrationIdentity{Source: source, Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return id
+	}
+	sum := sha256.Sum256(data)
+	id.SHA256 = hex.EncodeToString(sum[:])
+	var profile calibration.CalibrationProfile
+	if json.Unmarshal(data, &profile) == nil {
+		id.CPUModel = profile.CPUModel
+		if !profile.CalibratedAt.IsZero() {
+			id.CalibratedAt = &profile.CalibratedAt
+		}
+	}
+	return id
+}
+
+// buildManifest describes a calculation run with the given configuration.
+//
+// Parameters:
+//   - cfg: The configuration of the run, after algorithm selection and
+//     calibration.
+//   - id: The source of the thresholds.
+//
+// Returns:
+//   - Manifest: The manifest of the run.
+func buildManifest(cfg config.AppConfig, id CalibrationIdentity) Manifest {
+	if id.Source == "" {
+		id.Source = CalibrationNone
+	}
+	opts := cfg.ToCalculationOptions().Normalized()
+	m := Manifest{
+		Build:      GetVersionInfo(),
+		CPU:        cpuInfo(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		NumCPU:     runtime.NumCPU(),
+		N:          cfg.N,
+		Strategy:   cfg.Algo,
+		Race:       cfg.Race,
+		Thresholds: ManifestThresholds{
+			Parallel:  opts.ParallelThreshold,
+			FFT:       opts.FFTThreshold,
+			Karatsuba: opts.KaratsubaThreshold,
+			Strassen:  opts.StrassenThreshold,
+			Dynamic:   opts.EnableDynamicThresholds,
+		},
+		Calibration:    id,
+		LowMemory:      opts.LowMemory,
+		IntegrityCheck: opts.IntegrityCheck,
+		Timestamp:      time.Now().UTC(),
+	}
+	if opts.IntegrityCheck {
+		m.IntegrityInterval = max(opts.IntegrityInterval, 1)
+	}
+	return m
+}
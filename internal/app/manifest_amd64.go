@@ -0,0 +1,24 @@
+//go:build amd64
+
+package app
+
+import "github.com/agbru/fibcalc/internal/bigfft"
+
+// cpuInfo describes the SIMD features detected by bigfft.
+func cpuInfo() CPUInfo {
+	f := bigfft.GetCPUFeatures()
+	features := []string{}
+	for _, feature := range []struct {
+		name    string
+		present bool
+	}{{"AVX-512", f.AVX512}, {"AVX2", f.AVX2}, {"BMI2", f.BMI2}, {"ADX", f.ADX}} {
+		if feature.present {
+			features = append(features, feature.name)
+		}
+	}
+	return CPUInfo{
+		Features:             features,
+		SIMDLevel:            f.SIMDLevel.String(),
+		ActiveImplementation: bigfft.GetActiveImplementation().String(),
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !amd64
+
+package app
+
+// cpuInfo reports no SIMD features: bigfft has no SIMD dispatch on this
+// architecture.
+func cpuInfo() CPUInfo {
+	return CPUInfo{Features: []string{}, SIMDLevel: "None", ActiveImplementation: "None"}
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// TestJSONManifest verifies that the JSON output carries the versioned
+// manifest of the run and a digest of each result.
+func TestJSONManifest(t *testing.T) {
+	t.Parallel()
+
+	profilePath := filepath.Join(t.TempDir(), "profile.json")
+	profile := []byte(`{"cpu_model": "Test CPU", "calibrated_at": "2026-01-02T03:04:05Z", "optimal_parallel_threshold": 8192}`)
+	if err := os.WriteFile(profilePath, profile, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var outBuf bytes.Buffer
+	app := &Application{
+		Config: config.AppConfig{
+			N:                 10,
+			Algo:              "fast",
+			Timeout:           time.Minute,
+			JSONOutput:        true,
+			Quiet:             true,
+			Threshold:         8192,
+			StrassenThreshold: 1024,
+			IntegrityCheck:    true,
+			IntegrityInterval: 4,
+		},
+		Factory:     createMockFactory(big.NewInt(55), nil),
+		ErrWriter:   &bytes.Buffer{},
+		calibration: profileIdentity(CalibrationProfile, profilePath),
+	}
+	if exitCode := app.Run(context.Background(), &outBuf); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+
+	var report jsonReport
+	if err := json.Unmarshal(outBuf.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, outBuf.String())
+	}
+	if report.SchemaVersion != ManifestSchemaVersion {
+		t.Errorf("schema_version = %d, want %d", report.SchemaVersion, ManifestSchemaVersion)
+	}
+
+	m := report.Manifest
+	if m.Build.Version != Version || m.Build.GoVersion != runtime.Version() {
+		t.Errorf("Unexpected build info %+v", m.Build)
+	}
+	if m.GOMAXPROCS != runtime.GOMAXPROCS(0) || m.N != 10 || m.CPU.SIMDLevel == "" || m.CPU.Features == nil {
+		t.Errorf("Unexpected environment in manifest %+v", m)
+	}
+	if m.Strategy != "fast" {
+		t.Errorf("strategy = %q, want %q", m.Strategy, "fast")
+	}
+	want := ManifestThresholds{
+		Parallel:  8192,
+		FFT:       fibonacci.DefaultFFTThreshold,
+		Karatsuba: fibonacci.DefaultKaratsubaThreshold,
+		Strassen:  1024,
+	}
+	if m.Thresholds != want {
+		t.Errorf("thresholds = %+v, want %+v", m.Thresholds, want)
+	}
+	if !m.IntegrityCheck || m.IntegrityInterval != 4 {
+		t.Errorf("Unexpected integrity settings %v %d", m.IntegrityCheck, m.IntegrityInterval)
+	}
+
+	id := m.Calibration
+	if id.Source != CalibrationProfile || id.Path != profilePath || len(id.SHA256) != 64 || id.CPUModel != "Test CPU" ||
+		id.CalibratedAt == nil || id.CalibratedAt.Year() != 2026 {
+		t.Errorf("Unexpected calibration identity %+v", id)
+	}
+
+	// SHA-256 of the big-endian magnitude of 55
+	const digest55 = "7902699be42c8a8e46fbbb4501726517e86b22c56a189f7625a6da49081b2451"
+	if len(report.Results) != 1 || report.Results[0].SHA256 != digest55 || report.Results[0].Bits != 6 {
+		t.Errorf("Unexpected results %+v", report.Results)
+	}
+}
+
+// TestProfileIdentity_Missing verifies that a missing profile is identified
+// by its path only.
+func TestProfileIdentity_Missing(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "missing.json")
+	if id := profileIdentity(CalibrationAuto, path); id.Source != CalibrationAuto || id.Path != path || id.SHA256 != "" || id.CalibratedAt != nil {
+		t.Errorf("Unexpected identity %+v", id)
+	}
+	if id := buildManifest(config.AppConfig{}, CalibrationIdentity{}).Calibration; id.Source != CalibrationNone {
+		t.Errorf("Expected source %q without a profile, got %+v", CalibrationNone, id)
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/session"
+)
+
+// runReplay replays the session of --replay through the progress reporter
+// of the command line, and presents the recorded results as the run did.
+func (a *Application) runReplay(ctx context.Context, out io.Writer) int {
+	s, err := session.LoadFile(a.Config.Replay)
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Error: %v\n", err)
+		return apperrors.ExitErrorConfig
+	}
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	// The results are those of the recorded run
+	a.Config.N, a.Config.Race = s.Header.N, s.Header.Race
+	runCfg := a.Config
+	if !runCfg.JSONOutput && !runCfg.Quiet {
+		h := s.Header
+		fmt.Fprintf(out, "Replaying %s: F(%d) with %s, recorded with %s on %s (GOMAXPROCS=%d), %s.\n",
+			runCfg.Replay, h.N, strings.Join(h.Algorithms, ", "), h.GoVersion, h.Platform, h.GOMAXPROCS,
+			replaySpeed(runCfg.ReplaySpeed))
+	}
+
+	reporter, progressOut, closeProgress := a.progressOutput(s.Header.Algorithms, out)
+	results, err := session.Replay(ctx, s, runCfg.ReplaySpeed, reporter, progressOut)
+	closeProgress()
+	if err != nil {
+		fmt.Fprintf(a.ErrWriter, "Replay canceled: %v\n", err)
+		return apperrors.ExitErrorCanceled
+	}
+	if !s.Complete {
+		fmt.Fprintf(out, "\nEnd of the session: the recorded run was interrupted before its results.\n")
+		return apperrors.ExitSuccess
+	}
+	for _, r := range s.Results {
+		if r != nil && r.Error == "" && r.Value == nil {
+			return presentReplaySummary(s, out)
+		}
+	}
+	return a.presentResults(runCfg, results, nil, out)
+}
+
+// replaySpeed describes the speed factor of a replay.
+func replaySpeed(speed float64) string {
+	switch speed {
+	case 0:
+		return "without delays"
+	case 1:
+		return "in real time"
+	default:
+		return fmt.Sprintf("at %g× speed", speed)
+	}
+}
+
+// presentReplaySummary presents recorded results whose value is too large
+// to be in the session, as a comparison run does: the comparison table, the
+// consistency of the algorithms, checked by digest, and the size and digest
+// of the result.
+func presentReplaySummary(s *session.Session, out io.Writer) int {
+	cfg := config.AppConfig{N: s.Header.N}
+	return orchestration.AnalyzeComparisonResults(s.CalculationResults(), cfg, replayPresenter{session: s}, out)
+}
+
+// replayPresenter is the presenter of the command line for recorded results,
+// which presents a result without its value by its size and digest.
+type replayPresenter struct {
+	cli.CLIResultPresenter
+	session *session.Session
+}
+
+// PresentResult implements orchestration.ResultPresenter.
+func (p replayPresenter) PresentResult(result orchestration.CalculationResult, n uint64, verbose, details, concise bool, out io.Writer) {
+	if result.Result != nil {
+		p.CLIResultPresenter.PresentResult(result, n, verbose, details, concise, out)
+		return
+	}
+	bits := 0
+	for _, r := range p.session.Results {
+		if r != nil && r.Error == "" && r.SHA256 == result.Digest {
+			bits = r.Bits
+			break
+		}
+	}
+	fmt.Fprintf(out, "F(%d) has %d bits, SHA-256 %s (values over %d bits are not recorded).\n",
+		n, bits, result.Digest, session.MaxValueBits)
+}
+
+// Verify interface compliance
+var _ orchestration.ResultPresenter = replayPresenter{}
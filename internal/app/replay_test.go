@@ -0,0 +1,108 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/session"
+)
+
+// replay replays the session at path without delays.
+func replay(t *testing.T, path string) (int, string) {
+	t.Helper()
+	app := &Application{
+		Config:    config.AppConfig{Algo: "all", Timeout: time.Minute, Replay: path, NoColor: true},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+	var out bytes.Buffer
+	return app.Run(context.Background(), &out), out.String()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	app := &Application{
+		Config:    config.AppConfig{N: 1000, Algo: "all", Timeout: time.Minute, Quiet: true, Record: path},
+		Factory:   createMockFactory(big.NewInt(42), nil),
+		ErrWriter: &bytes.Buffer{},
+	}
+	if exitCode := app.Run(context.Background(), &bytes.Buffer{}); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d", apperrors.ExitSuccess, exitCode)
+	}
+
+	s, err := session.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Complete || s.Header.N != 1000 || len(s.Header.Algorithms) != 3 || len(s.Events) == 0 {
+		t.Fatalf("Unexpected session %+v", s)
+	}
+
+	exitCode, out := replay(t, path)
+	if exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d:\n%s", apperrors.ExitSuccess, exitCode, out)
+	}
+	for _, want := range []string{"Replaying " + path + ": F(1000)", "without delays", "Comparison Summary", "Global Status: Success"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestReplay_Summary(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	large := new(big.Int).Lsh(big.NewInt(1), session.MaxValueBits)
+
+	write := func(name string, results []orchestration.CalculationResult) string {
+		var buf bytes.Buffer
+		r := session.NewRecorder(&buf, session.Header{N: 5_000_000, Algorithms: []string{"fast", "matrix"}})
+		if results != nil {
+			if err := r.Finish(results); err != nil {
+				t.Fatal(err)
+			}
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// Results too large to be recorded are checked by digest
+	exitCode, out := replay(t, write("large.jsonl", []orchestration.CalculationResult{
+		{Name: "fast", Result: large, Duration: time.Second},
+		{Name: "matrix", Result: large, Duration: 2 * time.Second},
+	}))
+	if exitCode != apperrors.ExitSuccess || !strings.Contains(out, "F(5000000) has 1048577 bits, SHA-256 ") {
+		t.Errorf("Unexpected replay (exit code %d):\n%s", exitCode, out)
+	}
+
+	exitCode, out = replay(t, write("mismatch.jsonl", []orchestration.CalculationResult{
+		{Name: "fast", Result: large, Duration: time.Second},
+		{Name: "matrix", Result: new(big.Int).Add(large, big.NewInt(1)), Duration: 2 * time.Second},
+	}))
+	if exitCode != apperrors.ExitErrorMismatch {
+		t.Errorf("Expected a mismatch, got exit code %d:\n%s", exitCode, out)
+	}
+
+	// The session of an interrupted run ends without results
+	exitCode, out = replay(t, write("interrupted.jsonl", nil))
+	if exitCode != apperrors.ExitSuccess || !strings.Contains(out, "interrupted before its results") {
+		t.Errorf("Unexpected replay (exit code %d):\n%s", exitCode, out)
+	}
+
+	if exitCode, _ = replay(t, filepath.Join(dir, "missing.jsonl")); exitCode != apperrors.ExitErrorConfig {
+		t.Errorf("Expected a configuration error for a missing session, got %d", exitCode)
+	}
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/report"
+)
+
+// reportMetadata describes a run for the HTML report, from its manifest and
+// calibration profile.
+func reportMetadata(cfg config.AppConfig, id CalibrationIdentity) report.Metadata {
+	m := buildManifest(cfg, id)
+	meta := report.Metadata{
+		N:        m.N,
+		Strategy: m.Strategy,
+		Race:     m.Race,
+		Thresholds: report.Thresholds{
+			Parallel:  m.Thresholds.Parallel,
+			FFT:       m.Thresholds.FFT,
+			Karatsuba: m.Thresholds.Karatsuba,
+			Strassen:  m.Thresholds.Strassen,
+			Dynamic:   m.Thresholds.Dynamic,
+		},
+		LowMemory:      m.LowMemory,
+		IntegrityCheck: m.IntegrityCheck,
+		Machine: report.Machine{
+			Version:     m.Build.Version,
+			GoVersion:   m.Build.GoVersion,
+			GOOS:        m.Build.OS,
+			GOARCH:      m.Build.Arch,
+			NumCPU:      m.NumCPU,
+			GOMAXPROCS:  m.GOMAXPROCS,
+			CPUFeatures: m.CPU.Features,
+			SIMD:        m.CPU.ActiveImplementation,
+		},
+		Generated: m.Timestamp,
+	}
+	if id.Path == "" {
+		return meta
+	}
+	if profile, err := calibration.LoadProfile(id.Path); err == nil {
+		meta.Profile = &report.Profile{
+			Path:         id.Path,
+			Source:       m.Calibration.Source,
+			CPUModel:     profile.CPUModel,
+			NumCPU:       profile.NumCPU,
+			CalibratedAt: profile.CalibratedAt,
+			CalibrationN: profile.CalibrationN,
+			Parallel:     profile.OptimalParallelThreshold,
+			FFT:          profile.OptimalFFTThreshold,
+			Strassen:     profile.OptimalStrassenThreshold,
+		}
+	}
+	return meta
+}
+
+// writeReport writes the HTML report of a run to runCfg.Report. The results
+// are analyzed by the report presenter as they are by the CLI presenter.
+//
+// Parameters:
+//   - runCfg: The configuration the calculations ran with.
+//   - calculators: The calculators of the run, by calculator index.
+//   - results: The results of the run.
+//   - recorder: The progress recorder of the run.
+//
+// Returns:
+//   - error: An error if the report cannot be written.
+func (a *Application) writeReport(runCfg config.AppConfig, calculators []fibonacci.Calculator, results []orchestration.CalculationResult, recorder *report.ProgressRecorder) error {
+	names := make([]string, len(calculators))
+	for i, calc := range calculators {
+		names[i] = calc.Name()
+	}
+	presenter := report.NewHTMLPresenter(reportMetadata(runCfg, a.calibration), names, recorder)
+
+	// The analysis sorts the results, which the caller still owns.
+	results = append([]orchestration.CalculationResult(nil), results...)
+	if runCfg.Race {
+		orchestration.AnalyzeRaceResults(results, runCfg, presenter, io.Discard)
+	} else {
+		orchestration.AnalyzeComparisonResults(results, runCfg, presenter, io.Discard)
+	}
+	if err := presenter.WriteFile(runCfg.Report); err != nil {
+		return fmt.Errorf("cannot write the report: %w", err)
+	}
+	return nil
+}
+
+// reportExitCode reports a failure to write the report on errOut, and
+// returns the exit code of the run: a successful run fails if its report
+// cannot be written.
+func reportExitCode(exitCode int, err error, errOut io.Writer) int {
+	if err == nil {
+		return exitCode
+	}
+	fmt.Fprintf(errOut, "Error: %v\n", err)
+	if exitCode == apperrors.ExitSuccess {
+		return apperrors.ExitErrorGeneric
+	}
+	return exitCode
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+// TestReport verifies that --report writes an HTML report of the run, and
+// that a report that cannot be written fails the run.
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	profilePath := filepath.Join(dir, "profile.json")
+	profile := []byte(`{"cpu_model": "Report CPU", "num_cpu": 8, "calibrated_at": "2026-01-02T03:04:05Z", "optimal_parallel_threshold": 8192}`)
+	if err := os.WriteFile(profilePath, profile, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(reportPath string) (int, string) {
+		var errBuf bytes.Buffer
+		app := &Application{
+			Config: config.AppConfig{
+				N:         10,
+				Algo:      "all",
+				Timeout:   time.Minute,
+				Quiet:     true,
+				Threshold: 8192,
+				Report:    reportPath,
+			},
+			Factory:     createMockFactory(big.NewInt(55), nil),
+			ErrWriter:   &errBuf,
+			calibration: profileIdentity(CalibrationProfile, profilePath),
+		}
+		return app.Run(context.Background(), &bytes.Buffer{}), errBuf.String()
+	}
+
+	reportPath := filepath.Join(dir, "reports", "run.html")
+	if exitCode, stderr := run(reportPath); exitCode != apperrors.ExitSuccess {
+		t.Fatalf("Expected exit code %d, got %d: %s", apperrors.ExitSuccess, exitCode, stderr)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Report not written: %v", err)
+	}
+	html := string(data)
+	for _, want := range []string{"<!DOCTYPE html>", "F(10)", "8192 bits", "Report CPU", "status success"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Report does not contain %q", want)
+		}
+	}
+
+	// The parent of the report is a file
+	if exitCode, stderr := run(filepath.Join(profilePath, "run.html")); exitCode != apperrors.ExitErrorGeneric || !strings.Contains(stderr, "report") {
+		t.Errorf("Expected exit code %d with an error, got %d: %q", apperrors.ExitErrorGeneric, exitCode, stderr)
+	}
+}
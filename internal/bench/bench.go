@@ -0,0 +1,191 @@
+// Package bench implements the statistical benchmark mode (--bench).
+//
+// Unlike --algo all, which runs the calculators concurrently so that they
+// compete for cores, the benchmark runs each calculator sequentially, after
+// warm-up runs and a garbage collection, optionally under several GOMAXPROCS
+// limits. It reports the distribution of the measured durations, exports the
+// report as JSON or CSV and compares it against a saved baseline to flag
+// regressions.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// DefaultRuns is the number of measured repetitions used when Options.Runs
+// is not positive.
+const DefaultRuns = 5
+
+// Options configures a benchmark.
+type Options struct {
+	// N is the index of the Fibonacci number to compute.
+	N uint64
+	// Runs is the number of measured repetitions per calculator.
+	Runs int
+	// Warmup is the number of unmeasured runs before the measurements.
+	Warmup int
+	// Procs lists the GOMAXPROCS values to benchmark under. Empty means the
+	// current setting only.
+	Procs []int
+	// Timeout bounds each run; zero means no limit.
+	Timeout time.Duration
+	// Calc holds the calculation options (thresholds, low-memory mode).
+	Calc fibonacci.Options
+}
+
+// Result holds the measurements of one calculator under one GOMAXPROCS value.
+type Result struct {
+	// Algorithm is the calculator name.
+	Algorithm string `json:"algorithm"`
+	// Procs is the GOMAXPROCS value in effect.
+	Procs int `json:"procs"`
+	// Durations are the measured durations, in run order.
+	Durations []time.Duration `json:"durations_ns"`
+	// Stats summarizes Durations.
+	Stats Stats `json:"stats"`
+	// Error is the error that interrupted the measurements, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the outcome of a benchmark, together with the environment it
+// was measured in.
+type Report struct {
+	// N is the index of the computed Fibonacci number.
+	N uint64 `json:"n"`
+	// Runs is the number of measured repetitions.
+	Runs int `json:"runs"`
+	// Warmup is the number of warm-up runs.
+	Warmup int `json:"warmup"`
+	// GoVersion, GOOS, GOARCH and NumCPU describe the environment.
+	GoVersion string `json:"go_version"`
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+	NumCPU    int    `json:"num_cpu"`
+	// Timestamp is the start time of the benchmark.
+	Timestamp time.Time `json:"timestamp"`
+	// Results holds one entry per calculator and GOMAXPROCS value.
+	Results []Result `json:"results"`
+}
+
+// ParseProcs parses a comma-separated list of GOMAXPROCS values such as
+// "1,4,8". An empty string yields an empty list.
+//
+// Parameters:
+//   - s: The list to parse.
+//
+// Returns:
+//   - []int: The parsed values.
+//   - error: An error if a value is not a positive integer.
+func ParseProcs(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	var procs []int
+	for _, field := range strings.Split(s, ",") {
+		p, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || p <= 0 {
+			return nil, fmt.Errorf("invalid GOMAXPROCS value %q: must be a positive integer", field)
+		}
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
+
+// Run benchmarks the calculators one after the other. For each GOMAXPROCS
+// value and each calculator it performs the warm-up runs, then the measured
+// runs, each preceded by a garbage collection so that the garbage of a run
+// is not collected during the next. GOMAXPROCS is restored on return.
+//
+// Parameters:
+//   - ctx: The context for cancellation.
+//   - calculators: The calculators to benchmark.
+//   - opts: The benchmark options.
+//
+// Returns:
+//   - *Report: The benchmark report; calculator failures are recorded in
+//     the corresponding Result.
+//   - error: The context error if the benchmark was interrupted.
+func Run(ctx context.Context, calculators []fibonacci.Calculator, opts Options) (*Report, error) {
+	if opts.Runs <= 0 {
+		opts.Runs = DefaultRuns
+	}
+	if opts.Warmup < 0 {
+		opts.Warmup = 0
+	}
+	procs := opts.Procs
+	if len(procs) == 0 {
+		procs = []int{runtime.GOMAXPROCS(0)}
+	}
+
+	report := &Report{
+		N:         opts.N,
+		Runs:      opts.Runs,
+		Warmup:    opts.Warmup,
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Timestamp: time.Now(),
+	}
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, p := range procs {
+		runtime.GOMAXPROCS(p)
+		for _, calc := range calculators {
+			res := measure(ctx, calc, p, opts)
+			report.Results = append(report.Results, res)
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// measure performs the warm-up and measured runs of one calculator.
+func measure(ctx context.Context, calc fibonacci.Calculator, procs int, opts Options) Result {
+	res := Result{Algorithm: calc.Name(), Procs: procs}
+
+	for i := 0; i < opts.Warmup; i++ {
+		if _, err := runOnce(ctx, calc, opts); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+	}
+	for i := 0; i < opts.Runs; i++ {
+		d, err := runOnce(ctx, calc, opts)
+		if err != nil {
+			res.Error = err.Error()
+			break
+		}
+		res.Durations = append(res.Durations, d)
+	}
+	res.Stats = ComputeStats(res.Durations)
+	return res
+}
+
+// runOnce collects the garbage, then times a single calculation.
+func runOnce(ctx context.Context, calc fibonacci.Calculator, opts Options) (time.Duration, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	runtime.GC()
+	start := time.Now()
+	result, err := calc.Calculate(ctx, nil, 0, opts.N, opts.Calc)
+	d := time.Since(start)
+	if err == nil && result == nil {
+		err = errors.New("calculator returned no result")
+	}
+	return d, err
+}
@@ -0,0 +1,205 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// stubCalculator counts its calls and records the GOMAXPROCS in effect.
+type stubCalculator struct {
+	name  string
+	calls int
+	procs []int
+	err   error
+}
+
+func (c *stubCalculator) Name() string { return c.name }
+
+func (c *stubCalculator) Calculate(_ context.Context, _ chan<- fibonacci.ProgressUpdate, _ int, n uint64, _ fibonacci.Options) (*big.Int, error) {
+	c.calls++
+	c.procs = append(c.procs, runtime.GOMAXPROCS(0))
+	if c.err != nil {
+		return nil, c.err
+	}
+	return new(big.Int).SetUint64(n), nil
+}
+
+func TestComputeStats(t *testing.T) {
+	t.Parallel()
+
+	if s := ComputeStats(nil); s.Count != 0 {
+		t.Errorf("empty series should give zero stats, got %+v", s)
+	}
+
+	ms := time.Millisecond
+	s := ComputeStats([]time.Duration{4 * ms, 2 * ms, 6 * ms, 8 * ms})
+	if s.Count != 4 || s.Min != 2*ms || s.Max != 8*ms || s.Median != 5*ms || s.Mean != 5*ms {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+	// Sample standard deviation of {2,4,6,8} is sqrt(20/3) ≈ 2.582ms
+	if s.StdDev < 2581*time.Microsecond || s.StdDev > 2583*time.Microsecond {
+		t.Errorf("StdDev = %v, want ~2.582ms", s.StdDev)
+	}
+	// Margin = t(3) * sd / sqrt(4) = 3.182 * 2.582 / 2 ≈ 4.108ms
+	if margin := s.Mean - s.CILow; margin < 4100*time.Microsecond || margin > 4115*time.Microsecond {
+		t.Errorf("CI margin = %v, want ~4.108ms", margin)
+	}
+	if diff := (s.CIHigh - s.Mean) - (s.Mean - s.CILow); diff < -1 || diff > 1 {
+		t.Error("confidence interval should be symmetric around the mean")
+	}
+
+	single := ComputeStats([]time.Duration{3 * ms})
+	if single.Median != 3*ms || single.StdDev != 0 || single.CILow != 3*ms || single.CIHigh != 3*ms {
+		t.Errorf("unexpected single-run stats: %+v", single)
+	}
+}
+
+func TestParseProcs(t *testing.T) {
+	t.Parallel()
+
+	procs, err := ParseProcs(" 1, 4,8 ")
+	if err != nil || len(procs) != 3 || procs[0] != 1 || procs[1] != 4 || procs[2] != 8 {
+		t.Errorf("ParseProcs = %v, %v", procs, err)
+	}
+	if procs, err := ParseProcs(""); err != nil || procs != nil {
+		t.Errorf("empty list = %v, %v", procs, err)
+	}
+	for _, bad := range []string{"0", "a", "2,-1"} {
+		if _, err := ParseProcs(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+// TestRun verifies warm-up, repetitions and GOMAXPROCS handling. It is not
+// parallel because it changes GOMAXPROCS.
+func TestRun(t *testing.T) {
+	prev := runtime.GOMAXPROCS(0)
+	fast := &stubCalculator{name: "fast"}
+	broken := &stubCalculator{name: "broken", err: errors.New("boom")}
+
+	report, err := Run(context.Background(), []fibonacci.Calculator{fast, broken}, Options{
+		N: 10, Runs: 3, Warmup: 2, Procs: []int{1, 2},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if runtime.GOMAXPROCS(0) != prev {
+		t.Errorf("GOMAXPROCS not restored: %d, want %d", runtime.GOMAXPROCS(0), prev)
+	}
+	if fast.calls != 2*(2+3) {
+		t.Errorf("expected 10 calls (2 procs × (2 warm-up + 3 runs)), got %d", fast.calls)
+	}
+	if fast.procs[0] != 1 || fast.procs[len(fast.procs)-1] != 2 {
+		t.Errorf("unexpected GOMAXPROCS sequence: %v", fast.procs)
+	}
+	if len(report.Results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(report.Results))
+	}
+	if r := report.Results[0]; r.Algorithm != "fast" || r.Procs != 1 || r.Stats.Count != 3 || len(r.Durations) != 3 {
+		t.Errorf("unexpected first result: %+v", r)
+	}
+	if r := report.Results[1]; r.Error != "boom" || r.Stats.Count != 0 {
+		t.Errorf("failure should be recorded: %+v", r)
+	}
+	if broken.calls != 2 {
+		t.Errorf("a failing warm-up should stop the calculator, got %d calls", broken.calls)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calc := &stubCalculator{name: "fast"}
+	if _, err := Run(ctx, []fibonacci.Calculator{calc, calc}, Options{N: 1, Runs: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	ms := time.Millisecond
+	result := func(algo string, procs int, d ...time.Duration) Result {
+		return Result{Algorithm: algo, Procs: procs, Durations: d, Stats: ComputeStats(d)}
+	}
+	baseline := &Report{Results: []Result{
+		result("fast", 1, 10*ms, 10*ms, 10*ms),
+		result("matrix", 1, 20*ms, 21*ms, 19*ms),
+	}}
+	current := &Report{Results: []Result{
+		result("fast", 1, 15*ms, 15*ms, 15*ms),       // +50%, disjoint intervals
+		result("matrix", 1, 20*ms, 21*ms, 20*ms),     // unchanged
+		result("fft", 1, 5*ms),                       // no baseline
+		{Algorithm: "fast", Procs: 4, Error: "boom"}, // failed
+	}}
+
+	comparisons := Compare(baseline, current, 5)
+	if len(comparisons) != 2 {
+		t.Fatalf("expected 2 comparisons, got %+v", comparisons)
+	}
+	if c := comparisons[0]; !c.Regression || c.Change < 49.9 || c.Change > 50.1 {
+		t.Errorf("fast should regress by 50%%: %+v", c)
+	}
+	if comparisons[1].Regression {
+		t.Errorf("matrix should not regress: %+v", comparisons[1])
+	}
+	if !HasRegression(comparisons) || HasRegression(comparisons[1:]) {
+		t.Error("HasRegression mismatch")
+	}
+	if Compare(baseline, current, 60)[0].Regression {
+		t.Error("a slowdown within the tolerance is not a regression")
+	}
+}
+
+func TestReportExport(t *testing.T) {
+	t.Parallel()
+
+	d := []time.Duration{time.Millisecond, 3 * time.Millisecond}
+	report := &Report{N: 42, Runs: 2, Results: []Result{
+		{Algorithm: "fast", Procs: 2, Durations: d, Stats: ComputeStats(d)},
+	}}
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "bench.json")
+	if err := SaveReport(report, jsonPath); err != nil {
+		t.Fatalf("SaveReport(json) failed: %v", err)
+	}
+	loaded, err := LoadReport(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadReport failed: %v", err)
+	}
+	if loaded.N != 42 || len(loaded.Results) != 1 || loaded.Results[0].Stats != report.Results[0].Stats {
+		t.Errorf("round trip mismatch: %+v", loaded)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("invalid CSV (%v): %v", err, rows)
+	}
+	if rows[1][0] != "fast" || rows[1][1] != "2" || rows[1][2] != "42" || rows[1][5] != "2000000" {
+		t.Errorf("unexpected CSV row: %v", rows[1])
+	}
+
+	if FormatForPath("out.CSV") != FormatCSV || FormatForPath("out.json") != FormatJSON {
+		t.Error("FormatForPath mismatch")
+	}
+	if _, err := LoadReport(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing baseline")
+	}
+}
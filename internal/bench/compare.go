@@ -0,0 +1,64 @@
+package bench
+
+// Comparison relates a result to its baseline.
+type Comparison struct {
+	// Algorithm and Procs identify the result.
+	Algorithm string
+	Procs     int
+	// Baseline and Current are the statistics of both runs.
+	Baseline Stats
+	Current  Stats
+	// Change is the relative change of the median, in percent (positive
+	// means slower).
+	Change float64
+	// Regression is true when the median is slower than the baseline by more
+	// than the tolerance and the confidence intervals do not overlap.
+	Regression bool
+}
+
+// Compare matches the results of current with those of baseline (same
+// algorithm and GOMAXPROCS) and flags regressions. Results without a
+// baseline counterpart, or that failed, are skipped.
+//
+// Parameters:
+//   - baseline: The reference report.
+//   - current: The new report.
+//   - tolerance: The accepted slowdown of the median, in percent.
+//
+// Returns:
+//   - []Comparison: One comparison per matched result, in current order.
+func Compare(baseline, current *Report, tolerance float64) []Comparison {
+	type key struct {
+		algo  string
+		procs int
+	}
+	base := make(map[key]Stats, len(baseline.Results))
+	for _, res := range baseline.Results {
+		if res.Error == "" && res.Stats.Count > 0 {
+			base[key{res.Algorithm, res.Procs}] = res.Stats
+		}
+	}
+
+	var comparisons []Comparison
+	for _, res := range current.Results {
+		b, ok := base[key{res.Algorithm, res.Procs}]
+		if !ok || res.Error != "" || res.Stats.Count == 0 || b.Median <= 0 {
+			continue
+		}
+		c := Comparison{Algorithm: res.Algorithm, Procs: res.Procs, Baseline: b, Current: res.Stats}
+		c.Change = (float64(res.Stats.Median) - float64(b.Median)) / float64(b.Median) * 100
+		c.Regression = c.Change > tolerance && res.Stats.CILow > b.CIHigh
+		comparisons = append(comparisons, c)
+	}
+	return comparisons
+}
+
+// HasRegression reports whether any comparison is a regression.
+func HasRegression(comparisons []Comparison) bool {
+	for _, c := range comparisons {
+		if c.Regression {
+			return true
+		}
+	}
+	return false
+}
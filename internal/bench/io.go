@@ -0,0 +1,132 @@
+package bench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Export formats.
+const (
+	// FormatJSON exports the full report as an indented JSON document.
+	FormatJSON = "json"
+	// FormatCSV exports one row per result with the summary statistics.
+	FormatCSV = "csv"
+)
+
+// csvHeader is the header row of the CSV export. Durations are in
+// nanoseconds.
+var csvHeader = []string{
+	"algorithm", "procs", "n", "runs", "min_ns", "median_ns", "mean_ns",
+	"max_ns", "stddev_ns", "ci95_low_ns", "ci95_high_ns", "error",
+}
+
+// FormatForPath returns the export format implied by the extension of path:
+// CSV for ".csv", JSON otherwise.
+func FormatForPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return FormatCSV
+	}
+	return FormatJSON
+}
+
+// WriteJSON writes the report as an indented JSON document.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if encoding fails.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one CSV row per result with the summary statistics.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if writing fails.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, res := range r.Results {
+		s := res.Stats
+		row := []string{
+			res.Algorithm,
+			strconv.Itoa(res.Procs),
+			strconv.FormatUint(r.N, 10),
+			strconv.Itoa(s.Count),
+			strconv.FormatInt(int64(s.Min), 10),
+			strconv.FormatInt(int64(s.Median), 10),
+			strconv.FormatInt(int64(s.Mean), 10),
+			strconv.FormatInt(int64(s.Max), 10),
+			strconv.FormatInt(int64(s.StdDev), 10),
+			strconv.FormatInt(int64(s.CILow), 10),
+			strconv.FormatInt(int64(s.CIHigh), 10),
+			res.Error,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SaveReport writes the report to path, as CSV or JSON depending on the
+// file extension (see FormatForPath).
+//
+// Parameters:
+//   - r: The report to save.
+//   - path: The destination file.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func SaveReport(r *Report, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create benchmark report: %w", err)
+	}
+	if FormatForPath(path) == FormatCSV {
+		err = r.WriteCSV(f)
+	} else {
+		err = r.WriteJSON(f)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("cannot write benchmark report: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadReport reads a JSON report previously written by SaveReport, for use
+// as a baseline.
+//
+// Parameters:
+//   - path: The report file.
+//
+// Returns:
+//   - *Report: The loaded report.
+//   - error: An error if the file cannot be read or is not a JSON report.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read baseline: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s (a JSON benchmark report is expected): %w", path, err)
+	}
+	return &r, nil
+}
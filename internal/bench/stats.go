@@ -0,0 +1,87 @@
+package bench
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Stats summarizes a series of measured durations.
+type Stats struct {
+	// Count is the number of measurements.
+	Count int `json:"count"`
+	// Min, Max, Median and Mean describe the distribution.
+	Min    time.Duration `json:"min_ns"`
+	Max    time.Duration `json:"max_ns"`
+	Median time.Duration `json:"median_ns"`
+	Mean   time.Duration `json:"mean_ns"`
+	// StdDev is the sample standard deviation.
+	StdDev time.Duration `json:"stddev_ns"`
+	// CILow and CIHigh bound the 95% confidence interval of the mean
+	// (Student's t distribution).
+	CILow  time.Duration `json:"ci95_low_ns"`
+	CIHigh time.Duration `json:"ci95_high_ns"`
+}
+
+// tCritical95 holds the two-sided 95% critical values of Student's t
+// distribution for 1 to 30 degrees of freedom.
+var tCritical95 = [...]float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// tValue95 returns the two-sided 95% critical value of Student's t
+// distribution, using the normal approximation beyond 30 degrees of freedom.
+func tValue95(df int) float64 {
+	if df >= 1 && df <= len(tCritical95) {
+		return tCritical95[df-1]
+	}
+	return 1.960
+}
+
+// ComputeStats computes the summary statistics of the given durations. With
+// fewer than two measurements the standard deviation is zero and the
+// confidence interval collapses to the mean.
+//
+// Parameters:
+//   - durations: The measured durations.
+//
+// Returns:
+//   - Stats: The summary statistics (zero for an empty series).
+func ComputeStats(durations []time.Duration) Stats {
+	n := len(durations)
+	if n == 0 {
+		return Stats{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	s := Stats{Count: n, Min: sorted[0], Max: sorted[n-1]}
+	if n%2 == 1 {
+		s.Median = sorted[n/2]
+	} else {
+		s.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	var sum float64
+	for _, d := range sorted {
+		sum += float64(d)
+	}
+	mean := sum / float64(n)
+	s.Mean = time.Duration(mean)
+
+	var margin float64
+	if n > 1 {
+		var sq float64
+		for _, d := range sorted {
+			sq += (float64(d) - mean) * (float64(d) - mean)
+		}
+		stddev := math.Sqrt(sq / float64(n-1))
+		s.StdDev = time.Duration(stddev)
+		margin = tValue95(n-1) * stddev / math.Sqrt(float64(n))
+	}
+	s.CILow = time.Duration(mean - margin)
+	s.CIHigh = time.Duration(mean + margin)
+	return s
+}
@@ -84,8 +84,12 @@ func ReleaseBumpAllocator(ba *BumpAllocator) {
 	if ba == nil {
 		return
 	}
-	// Reset offset but keep buffer for reuse
+	// Reset offset but keep buffer for reuse, unless it is oversized
+	// for the low-memory mode
 	ba.offset = 0
+	if LowMemoryMode() && cap(ba.buffer) > LowMemoryBumpRetainWords {
+		ba.buffer = nil
+	}
 	bumpAllocatorPool.Put(ba)
 }
 
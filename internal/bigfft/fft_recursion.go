@@ -74,8 +74,10 @@ func fourierRecursiveUnified(dst, src []fermat, backward bool, n int, k, size, d
 
 	// Try to acquire token for parallelism
 	// We only try to parallelize if the size is large enough to justify overhead
-	// and we haven't exceeded the maximum parallelism depth
-	if size >= ParallelFFTRecursionThreshold && depth < MaxParallelFFTDepth {
+	// and we haven't exceeded the maximum parallelism depth.
+	// Parallel branches need their own temporaries, so the low-memory mode
+	// keeps the recursion sequential.
+	if size >= ParallelFFTRecursionThreshold && depth < MaxParallelFFTDepth && !LowMemoryMode() {
 		select {
 		case getSemaphore() <- struct{}{}:
 			// Got token, run second half in parallel
@@ -0,0 +1,45 @@
+// Package bigfft implements multiplication of big.Int using FFT.
+// This file provides the low-memory execution mode.
+package bigfft
+
+import "sync/atomic"
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Low-Memory Mode
+// ─────────────────────────────────────────────────────────────────────────────
+
+// LowMemoryMaxPooledWords is the largest word or fermat slice retained by the
+// pools while the low-memory mode is active. Larger buffers are left to the GC.
+const LowMemoryMaxPooledWords = 65536 // 512 KB on 64-bit
+
+// LowMemoryBumpRetainWords is the largest bump allocator buffer kept for reuse
+// while the low-memory mode is active.
+const LowMemoryBumpRetainWords = 262144 // 2 MB on 64-bit
+
+// lowMemoryMode is the process-wide low-memory switch.
+var lowMemoryMode atomic.Bool
+
+// SetLowMemoryMode enables or disables the low-memory mode. While enabled,
+// the pools stop retaining large buffers, bump allocators release oversized
+// buffers, pool pre-warming is skipped and FFT recursion runs sequentially.
+// Calculations are slower but keep a smaller working set.
+//
+// Parameters:
+//   - enabled: Whether the low-memory mode is active.
+func SetLowMemoryMode(enabled bool) {
+	lowMemoryMode.Store(enabled)
+}
+
+// LowMemoryMode reports whether the low-memory mode is active.
+//
+// Returns:
+//   - bool: True if the low-memory mode is active.
+func LowMemoryMode() bool {
+	return lowMemoryMode.Load()
+}
+
+// retainPooled reports whether a released buffer of the given capacity
+// (in words) should go back to its pool.
+func retainPooled(capacity int) bool {
+	return !lowMemoryMode.Load() || capacity <= LowMemoryMaxPooledWords
+}
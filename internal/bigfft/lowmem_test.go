@@ -0,0 +1,29 @@
+package bigfft
+
+import "testing"
+
+func TestLowMemoryMode(t *testing.T) {
+	defer SetLowMemoryMode(false)
+
+	SetLowMemoryMode(true)
+	if !LowMemoryMode() {
+		t.Fatal("expected low-memory mode to be active")
+	}
+	if retainPooled(LowMemoryMaxPooledWords + 1) {
+		t.Error("large buffers must not be pooled in low-memory mode")
+	}
+	if !retainPooled(LowMemoryMaxPooledWords) {
+		t.Error("small buffers should still be pooled in low-memory mode")
+	}
+
+	ba := AcquireBumpAllocator(LowMemoryBumpRetainWords + 1)
+	ReleaseBumpAllocator(ba)
+	if ba.buffer != nil {
+		t.Error("oversized bump buffer should be released in low-memory mode")
+	}
+
+	SetLowMemoryMode(false)
+	if !retainPooled(LowMemoryMaxPooledWords + 1) {
+		t.Error("large buffers should be pooled in normal mode")
+	}
+}
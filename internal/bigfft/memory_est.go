@@ -9,10 +9,18 @@ type MemoryEstimate struct {
 	MaxFermatSize      int
 	MaxNatSliceSize    int
 	MaxFermatSliceSize int
+
+	// ResultWords is the estimated size of F(n) in words.
+	ResultWords int
+	// PeakBytes is the estimated peak working set of a sequential
+	// fast doubling calculation of F(n), in bytes. Use EstimatePeakBytes
+	// to account for parallel branches and the transform cache.
+	PeakBytes uint64
 }
 
 // EstimateMemoryNeeds estimates the memory requirements for calculating F(n).
-// This is a heuristic estimation used for pool pre-warming.
+// This is a heuristic estimation used for pool pre-warming and for
+// reporting the expected peak memory of a calculation.
 func EstimateMemoryNeeds(n uint64) MemoryEstimate {
 	// F(n) has approximately n * log10(phi) / log10(2) bits
 	// log2(phi) ≈ 0.69424
@@ -72,5 +80,57 @@ func EstimateMemoryNeeds(n uint64) MemoryEstimate {
 		MaxFermatSize:      maxFermat,
 		MaxNatSliceSize:    maxNatSlice,
 		MaxFermatSliceSize: maxFermatSlice,
+		ResultWords:        wordLen,
+		PeakBytes:          EstimatePeakBytes(n, DefaultPeakMemoryOptions()),
+	}
+}
+
+// PeakMemoryOptions describes the execution features that affect the peak
+// working set of a calculation.
+type PeakMemoryOptions struct {
+	// StateOperands is the number of full-size integers held by the
+	// algorithm state at the last step (7 for fast doubling, counting the
+	// returned copy of the result).
+	StateOperands int
+	// ParallelBranches is the number of multiplications that may run
+	// concurrently, each with its own FFT buffers (1 when sequential).
+	ParallelBranches int
+	// CacheEntries is the capacity of the FFT transform cache (0 if disabled).
+	CacheEntries int
+}
+
+// DefaultPeakMemoryOptions returns the options matching a sequential fast
+// doubling calculation without transform caching.
+func DefaultPeakMemoryOptions() PeakMemoryOptions {
+	return PeakMemoryOptions{StateOperands: 7, ParallelBranches: 1}
+}
+
+// EstimatePeakBytes estimates the peak working set of a calculation of F(n).
+//
+// The last step dominates: the state holds StateOperands integers of up to
+// the result size, each concurrent multiplication of the half-size operands
+// needs bump allocator space for its forward transforms and pointwise
+// products, and the transform cache retains up to three transforms per step
+// (the earlier, smaller steps add at most as much again).
+//
+// Parameters:
+//   - n: The Fibonacci index.
+//   - opts: The execution features of the calculation.
+//
+// Returns:
+//   - uint64: The estimated peak in bytes.
+func EstimatePeakBytes(n uint64, opts PeakMemoryOptions) uint64 {
+	wordLen := int((uint64(float64(n)*0.69424) + 63) / 64)
+	if wordLen <= 0 {
+		return 0
+	}
+	branches := max(opts.ParallelBranches, 1)
+	halfLen := (wordLen + 1) / 2
+	fftWords := EstimateBumpCapacity(halfLen)
+
+	words := opts.StateOperands*wordLen + branches*2*fftWords
+	if opts.CacheEntries > 0 {
+		words += 2 * min(opts.CacheEntries, 3) * fftWords / 2
 	}
+	return uint64(words) * uint64(_W/8)
 }
@@ -76,3 +76,49 @@ func TestEstimateMemoryNeeds(t *testing.T) {
 		})
 	}
 }
+
+func TestEstimateMemoryNeedsPeakBytes(t *testing.T) {
+	t.Parallel()
+	if got := EstimateMemoryNeeds(0).PeakBytes; got != 0 {
+		t.Errorf("PeakBytes(0) = %d, want 0", got)
+	}
+	prev := uint64(0)
+	for _, n := range []uint64{1_000, 100_000, 10_000_000, 100_000_000} {
+		est := EstimateMemoryNeeds(n)
+		resultBytes := uint64(est.ResultWords) * 8
+		if est.PeakBytes < 7*resultBytes {
+			t.Errorf("n=%d: PeakBytes = %d, want at least 7x the result size (%d)", n, est.PeakBytes, resultBytes)
+		}
+		if est.PeakBytes <= prev {
+			t.Errorf("n=%d: PeakBytes = %d must grow with n (prev %d)", n, est.PeakBytes, prev)
+		}
+		prev = est.PeakBytes
+	}
+}
+
+func TestEstimatePeakBytes(t *testing.T) {
+	t.Parallel()
+	const n = 10_000_000
+	base := EstimatePeakBytes(n, DefaultPeakMemoryOptions())
+	if base != EstimateMemoryNeeds(n).PeakBytes {
+		t.Errorf("default options should match EstimateMemoryNeeds: %d vs %d", base, EstimateMemoryNeeds(n).PeakBytes)
+	}
+
+	parallel := DefaultPeakMemoryOptions()
+	parallel.ParallelBranches = 3
+	if got := EstimatePeakBytes(n, parallel); got <= base {
+		t.Errorf("parallel branches should increase the estimate: %d <= %d", got, base)
+	}
+
+	cached := DefaultPeakMemoryOptions()
+	cached.CacheEntries = 128
+	if got := EstimatePeakBytes(n, cached); got <= base {
+		t.Errorf("the transform cache should increase the estimate: %d <= %d", got, base)
+	}
+
+	lean := DefaultPeakMemoryOptions()
+	lean.StateOperands = 5
+	if got := EstimatePeakBytes(n, lean); got >= base {
+		t.Errorf("fewer state operands should decrease the estimate: %d >= %d", got, base)
+	}
+}
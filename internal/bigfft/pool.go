@@ -8,6 +8,49 @@ import (
 	"sync/atomic"
 )
 
+// ─────────────────────────────────────────────────────────────────────────────
+// Pool Statistics
+// ─────────────────────────────────────────────────────────────────────────────
+
+// poolGets counts the buffers acquired from the word, fermat, nat and fermat
+// slice pools; poolMisses counts those that had to be allocated, because the
+// pool was empty or the size too large for pooling.
+var poolGets, poolMisses atomic.Uint64
+
+// PoolStats describes how well the buffer pools serve the acquisitions.
+type PoolStats struct {
+	// Gets is the number of buffers acquired.
+	Gets uint64
+	// Misses is the number of acquired buffers that were newly allocated.
+	Misses uint64
+	// HitRate is the fraction of the buffers reused from a pool.
+	HitRate float64
+}
+
+// GetPoolStats returns the cumulative statistics of the buffer pools since
+// the start of the process.
+//
+// Returns:
+//   - PoolStats: The pool statistics.
+func GetPoolStats() PoolStats {
+	gets := poolGets.Load()
+	misses := poolMisses.Load()
+	stats := PoolStats{Gets: gets, Misses: misses}
+	if gets > 0 && misses <= gets {
+		stats.HitRate = float64(gets-misses) / float64(gets)
+	}
+	return stats
+}
+
+// newBuffer returns the New function of a pool of buffers of the given
+// length, which counts the allocation as a miss.
+func newBuffer[S ~[]E, E any](size int) func() any {
+	return func() any {
+		poolMisses.Add(1)
+		return make(S, size)
+	}
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Word Slice Pools
 // ─────────────────────────────────────────────────────────────────────────────
@@ -16,16 +59,16 @@ import (
 // We use size classes to avoid fragmentation: 64, 256, 1K, 4K, 16K, 64K, 256K, 1M, 4M, 16M words.
 // Extended size classes support very large Fibonacci calculations (F > 10M).
 var wordSlicePools = [...]sync.Pool{
-	{New: func() any { return make([]big.Word, 64) }},
-	{New: func() any { return make([]big.Word, 256) }},
-	{New: func() any { return make([]big.Word, 1024) }},
-	{New: func() any { return make([]big.Word, 4096) }},
-	{New: func() any { return make([]big.Word, 16384) }},
-	{New: func() any { return make([]big.Word, 65536) }},
-	{New: func() any { return make([]big.Word, 262144) }},
-	{New: func() any { return make([]big.Word, 1048576) }},  // 1M words = 8MB on 64-bit
-	{New: func() any { return make([]big.Word, 4194304) }},  // 4M words = 32MB on 64-bit
-	{New: func() any { return make([]big.Word, 16777216) }}, // 16M words = 128MB on 64-bit
+	{New: newBuffer[[]big.Word](64)},
+	{New: newBuffer[[]big.Word](256)},
+	{New: newBuffer[[]big.Word](1024)},
+	{New: newBuffer[[]big.Word](4096)},
+	{New: newBuffer[[]big.Word](16384)},
+	{New: newBuffer[[]big.Word](65536)},
+	{New: newBuffer[[]big.Word](262144)},
+	{New: newBuffer[[]big.Word](1048576)},  // 1M words = 8MB on 64-bit
+	{New: newBuffer[[]big.Word](4194304)},  // 4M words = 32MB on 64-bit
+	{New: newBuffer[[]big.Word](16777216)}, // 16M words = 128MB on 64-bit
 }
 
 // wordSliceSizes defines the size classes for word slice pools.
@@ -53,9 +96,11 @@ func getWordSlicePoolIndex(size int) int {
 //
 // This ensures the slice is returned to the pool even if an error occurs.
 func acquireWordSlice(size int) []big.Word {
+	poolGets.Add(1)
 	idx := getWordSlicePoolIndex(size)
 	if idx < 0 {
 		// Too large for pooling, allocate directly
+		poolMisses.Add(1)
 		return make([]big.Word, size)
 	}
 	slice := wordSlicePools[idx].Get().([]big.Word)
@@ -80,7 +125,7 @@ func releaseWordSlice(slice []big.Word) {
 	// Get the original capacity to determine which pool it came from
 	cap := cap(slice)
 	idx := getWordSlicePoolIndex(cap)
-	if idx >= 0 && wordSliceSizes[idx] == cap {
+	if idx >= 0 && wordSliceSizes[idx] == cap && retainPooled(cap) {
 		// Restore full capacity before returning to pool
 		wordSlicePools[idx].Put(slice[:cap])
 	}
@@ -95,15 +140,15 @@ func releaseWordSlice(slice []big.Word) {
 // Fermat numbers are typically n+1 words where n is derived from FFT parameters.
 // Extended size classes support very large FFT operations.
 var fermatPools = [...]sync.Pool{
-	{New: func() any { return make(fermat, 32) }},
-	{New: func() any { return make(fermat, 128) }},
-	{New: func() any { return make(fermat, 512) }},
-	{New: func() any { return make(fermat, 2048) }},
-	{New: func() any { return make(fermat, 8192) }},
-	{New: func() any { return make(fermat, 32768) }},
-	{New: func() any { return make(fermat, 131072) }},  // 128K
-	{New: func() any { return make(fermat, 524288) }},  // 512K
-	{New: func() any { return make(fermat, 2097152) }}, // 2M
+	{New: newBuffer[fermat](32)},
+	{New: newBuffer[fermat](128)},
+	{New: newBuffer[fermat](512)},
+	{New: newBuffer[fermat](2048)},
+	{New: newBuffer[fermat](8192)},
+	{New: newBuffer[fermat](32768)},
+	{New: newBuffer[fermat](131072)},  // 128K
+	{New: newBuffer[fermat](524288)},  // 512K
+	{New: newBuffer[fermat](2097152)}, // 2M
 }
 
 // fermatSizes defines the size classes for fermat pools.
@@ -130,8 +175,10 @@ func getFermatPoolIndex(size int) int {
 //
 // This ensures the slice is returned to the pool even if an error occurs.
 func acquireFermat(size int) fermat {
+	poolGets.Add(1)
 	idx := getFermatPoolIndex(size)
 	if idx < 0 {
+		poolMisses.Add(1)
 		return make(fermat, size)
 	}
 	f := fermatPools[idx].Get().(fermat)
@@ -154,7 +201,7 @@ func releaseFermat(f fermat) {
 	}
 	cap := cap(f)
 	idx := getFermatPoolIndex(cap)
-	if idx >= 0 && fermatSizes[idx] == cap {
+	if idx >= 0 && fermatSizes[idx] == cap && retainPooled(cap) {
 		fermatPools[idx].Put(f[:cap])
 	}
 }
@@ -166,13 +213,13 @@ func releaseFermat(f fermat) {
 // natSlicePool pools []nat slices used for polynomial coefficients.
 // Extended to support larger FFT sizes.
 var natSlicePools = [...]sync.Pool{
-	{New: func() any { return make([]nat, 8) }},
-	{New: func() any { return make([]nat, 32) }},
-	{New: func() any { return make([]nat, 128) }},
-	{New: func() any { return make([]nat, 512) }},
-	{New: func() any { return make([]nat, 2048) }},
-	{New: func() any { return make([]nat, 8192) }},
-	{New: func() any { return make([]nat, 32768) }},
+	{New: newBuffer[[]nat](8)},
+	{New: newBuffer[[]nat](32)},
+	{New: newBuffer[[]nat](128)},
+	{New: newBuffer[[]nat](512)},
+	{New: newBuffer[[]nat](2048)},
+	{New: newBuffer[[]nat](8192)},
+	{New: newBuffer[[]nat](32768)},
 }
 
 // natSliceSizes defines the size classes for nat slice pools.
@@ -197,8 +244,10 @@ func getNatSlicePoolIndex(size int) int {
 //
 // This ensures the slice is returned to the pool even if an error occurs.
 func acquireNatSlice(size int) []nat {
+	poolGets.Add(1)
 	idx := getNatSlicePoolIndex(size)
 	if idx < 0 {
+		poolMisses.Add(1)
 		return make([]nat, size)
 	}
 	slice := natSlicePools[idx].Get().([]nat)
@@ -233,13 +282,13 @@ func releaseNatSlice(slice []nat) {
 // fermatSlicePool pools []fermat slices used for polynomial values.
 // Extended to support larger FFT sizes.
 var fermatSlicePools = [...]sync.Pool{
-	{New: func() any { return make([]fermat, 8) }},
-	{New: func() any { return make([]fermat, 32) }},
-	{New: func() any { return make([]fermat, 128) }},
-	{New: func() any { return make([]fermat, 512) }},
-	{New: func() any { return make([]fermat, 2048) }},
-	{New: func() any { return make([]fermat, 8192) }},
-	{New: func() any { return make([]fermat, 32768) }},
+	{New: newBuffer[[]fermat](8)},
+	{New: newBuffer[[]fermat](32)},
+	{New: newBuffer[[]fermat](128)},
+	{New: newBuffer[[]fermat](512)},
+	{New: newBuffer[[]fermat](2048)},
+	{New: newBuffer[[]fermat](8192)},
+	{New: newBuffer[[]fermat](32768)},
 }
 
 // fermatSliceSizes defines the size classes for []fermat pools.
@@ -264,8 +313,10 @@ func getFermatSlicePoolIndex(size int) int {
 //
 // This ensures the slice is returned to the pool even if an error occurs.
 func acquireFermatSlice(size int) []fermat {
+	poolGets.Add(1)
 	idx := getFermatSlicePoolIndex(size)
 	if idx < 0 {
+		poolMisses.Add(1)
 		return make([]fermat, size)
 	}
 	slice := fermatSlicePools[idx].Get().([]fermat)
@@ -454,6 +505,11 @@ var poolsWarmed atomic.Bool
 // Parameters:
 //   - maxN: The maximum Fibonacci index expected (used for estimation).
 func EnsurePoolsWarmed(maxN uint64) {
+	// Pre-warming trades memory for speed; skip it in low-memory mode
+	// without consuming the one-time initialization.
+	if LowMemoryMode() {
+		return
+	}
 	if poolsWarmed.CompareAndSwap(false, true) {
 		PreWarmPools(maxN)
 	}
@@ -491,3 +491,24 @@ func BenchmarkMulVsMulTo(b *testing.B) {
 		}
 	})
 }
+
+func TestPoolStats(t *testing.T) {
+	t.Parallel()
+	before := GetPoolStats()
+
+	// Other tests acquire buffers concurrently: only check lower bounds
+	releaseFermat(acquireFermat(100))
+	releaseNatSlice(acquireNatSlice(20))
+	acquireNatSlice(natSliceSizes[len(natSliceSizes)-1] + 1) // Too large for pooling
+
+	after := GetPoolStats()
+	if gets := after.Gets - before.Gets; gets < 3 {
+		t.Errorf("Expected at least 3 acquisitions, got %d", gets)
+	}
+	if misses := after.Misses - before.Misses; misses < 1 {
+		t.Errorf("Expected the unpooled allocation to count as a miss, got %d misses", misses)
+	}
+	if after.HitRate < 0 || after.HitRate > 1 || after.Misses > after.Gets {
+		t.Errorf("Inconsistent statistics %+v", after)
+	}
+}
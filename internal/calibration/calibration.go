@@ -149,6 +149,13 @@ func RunCalibrationWithOptions(ctx context.Context, out io.Writer, calculatorReg
 		profile.CalibrationN = fibonacci.CalibrationN
 		profile.CalibrationTime = calibrationDuration.String()
 
+		// Fit per-algorithm cost models for automatic algorithm selection
+		profile.CostModels = FitCostModels(ctx, calculatorRegistry, fibonacci.Options{
+			ParallelThreshold: profile.OptimalParallelThreshold,
+			FFTThreshold:      profile.OptimalFFTThreshold,
+			StrassenThreshold: profile.OptimalStrassenThreshold,
+		})
+
 		if err := profile.SaveProfile(opts.ProfilePath); err != nil {
 			fmt.Fprintf(out, "%sWarning: failed to save profile: %v%s\n",
 				ui.ColorYellow(), err, ui.ColorReset())
@@ -0,0 +1,375 @@
+// Package calibration provides performance calibration for the Fibonacci calculator.
+// This file implements the per-algorithm cost model and the automatic
+// algorithm selection ("auto") built on top of it.
+package calibration
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Cost Model
+// ─────────────────────────────────────────────────────────────────────────────
+
+// CostModelSampleSizes are the Fibonacci indices measured for each algorithm
+// when fitting cost models during a full calibration. They span two orders of
+// magnitude so that the fitted exponent is meaningful, while keeping the
+// slowest algorithm well under a second per sample.
+var CostModelSampleSizes = []uint64{50_000, 250_000, 1_000_000}
+
+// AlgorithmCostModel predicts the running time of an algorithm as a power law
+// of the Fibonacci index: t(n) = Coefficient * n^Exponent nanoseconds.
+//
+// The model is fitted by least squares in log-log space, which is a good
+// approximation for the O(M(n)·log n) algorithms used here across the range
+// of sizes covered by a calibration run.
+type AlgorithmCostModel struct {
+	// Algorithm is the registered calculator name (e.g., "fast").
+	Algorithm string `json:"algorithm"`
+	// Coefficient is the multiplicative constant, in nanoseconds.
+	Coefficient float64 `json:"coefficient"`
+	// Exponent is the growth exponent of the power law.
+	Exponent float64 `json:"exponent"`
+	// Samples is the number of measurements used for the fit (0 for built-in defaults).
+	Samples int `json:"samples"`
+}
+
+// Predict returns the estimated duration of computing F(n) with this model.
+//
+// Parameters:
+//   - n: The Fibonacci index.
+//
+// Returns:
+//   - time.Duration: The predicted duration (0 for n == 0).
+func (m AlgorithmCostModel) Predict(n uint64) time.Duration {
+	if n == 0 {
+		return 0
+	}
+	ns := m.Coefficient * math.Pow(float64(n), m.Exponent)
+	if ns > float64(math.MaxInt64) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(ns)
+}
+
+// defaultCostModels are conservative, hardware-independent models used when
+// no calibrated model is available. They encode the relative ordering observed
+// on typical 64-bit machines: fast doubling leads for most sizes, the FFT-only
+// variant catches up on very large inputs, and matrix exponentiation grows
+// like fast doubling with a constant-factor penalty for its extra
+// multiplications, so that it is never the default choice.
+var defaultCostModels = map[string]AlgorithmCostModel{
+	"fast":   {Algorithm: "fast", Coefficient: 0.20, Exponent: 1.40},
+	"fft":    {Algorithm: "fft", Coefficient: 0.30, Exponent: 1.38},
+	"matrix": {Algorithm: "matrix", Coefficient: 0.30, Exponent: 1.40},
+	"gmp":    {Algorithm: "gmp", Coefficient: 0.12, Exponent: 1.40},
+}
+
+// algorithmStrategies maps registered calculators to the multiplication
+// strategy they use, for the purpose of explaining a selection.
+var algorithmStrategies = map[string]string{
+	"fast":   "Adaptive (Karatsuba/FFT)",
+	"fft":    "FFT-Only",
+	"matrix": "Adaptive + Strassen",
+	"gmp":    "GMP (mpz_mul)",
+}
+
+// FitCostModel fits a power-law cost model to the given measurements.
+//
+// Parameters:
+//   - algorithm: The algorithm name the measurements belong to.
+//   - ns: The Fibonacci indices measured.
+//   - durations: The measured durations, parallel to ns.
+//
+// Returns:
+//   - AlgorithmCostModel: The fitted model.
+//   - error: An error if fewer than two usable measurements were provided.
+func FitCostModel(algorithm string, ns []uint64, durations []time.Duration) (AlgorithmCostModel, error) {
+	if len(ns) != len(durations) {
+		return AlgorithmCostModel{}, fmt.Errorf("mismatched sample lengths: %d indices, %d durations", len(ns), len(durations))
+	}
+
+	var xs, ys []float64
+	for i := range ns {
+		if ns[i] == 0 || durations[i] <= 0 {
+			continue
+		}
+		xs = append(xs, math.Log(float64(ns[i])))
+		ys = append(ys, math.Log(float64(durations[i].Nanoseconds())))
+	}
+	if len(xs) < 2 {
+		return AlgorithmCostModel{}, fmt.Errorf("at least 2 valid samples are required to fit a cost model, got %d", len(xs))
+	}
+
+	var sumX, sumY, sumXX, sumXY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXX += xs[i] * xs[i]
+		sumXY += xs[i] * ys[i]
+	}
+	count := float64(len(xs))
+	denom := count*sumXX - sumX*sumX
+	if denom == 0 {
+		return AlgorithmCostModel{}, fmt.Errorf("cannot fit cost model: all samples have the same index")
+	}
+
+	exponent := (count*sumXY - sumX*sumY) / denom
+	intercept := (sumY - exponent*sumX) / count
+
+	return AlgorithmCostModel{
+		Algorithm:   algorithm,
+		Coefficient: math.Exp(intercept),
+		Exponent:    exponent,
+		Samples:     len(xs),
+	}, nil
+}
+
+// FitCostModels measures every calculator of the registry at
+// CostModelSampleSizes and fits one cost model per algorithm. Algorithms
+// whose measurements fail are skipped.
+//
+// Parameters:
+//   - ctx: The context for cancellation.
+//   - calculatorRegistry: The calculators to measure.
+//   - opts: The calculation options (thresholds) to measure with.
+//
+// Returns:
+//   - []AlgorithmCostModel: The fitted models, sorted by algorithm name.
+func FitCostModels(ctx context.Context, calculatorRegistry map[string]fibonacci.Calculator, opts fibonacci.Options) []AlgorithmCostModel {
+	names := make([]string, 0, len(calculatorRegistry))
+	for name := range calculatorRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	models := make([]AlgorithmCostModel, 0, len(names))
+	for _, name := range names {
+		calc := calculatorRegistry[name]
+		// Warm up pools and caches so the first sample is not penalized
+		if _, err := calc.Calculate(ctx, nil, 0, CostModelSampleSizes[0], opts); err != nil {
+			continue
+		}
+		var ns []uint64
+		var durations []time.Duration
+		for _, n := range CostModelSampleSizes {
+			if ctx.Err() != nil {
+				return models
+			}
+			start := time.Now()
+			if _, err := calc.Calculate(ctx, nil, 0, n, opts); err != nil {
+				continue
+			}
+			ns = append(ns, n)
+			durations = append(durations, time.Since(start))
+		}
+		if model, err := FitCostModel(name, ns, durations); err == nil {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// CostModelFor returns the cost model for an algorithm, preferring a
+// calibrated model from the profile over the built-in defaults.
+//
+// Returns:
+//   - AlgorithmCostModel: The model.
+//   - bool: True if the model comes from calibration.
+//   - bool: True if any model was found.
+func (p *CalibrationProfile) CostModelFor(algorithm string) (model AlgorithmCostModel, calibrated, ok bool) {
+	if p != nil {
+		for _, m := range p.CostModels {
+			if m.Algorithm == algorithm && m.Samples > 0 {
+				return m, true, true
+			}
+		}
+	}
+	model, ok = defaultCostModels[algorithm]
+	return model, false, ok
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Automatic Algorithm Selection
+// ─────────────────────────────────────────────────────────────────────────────
+
+// CandidateEstimate is the predicted cost of one algorithm for a given n.
+type CandidateEstimate struct {
+	Algorithm string        `json:"algorithm"`
+	Strategy  string        `json:"strategy"`
+	Predicted time.Duration `json:"predicted_ns"`
+	Source    string        `json:"source"`
+}
+
+// SelectedThresholds are the thresholds recommended alongside a selection.
+type SelectedThresholds struct {
+	Parallel int    `json:"parallel"`
+	FFT      int    `json:"fft"`
+	Strassen int    `json:"strassen"`
+	Source   string `json:"source"`
+}
+
+// AlgorithmSelection describes the outcome of SelectAlgorithm and the
+// reasoning behind it, so that it can be shown to the user.
+type AlgorithmSelection struct {
+	N          uint64              `json:"n"`
+	Algorithm  string              `json:"algorithm"`
+	Strategy   string              `json:"strategy"`
+	Predicted  time.Duration       `json:"predicted_ns"`
+	Reason     string              `json:"reason"`
+	Thresholds *SelectedThresholds `json:"thresholds,omitempty"`
+	Candidates []CandidateEstimate `json:"candidates"`
+}
+
+// Cost model sources reported in CandidateEstimate.Source.
+const (
+	CostSourceCalibrated = "calibrated"
+	CostSourceDefault    = "default"
+)
+
+// SelectAlgorithm chooses the algorithm predicted to be fastest for F(n).
+//
+// Each available algorithm is scored with its cost model (calibrated from the
+// profile when present, built-in defaults otherwise). If the profile contains
+// thresholds for the range covering n (ThresholdsByRange), they are returned
+// with the selection so that the chosen calculator runs with them.
+//
+// Parameters:
+//   - n: The Fibonacci index to calculate.
+//   - profile: The calibration profile (may be nil).
+//   - available: The registered algorithm names.
+//
+// Returns:
+//   - AlgorithmSelection: The chosen algorithm with its explanation.
+func SelectAlgorithm(n uint64, profile *CalibrationProfile, available []string) AlgorithmSelection {
+	sel := AlgorithmSelection{N: n}
+
+	if profile != nil && profile.IsValid() {
+		fft, par, strassen := profile.GetThresholdsForN(n)
+		source := "profile defaults"
+		for _, r := range profile.ThresholdsByRange {
+			if n >= r.MinN && n <= r.MaxN && r.ConfidenceScore >= 0.5 {
+				source = fmt.Sprintf("profile range [%d, %d]", r.MinN, r.MaxN)
+				break
+			}
+		}
+		if fft > 0 || par > 0 || strassen > 0 {
+			sel.Thresholds = &SelectedThresholds{Parallel: par, FFT: fft, Strassen: strassen, Source: source}
+		}
+	}
+
+	for _, name := range available {
+		model, calibrated, ok := profile.CostModelFor(name)
+		if !ok {
+			continue
+		}
+		source := CostSourceDefault
+		if calibrated {
+			source = CostSourceCalibrated
+		}
+		sel.Candidates = append(sel.Candidates, CandidateEstimate{
+			Algorithm: name,
+			Strategy:  strategyFor(name),
+			Predicted: model.Predict(n),
+			Source:    source,
+		})
+	}
+	sort.SliceStable(sel.Candidates, func(i, j int) bool {
+		return sel.Candidates[i].Predicted < sel.Candidates[j].Predicted
+	})
+
+	switch {
+	case n <= fibonacci.MaxFibUint64:
+		sel.Algorithm = preferredFallback(available)
+		sel.Reason = fmt.Sprintf("n <= %d is answered from the precomputed table; every algorithm is equivalent", fibonacci.MaxFibUint64)
+	case len(sel.Candidates) == 0:
+		sel.Algorithm = preferredFallback(available)
+		sel.Reason = "no cost model available for the registered algorithms"
+	default:
+		best := sel.Candidates[0]
+		sel.Algorithm = best.Algorithm
+		sel.Predicted = best.Predicted
+		sel.Reason = fmt.Sprintf("lowest predicted duration using %s cost model", best.Source)
+		if len(sel.Candidates) > 1 {
+			runnerUp := sel.Candidates[1]
+			sel.Reason += fmt.Sprintf(" (%s expected %.2fx slower)", runnerUp.Algorithm, ratio(runnerUp.Predicted, best.Predicted))
+		}
+	}
+	sel.Strategy = strategyFor(sel.Algorithm)
+
+	return sel
+}
+
+// Explain renders the selection as a human-readable, multi-line report.
+func (s AlgorithmSelection) Explain() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Auto-selected algorithm: %s (strategy: %s)\n", s.Algorithm, s.Strategy)
+	fmt.Fprintf(&sb, "  Reason: %s\n", s.Reason)
+	if s.Thresholds != nil {
+		fmt.Fprintf(&sb, "  Thresholds (%s): parallel=%d, FFT=%d, Strassen=%d bits\n",
+			s.Thresholds.Source, s.Thresholds.Parallel, s.Thresholds.FFT, s.Thresholds.Strassen)
+	}
+	for _, c := range s.Candidates {
+		fmt.Fprintf(&sb, "  - %-8s %-26s predicted %-12s [%s]\n",
+			c.Algorithm, c.Strategy, c.Predicted.Round(time.Microsecond), c.Source)
+	}
+	return sb.String()
+}
+
+// strategyFor returns the strategy description for an algorithm.
+func strategyFor(algorithm string) string {
+	if s, ok := algorithmStrategies[algorithm]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// preferredFallback returns "fast" if available, otherwise the first algorithm.
+func preferredFallback(available []string) string {
+	for _, name := range available {
+		if name == "fast" {
+			return name
+		}
+	}
+	if len(available) > 0 {
+		return available[0]
+	}
+	return ""
+}
+
+// ratio returns a/b as a float, guarding against division by zero.
+func ratio(a, b time.Duration) float64 {
+	if b <= 0 {
+		return 1
+	}
+	return float64(a) / float64(b)
+}
+
+// EstimateDuration predicts the duration of computing F(n) with an algorithm.
+//
+// Parameters:
+//   - n: The Fibonacci index.
+//   - profile: The calibration profile (may be nil).
+//   - algorithm: The registered algorithm name.
+//
+// Returns:
+//   - time.Duration: The predicted duration.
+//   - string: The model source (CostSourceCalibrated or CostSourceDefault).
+//   - bool: False if no model exists for the algorithm.
+func EstimateDuration(n uint64, profile *CalibrationProfile, algorithm string) (time.Duration, string, bool) {
+	model, calibrated, ok := profile.CostModelFor(algorithm)
+	if !ok {
+		return 0, "", false
+	}
+	if calibrated {
+		return model.Predict(n), CostSourceCalibrated, true
+	}
+	return model.Predict(n), CostSourceDefault, true
+}
@@ -0,0 +1,166 @@
+package calibration
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+func TestFitCostModel(t *testing.T) {
+	t.Parallel()
+
+	// Samples generated from t(n) = 2 * n^1.5 ns
+	ns := []uint64{1_000, 10_000, 100_000}
+	durations := make([]time.Duration, len(ns))
+	for i, n := range ns {
+		durations[i] = time.Duration(2 * math.Pow(float64(n), 1.5))
+	}
+
+	model, err := FitCostModel("fast", ns, durations)
+	if err != nil {
+		t.Fatalf("FitCostModel returned error: %v", err)
+	}
+	if math.Abs(model.Exponent-1.5) > 0.01 {
+		t.Errorf("Exponent = %f, want ~1.5", model.Exponent)
+	}
+	if math.Abs(model.Coefficient-2) > 0.1 {
+		t.Errorf("Coefficient = %f, want ~2", model.Coefficient)
+	}
+	if model.Samples != 3 {
+		t.Errorf("Samples = %d, want 3", model.Samples)
+	}
+
+	want := time.Duration(2 * math.Pow(1_000_000, 1.5))
+	got := model.Predict(1_000_000)
+	if diff := math.Abs(float64(got-want)) / float64(want); diff > 0.05 {
+		t.Errorf("Predict(1e6) = %v, want ~%v", got, want)
+	}
+}
+
+func TestFitCostModelErrors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := FitCostModel("fast", []uint64{1000}, []time.Duration{time.Millisecond}); err == nil {
+		t.Error("expected error with a single sample")
+	}
+	if _, err := FitCostModel("fast", []uint64{1000, 1000}, []time.Duration{time.Millisecond, 2 * time.Millisecond}); err == nil {
+		t.Error("expected error when all samples share the same index")
+	}
+	if _, err := FitCostModel("fast", []uint64{1000}, nil); err == nil {
+		t.Error("expected error with mismatched lengths")
+	}
+}
+
+func TestFitCostModels(t *testing.T) {
+	t.Parallel()
+
+	registry := map[string]fibonacci.Calculator{
+		"fast": fibonacci.NewCalculator(&fibonacci.OptimizedFastDoubling{}),
+	}
+	models := FitCostModels(context.Background(), registry, fibonacci.Options{})
+	if len(models) != 1 || models[0].Algorithm != "fast" {
+		t.Fatalf("expected one model for 'fast', got %+v", models)
+	}
+	if models[0].Exponent <= 0 {
+		t.Errorf("expected positive exponent, got %f", models[0].Exponent)
+	}
+}
+
+func TestSelectAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	available := []string{"fast", "fft", "matrix"}
+
+	t.Run("Defaults without profile", func(t *testing.T) {
+		t.Parallel()
+		sel := SelectAlgorithm(10_000_000, nil, available)
+		if sel.Algorithm != "fast" {
+			t.Errorf("Algorithm = %q, want fast", sel.Algorithm)
+		}
+		if len(sel.Candidates) != 3 {
+			t.Errorf("expected 3 candidates, got %d", len(sel.Candidates))
+		}
+		for _, c := range sel.Candidates {
+			if c.Source != CostSourceDefault {
+				t.Errorf("candidate %s source = %q, want %q", c.Algorithm, c.Source, CostSourceDefault)
+			}
+		}
+		if sel.Thresholds != nil {
+			t.Error("expected no thresholds without a profile")
+		}
+	})
+
+	t.Run("Defaults never choose matrix", func(t *testing.T) {
+		t.Parallel()
+		for n := uint64(1_000); n <= 100_000_000; n = n * 3 / 2 {
+			if sel := SelectAlgorithm(n, nil, available); sel.Algorithm == "matrix" {
+				t.Fatalf("SelectAlgorithm(%d) chose matrix: %s", n, sel.Reason)
+			}
+		}
+	})
+
+	t.Run("Calibrated model wins", func(t *testing.T) {
+		t.Parallel()
+		profile := NewProfile()
+		profile.OptimalFFTThreshold = 500_000
+		profile.OptimalParallelThreshold = 4096
+		profile.OptimalStrassenThreshold = 3072
+		profile.ThresholdsByRange = []RangeThresholds{
+			{MinN: 1_000_000, MaxN: 10_000_000, FFTThreshold: 250_000, ParallelThreshold: 2048, ConfidenceScore: 0.9},
+		}
+		profile.CostModels = []AlgorithmCostModel{
+			{Algorithm: "matrix", Coefficient: 0.01, Exponent: 1.5, Samples: 3},
+		}
+
+		sel := SelectAlgorithm(5_000_000, profile, available)
+		if sel.Algorithm != "matrix" {
+			t.Errorf("Algorithm = %q, want matrix", sel.Algorithm)
+		}
+		if sel.Candidates[0].Source != CostSourceCalibrated {
+			t.Errorf("best candidate source = %q, want %q", sel.Candidates[0].Source, CostSourceCalibrated)
+		}
+		if sel.Thresholds == nil || sel.Thresholds.FFT != 250_000 || sel.Thresholds.Parallel != 2048 {
+			t.Errorf("expected range thresholds, got %+v", sel.Thresholds)
+		}
+		if sel.Thresholds.Strassen != 3072 {
+			t.Errorf("Strassen = %d, want profile default 3072", sel.Thresholds.Strassen)
+		}
+	})
+
+	t.Run("Small n", func(t *testing.T) {
+		t.Parallel()
+		sel := SelectAlgorithm(50, nil, []string{"matrix", "fast"})
+		if sel.Algorithm != "fast" {
+			t.Errorf("Algorithm = %q, want fast", sel.Algorithm)
+		}
+		if !strings.Contains(sel.Reason, "precomputed") {
+			t.Errorf("unexpected reason: %q", sel.Reason)
+		}
+	})
+
+	t.Run("Unknown algorithms", func(t *testing.T) {
+		t.Parallel()
+		sel := SelectAlgorithm(1_000_000, nil, []string{"custom"})
+		if sel.Algorithm != "custom" {
+			t.Errorf("Algorithm = %q, want custom", sel.Algorithm)
+		}
+		if len(sel.Candidates) != 0 {
+			t.Errorf("expected no candidates, got %d", len(sel.Candidates))
+		}
+	})
+}
+
+func TestAlgorithmSelectionExplain(t *testing.T) {
+	t.Parallel()
+	sel := SelectAlgorithm(10_000_000, nil, []string{"fast", "matrix"})
+	text := sel.Explain()
+	for _, want := range []string{"Auto-selected algorithm: fast", "Reason:", "matrix"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Explain() missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -31,6 +31,9 @@ type CalibrationProfile struct {
 	// Thresholds by N range for more accurate calibration
 	ThresholdsByRange []RangeThresholds `json:"thresholds_by_range,omitempty"`
 
+	// Per-algorithm cost models used by automatic algorithm selection
+	CostModels []AlgorithmCostModel `json:"cost_models,omitempty"`
+
 	// Calibration metadata
 	CalibratedAt    time.Time `json:"calibrated_at"`
 	CalibrationN    uint64    `json:"calibration_n"`
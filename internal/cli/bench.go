@@ -0,0 +1,79 @@
+// Package cli provides the command-line interface for the Fibonacci calculator.
+// This file renders the statistical benchmark reports of --bench.
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/bench"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// formatBenchDuration formats a benchmark duration to the microsecond
+// ("850µs", "1.234ms", "2.5s"). FormatExecutionDuration truncates to the
+// millisecond, which would show the sub-millisecond medians as 0ms and
+// collapse the bounds of narrow confidence intervals.
+//
+// Parameters:
+//   - d: The duration to format.
+//
+// Returns:
+//   - string: The formatted duration.
+func formatBenchDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+// DisplayBenchReport prints the benchmark results table.
+//
+// Parameters:
+//   - out: The writer for output.
+//   - r: The report to print.
+func DisplayBenchReport(out io.Writer, r *bench.Report) {
+	fmt.Fprintf(out, "\n--- Benchmark: F(%d), %d runs after %d warm-up ---\n", r.N, r.Runs, r.Warmup)
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Algorithm\tProcs\tMin\tMedian\tMean\tStdDev\t95%% CI\t\n")
+	for _, res := range r.Results {
+		if res.Error != "" {
+			fmt.Fprintf(tw, "%s\t%d\t%s❌ %s%s\t\t\t\t\t\n", res.Algorithm, res.Procs, ui.ColorRed(), res.Error, ui.ColorReset())
+			continue
+		}
+		s := res.Stats
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s – %s\t\n",
+			res.Algorithm, res.Procs,
+			formatBenchDuration(s.Min), formatBenchDuration(s.Median),
+			formatBenchDuration(s.Mean), formatBenchDuration(s.StdDev),
+			formatBenchDuration(s.CILow), formatBenchDuration(s.CIHigh))
+	}
+	tw.Flush()
+}
+
+// DisplayBenchComparison prints the comparison of a benchmark against its
+// baseline.
+//
+// Parameters:
+//   - out: The writer for output.
+//   - comparisons: The comparisons to print.
+//   - tolerance: The regression tolerance, in percent.
+func DisplayBenchComparison(out io.Writer, comparisons []bench.Comparison, tolerance float64) {
+	fmt.Fprintf(out, "\n--- Baseline comparison (tolerance %.1f%%) ---\n", tolerance)
+	if len(comparisons) == 0 {
+		fmt.Fprintf(out, "No result matches the baseline.\n")
+		return
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Algorithm\tProcs\tBaseline\tCurrent\tChange\tStatus\t\n")
+	for _, c := range comparisons {
+		status := fmt.Sprintf("%sok%s", ui.ColorGreen(), ui.ColorReset())
+		if c.Regression {
+			status = fmt.Sprintf("%sREGRESSION%s", ui.ColorRed(), ui.ColorReset())
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%+.1f%%\t%s\t\n",
+			c.Algorithm, c.Procs,
+			formatBenchDuration(c.Baseline.Median), formatBenchDuration(c.Current.Median),
+			c.Change, status)
+	}
+	tw.Flush()
+}
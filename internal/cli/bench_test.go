@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/bench"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestDisplayBenchReport(t *testing.T) {
+	t.Parallel()
+	d := []time.Duration{time.Millisecond, 3 * time.Millisecond}
+	report := &bench.Report{N: 42, Runs: 2, Warmup: 1, Results: []bench.Result{
+		{Algorithm: "fast", Procs: 2, Durations: d, Stats: bench.ComputeStats(d)},
+		{Algorithm: "matrix", Procs: 2, Error: "timeout"},
+	}}
+
+	var out bytes.Buffer
+	DisplayBenchReport(&out, report)
+	output := testutil.StripAnsiCodes(out.String())
+	for _, want := range []string{"Benchmark: F(42), 2 runs after 1 warm-up", "fast", "2ms", "❌ timeout"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report missing %q:\n%s", want, output)
+		}
+	}
+}
+
+// TestDisplayBenchReportMicroseconds verifies that sub-millisecond and
+// narrow statistics are shown to the microsecond instead of being truncated
+// to the millisecond.
+func TestDisplayBenchReportMicroseconds(t *testing.T) {
+	t.Parallel()
+	d := []time.Duration{400 * time.Microsecond, 1200 * time.Microsecond, 1600 * time.Microsecond}
+	report := &bench.Report{N: 42, Runs: 3, Results: []bench.Result{
+		{Algorithm: "fast", Procs: 1, Durations: d, Stats: bench.ComputeStats(d)},
+	}}
+
+	var out bytes.Buffer
+	DisplayBenchReport(&out, report)
+	output := testutil.StripAnsiCodes(out.String())
+	s := report.Results[0].Stats
+	for _, want := range []string{"400µs", "1.2ms", "1.067ms", formatBenchDuration(s.CILow) + " – " + formatBenchDuration(s.CIHigh)} {
+		if !strings.Contains(output, want) {
+			t.Errorf("report missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "0ms") {
+		t.Errorf("report truncates a duration to 0ms:\n%s", output)
+	}
+
+	comparisons := []bench.Comparison{{
+		Algorithm: "fast", Procs: 1, Change: 12.5,
+		Baseline: bench.Stats{Median: 800 * time.Microsecond},
+		Current:  bench.Stats{Median: 900 * time.Microsecond},
+	}}
+	out.Reset()
+	DisplayBenchComparison(&out, comparisons, 5)
+	if output := out.String(); !strings.Contains(output, "800µs") || !strings.Contains(output, "900µs") {
+		t.Errorf("comparison does not show the medians to the microsecond:\n%s", output)
+	}
+}
+
+func TestDisplayBenchComparison(t *testing.T) {
+	t.Parallel()
+	comparisons := []bench.Comparison{
+		{Algorithm: "fast", Procs: 1, Change: 50, Regression: true},
+		{Algorithm: "matrix", Procs: 1, Change: -2},
+	}
+
+	var out bytes.Buffer
+	DisplayBenchComparison(&out, comparisons, 5)
+	output := testutil.StripAnsiCodes(out.String())
+	for _, want := range []string{"tolerance 5.0%", "REGRESSION", "+50.0%", "-2.0%", "ok"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("comparison missing %q:\n%s", want, output)
+		}
+	}
+
+	out.Reset()
+	DisplayBenchComparison(&out, nil, 5)
+	if !strings.Contains(out.String(), "No result matches the baseline.") {
+		t.Errorf("unexpected output without comparisons:\n%s", out.String())
+	}
+}
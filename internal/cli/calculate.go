@@ -52,6 +52,10 @@ func PrintExecutionConfig(cfg config.AppConfig, out io.Writer) {
 		ui.ColorCyan(), runtime.NumCPU(), ui.ColorReset(), ui.ColorCyan(), runtime.Version(), ui.ColorReset())
 	writeOut(out, "Optimization thresholds: Parallelism=%s%d%s bits, FFT=%s%d%s bits.\n",
 		ui.ColorCyan(), cfg.Threshold, ui.ColorReset(), ui.ColorCyan(), cfg.FFTThreshold, ui.ColorReset())
+	if cfg.IntegrityCheck {
+		writeOut(out, "Integrity checks: every %s%d%s step(s), modulo a random 61-bit prime.\n",
+			ui.ColorCyan(), max(cfg.IntegrityInterval, 1), ui.ColorReset())
+	}
 }
 
 // PrintExecutionMode displays the execution mode (single algorithm vs comparison).
@@ -71,6 +75,17 @@ func PrintExecutionMode(calculators []fibonacci.Calculator, out io.Writer) {
 	writeOut(out, "\n--- Starting Execution ---\n")
 }
 
+// PrintRaceMode displays the execution mode of a race (--race), in which the
+// first successful calculator wins and the others are canceled.
+//
+// Parameters:
+//   - calculators: The list of calculators racing.
+//   - out: The output writer.
+func PrintRaceMode(calculators []fibonacci.Calculator, out io.Writer) {
+	writeOut(out, "Execution mode: Race between %d algorithms (first successful result wins).\n", len(calculators))
+	writeOut(out, "\n--- Starting Execution ---\n")
+}
+
 // writeOut writes a formatted string to the output writer.
 // It uses zerolog for structured output if configured, or falls back to
 // direct writing if the output is not a logger.
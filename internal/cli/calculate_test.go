@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/agbru/fibcalc/internal/config"
@@ -70,6 +71,16 @@ func TestPrintExecutionConfig(t *testing.T) {
 	if len(output) < 50 {
 		t.Errorf("PrintExecutionConfig output seems too short: %s", output)
 	}
+	if strings.Contains(output, "Integrity checks") {
+		t.Errorf("Integrity checks should not be mentioned when disabled: %s", output)
+	}
+
+	buf.Reset()
+	cfg.IntegrityCheck, cfg.IntegrityInterval = true, 8
+	PrintExecutionConfig(cfg, &buf)
+	if !strings.Contains(buf.String(), "Integrity checks: every") {
+		t.Errorf("Expected the integrity check line, got: %s", buf.String())
+	}
 }
 
 // TestPrintExecutionMode tests the PrintExecutionMode function.
@@ -45,7 +45,7 @@ _fibcalc_completions() {
     opts="--help -h --version -V -n -v -d --details --timeout --algo --threshold --fft-threshold --strassen-threshold --calibrate --auto-calibrate --calibration-profile --json --server --port --no-color --output -o --quiet -q --hex --interactive --completion"
 
     # Available algorithms
-    algorithms="%s all"
+    algorithms="%s all auto"
 
     case "${prev}" in
         --algo)
@@ -104,7 +104,7 @@ func generateZshCompletion(out io.Writer, algorithms []string) error {
 
 _fibcalc() {
     local -a algorithms
-    algorithms=(%s all)
+    algorithms=(%s all auto)
 
     _arguments -s \
         '(-h --help)'{-h,--help}'[Show help message]' \
@@ -162,7 +162,7 @@ complete -c fibcalc -s n -d 'Fibonacci index to calculate' -x
 complete -c fibcalc -s v -d 'Display full result value'
 complete -c fibcalc -s d -l details -d 'Show performance details'
 complete -c fibcalc -l timeout -d 'Maximum execution time' -xa '1m 5m 10m 30m 1h'
-complete -c fibcalc -l algo -d 'Algorithm to use' -xa '%s all'
+complete -c fibcalc -l algo -d 'Algorithm to use' -xa '%s all auto'
 complete -c fibcalc -l threshold -d 'Parallelism threshold in bits' -xa '1024 2048 4096 8192 16384'
 complete -c fibcalc -l fft-threshold -d 'FFT threshold in bits' -xa '100000 500000 1000000'
 complete -c fibcalc -l strassen-threshold -d 'Strassen threshold' -xa '1024 2048 3072 4096'
@@ -204,7 +204,7 @@ func generatePowerShellCompletion(out io.Writer, algorithms []string) error {
 	script := `# PowerShell completion script for fibcalc
 # Add this to your $PROFILE
 
-$fibcalcAlgorithms = @(%s, 'all')
+$fibcalcAlgorithms = @(%s, 'all', 'auto')
 
 Register-ArgumentCompleter -CommandName 'fibcalc' -Native -ScriptBlock {
     param($wordToComplete, $commandAst, $cursorPosition)
@@ -137,7 +137,7 @@ func TestGenerateCompletion_EmptyAlgorithms(t *testing.T) {
 		t.Errorf("Should not error with empty algorithms: %v", err)
 	}
 	output := buf.String()
-	if !strings.Contains(output, "algorithms=\" all\"") {
+	if !strings.Contains(output, "algorithms=\" all auto\"") {
 		t.Error("Should handle empty algorithm list")
 	}
 }
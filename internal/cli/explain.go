@@ -0,0 +1,76 @@
+// Package cli provides the command-line interface for the Fibonacci calculator.
+// This file renders execution plans produced by fibonacci.PlanCalculation
+// (used by --explain and the REPL "explain" command).
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// DisplayExecutionPlan prints an execution plan: the thresholds in effect,
+// the doubling-step schedule with operand sizes, where each multiplication
+// method and parallelism engage, the expected peak memory and the estimated
+// duration.
+//
+// Parameters:
+//   - out: The writer for output.
+//   - plan: The plan to display.
+func DisplayExecutionPlan(out io.Writer, plan fibonacci.ExecutionPlan) {
+	fmt.Fprintf(out, "\n%s--- Execution Plan: F(%d) with '%s' ---%s\n", ui.ColorBold(), plan.N, plan.Algorithm, ui.ColorReset())
+	fmt.Fprintf(out, "Result size: %s%d%s bits (~%d decimal digits)\n",
+		ui.ColorCyan(), plan.ResultBits, ui.ColorReset(), int(float64(plan.ResultBits)*0.30103)+1)
+	fmt.Fprintf(out, "Thresholds: parallel=%d, Karatsuba=%d, FFT=%d, Strassen=%d bits\n",
+		plan.Thresholds.Parallel, plan.Thresholds.Karatsuba, plan.Thresholds.FFT, plan.Thresholds.Strassen)
+
+	fmt.Fprintf(out, "\n%-6s %-5s %-4s %14s  %-10s %s\n", "Step", "Bit", "Val", "Operand bits", "Method", "Parallel")
+	for _, st := range plan.Steps {
+		parallel := "-"
+		if st.Parallel {
+			parallel = "yes"
+		}
+		fmt.Fprintf(out, "%-6d %-5d %-4d %14d  %-10s %s\n", st.Step, st.BitIndex, st.Bit, st.OperandBits, st.Method, parallel)
+	}
+
+	if len(plan.Switches) > 0 {
+		fmt.Fprintf(out, "\nSwitch points:\n")
+		for _, sw := range plan.Switches {
+			fmt.Fprintf(out, "  %-10s from step %d (operands ~%d bits)\n", sw.Feature, sw.Step, sw.OperandBits)
+		}
+	}
+
+	mode := ""
+	if plan.LowMemory {
+		mode = " (low-memory mode)"
+	}
+	fmt.Fprintf(out, "\nExpected peak memory: %s%s%s%s\n", ui.ColorYellow(), FormatBytes(plan.PeakMemoryBytes), ui.ColorReset(), mode)
+	if plan.EstimateSource != "" {
+		fmt.Fprintf(out, "Estimated duration:   %s%s%s (%s cost model)\n",
+			ui.ColorYellow(), FormatExecutionDuration(plan.EstimatedDuration), ui.ColorReset(), plan.EstimateSource)
+	} else {
+		fmt.Fprintf(out, "Estimated duration:   unavailable (no cost model)\n")
+	}
+}
+
+// FormatBytes formats a byte count using binary units (KiB, MiB, GiB).
+//
+// Parameters:
+//   - b: The number of bytes.
+//
+// Returns:
+//   - string: The formatted size.
+func FormatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
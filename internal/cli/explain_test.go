@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestDisplayExecutionPlan(t *testing.T) {
+	t.Parallel()
+	plan := fibonacci.PlanCalculation(1_000_000, "fast", fibonacci.Options{FFTThreshold: 100_000})
+
+	var out bytes.Buffer
+	DisplayExecutionPlan(&out, plan)
+	output := testutil.StripAnsiCodes(out.String())
+	for _, want := range []string{"Execution Plan: F(1000000) with 'fast'", "Switch points:", "fft", "Expected peak memory:", "unavailable"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	plan.EstimatedDuration, plan.EstimateSource = 1500*time.Millisecond, "calibrated"
+	out.Reset()
+	DisplayExecutionPlan(&out, plan)
+	if !strings.Contains(testutil.StripAnsiCodes(out.String()), "(calibrated cost model)") {
+		t.Errorf("expected estimate source in output:\n%s", out.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   uint64
+		want string
+	}{
+		{512, "512 B"},
+		{2048, "2.0 KiB"},
+		{3 << 20, "3.0 MiB"},
+		{5 << 30, "5.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.in); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -20,11 +20,13 @@ package cli
 import (
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/resultfile"
 	"github.com/agbru/fibcalc/internal/ui"
 )
 
@@ -40,6 +42,14 @@ type OutputConfig struct {
 	Verbose bool
 	// Concise enables the calculated value display when true (disabled by default).
 	Concise bool
+	// Format is the result file format: "dec", "hex" or "bin". When empty,
+	// the file is decimal, or hexadecimal with HexOutput.
+	Format string
+	// Compress is the payload compression of binary result files, as
+	// accepted by resultfile.Write.
+	Compress string
+	// Thresholds are recorded in the header of binary result files.
+	Thresholds resultfile.Thresholds
 }
 
 // WriteResultToFile writes a calculation result to a file.
@@ -57,6 +67,17 @@ func WriteResultToFile(result *big.Int, n uint64, duration time.Duration, algo s
 	if config.OutputFile == "" {
 		return nil
 	}
+	if config.Format == resultfile.FormatBinary {
+		return resultfile.WriteFile(config.OutputFile, result, resultfile.Header{
+			N:          n,
+			Algorithm:  algo,
+			Thresholds: config.Thresholds,
+			Build:      resultfile.CurrentBuild(),
+			Timestamp:  time.Now(),
+			Duration:   duration,
+		}, config.Compress)
+	}
+	hexOutput := config.Format == resultfile.FormatHex || (config.Format == "" && config.HexOutput)
 
 	// Ensure directory exists
 	dir := filepath.Dir(config.OutputFile)
@@ -83,7 +104,7 @@ func WriteResultToFile(result *big.Int, n uint64, duration time.Duration, algo s
 	fmt.Fprintf(file, "\n")
 
 	// Write result
-	if config.HexOutput {
+	if hexOutput {
 		fmt.Fprintf(file, "F(%d) [hex] =\n0x%s\n", n, result.Text(16))
 	} else {
 		fmt.Fprintf(file, "F(%d) =\n%s\n", n, result.String())
@@ -184,3 +205,49 @@ func DisplayResultWithConfig(out io.Writer, result *big.Int, n uint64, duration
 
 	return nil
 }
+
+// DisplayResultFileInfo prints the metadata of a result file read by
+// resultfile.Load: its format, the index and size of the stored number and,
+// for binary files, the header and the verified checksum.
+//
+// Parameters:
+//   - out: The io.Writer for the output.
+//   - path: The path of the file.
+//   - loaded: The loaded result.
+func DisplayResultFileInfo(out io.Writer, path string, loaded *resultfile.Loaded) {
+	fmt.Fprintf(out, "\n%s--- Result file: %s ---%s\n", ui.ColorBold(), path, ui.ColorReset())
+	format := loaded.Format
+	if info := loaded.Info; info != nil {
+		format = fmt.Sprintf("%s (version %d, %s compression)", format, info.Version, info.Compression)
+	}
+	fmt.Fprintf(out, "Format                  : %s\n", format)
+	if loaded.N > 0 || loaded.Info != nil {
+		fmt.Fprintf(out, "Index                   : %sF(%d)%s\n", ui.ColorCyan(), loaded.N, ui.ColorReset())
+	}
+	if loaded.Algorithm != "" {
+		fmt.Fprintf(out, "Algorithm               : %s\n", loaded.Algorithm)
+	}
+	bitLen := loaded.Value.BitLen()
+	fmt.Fprintf(out, "Size                    : %s%d%s bits (≈ %d digits)\n",
+		ui.ColorCyan(), bitLen, ui.ColorReset(), int(float64(bitLen)*math.Log10(2))+1)
+
+	info := loaded.Info
+	if info == nil {
+		fmt.Fprintf(out, "Checksum                : none (text format)\n")
+		return
+	}
+	h := info.Header
+	fmt.Fprintf(out, "Duration                : %s\n", FormatExecutionDuration(h.Duration))
+	fmt.Fprintf(out, "Thresholds              : parallel %d, FFT %d, Strassen %d bits\n",
+		h.Thresholds.Parallel, h.Thresholds.FFT, h.Thresholds.Strassen)
+	fmt.Fprintf(out, "Written                 : %s\n", h.Timestamp.Format(time.RFC3339))
+	build := fmt.Sprintf("%s %s/%s", h.Build.GoVersion, h.Build.GOOS, h.Build.GOARCH)
+	if h.Build.Version != "" {
+		build += fmt.Sprintf(", %s %s", h.Build.Module, h.Build.Version)
+	}
+	if h.Build.Revision != "" {
+		build += fmt.Sprintf(" (%s)", h.Build.Revision)
+	}
+	fmt.Fprintf(out, "Build                   : %s\n", build)
+	fmt.Fprintf(out, "SHA-256                 : %s %s(verified)%s\n", info.Checksum, ui.ColorGreen(), ui.ColorReset())
+}
@@ -2,12 +2,15 @@ package cli
 
 import (
 	"bytes"
+	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/resultfile"
 )
 
 func TestWriteResultToFile(t *testing.T) {
@@ -110,6 +113,40 @@ func TestWriteResultToFile(t *testing.T) {
 	}
 }
 
+func TestWriteResultToFile_Formats(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	result := big.NewInt(832040)
+
+	for _, tc := range []struct {
+		config OutputConfig
+		format string
+	}{
+		{OutputConfig{}, resultfile.FormatDecimal},
+		{OutputConfig{HexOutput: true}, resultfile.FormatHex},
+		{OutputConfig{Format: resultfile.FormatHex}, resultfile.FormatHex},
+		{OutputConfig{Format: resultfile.FormatDecimal}, resultfile.FormatDecimal},
+		{OutputConfig{Format: resultfile.FormatBinary, Thresholds: resultfile.Thresholds{FFT: 1000}}, resultfile.FormatBinary},
+		{OutputConfig{Format: resultfile.FormatBinary, Compress: resultfile.CompressionGzip}, resultfile.FormatBinary},
+		{OutputConfig{Format: resultfile.FormatBinary, Compress: resultfile.CompressionZstd}, resultfile.FormatBinary},
+	} {
+		tc.config.OutputFile = filepath.Join(tmpDir, strings.ReplaceAll(fmt.Sprintf("%+v", tc.config), " ", "_"))
+		if err := WriteResultToFile(result, 30, time.Second, "Fast Doubling", tc.config); err != nil {
+			t.Fatalf("WriteResultToFile(%+v) failed: %v", tc.config, err)
+		}
+		loaded, err := resultfile.Load(tc.config.OutputFile)
+		if err != nil {
+			t.Fatalf("Load after WriteResultToFile(%+v) failed: %v", tc.config, err)
+		}
+		if loaded.Format != tc.format || loaded.N != 30 || loaded.Algorithm != "Fast Doubling" || loaded.Value.Cmp(result) != 0 {
+			t.Errorf("%+v: loaded format %q, N %d, algorithm %q, value %s", tc.config, loaded.Format, loaded.N, loaded.Algorithm, loaded.Value)
+		}
+		if loaded.Info != nil && loaded.Info.Header.Thresholds != tc.config.Thresholds {
+			t.Errorf("%+v: thresholds not recorded: %+v", tc.config, loaded.Info.Header.Thresholds)
+		}
+	}
+}
+
 func TestFormatQuietResult(t *testing.T) {
 	t.Parallel()
 	result := big.NewInt(55)
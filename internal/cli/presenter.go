@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"runtime"
 	"sync"
 	"time"
 
@@ -34,31 +37,41 @@ type CLIResultPresenter struct{}
 var _ orchestration.ResultPresenter = CLIResultPresenter{}
 
 // PresentComparisonTable displays the comparison summary table with
-// algorithm names, durations, and status in a formatted tabular layout.
+// algorithm names, durations, CPU time, parallel efficiency and status in a
+// formatted tabular layout.
 // Uses manual padding to correctly handle ANSI color codes.
 func (CLIResultPresenter) PresentComparisonTable(results []orchestration.CalculationResult, out io.Writer) {
 	fmt.Fprintf(out, "\n--- Comparison Summary ---\n")
 
-	// Find the maximum algorithm name width for proper alignment
-	maxNameLen := 9 // "Algorithm" header length
+	// Find the maximum column widths for proper alignment
+	maxNameLen := 9     // "Algorithm" header length
 	maxDurationLen := 8 // "Duration" header length
+	maxCPULen := 8      // "CPU Time" header length
+	maxEffLen := 10     // "Efficiency" header length
+	shared := false
 	for _, res := range results {
 		if len(res.Name) > maxNameLen {
 			maxNameLen = len(res.Name)
 		}
-		duration := FormatExecutionDuration(res.Duration)
-		if res.Duration == 0 {
-			duration = "< 1µs"
-		}
+		duration := formatTableDuration(res.Duration)
 		if len(duration) > maxDurationLen {
 			maxDurationLen = len(duration)
 		}
+		if cpu := formatCPUTime(res.Resources); len(cpu) > maxCPULen {
+			maxCPULen = len(cpu)
+		}
+		if eff := FormatEfficiency(res.Resources); len(eff) > maxEffLen {
+			maxEffLen = len(eff)
+		}
+		shared = shared || res.Resources.Shared
 	}
 
 	// Print header with proper padding
-	fmt.Fprintf(out, "%sAlgorithm%s%s   %sDuration%s%s   %sStatus%s\n",
+	fmt.Fprintf(out, "%sAlgorithm%s%s   %sDuration%s%s   %sCPU Time%s%s   %sEfficiency%s%s   %sStatus%s\n",
 		ui.ColorUnderline(), ui.ColorReset(), padRight("", maxNameLen-9),
 		ui.ColorUnderline(), ui.ColorReset(), padRight("", maxDurationLen-8),
+		ui.ColorUnderline(), ui.ColorReset(), padRight("", maxCPULen-8),
+		ui.ColorUnderline(), ui.ColorReset(), padRight("", maxEffLen-10),
 		ui.ColorUnderline(), ui.ColorReset())
 
 	// Print each result row
@@ -69,15 +82,62 @@ func (CLIResultPresenter) PresentComparisonTable(results []orchestration.Calcula
 		} else {
 			status = fmt.Sprintf("%s✅ Success%s", ui.ColorGreen(), ui.ColorReset())
 		}
-		duration := FormatExecutionDuration(res.Duration)
-		if res.Duration == 0 {
-			duration = "< 1µs"
-		}
-		fmt.Fprintf(out, "%s%s%s%s   %s%s%s%s   %s\n",
+		duration := formatTableDuration(res.Duration)
+		cpu := formatCPUTime(res.Resources)
+		eff := FormatEfficiency(res.Resources)
+		fmt.Fprintf(out, "%s%s%s%s   %s%s%s%s   %s%s   %s%s   %s\n",
 			ui.ColorBlue(), res.Name, ui.ColorReset(), padRight("", maxNameLen-len(res.Name)),
 			ui.ColorYellow(), duration, ui.ColorReset(), padRight("", maxDurationLen-len(duration)),
+			cpu, padRight("", maxCPULen-len(cpu)),
+			eff, padRight("", maxEffLen-len(eff)),
 			status)
 	}
+	if shared {
+		fmt.Fprintf(out, "(shared): process-wide totals, including the calculations that ran concurrently; --sequential measures each alone.\n")
+	}
+}
+
+// formatTableDuration formats a duration for the comparison table, showing
+// "< 1µs" for durations too short to measure.
+func formatTableDuration(d time.Duration) string {
+	if d == 0 {
+		return "< 1µs"
+	}
+	return FormatExecutionDuration(d)
+}
+
+// formatCPUTime formats the CPU time of a calculation for the comparison
+// table, or "n/a" when its resource usage is not available.
+func formatCPUTime(usage orchestration.ResourceUsage) string {
+	if !usage.Available() {
+		return "n/a"
+	}
+	return formatTableDuration(usage.CPUTime()) + sharedSuffix(usage)
+}
+
+// sharedSuffix labels the figures of a usage shared with other calculations.
+func sharedSuffix(usage orchestration.ResourceUsage) string {
+	if usage.Shared {
+		return " (shared)"
+	}
+	return ""
+}
+
+// FormatEfficiency formats the parallel efficiency of a calculation as a
+// percentage, labelled "(shared)" when it includes other calculations, or
+// "n/a" when its resource usage is not available (see
+// orchestration.ResourceUsage.Available).
+//
+// Parameters:
+//   - usage: The resources consumed by the calculation.
+//
+// Returns:
+//   - string: The formatted efficiency.
+func FormatEfficiency(usage orchestration.ResourceUsage) string {
+	if !usage.Available() {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f%%", usage.ParallelEfficiency*100) + sharedSuffix(usage)
 }
 
 // padRight returns a string of spaces with the given length.
@@ -92,6 +152,63 @@ func padRight(s string, length int) string {
 // DisplayResult function.
 func (CLIResultPresenter) PresentResult(result orchestration.CalculationResult, n uint64, verbose, details, concise bool, out io.Writer) {
 	DisplayResult(result.Result, n, result.Duration, verbose, details, concise, out)
+	if details {
+		DisplayResourceUsage(out, result.Resources)
+	}
+}
+
+// DisplayResourceUsage prints the resources consumed by a calculation: CPU
+// time, parallel efficiency, peak RSS growth, allocations and GC activity.
+//
+// Parameters:
+//   - out: The io.Writer for the output.
+//   - usage: The resources consumed by the calculation.
+func DisplayResourceUsage(out io.Writer, usage orchestration.ResourceUsage) {
+	fmt.Fprintf(out, "\n%s--- Resource usage ---%s\n", ui.ColorBold(), ui.ColorReset())
+	if !usage.Available() {
+		fmt.Fprintf(out, "Not available for this calculation.\n")
+		return
+	}
+	if usage.Shared {
+		fmt.Fprintf(out, "Shared: other calculations ran concurrently, these are process-wide totals.\n")
+	}
+	fmt.Fprintf(out, "CPU time (user/system)  : %s%s / %s%s\n",
+		ui.ColorGreen(), formatTableDuration(usage.UserCPU), formatTableDuration(usage.SystemCPU), ui.ColorReset())
+	fmt.Fprintf(out, "Parallel efficiency     : %s%.0f%%%s (%d cores)\n",
+		ui.ColorCyan(), usage.ParallelEfficiency*100, ui.ColorReset(), runtime.GOMAXPROCS(0))
+	fmt.Fprintf(out, "Peak RSS increase       : %s%s%s\n",
+		ui.ColorCyan(), FormatBytes(uint64(max(usage.PeakRSSDelta, 0))), ui.ColorReset())
+	fmt.Fprintf(out, "Bytes allocated         : %s%s%s\n",
+		ui.ColorCyan(), FormatBytes(usage.BytesAllocated), ui.ColorReset())
+	fmt.Fprintf(out, "GC cycles (pause)       : %s%d%s (%s)\n",
+		ui.ColorCyan(), usage.GCCycles, ui.ColorReset(), formatTableDuration(usage.GCPause))
+}
+
+// PresentRaceSummary displays the winner of a race and its margin over each
+// other calculation: measured if it finished, estimated from its progress if
+// it was canceled.
+func (CLIResultPresenter) PresentRaceSummary(winner orchestration.CalculationResult, others []orchestration.CalculationResult, out io.Writer) {
+	fmt.Fprintf(out, "\n--- Race Summary ---\n")
+	fmt.Fprintf(out, "🏁 Winner: %s%s%s in %s%s%s\n",
+		ui.ColorGreen(), winner.Name, ui.ColorReset(),
+		ui.ColorYellow(), formatTableDuration(winner.Duration), ui.ColorReset())
+	for _, res := range others {
+		margin, ok := orchestration.RaceMargin(winner, res)
+		switch {
+		case res.Err == nil:
+			fmt.Fprintf(out, "   %s%s%s: finished in %s (+%s)\n",
+				ui.ColorBlue(), res.Name, ui.ColorReset(), formatTableDuration(res.Duration), formatTableDuration(margin))
+		case !errors.Is(res.Err, context.Canceled):
+			fmt.Fprintf(out, "   %s%s%s: %sfailed (%v)%s\n",
+				ui.ColorBlue(), res.Name, ui.ColorReset(), ui.ColorRed(), res.Err, ui.ColorReset())
+		case ok:
+			fmt.Fprintf(out, "   %s%s%s: canceled at %.0f%% (≈ +%s behind)\n",
+				ui.ColorBlue(), res.Name, ui.ColorReset(), res.Progress*100, formatTableDuration(margin))
+		default:
+			fmt.Fprintf(out, "   %s%s%s: canceled before reporting progress\n",
+				ui.ColorBlue(), res.Name, ui.ColorReset())
+		}
+	}
 }
 
 // FormatDuration formats a duration for display using the CLI's standard
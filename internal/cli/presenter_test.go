@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// TestPresentResourceUsage verifies that resource usage appears in the
+// comparison table and in the detailed result.
+func TestPresentResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	res := orchestration.CalculationResult{
+		Name:     "Fast",
+		Result:   big.NewInt(55),
+		Duration: 100 * time.Millisecond,
+		Resources: orchestration.ResourceUsage{
+			UserCPU:            300 * time.Millisecond,
+			SystemCPU:          100 * time.Millisecond,
+			PeakRSSDelta:       2048,
+			BytesAllocated:     1 << 20,
+			GCCycles:           3,
+			GCPause:            time.Millisecond,
+			ParallelEfficiency: 0.5,
+		},
+	}
+
+	var table bytes.Buffer
+	CLIResultPresenter{}.PresentComparisonTable([]orchestration.CalculationResult{res}, &table)
+	for _, want := range []string{"CPU Time", "Efficiency", "400ms", "50%"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("comparison table missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var details bytes.Buffer
+	CLIResultPresenter{}.PresentResult(res, 10, false, true, false, &details)
+	for _, want := range []string{"Resource usage", "300ms / 100ms", "GC cycles", FormatBytes(1 << 20)} {
+		if !strings.Contains(details.String(), want) {
+			t.Errorf("details missing %q:\n%s", want, details.String())
+		}
+	}
+
+	var plain bytes.Buffer
+	CLIResultPresenter{}.PresentResult(res, 10, false, false, false, &plain)
+	if strings.Contains(plain.String(), "Resource usage") {
+		t.Error("resource usage should only be shown with details")
+	}
+
+	// Overlapped calculations report shared process-wide totals
+	res.Resources.Shared = true
+	table.Reset()
+	CLIResultPresenter{}.PresentComparisonTable([]orchestration.CalculationResult{res}, &table)
+	for _, want := range []string{"400ms (shared)", "50% (shared)", "--sequential"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("comparison table missing %q:\n%s", want, table.String())
+		}
+	}
+	details.Reset()
+	CLIResultPresenter{}.PresentResult(res, 10, false, true, false, &details)
+	if !strings.Contains(details.String(), "Shared") || !strings.Contains(details.String(), "GC cycles") {
+		t.Errorf("expected the shared resource usage:\n%s", details.String())
+	}
+
+	// Calculations on a server have no resource usage
+	res.Resources = orchestration.ResourceUsage{}
+	table.Reset()
+	CLIResultPresenter{}.PresentComparisonTable([]orchestration.CalculationResult{res}, &table)
+	if got := strings.Count(table.String(), "n/a"); got != 2 {
+		t.Errorf("expected CPU time and efficiency not available:\n%s", table.String())
+	}
+	details.Reset()
+	CLIResultPresenter{}.PresentResult(res, 10, false, true, false, &details)
+	if !strings.Contains(details.String(), "Not available") || strings.Contains(details.String(), "GC cycles") {
+		t.Errorf("expected the resource usage not available:\n%s", details.String())
+	}
+}
+
+// TestPresentRaceSummary verifies the winner and margins of a race.
+func TestPresentRaceSummary(t *testing.T) {
+	t.Parallel()
+
+	ms := time.Millisecond
+	winner := orchestration.CalculationResult{Name: "Fast", Result: big.NewInt(55), Duration: 100 * ms, Progress: 1}
+	others := []orchestration.CalculationResult{
+		{Name: "Matrix", Duration: 100 * ms, Progress: 0.5, Err: fmt.Errorf("canceled: %w", context.Canceled)},
+		{Name: "FFT", Result: big.NewInt(55), Duration: 130 * ms, Progress: 1},
+		{Name: "Broken", Duration: 10 * ms, Err: errors.New("boom")},
+		{Name: "Late", Duration: 100 * ms, Err: context.Canceled},
+	}
+
+	var out bytes.Buffer
+	CLIResultPresenter{}.PresentRaceSummary(winner, others, &out)
+	for _, want := range []string{
+		"Winner:", "Fast",
+		": canceled at 50% (≈ +100ms behind)",
+		": finished in 130ms (+30ms)",
+		"failed (boom)",
+		": canceled before reporting progress",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("race summary missing %q:\n%s", want, out.String())
+		}
+	}
+}
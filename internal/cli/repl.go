@@ -3,11 +3,11 @@
 package cli
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"strconv"
 	"strings"
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/resultfile"
 	"github.com/agbru/fibcalc/internal/ui"
 )
 
@@ -30,15 +31,34 @@ type REPLConfig struct {
 	FFTThreshold int
 	// HexOutput displays results in hexadecimal format.
 	HexOutput bool
+	// StrassenThreshold is the Strassen matrix multiplication threshold.
+	StrassenThreshold int
+	// Estimator, if set, provides duration estimates for the "explain" command.
+	Estimator PlanEstimator
+	// HistoryFile is the file the line history is persisted to when the
+	// REPL runs in a terminal. If empty, the history is kept in memory.
+	HistoryFile string
 }
 
+// PlanEstimator predicts the duration of computing F(n) with an algorithm.
+// It returns the estimate, a description of its source, and false if no
+// estimate is available.
+type PlanEstimator func(n uint64, algo string) (time.Duration, string, bool)
+
 // REPL represents an interactive Fibonacci calculator session.
 type REPL struct {
 	config      REPLConfig
 	registry    map[string]fibonacci.Calculator
 	currentAlgo string
+	vars        map[string]exprValue
+	jobs        *jobTable
 	in          io.Reader
 	out         io.Writer
+
+	// interrupts relays Ctrl-C during foreground calculations.
+	interrupts func() (<-chan os.Signal, func())
+	// failure records why the last command failed, for scripts.
+	failure *commandFailure
 }
 
 // NewREPL creates a new REPL instance.
@@ -51,7 +71,7 @@ type REPL struct {
 //   - *REPL: A new REPL instance.
 func NewREPL(registry map[string]fibonacci.Calculator, config REPLConfig) *REPL {
 	currentAlgo := config.DefaultAlgo
-	if currentAlgo == "" || currentAlgo == "all" {
+	if currentAlgo == "" || currentAlgo == "all" || currentAlgo == "auto" {
 		// Pick the first available algorithm as default
 		for name := range registry {
 			currentAlgo = name
@@ -63,8 +83,11 @@ func NewREPL(registry map[string]fibonacci.Calculator, config REPLConfig) *REPL
 		config:      config,
 		registry:    registry,
 		currentAlgo: currentAlgo,
+		vars:        make(map[string]exprValue),
+		jobs:        newJobTable(),
 		in:          os.Stdin,
 		out:         os.Stdout,
+		interrupts:  notifyInterrupt,
 	}
 }
 
@@ -80,24 +103,27 @@ func (r *REPL) SetOutput(out io.Writer) {
 
 // Start begins the interactive REPL session.
 // It continuously reads user input and processes commands until
-// the user exits or EOF is reached.
+// the user exits or EOF is reached. In a terminal, lines are edited with
+// cursor movement, history (persisted to REPLConfig.HistoryFile), reverse
+// search (Ctrl-R) and tab completion. Background jobs still running are
+// canceled when the session ends.
 func (r *REPL) Start() {
 	r.printBanner()
 	r.printHelp()
 	fmt.Fprintln(r.out)
 
-	reader := bufio.NewReader(r.in)
+	lines := r.newLineReader()
+	defer r.stopJobs()
 
 	for {
-		fmt.Fprint(r.out, ui.ColorGreen()+"fib> "+ui.ColorReset())
-
-		input, err := reader.ReadString('\n')
+		r.reportJobs()
+		input, err := lines.ReadLine(ui.ColorGreen() + "fib> " + ui.ColorReset())
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				fmt.Fprintln(r.out, "\nGoodbye!")
 				return
 			}
-			fmt.Fprintf(r.out, "%sRead error: %v%s\n", ui.ColorRed(), err, ui.ColorReset())
+			r.errorf("Read error: %v", err)
 			continue
 		}
 
@@ -123,9 +149,21 @@ func (r *REPL) printBanner() {
 // printHelp displays available commands.
 func (r *REPL) printHelp() {
 	fmt.Fprintf(r.out, "%sAvailable commands:%s\n", ui.ColorBold(), ui.ColorReset())
-	fmt.Fprintf(r.out, "  %scalc <n>%s      - Calculate F(n) with current algorithm\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %scalc <n>%s      - Calculate F(n) with current algorithm (Ctrl-C cancels it)\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %scalc <n> &%s    - Calculate F(n) in the background\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %sjobs%s          - List background calculations with their progress\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %swait <job>%s    - Wait for a background calculation and display it\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %scancel <job>%s  - Cancel a background calculation\n", ui.ColorYellow(), ui.ColorReset())
 	fmt.Fprintf(r.out, "  %salgo <name>%s   - Change algorithm (%s)\n", ui.ColorYellow(), ui.ColorReset(), r.getAlgoList())
 	fmt.Fprintf(r.out, "  %scompare <n>%s   - Compare all algorithms for F(n)\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %sexplain <n>%s   - Show the execution plan for F(n) without computing it\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %sload <file>%s   - Load and verify a saved result file\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %s<expr>%s        - Evaluate an expression, e.g. x = fib(1000) mod 97\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "                  operators: + - * / mod ^ == != < <= > >=\n")
+	fmt.Fprintf(r.out, "                  functions: %s\n", strings.Join(builtinNames(), ", "))
+	fmt.Fprintf(r.out, "  %svars%s          - List variables (last, last_n and last_ms describe the last calculation)\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %sassert <expr>%s - Check that an expression is true, e.g. assert digits(last) == 209\n", ui.ColorYellow(), ui.ColorReset())
+	fmt.Fprintf(r.out, "  %sset <opt> <v>%s - Set algo, timeout, threshold, fft-threshold, strassen-threshold or hex\n", ui.ColorYellow(), ui.ColorReset())
 	fmt.Fprintf(r.out, "  %slist%s          - List available algorithms\n", ui.ColorYellow(), ui.ColorReset())
 	fmt.Fprintf(r.out, "  %shex%s           - Toggle hexadecimal display\n", ui.ColorYellow(), ui.ColorReset())
 	fmt.Fprintf(r.out, "  %sstatus%s        - Display current configuration\n", ui.ColorYellow(), ui.ColorReset())
@@ -143,16 +181,32 @@ func (r *REPL) getAlgoList() string {
 }
 
 // processCommand parses and executes a user command.
+// A line is a command if its first word is a command name, and an
+// expression otherwise; a lone number calculates F(n).
 // Returns false if the REPL should exit.
 func (r *REPL) processCommand(input string) bool {
+	r.failure = nil
 	parts := strings.Fields(input)
 	if len(parts) == 0 {
 		return true
 	}
 
+	// A trailing "&" runs the calculation in the background
+	if background, ok := strings.CutSuffix(input, "&"); ok {
+		r.cmdBackground(strings.Fields(background))
+		return true
+	}
+
 	cmd := strings.ToLower(parts[0])
 	args := parts[1:]
 
+	// Assignments take precedence, so that variables may be named like
+	// command aliases (e.g. "c = 3")
+	if isAssignment(input) {
+		r.cmdEval(input)
+		return true
+	}
+
 	switch cmd {
 	case "calc", "c":
 		r.cmdCalc(args)
@@ -160,6 +214,22 @@ func (r *REPL) processCommand(input string) bool {
 		r.cmdAlgo(args)
 	case "compare", "cmp":
 		r.cmdCompare(args)
+	case "explain", "ex":
+		r.cmdExplain(args)
+	case "load":
+		r.cmdLoad(args)
+	case "vars":
+		r.cmdVars()
+	case "assert":
+		r.cmdAssert(strings.TrimSpace(strings.TrimSpace(input)[len(parts[0]):]))
+	case "set":
+		r.cmdSet(args)
+	case "jobs":
+		r.cmdJobs()
+	case "wait":
+		r.cmdWait(args)
+	case "cancel":
+		r.cmdCancel(args)
 	case "list", "ls":
 		r.cmdList()
 	case "hex":
@@ -173,27 +243,47 @@ func (r *REPL) processCommand(input string) bool {
 		return false
 	default:
 		// Try to interpret as a number for quick calculation
-		if n, err := strconv.ParseUint(cmd, 10, 64); err == nil {
+		if n, err := strconv.ParseUint(cmd, 10, 64); err == nil && len(parts) == 1 {
 			r.calculate(n)
-		} else {
-			fmt.Fprintf(r.out, "%sUnknown command: %s%s\n", ui.ColorRed(), cmd, ui.ColorReset())
+		} else if _, isVar := r.vars[parts[0]]; len(parts) == 1 && !isVar && isIdentifier(parts[0]) {
+			r.errorf("Unknown command: %s", cmd)
 			fmt.Fprintf(r.out, "Type %shelp%s to see available commands.\n", ui.ColorYellow(), ui.ColorReset())
+		} else {
+			r.cmdEval(input)
 		}
 	}
 
 	return true
 }
 
+// cmdBackground handles "calc <n> &" and "<n> &", given the words before
+// the "&".
+func (r *REPL) cmdBackground(words []string) {
+	if len(words) > 0 && (strings.EqualFold(words[0], "calc") || strings.EqualFold(words[0], "c")) {
+		words = words[1:]
+	}
+	if len(words) != 1 {
+		r.errorf("Usage: calc <n> &")
+		return
+	}
+	n, err := strconv.ParseUint(words[0], 10, 64)
+	if err != nil {
+		r.errorf("Invalid value: %s", words[0])
+		return
+	}
+	r.startJob(n)
+}
+
 // cmdCalc handles the "calc" command.
 func (r *REPL) cmdCalc(args []string) {
 	if len(args) == 0 {
-		fmt.Fprintf(r.out, "%sUsage: calc <n>%s\n", ui.ColorRed(), ui.ColorReset())
+		r.errorf("Usage: calc <n>")
 		return
 	}
 
 	n, err := strconv.ParseUint(args[0], 10, 64)
 	if err != nil {
-		fmt.Fprintf(r.out, "%sInvalid value: %s%s\n", ui.ColorRed(), args[0], ui.ColorReset())
+		r.errorf("Invalid value: %s", args[0])
 		return
 	}
 
@@ -204,22 +294,19 @@ func (r *REPL) cmdCalc(args []string) {
 func (r *REPL) calculate(n uint64) {
 	calc, ok := r.registry[r.currentAlgo]
 	if !ok {
-		fmt.Fprintf(r.out, "%sAlgorithm not found: %s%s\n", ui.ColorRed(), r.currentAlgo, ui.ColorReset())
+		r.errorf("Algorithm not found: %s", r.currentAlgo)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+	ctx, cancel := r.timeoutContext()
 	defer cancel()
+	ctx, stop := r.interruptible(ctx)
+	defer stop()
 
 	fmt.Fprintf(r.out, "Calculating F(%s%d%s) with %s%s%s...\n",
 		ui.ColorMagenta(), n, ui.ColorReset(),
 		ui.ColorCyan(), calc.Name(), ui.ColorReset())
 
-	opts := fibonacci.Options{
-		ParallelThreshold: r.config.Threshold,
-		FFTThreshold:      r.config.FFTThreshold,
-	}
-
 	// Create a progress channel
 	progressChan := make(chan fibonacci.ProgressUpdate, 10)
 
@@ -229,16 +316,26 @@ func (r *REPL) calculate(n uint64) {
 	go DisplayProgress(&wg, progressChan, 1, r.out)
 
 	start := time.Now()
-	result, err := calc.Calculate(ctx, progressChan, 0, n, opts)
+	result, err := calc.Calculate(ctx, progressChan, 0, n, r.options())
 	duration := time.Since(start)
 	close(progressChan)
 	wg.Wait()
 
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintf(r.out, "\n%sCalculation canceled.%s\n", ui.ColorYellow(), ui.ColorReset())
+		r.fail("calculation canceled")
+		return
+	}
 	if err != nil {
-		fmt.Fprintf(r.out, "%sError: %v%s\n", ui.ColorRed(), err, ui.ColorReset())
+		r.errorf("Error: %v", err)
 		return
 	}
+	r.setLast(n, result, duration)
+	r.displayResult(n, result, duration)
+}
 
+// displayResult prints the duration, size and value of F(n).
+func (r *REPL) displayResult(n uint64, result *big.Int, duration time.Duration) {
 	// Format duration
 	durationStr := FormatExecutionDuration(duration)
 
@@ -247,6 +344,22 @@ func (r *REPL) calculate(n uint64) {
 	fmt.Fprintf(r.out, "  Time: %s%s%s\n", ui.ColorGreen(), durationStr, ui.ColorReset())
 	fmt.Fprintf(r.out, "  Bits:  %s%d%s\n", ui.ColorCyan(), result.BitLen(), ui.ColorReset())
 
+	r.displayValue(n, result)
+	fmt.Fprintln(r.out)
+}
+
+// options returns the calculation options of the session.
+func (r *REPL) options() fibonacci.Options {
+	return fibonacci.Options{
+		ParallelThreshold: r.config.Threshold,
+		FFTThreshold:      r.config.FFTThreshold,
+		StrassenThreshold: r.config.StrassenThreshold,
+	}
+}
+
+// displayValue prints the number of digits of F(n) and its value, truncated
+// if long, in decimal or hexadecimal.
+func (r *REPL) displayValue(n uint64, result *big.Int) {
 	resultStr := result.String()
 	numDigits := len(resultStr)
 	fmt.Fprintf(r.out, "  Digits: %s%d%s\n", ui.ColorCyan(), numDigits, ui.ColorReset())
@@ -265,20 +378,63 @@ func (r *REPL) calculate(n uint64) {
 	} else {
 		fmt.Fprintf(r.out, "  F(%d) = %s%s%s\n", n, ui.ColorGreen(), resultStr, ui.ColorReset())
 	}
+}
+
+// cmdLoad handles the "load" command.
+// It reads a result file in any supported format and displays its metadata
+// and value.
+func (r *REPL) cmdLoad(args []string) {
+	if len(args) == 0 {
+		r.errorf("Usage: load <file>")
+		return
+	}
+
+	path := strings.Join(args, " ")
+	loaded, err := resultfile.Load(path)
+	if err != nil {
+		r.errorf("Error: %v", err)
+		return
+	}
+	DisplayResultFileInfo(r.out, path, loaded)
+	fmt.Fprintln(r.out)
+	r.displayValue(loaded.N, loaded.Value)
 	fmt.Fprintln(r.out)
 }
 
+// cmdExplain handles the "explain" command.
+// It displays the execution plan of F(n) for the current algorithm.
+func (r *REPL) cmdExplain(args []string) {
+	if len(args) == 0 {
+		r.errorf("Usage: explain <n>")
+		return
+	}
+
+	n, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		r.errorf("Invalid value: %s", args[0])
+		return
+	}
+
+	plan := fibonacci.PlanCalculation(n, r.currentAlgo, r.options())
+	if r.config.Estimator != nil {
+		if d, source, ok := r.config.Estimator(n, r.currentAlgo); ok {
+			plan.EstimatedDuration, plan.EstimateSource = d, source
+		}
+	}
+	DisplayExecutionPlan(r.out, plan)
+}
+
 // cmdAlgo handles the "algo" command.
 func (r *REPL) cmdAlgo(args []string) {
 	if len(args) == 0 {
-		fmt.Fprintf(r.out, "%sUsage: algo <name>%s\n", ui.ColorRed(), ui.ColorReset())
+		r.errorf("Usage: algo <name>")
 		fmt.Fprintf(r.out, "Available algorithms: %s\n", r.getAlgoList())
 		return
 	}
 
 	name := strings.ToLower(args[0])
 	if _, ok := r.registry[name]; !ok {
-		fmt.Fprintf(r.out, "%sUnknown algorithm: %s%s\n", ui.ColorRed(), name, ui.ColorReset())
+		r.errorf("Unknown algorithm: %s", name)
 		fmt.Fprintf(r.out, "Available algorithms: %s\n", r.getAlgoList())
 		return
 	}
@@ -290,29 +446,34 @@ func (r *REPL) cmdAlgo(args []string) {
 // cmdCompare handles the "compare" command.
 func (r *REPL) cmdCompare(args []string) {
 	if len(args) == 0 {
-		fmt.Fprintf(r.out, "%sUsage: compare <n>%s\n", ui.ColorRed(), ui.ColorReset())
+		r.errorf("Usage: compare <n>")
 		return
 	}
 
 	n, err := strconv.ParseUint(args[0], 10, 64)
 	if err != nil {
-		fmt.Fprintf(r.out, "%sInvalid value: %s%s\n", ui.ColorRed(), args[0], ui.ColorReset())
+		r.errorf("Invalid value: %s", args[0])
 		return
 	}
 
 	fmt.Fprintf(r.out, "\n%sComparison for F(%d):%s\n", ui.ColorBold(), n, ui.ColorReset())
 	fmt.Fprintf(r.out, "%s─────────────────────────────────────────────%s\n", ui.ColorCyan(), ui.ColorReset())
 
-	opts := fibonacci.Options{
-		ParallelThreshold: r.config.Threshold,
-		FFTThreshold:      r.config.FFTThreshold,
-	}
+	opts := r.options()
+
+	// Ctrl-C cancels the remaining calculations
+	interrupted, stop := r.interruptible(context.Background())
+	defer stop()
 
 	results := make(map[string]string)
 	var firstResult string
 
 	for name, calc := range r.registry {
-		ctx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+		if interrupted.Err() != nil {
+			fmt.Fprintf(r.out, "  %sComparison canceled.%s\n", ui.ColorYellow(), ui.ColorReset())
+			break
+		}
+		ctx, cancel := context.WithTimeout(interrupted, r.config.Timeout)
 
 		// Create a progress channel for this calculation
 		progressChan := make(chan fibonacci.ProgressUpdate, 10)
@@ -332,6 +493,7 @@ func (r *REPL) cmdCompare(args []string) {
 			fmt.Fprintf(r.out, "  %s%-20s%s: %sError - %v%s\n",
 				ui.ColorYellow(), name, ui.ColorReset(),
 				ui.ColorRed(), err, ui.ColorReset())
+			r.fail(fmt.Sprintf("%s: %v", name, err))
 			continue
 		}
 
@@ -347,6 +509,7 @@ func (r *REPL) cmdCompare(args []string) {
 		status := ui.ColorGreen() + "✓" + ui.ColorReset()
 		if resultStr != firstResult {
 			status = ui.ColorRed() + "✗ INCONSISTENT" + ui.ColorReset()
+			r.fail(name + ": inconsistent result")
 		}
 
 		fmt.Fprintf(r.out, "  %s%-20s%s: %s%12s%s %s\n",
@@ -388,6 +551,7 @@ func (r *REPL) cmdStatus() {
 	fmt.Fprintf(r.out, "  Timeout:        %s%s%s\n", ui.ColorCyan(), r.config.Timeout, ui.ColorReset())
 	fmt.Fprintf(r.out, "  Threshold:      %s%d%s bits\n", ui.ColorCyan(), r.config.Threshold, ui.ColorReset())
 	fmt.Fprintf(r.out, "  FFT Threshold:  %s%d%s bits\n", ui.ColorCyan(), r.config.FFTThreshold, ui.ColorReset())
+	fmt.Fprintf(r.out, "  Strassen:       %s%d%s bits\n", ui.ColorCyan(), r.config.StrassenThreshold, ui.ColorReset())
 	hexStatus := "no"
 	if r.config.HexOutput {
 		hexStatus = "yes"
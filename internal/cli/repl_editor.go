@@ -0,0 +1,373 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// DefaultHistoryFileName is the name of the REPL history file in the
+// user's home directory.
+const DefaultHistoryFileName = ".fibcalc_history"
+
+// MaxHistoryEntries bounds the number of lines kept in the REPL history.
+const MaxHistoryEntries = 1000
+
+// Keys handled by the line editor on top of golang.org/x/term.
+const (
+	keyTab   = '\t'
+	keyCtrlC = 'C' - '@'
+	keyCtrlR = 'R' - '@'
+	// keyInterrupt stands for Ctrl-C, which term.Terminal would otherwise
+	// report as io.EOF. It is a private-use code point, so no key produces
+	// it.
+	keyInterrupt = '\uE003'
+)
+
+// DefaultHistoryPath returns the default path of the REPL history file.
+func DefaultHistoryPath() string {
+	return HomeFilePath(DefaultHistoryFileName)
+}
+
+// HomeFilePath returns the path of a file in the user's home directory, or
+// in the current directory if the home directory is unknown.
+//
+// Parameters:
+//   - name: The file name.
+//
+// Returns:
+//   - string: The path of the file.
+func HomeFilePath(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(home, name)
+}
+
+// lineReader reads the input lines of a REPL session.
+type lineReader interface {
+	// ReadLine displays prompt and returns the next line, without its line
+	// terminator, or io.EOF at the end of the input.
+	ReadLine(prompt string) (string, error)
+}
+
+// newLineReader returns a line editor when the REPL reads from and writes
+// to a terminal, and a plain line reader otherwise.
+func (r *REPL) newLineReader() lineReader {
+	in, inOK := r.in.(*os.File)
+	out, outOK := r.out.(*os.File)
+	if inOK && outOK && term.IsTerminal(int(in.Fd())) && term.IsTerminal(int(out.Fd())) {
+		return newTerminalReader(int(in.Fd()), in, out, loadHistory(r.config.HistoryFile, MaxHistoryEntries), r.complete)
+	}
+	return &plainReader{in: bufio.NewReader(r.in), out: r.out}
+}
+
+// ─── Plain input ───
+
+// plainReader reads lines without editing, for input that is not a
+// terminal (pipes, files, tests).
+type plainReader struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ReadLine implements lineReader.
+func (p *plainReader) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(p.out, prompt)
+	line, err := p.in.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ─── Terminal input ───
+
+// terminalReader edits lines with golang.org/x/term, which provides cursor
+// movement and history navigation, and adds tab completion and reverse
+// history search (Ctrl-R). The terminal is in raw mode only while a line is
+// read, so that the output of commands is unaffected.
+type terminalReader struct {
+	fd       int
+	terminal *term.Terminal
+	history  *fileHistory
+	complete completer
+	prompt   string
+
+	// search is the state of the reverse search: the query, the line it
+	// produced and the history index to continue from.
+	search struct {
+		query, match string
+		next         int
+	}
+}
+
+// completer completes the word before pos in line. It returns the new line
+// and cursor position, and the candidates to list if the word is ambiguous.
+type completer func(line string, pos int) (string, int, []string)
+
+// newTerminalReader returns a line editor reading keys from in and echoing
+// them to out; fd is the terminal put in raw mode while a line is read.
+func newTerminalReader(fd int, in io.Reader, out io.Writer, history *fileHistory, complete completer) *terminalReader {
+	t := &terminalReader{
+		fd: fd,
+		terminal: term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{&interruptReader{r: in}, out}, ""),
+		history:  history,
+		complete: complete,
+	}
+	t.terminal.History = history
+	t.terminal.AutoCompleteCallback = t.handleKey
+	return t
+}
+
+// ReadLine implements lineReader. Ctrl-D on an empty line ends the session,
+// as EOF does; Ctrl-C abandons the line being edited.
+func (t *terminalReader) ReadLine(prompt string) (string, error) {
+	state, err := term.MakeRaw(t.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(t.fd, state)
+
+	if width, height, err := term.GetSize(t.fd); err == nil {
+		t.terminal.SetSize(width, height)
+	}
+	t.prompt = prompt
+	t.terminal.SetPrompt(prompt)
+	line, err := t.terminal.ReadLine()
+	if errors.Is(err, term.ErrPasteIndicator) {
+		err = nil
+	}
+	return line, err
+}
+
+// handleKey is the term.Terminal callback for the keys it does not handle.
+func (t *terminalReader) handleKey(line string, pos int, key rune) (string, int, bool) {
+	switch key {
+	case keyTab:
+		newLine, newPos, candidates := t.complete(line, pos)
+		if len(candidates) > 0 {
+			fmt.Fprintln(t.terminal, strings.Join(candidates, "  "))
+		}
+		return newLine, newPos, true
+	case keyCtrlR:
+		return t.reverseSearch(line)
+	case keyInterrupt:
+		// Leave the abandoned line on screen and start an empty one
+		fmt.Fprintln(t.terminal, t.prompt+line+"^C")
+		t.search.query, t.search.match = "", ""
+		return "", 0, true
+	}
+	return "", 0, false
+}
+
+// interruptReader replaces Ctrl-C with keyInterrupt in the input of
+// term.Terminal, so that it reaches handleKey instead of ending the session.
+type interruptReader struct {
+	r       io.Reader
+	pending []byte
+	err     error
+}
+
+// Read implements io.Reader.
+func (c *interruptReader) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		buf := make([]byte, len(p))
+		var n int
+		n, c.err = c.r.Read(buf)
+		for _, b := range buf[:n] {
+			if b == keyCtrlC {
+				c.pending = utf8.AppendRune(c.pending, keyInterrupt)
+			} else {
+				c.pending = append(c.pending, b)
+			}
+		}
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// reverseSearch replaces line with the most recent history entry containing
+// it. Pressing Ctrl-R again, without editing the line, continues with older
+// entries for the same query.
+func (t *terminalReader) reverseSearch(line string) (string, int, bool) {
+	if line != t.search.match || t.search.query == "" {
+		t.search.query, t.search.next = line, 0
+	}
+	for i := t.search.next; i < t.history.Len(); i++ {
+		if entry := t.history.At(i); entry != line && strings.Contains(entry, t.search.query) {
+			t.search.match, t.search.next = entry, i+1
+			return entry, len(entry), true
+		}
+	}
+	return line, len(line), true
+}
+
+// ─── History ───
+
+// fileHistory is a term.History persisted to a file, one entry per line.
+// Persistence is best effort: the history stays usable in memory if the
+// file cannot be read or written.
+type fileHistory struct {
+	path    string
+	max     int
+	entries []string // oldest first
+}
+
+// loadHistory reads the last max entries of the history file at path. An
+// empty path keeps the history in memory only.
+func loadHistory(path string, max int) *fileHistory {
+	h := &fileHistory{path: path, max: max}
+	if path == "" {
+		return h
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if n := len(h.entries); n > max {
+		h.entries = h.entries[n-max:]
+		// Compact the file once it holds twice the entries kept
+		if n > 2*max {
+			_ = os.WriteFile(path, []byte(strings.Join(h.entries, "\n")+"\n"), 0600)
+		}
+	}
+	return h
+}
+
+// Add implements term.History. Blank lines and repetitions of the last entry
+// are not recorded.
+func (h *fileHistory) Add(entry string) {
+	if strings.TrimSpace(entry) == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.max {
+		h.entries = h.entries[1:]
+	}
+	if h.path == "" {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(f, entry)
+	f.Close()
+}
+
+// Len implements term.History.
+func (h *fileHistory) Len() int {
+	return len(h.entries)
+}
+
+// At implements term.History: index 0 is the most recent entry.
+func (h *fileHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+// ─── Completion ───
+
+// replCommands are the command names offered by tab completion.
+var replCommands = []string{"algo", "assert", "calc", "cancel", "compare", "exit", "explain", "help", "hex", "jobs", "list", "load", "quit", "set", "status", "vars", "wait"}
+
+// completion is a tab completion candidate and the text inserted after it
+// when it is the only one.
+type completion struct {
+	word, suffix string
+}
+
+// complete implements completer: command names, variables and built-in
+// functions at the start of a line, algorithm names after "algo", and
+// variables and built-in functions in expressions.
+func (r *REPL) complete(line string, pos int) (string, int, []string) {
+	start := pos
+	for start > 0 && isWordByte(line[start-1]) {
+		start--
+	}
+	prefix := line[start:pos]
+	before := strings.Fields(line[:start])
+
+	var candidates []completion
+	switch {
+	case len(before) == 1 && (strings.EqualFold(before[0], "algo") || strings.EqualFold(before[0], "a")):
+		for name := range r.registry {
+			candidates = append(candidates, completion{name, ""})
+		}
+	case len(before) == 0:
+		for _, cmd := range replCommands {
+			candidates = append(candidates, completion{cmd, " "})
+		}
+		fallthrough
+	default:
+		for _, name := range builtinNames() {
+			candidates = append(candidates, completion{name, "("})
+		}
+		for name := range r.vars {
+			candidates = append(candidates, completion{name, ""})
+		}
+	}
+
+	var matches []completion
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if strings.HasPrefix(c.word, prefix) && !seen[c.word] {
+			seen[c.word] = true
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return line, pos, nil
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].word < matches[j].word })
+
+	insert := matches[0].word + matches[0].suffix
+	var list []string
+	if len(matches) > 1 {
+		insert = matches[0].word
+		for _, m := range matches[1:] {
+			insert = commonPrefix(insert, m.word)
+		}
+		if insert == prefix {
+			for _, m := range matches {
+				list = append(list, m.word)
+			}
+		}
+	}
+	return line[:start] + insert + line[pos:], start + len(insert), list
+}
+
+// isWordByte reports whether c may be part of a completed word.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+func TestFileHistory(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(path, []byte("calc 1\n\ncalc 2\ncalc 3\ncalc 4\ncalc 5\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file holds more than twice the entries kept: it is compacted.
+	h := loadHistory(path, 2)
+	if h.Len() != 2 || h.At(0) != "calc 5" || h.At(1) != "calc 4" {
+		t.Fatalf("Unexpected history %v", h.entries)
+	}
+	h.Add("calc 6")
+	h.Add("calc 6")
+	h.Add("   ")
+	if h.Len() != 2 || h.At(0) != "calc 6" || h.At(1) != "calc 5" {
+		t.Errorf("Unexpected history after Add %v", h.entries)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "calc 4\ncalc 5\ncalc 6\n" {
+		t.Errorf("Unexpected history file %q", got)
+	}
+	if h := loadHistory(path, 10); h.Len() != 3 {
+		t.Errorf("Expected 3 entries after reload, got %v", h.entries)
+	}
+
+	// Without a file, the history is kept in memory
+	h = loadHistory("", 10)
+	h.Add("vars")
+	if h.Len() != 1 || h.At(0) != "vars" {
+		t.Errorf("Unexpected in-memory history %v", h.entries)
+	}
+}
+
+func TestHomeFilePath(t *testing.T) {
+	t.Parallel()
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	if got, want := HomeFilePath(".fibcalc_test"), filepath.Join(home, ".fibcalc_test"); got != want {
+		t.Errorf("HomeFilePath = %q, want %q", got, want)
+	}
+	if got := DefaultHistoryPath(); got != HomeFilePath(DefaultHistoryFileName) {
+		t.Errorf("DefaultHistoryPath = %q", got)
+	}
+}
+
+func TestREPLComplete(t *testing.T) {
+	t.Parallel()
+	registry := map[string]fibonacci.Calculator{
+		"fast":   &fibonacci.MockCalculator{Result: big.NewInt(0)},
+		"fft":    &fibonacci.MockCalculator{Result: big.NewInt(0)},
+		"matrix": &fibonacci.MockCalculator{Result: big.NewInt(0)},
+	}
+	repl := NewREPL(registry, REPLConfig{DefaultAlgo: "fast"})
+	repl.vars["total"] = intValue(big.NewInt(1))
+
+	tests := []struct {
+		line       string
+		wantLine   string
+		candidates []string
+	}{
+		{"cal", "calc ", nil},
+		{"sta", "status ", nil},
+		{"ex", "ex", []string{"exit", "explain"}},
+		{"algo m", "algo matrix", nil},
+		{"algo f", "algo f", []string{"fast", "fft"}},
+		{"x = fi", "x = fib(", nil},
+		{"tot", "total", nil},
+		{"fib(10) + to", "fib(10) + total", nil},
+		{"zzz", "zzz", nil},
+	}
+	for _, tt := range tests {
+		line, pos, candidates := repl.complete(tt.line, len(tt.line))
+		if line != tt.wantLine || pos != len(tt.wantLine) || !reflect.DeepEqual(candidates, tt.candidates) {
+			t.Errorf("complete(%q) = %q, %d, %v; want %q, %v", tt.line, line, pos, candidates, tt.wantLine, tt.candidates)
+		}
+	}
+
+	// Completion in the middle of a line keeps the rest of it
+	if line, pos, _ := repl.complete("cal 10", 3); line != "calc  10" || pos != 5 {
+		t.Errorf("Unexpected completion %q at %d", line, pos)
+	}
+}
+
+// readLines feeds keystrokes to a terminal line editor and returns the
+// lines it reads and its output, bypassing the raw mode that needs a real
+// terminal.
+func readLines(t *testing.T, r *REPL, history *fileHistory, keys string) ([]string, string) {
+	t.Helper()
+	var out bytes.Buffer
+	tr := newTerminalReader(-1, strings.NewReader(keys), &out, history, r.complete)
+
+	var lines []string
+	for {
+		line, err := tr.terminal.ReadLine()
+		if err != nil {
+			return lines, out.String()
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestTerminalReader(t *testing.T) {
+	t.Parallel()
+	repl := NewREPL(map[string]fibonacci.Calculator{"fast": &fibonacci.MockCalculator{}}, REPLConfig{})
+	history := loadHistory("", 10)
+
+	const (
+		up    = "\x1b[A"
+		left  = "\x1b[D"
+		ctrlC = "\x03"
+		ctrlD = "\x04"
+		ctrlR = "\x12"
+	)
+	keys := "cal\t10\r" + // tab completion
+		"compare 20\r" +
+		up + up + "\r" + // history: calc 10
+		"ca" + ctrlR + "\r" + // reverse search: calc 10
+		"c" + ctrlR + ctrlR + "\r" + // older match: compare 20
+		"fib(0)" + left + left + "1\r" + // cursor movement
+		"ex\tit\r" + // ambiguous completion lists the candidates
+		"calc 3" + ctrlC + ctrlC + "list\r" + // Ctrl-C abandons the line
+		ctrlD + "calc 9\r" // Ctrl-D on an empty line ends the input
+	got, out := readLines(t, repl, history, keys)
+	want := []string{"calc 10", "compare 20", "calc 10", "calc 10", "compare 20", "fib(10)", "exit", "list"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read lines %q, want %q", got, want)
+	}
+	if !strings.Contains(out, "calc 3^C") {
+		t.Errorf("Expected the abandoned line to be echoed with ^C, got %q", out)
+	}
+}
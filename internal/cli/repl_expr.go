@@ -0,0 +1,714 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// Bounds of the operations that cannot be interrupted. A timeout or Ctrl-C
+// returns to the prompt at once, but such an operation then runs to
+// completion in the background; the bounds keep it under about ten seconds
+// on a recent x86-64 machine, where the durations below were measured.
+const (
+	// maxPowerBits bounds the size of the results of "^" and "*":
+	// (2^64-1)^(2^20) takes 8s, 3^(2^25) 6s, and the product of two
+	// 2^25-bit integers 5s.
+	maxPowerBits = 1 << 26
+	// maxGCDBits bounds the operands of gcd, whose cost is quadratic: 7s.
+	maxGCDBits = 1 << 21
+	// maxDigitsBits bounds the argument of digits, which converts it to
+	// decimal: 3s.
+	maxDigitsBits = 1 << 24
+	// maxPrimalityBits bounds the argument of isprime, whose cost is about
+	// cubic: a prime of this size takes about 8s.
+	maxPrimalityBits = 1 << 13
+)
+
+// primalityRounds is the number of Miller-Rabin rounds of isprime, in
+// addition to the Baillie-PSW test done by big.Int.ProbablyPrime.
+const primalityRounds = 20
+
+// exprValue is the value of an expression: an integer, or a truth value for
+// comparisons and isprime.
+type exprValue struct {
+	// n is the integer value, nil for truth values.
+	n *big.Int
+	// truth is the truth value when n is nil.
+	truth bool
+	// hex requests a hexadecimal display (see the hex built-in).
+	hex bool
+}
+
+func intValue(n *big.Int) exprValue { return exprValue{n: n} }
+
+func boolValue(b bool) exprValue { return exprValue{truth: b} }
+
+// integer returns the integer value, or an error for truth values.
+func (v exprValue) integer() (*big.Int, error) {
+	if v.n == nil {
+		return nil, errors.New("expected an integer, got a truth value")
+	}
+	return v.n, nil
+}
+
+// ─── Lexer ───
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based column, for error messages
+}
+
+// exprOperators are the operators and punctuation of the language.
+var exprOperators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "^": true,
+	"(": true, ")": true, ",": true, "=": true,
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+}
+
+// tokenize splits an expression into tokens. Numbers are decimal, or
+// hexadecimal with a 0x prefix.
+func tokenize(input string) ([]token, error) {
+	var toks []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case unicode.IsDigit(c):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			toks = append(toks, token{tokNumber, string(runes[start:i]), start + 1})
+		case unicode.IsLetter(c) || c == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			toks = append(toks, token{tokIdent, string(runes[start:i]), start + 1})
+		default:
+			op := string(c)
+			if i+1 < len(runes) && runes[i+1] == '=' && exprOperators[op+"="] {
+				op += "="
+			}
+			if !exprOperators[op] {
+				return nil, fmt.Errorf("unexpected character %q at column %d", c, start+1)
+			}
+			i += len(op)
+			toks = append(toks, token{tokOp, op, start + 1})
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(runes) + 1}), nil
+}
+
+// ─── Parser ───
+
+// exprNode is a node of the syntax tree of an expression.
+type exprNode interface {
+	eval(ev *evaluator) (exprValue, error)
+}
+
+type (
+	numberNode struct{ n *big.Int }
+	varNode    struct {
+		name string
+		pos  int
+	}
+	unaryNode  struct{ x exprNode }
+	binaryNode struct {
+		op   string
+		x, y exprNode
+	}
+	callNode struct {
+		fn   string
+		args []exprNode
+		pos  int
+	}
+)
+
+// parser is a recursive descent parser of the grammar:
+//
+//	statement  = [ident "="] comparison
+//	comparison = sum [("==" | "!=" | "<" | "<=" | ">" | ">=") sum]
+//	sum        = product {("+" | "-") product}
+//	product    = unary {("*" | "/" | "%" | "mod") unary}
+//	unary      = "-" unary | power
+//	power      = primary ["^" unary]
+//	primary    = number | ident | ident "(" [comparison {"," comparison}] ")" | "(" comparison ")"
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of ops.
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp && !(t.kind == tokIdent && t.text == "mod") {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.i++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokEOF {
+		return errors.New("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at column %d", t.text, t.pos)
+}
+
+// parseStatement parses an expression, optionally assigned to a variable.
+// It returns the name of the variable, or "" if there is no assignment.
+func parseStatement(input string) (string, exprNode, error) {
+	toks, err := tokenize(input)
+	if err != nil {
+		return "", nil, err
+	}
+	p := &parser{toks: toks}
+	var name string
+	if len(toks) > 2 && toks[0].kind == tokIdent && toks[1].kind == tokOp && toks[1].text == "=" {
+		name = toks[0].text
+		if _, ok := exprBuiltins[name]; ok || name == "mod" {
+			return "", nil, fmt.Errorf("cannot assign to %q: reserved name", name)
+		}
+		p.i = 2
+	}
+	node, err := p.comparison()
+	if err != nil {
+		return "", nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return "", nil, p.unexpected()
+	}
+	return name, node, nil
+}
+
+func (p *parser) comparison() (exprNode, error) {
+	x, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept("==", "!=", "<", "<=", ">", ">="); ok {
+		y, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op, x, y}
+	}
+	return x, nil
+}
+
+func (p *parser) sum() (exprNode, error) {
+	x, err := p.product()
+	for err == nil {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			break
+		}
+		var y exprNode
+		if y, err = p.product(); err == nil {
+			x = binaryNode{op, x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) product() (exprNode, error) {
+	x, err := p.unary()
+	for err == nil {
+		op, ok := p.accept("*", "/", "%", "mod")
+		if !ok {
+			break
+		}
+		if op == "mod" {
+			op = "%"
+		}
+		var y exprNode
+		if y, err = p.unary(); err == nil {
+			x = binaryNode{op, x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) unary() (exprNode, error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{x}, nil
+	}
+	return p.power()
+}
+
+// power parses "^", which is right-associative and binds tighter than the
+// unary minus on its left: -2^2 is -4 and 2^-1 is an error.
+func (p *parser) power() (exprNode, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("^"); ok {
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{"^", x, y}
+	}
+	return x, nil
+}
+
+func (p *parser) primary() (exprNode, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokNumber:
+		p.next()
+		text, base := t.text, 10
+		if len(text) > 2 && (text[:2] == "0x" || text[:2] == "0X") {
+			text, base = text[2:], 16
+		}
+		n, ok := new(big.Int).SetString(text, base)
+		if !ok || strings.Contains(text, "_") {
+			return nil, fmt.Errorf("invalid number %q at column %d", t.text, t.pos)
+		}
+		return numberNode{n}, nil
+	case t.kind == tokIdent && t.text != "mod":
+		p.next()
+		if _, ok := p.accept("("); !ok {
+			return varNode{t.text, t.pos}, nil
+		}
+		call := callNode{fn: t.text, pos: t.pos}
+		if _, ok := p.accept(")"); ok {
+			return call, nil
+		}
+		for {
+			arg, err := p.comparison()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		return call, p.expect(")")
+	case t.kind == tokOp && t.text == "(":
+		p.next()
+		x, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	}
+	return nil, p.unexpected()
+}
+
+// ─── Evaluation ───
+
+// evaluator evaluates expressions against the variables of a REPL session.
+type evaluator struct {
+	ctx  context.Context
+	vars map[string]exprValue
+	// fib computes F(n) with the current calculator.
+	fib func(ctx context.Context, n uint64) (*big.Int, error)
+}
+
+func (n numberNode) eval(*evaluator) (exprValue, error) { return intValue(n.n), nil }
+
+func (n varNode) eval(ev *evaluator) (exprValue, error) {
+	v, ok := ev.vars[n.name]
+	if !ok {
+		return exprValue{}, fmt.Errorf("undefined variable %q at column %d", n.name, n.pos)
+	}
+	return v, nil
+}
+
+func (n unaryNode) eval(ev *evaluator) (exprValue, error) {
+	v, err := n.x.eval(ev)
+	if err != nil {
+		return exprValue{}, err
+	}
+	x, err := v.integer()
+	if err != nil {
+		return exprValue{}, err
+	}
+	return intValue(new(big.Int).Neg(x)), nil
+}
+
+func (n binaryNode) eval(ev *evaluator) (exprValue, error) {
+	if err := ev.ctx.Err(); err != nil {
+		return exprValue{}, err
+	}
+	vx, err := n.x.eval(ev)
+	if err != nil {
+		return exprValue{}, err
+	}
+	vy, err := n.y.eval(ev)
+	if err != nil {
+		return exprValue{}, err
+	}
+	if (n.op == "==" || n.op == "!=") && vx.n == nil && vy.n == nil {
+		return boolValue((vx.truth == vy.truth) == (n.op == "==")), nil
+	}
+	x, err := vx.integer()
+	if err != nil {
+		return exprValue{}, err
+	}
+	y, err := vy.integer()
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	switch n.op {
+	case "+":
+		return intValue(new(big.Int).Add(x, y)), nil
+	case "-":
+		return intValue(new(big.Int).Sub(x, y)), nil
+	case "*":
+		if x.BitLen()+y.BitLen() > maxPowerBits {
+			return exprValue{}, fmt.Errorf("result of * too large (over %d bits)", maxPowerBits)
+		}
+		return intValue(new(big.Int).Mul(x, y)), nil
+	case "/", "%":
+		if y.Sign() == 0 {
+			return exprValue{}, errors.New("division by zero")
+		}
+		// Euclidean division, so that x mod y is never negative
+		if n.op == "/" {
+			return intValue(new(big.Int).Div(x, y)), nil
+		}
+		return intValue(new(big.Int).Mod(x, y)), nil
+	case "^":
+		return power(x, y)
+	}
+	c := x.Cmp(y)
+	switch n.op {
+	case "==":
+		return boolValue(c == 0), nil
+	case "!=":
+		return boolValue(c != 0), nil
+	case "<":
+		return boolValue(c < 0), nil
+	case "<=":
+		return boolValue(c <= 0), nil
+	case ">":
+		return boolValue(c > 0), nil
+	default: // ">="
+		return boolValue(c >= 0), nil
+	}
+}
+
+// power returns x^y for a non-negative y, refusing the exponents for which
+// the result could be larger than maxPowerBits: x^y has at most
+// bits(x)*y bits.
+func power(x, y *big.Int) (exprValue, error) {
+	if y.Sign() < 0 {
+		return exprValue{}, errors.New("negative exponent")
+	}
+	if x.CmpAbs(big.NewInt(1)) > 0 {
+		if !y.IsUint64() || y.Uint64() > maxPowerBits/uint64(x.BitLen()) {
+			return exprValue{}, fmt.Errorf("result of ^ too large (over %d bits)", maxPowerBits)
+		}
+	}
+	return intValue(new(big.Int).Exp(x, y, nil)), nil
+}
+
+func (n callNode) eval(ev *evaluator) (exprValue, error) {
+	fn, ok := exprBuiltins[n.fn]
+	if !ok {
+		return exprValue{}, fmt.Errorf("unknown function %q at column %d", n.fn, n.pos)
+	}
+	if len(n.args) != fn.arity {
+		return exprValue{}, fmt.Errorf("%s expects %d argument(s), got %d", n.fn, fn.arity, len(n.args))
+	}
+	args := make([]*big.Int, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(ev)
+		if err != nil {
+			return exprValue{}, err
+		}
+		if args[i], err = v.integer(); err != nil {
+			return exprValue{}, fmt.Errorf("%s: %w", n.fn, err)
+		}
+	}
+	v, err := fn.call(ev, args)
+	if err != nil {
+		return exprValue{}, fmt.Errorf("%s: %w", n.fn, err)
+	}
+	return v, nil
+}
+
+// builtin is a function of the expression language.
+type builtin struct {
+	arity int
+	call  func(ev *evaluator, args []*big.Int) (exprValue, error)
+}
+
+// exprBuiltins are the functions of the expression language.
+var exprBuiltins = map[string]builtin{
+	"fib": {1, func(ev *evaluator, args []*big.Int) (exprValue, error) {
+		n, err := index(args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		f, err := ev.fib(ev.ctx, n)
+		return intValue(f), err
+	}},
+	"lucas": {1, func(ev *evaluator, args []*big.Int) (exprValue, error) {
+		n, err := index(args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		if n == ^uint64(0) {
+			return exprValue{}, errors.New("index too large")
+		}
+		fn, err := ev.fib(ev.ctx, n)
+		if err != nil {
+			return exprValue{}, err
+		}
+		fn1, err := ev.fib(ev.ctx, n+1)
+		if err != nil {
+			return exprValue{}, err
+		}
+		l := new(big.Int).Lsh(fn1, 1)
+		return intValue(l.Sub(l, fn)), nil
+	}},
+	"gcd": {2, func(_ *evaluator, args []*big.Int) (exprValue, error) {
+		if err := checkBits(maxGCDBits, args...); err != nil {
+			return exprValue{}, err
+		}
+		return intValue(new(big.Int).GCD(nil, nil, args[0], args[1])), nil
+	}},
+	"digits": {1, func(_ *evaluator, args []*big.Int) (exprValue, error) {
+		if err := checkBits(maxDigitsBits, args...); err != nil {
+			return exprValue{}, err
+		}
+		return intValue(big.NewInt(int64(len(new(big.Int).Abs(args[0]).String())))), nil
+	}},
+	"bits": {1, func(_ *evaluator, args []*big.Int) (exprValue, error) {
+		return intValue(big.NewInt(int64(args[0].BitLen()))), nil
+	}},
+	"isprime": {1, func(_ *evaluator, args []*big.Int) (exprValue, error) {
+		if err := checkBits(maxPrimalityBits, args...); err != nil {
+			return exprValue{}, err
+		}
+		return boolValue(args[0].Sign() > 0 && args[0].ProbablyPrime(primalityRounds)), nil
+	}},
+	"hex": {1, func(_ *evaluator, args []*big.Int) (exprValue, error) {
+		return exprValue{n: args[0], hex: true}, nil
+	}},
+}
+
+// checkBits returns an error if an argument has more than limit bits.
+func checkBits(limit int, args ...*big.Int) error {
+	for _, x := range args {
+		if x.BitLen() > limit {
+			return fmt.Errorf("argument too large (%d bits, at most %d)", x.BitLen(), limit)
+		}
+	}
+	return nil
+}
+
+// index converts the argument of fib and lucas to an index.
+func index(x *big.Int) (uint64, error) {
+	if x.Sign() < 0 {
+		return 0, errors.New("negative index")
+	}
+	if !x.IsUint64() {
+		return 0, errors.New("index too large")
+	}
+	return x.Uint64(), nil
+}
+
+// builtinNames returns the names of the built-in functions, sorted.
+func builtinNames() []string {
+	names := make([]string, 0, len(exprBuiltins))
+	for name := range exprBuiltins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ─── REPL commands ───
+
+// isAssignment reports whether input assigns a variable.
+func isAssignment(input string) bool {
+	toks, err := tokenize(input)
+	return err == nil && len(toks) > 2 && toks[0].kind == tokIdent && toks[1].kind == tokOp && toks[1].text == "="
+}
+
+// isIdentifier reports whether word is a valid variable name.
+func isIdentifier(word string) bool {
+	toks, err := tokenize(word)
+	return err == nil && len(toks) == 2 && toks[0].kind == tokIdent
+}
+
+// cmdEval evaluates an expression, assigning it to a variable if the input
+// is an assignment. Calls to fib and lucas use the current algorithm, and the
+// whole evaluation is bounded by the REPL timeout and canceled by Ctrl-C.
+func (r *REPL) cmdEval(input string) {
+	name, node, err := parseStatement(input)
+	if err != nil {
+		r.errorf("Syntax error: %v", err)
+		return
+	}
+
+	v, err := r.evaluate(node)
+	if err != nil {
+		r.errorf("Error: %v", err)
+		return
+	}
+
+	if name != "" {
+		r.vars[name] = v
+		fmt.Fprintf(r.out, "%s = %s\n", name, r.formatExprValue(v))
+		return
+	}
+	fmt.Fprintf(r.out, "= %s\n", r.formatExprValue(v))
+}
+
+// evaluate evaluates node, bounded by the REPL timeout and canceled by
+// Ctrl-C. The evaluation runs in its own goroutine on a copy of the session
+// state, so that it returns at once even from an operation that cannot be
+// interrupted.
+func (r *REPL) evaluate(node exprNode) (exprValue, error) {
+	ctx, cancel := r.timeoutContext()
+	defer cancel()
+	ctx, stop := r.interruptible(ctx)
+	defer stop()
+
+	type outcome struct {
+		v   exprValue
+		err error
+	}
+	done := make(chan outcome, 1)
+	ev := &evaluator{ctx: ctx, vars: maps.Clone(r.vars), fib: r.fibFunc()}
+	go func() {
+		v, err := node.eval(ev)
+		done <- outcome{v, err}
+	}()
+
+	var v exprValue
+	var err error
+	select {
+	case o := <-done:
+		v, err = o.v, o.err
+		if err == nil {
+			err = ctx.Err()
+		}
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return exprValue{}, fmt.Errorf("evaluation timed out after %s", r.config.Timeout)
+	case errors.Is(err, context.Canceled):
+		return exprValue{}, errors.New("evaluation canceled")
+	}
+	return v, err
+}
+
+// fibFunc returns a function computing F(n) with the current algorithm and
+// options, without progress display. It captures them, so that it can be
+// called from another goroutine.
+func (r *REPL) fibFunc() func(ctx context.Context, n uint64) (*big.Int, error) {
+	algo := r.currentAlgo
+	calc, ok := r.registry[algo]
+	opts := r.options()
+	return func(ctx context.Context, n uint64) (*big.Int, error) {
+		if !ok {
+			return nil, fmt.Errorf("algorithm not found: %s", algo)
+		}
+		return calc.Calculate(ctx, nil, 0, n, opts)
+	}
+}
+
+// formatExprValue formats a value for display: truth values as true or
+// false, integers in decimal or hexadecimal (with hex() or the hex toggle),
+// truncated if long.
+func (r *REPL) formatExprValue(v exprValue) string {
+	text, size := r.exprText(v)
+	return ui.ColorGreen() + text + ui.ColorReset() + size
+}
+
+// exprText formats a value without colors. Long integers are truncated, and
+// size then describes their length.
+func (r *REPL) exprText(v exprValue) (text, size string) {
+	if v.n == nil {
+		return strconv.FormatBool(v.truth), ""
+	}
+
+	sign, abs := "", v.n
+	if v.n.Sign() < 0 {
+		sign, abs = "-", new(big.Int).Abs(v.n)
+	}
+	digits, prefix, edges, unit := abs.String(), "", DisplayEdges, "digits"
+	if v.hex || r.config.HexOutput {
+		digits, prefix, edges, unit = abs.Text(16), "0x", HexDisplayEdges, "hex digits"
+	}
+	if len(digits) > TruncationLimit {
+		return sign + prefix + digits[:edges] + "..." + digits[len(digits)-edges:], fmt.Sprintf(" (%d %s)", len(digits), unit)
+	}
+	return sign + prefix + digits, ""
+}
+
+// cmdVars lists the variables of the session.
+func (r *REPL) cmdVars() {
+	if len(r.vars) == 0 {
+		fmt.Fprintln(r.out, "No variables defined.")
+		return
+	}
+	names := make([]string, 0, len(r.vars))
+	for name := range r.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(r.out, "  %s%s%s = %s\n", ui.ColorYellow(), name, ui.ColorReset(), r.formatExprValue(r.vars[name]))
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+// newExprREPL returns a REPL whose calculator computes F(n) iteratively.
+func newExprREPL(timeout time.Duration) (*REPL, *bytes.Buffer) {
+	calc := &fibonacci.MockCalculator{
+		Fn: func(ctx context.Context, n uint64) (*big.Int, error) {
+			a, b := big.NewInt(0), big.NewInt(1)
+			for i := uint64(0); i < n; i++ {
+				a.Add(a, b)
+				a, b = b, a
+			}
+			return a, nil
+		},
+	}
+	repl := NewREPL(map[string]fibonacci.Calculator{"mock": calc}, REPLConfig{DefaultAlgo: "mock", Timeout: timeout})
+	var out bytes.Buffer
+	repl.SetOutput(&out)
+	return repl, &out
+}
+
+func TestREPLExpressions(t *testing.T) {
+	t.Parallel()
+	repl, out := newExprREPL(time.Second)
+
+	// Statements run in order, so later ones may use earlier variables.
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1 + 2 * 3", "= 7"},
+		{"(1 + 2) * 3", "= 9"},
+		{"-2^2", "= -4"},
+		{"2^3^2", "= 512"},
+		{"-7 mod 3", "= 2"},
+		{"-7 % 3", "= 2"},
+		{"-7 / 2", "= -4"},
+		{"0x10 + 1", "= 17"},
+		{"20 + 1", "= 21"},
+		{"x = fib(10)", "x = 55"},
+		{"x * 2", "= 110"},
+		{"x", "= 55"},
+		{"c = 3", "c = 3"},
+		{"lucas(10)", "= 123"},
+		{"gcd(fib(12), fib(18))", "= 8"},
+		{"digits(fib(100))", "= 21"},
+		{"bits(255)", "= 8"},
+		{"isprime(fib(11))", "= true"},
+		{"isprime(fib(12))", "= false"},
+		{"hex(255)", "= 0xff"},
+		{"hex(-255)", "= -0xff"},
+		{"fib(10) == 55", "= true"},
+		{"2 < 1", "= false"},
+		{"isprime(7) == (1 < 2)", "= true"},
+		{"digits(fib(1000))", "= 209"},
+		{"fib(1000)", "(209 digits)"},
+
+		{"1 / 0", "Error: division by zero"},
+		{"y + 1", `Error: undefined variable "y" at column 1`},
+		{"fib(-1)", "Error: fib: negative index"},
+		{"fib(1, 2)", "Error: fib expects 1 argument(s), got 2"},
+		{"sqrt(4)", `Error: unknown function "sqrt"`},
+		{"2^-1", "Error: negative exponent"},
+		{"2^(2^40)", "too large"},
+		{"3^2147483648", "Error: result of ^ too large (over 67108864 bits)"},
+		{"3^(2^25 + 1)", "too large"},
+		{"10^(2^24 + 1)", "too large"},
+		{"(2^64 - 1)^(2^20 + 1)", "too large"},
+		{"(-3)^(2^25 + 1)", "too large"},
+		{"2^(2^25) * 2^(2^25)", "Error: result of * too large"},
+		{"bits(3^(2^10) * 7^(2^10))", "= 4498"},
+		{"isprime(2^10000 + 1)", "Error: isprime: argument too large (10001 bits, at most 8192)"},
+		{"gcd(2^(2^22), 3)", "Error: gcd: argument too large"},
+		{"isprime(7) + 1", "Error: expected an integer, got a truth value"},
+		{"1 +", "Syntax error: unexpected end of expression"},
+		{"1 $ 2", `Syntax error: unexpected character '$' at column 3`},
+		{"fib = 3", `Syntax error: cannot assign to "fib": reserved name`},
+		{"012 + 0", "= 12"},
+		{"0b11", `Syntax error: invalid number "0b11"`},
+	}
+	for _, tt := range tests {
+		out.Reset()
+		repl.processCommand(tt.input)
+		if got := testutil.StripAnsiCodes(out.String()); !strings.Contains(got, tt.want) {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	out.Reset()
+	repl.processCommand("vars")
+	if got := testutil.StripAnsiCodes(out.String()); !strings.Contains(got, "c = 3") || !strings.Contains(got, "x = 55") {
+		t.Errorf("Expected the variables, got %q", got)
+	}
+}
+
+func TestREPLExpressions_Timeout(t *testing.T) {
+	t.Parallel()
+	calc := &fibonacci.MockCalculator{
+		Fn: func(ctx context.Context, n uint64) (*big.Int, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	repl := NewREPL(map[string]fibonacci.Calculator{"mock": calc}, REPLConfig{DefaultAlgo: "mock", Timeout: 20 * time.Millisecond})
+	var out bytes.Buffer
+	repl.SetOutput(&out)
+
+	repl.processCommand("x = fib(100) + 1")
+	if got := testutil.StripAnsiCodes(out.String()); !strings.Contains(got, "timed out") {
+		t.Errorf("Expected a timeout, got %q", got)
+	}
+	if _, ok := repl.vars["x"]; ok {
+		t.Error("A failed assignment must not define the variable")
+	}
+}
+
+func TestREPLExpressions_Interrupt(t *testing.T) {
+	t.Parallel()
+	repl, out := newExprREPL(0)
+	interrupts := make(chan os.Signal, 1)
+	repl.interrupts = func() (<-chan os.Signal, func()) { return interrupts, func() {} }
+
+	// A product of two 16M-bit numbers takes seconds and cannot be
+	// interrupted; Ctrl-C must return to the prompt before it completes.
+	a := new(big.Int).Lsh(big.NewInt(1), 1<<24)
+	repl.vars["a"] = intValue(a.Sub(a, big.NewInt(1)))
+	start := time.Now()
+	interrupts <- os.Interrupt
+	repl.processCommand("b = a * a")
+	if got := testutil.StripAnsiCodes(out.String()); !strings.Contains(got, "evaluation canceled") {
+		t.Errorf("Expected a canceled evaluation, got %q", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Ctrl-C took %s to return", elapsed)
+	}
+	if _, ok := repl.vars["b"]; ok {
+		t.Error("A canceled assignment must not define the variable")
+	}
+}
@@ -0,0 +1,387 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// observableCalculator is implemented by calculators that report progress to
+// a fibonacci.ProgressSubject, such as fibonacci.FibCalculator.
+type observableCalculator interface {
+	CalculateWithObservers(ctx context.Context, subject *fibonacci.ProgressSubject, calcIndex int, n uint64, opts fibonacci.Options) (*big.Int, error)
+}
+
+// ─── Interrupts ───
+
+// notifyInterrupt relays Ctrl-C (SIGINT) to the returned channel, instead of
+// ending the process, until stop is called.
+func notifyInterrupt() (<-chan os.Signal, func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	return ch, func() { signal.Stop(ch) }
+}
+
+// interruptible returns a context canceled by Ctrl-C, so that Ctrl-C cancels
+// the foreground calculation and keeps the session alive. stop restores the
+// default handling of Ctrl-C.
+func (r *REPL) interruptible(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	interrupts, stopInterrupts := r.interrupts()
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		stopInterrupts()
+		close(done)
+		cancel()
+	}
+}
+
+// timeoutContext returns a context bounded by the REPL timeout, if any.
+func (r *REPL) timeoutContext() (context.Context, context.CancelFunc) {
+	if r.config.Timeout > 0 {
+		return context.WithTimeout(context.Background(), r.config.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// ─── Jobs ───
+
+// jobStatus is the state of a background job.
+type jobStatus int
+
+const (
+	jobRunning jobStatus = iota
+	jobDone
+	jobFailed
+	jobCanceled
+)
+
+// String returns the name of the status.
+func (s jobStatus) String() string {
+	switch s {
+	case jobRunning:
+		return "running"
+	case jobDone:
+		return "done"
+	case jobCanceled:
+		return "canceled"
+	default:
+		return "failed"
+	}
+}
+
+// job is a calculation running in the background of a REPL session.
+type job struct {
+	id       int
+	n        uint64
+	algo     string
+	start    time.Time
+	cancel   context.CancelFunc
+	progress *jobProgress
+	done     chan struct{}
+
+	// result, err and duration are set before done is closed.
+	result   *big.Int
+	err      error
+	duration time.Duration
+}
+
+// status returns the state of the job.
+func (j *job) status() jobStatus {
+	select {
+	case <-j.done:
+	default:
+		return jobRunning
+	}
+	switch {
+	case j.err == nil:
+		return jobDone
+	case errors.Is(j.err, context.Canceled):
+		return jobCanceled
+	default:
+		return jobFailed
+	}
+}
+
+// jobProgress is a fibonacci.ProgressEventObserver recording the latest
+// progress of a job.
+type jobProgress struct {
+	mu    sync.Mutex
+	value float64
+	event *fibonacci.ProgressEvent
+}
+
+// Update implements fibonacci.ProgressObserver.
+func (p *jobProgress) Update(_ int, progress float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.value = progress
+}
+
+// OnProgressEvent implements fibonacci.ProgressEventObserver.
+func (p *jobProgress) OnProgressEvent(event fibonacci.ProgressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.value, p.event = event.Progress, &event
+}
+
+// snapshot returns the latest progress and step event, if any.
+func (p *jobProgress) snapshot() (float64, *fibonacci.ProgressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.value, p.event
+}
+
+// jobTable holds the jobs of a REPL session, in order of creation.
+type jobTable struct {
+	mu       sync.Mutex
+	jobs     []*job
+	reported map[int]bool
+}
+
+func newJobTable() *jobTable {
+	return &jobTable{reported: make(map[int]bool)}
+}
+
+// add assigns the next job ID to j and records it.
+func (t *jobTable) add(j *job) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	j.id = len(t.jobs) + 1
+	t.jobs = append(t.jobs, j)
+}
+
+// get returns the job with the given ID, or nil.
+func (t *jobTable) get(id int) *job {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id < 1 || id > len(t.jobs) {
+		return nil
+	}
+	return t.jobs[id-1]
+}
+
+// all returns the jobs, in order of creation.
+func (t *jobTable) all() []*job {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*job(nil), t.jobs...)
+}
+
+// finished returns the jobs that finished since the last call, and marks
+// them as reported.
+func (t *jobTable) finished() []*job {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var jobs []*job
+	for _, j := range t.jobs {
+		if !t.reported[j.id] && j.status() != jobRunning {
+			t.reported[j.id] = true
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// markReported records that the outcome of j was displayed.
+func (t *jobTable) markReported(j *job) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reported[j.id] = true
+}
+
+// calculateObserved computes F(n) with calc, reporting progress to observer.
+// Calculators that only support progress channels are relayed to it.
+func calculateObserved(ctx context.Context, calc fibonacci.Calculator, n uint64, opts fibonacci.Options, observer *jobProgress) (*big.Int, error) {
+	if oc, ok := calc.(observableCalculator); ok {
+		subject := fibonacci.NewProgressSubject()
+		subject.Register(observer)
+		return oc.CalculateWithObservers(ctx, subject, 0, n, opts)
+	}
+
+	progressChan := make(chan fibonacci.ProgressUpdate, 10)
+	relayed := make(chan struct{})
+	go func() {
+		defer close(relayed)
+		for update := range progressChan {
+			if update.Event != nil {
+				observer.OnProgressEvent(*update.Event)
+			} else {
+				observer.Update(update.CalculatorIndex, update.Value)
+			}
+		}
+	}()
+	result, err := calc.Calculate(ctx, progressChan, 0, n, opts)
+	close(progressChan)
+	<-relayed
+	return result, err
+}
+
+// ─── Commands ───
+
+// startJob starts the calculation of F(n) with the current algorithm in the
+// background.
+func (r *REPL) startJob(n uint64) {
+	calc, ok := r.registry[r.currentAlgo]
+	if !ok {
+		r.errorf("Algorithm not found: %s", r.currentAlgo)
+		return
+	}
+
+	ctx, cancel := r.timeoutContext()
+	j := &job{
+		n:        n,
+		algo:     calc.Name(),
+		start:    time.Now(),
+		cancel:   cancel,
+		progress: &jobProgress{},
+		done:     make(chan struct{}),
+	}
+	r.jobs.add(j)
+	opts := r.options()
+	go func() {
+		defer cancel()
+		j.result, j.err = calculateObserved(ctx, calc, n, opts, j.progress)
+		j.duration = time.Since(j.start)
+		close(j.done)
+	}()
+
+	fmt.Fprintf(r.out, "[%d] F(%s%d%s) with %s%s%s started in the background\n",
+		j.id, ui.ColorMagenta(), n, ui.ColorReset(), ui.ColorCyan(), j.algo, ui.ColorReset())
+}
+
+// cmdJobs lists the jobs of the session with their progress.
+func (r *REPL) cmdJobs() {
+	jobs := r.jobs.all()
+	if len(jobs) == 0 {
+		fmt.Fprintln(r.out, "No jobs.")
+		return
+	}
+	for _, j := range jobs {
+		status := j.status()
+		fmt.Fprintf(r.out, "  [%d] %-8s F(%d) with %s", j.id, status, j.n, j.algo)
+		switch status {
+		case jobRunning:
+			progress, event := j.progress.snapshot()
+			fmt.Fprintf(r.out, "  %s%6.2f%%%s %s  %s",
+				ui.ColorGreen(), progress*100, ui.ColorReset(), progressBar(progress, 20),
+				FormatExecutionDuration(time.Since(j.start)))
+			if event != nil {
+				fmt.Fprintf(r.out, "  %s", FormatStepDetail(*event))
+			}
+		case jobDone:
+			fmt.Fprintf(r.out, "  in %s", FormatExecutionDuration(j.duration))
+		default:
+			fmt.Fprintf(r.out, "  %s%v%s", ui.ColorRed(), j.err, ui.ColorReset())
+		}
+		fmt.Fprintln(r.out)
+	}
+}
+
+// cmdCancel cancels a running job.
+func (r *REPL) cmdCancel(args []string) {
+	j := r.jobArg("cancel", args)
+	if j == nil {
+		return
+	}
+	if j.status() != jobRunning {
+		fmt.Fprintf(r.out, "Job %d is not running (%s).\n", j.id, j.status())
+		return
+	}
+	j.cancel()
+	<-j.done
+	r.jobs.markReported(j)
+	fmt.Fprintf(r.out, "[%d] canceled\n", j.id)
+}
+
+// cmdWait waits for a job and displays its result. Waiting brings the job to
+// the foreground: Ctrl-C cancels it.
+func (r *REPL) cmdWait(args []string) {
+	j := r.jobArg("wait", args)
+	if j == nil {
+		return
+	}
+
+	ctx, stop := r.interruptible(context.Background())
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		j.cancel()
+		<-j.done
+	}
+	stop()
+
+	r.jobs.markReported(j)
+	switch j.status() {
+	case jobDone:
+		fmt.Fprintf(r.out, "[%d] F(%d) with %s\n", j.id, j.n, j.algo)
+		r.setLast(j.n, j.result, j.duration)
+		r.displayResult(j.n, j.result, j.duration)
+	case jobCanceled:
+		fmt.Fprintf(r.out, "[%d] canceled\n", j.id)
+		r.fail(fmt.Sprintf("job %d canceled", j.id))
+	default:
+		fmt.Fprintf(r.out, "[%d] ", j.id)
+		r.errorf("Error: %v", j.err)
+	}
+}
+
+// jobArg returns the job named by the argument of a command, or nil after
+// printing an error.
+func (r *REPL) jobArg(cmd string, args []string) *job {
+	if len(args) == 0 {
+		r.errorf("Usage: %s <job>", cmd)
+		return nil
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		r.errorf("Invalid job: %s", args[0])
+		return nil
+	}
+	j := r.jobs.get(id)
+	if j == nil {
+		r.errorf("No such job: %d", id)
+	}
+	return j
+}
+
+// reportJobs prints a line for each job that finished since the last prompt.
+func (r *REPL) reportJobs() {
+	for _, j := range r.jobs.finished() {
+		switch j.status() {
+		case jobDone:
+			fmt.Fprintf(r.out, "[%d] done: F(%d) in %s (%swait %d%s to display it)\n",
+				j.id, j.n, FormatExecutionDuration(j.duration), ui.ColorYellow(), j.id, ui.ColorReset())
+		case jobCanceled:
+			fmt.Fprintf(r.out, "[%d] canceled: F(%d)\n", j.id, j.n)
+		default:
+			fmt.Fprintf(r.out, "[%d] %sfailed: F(%d): %v%s\n", j.id, ui.ColorRed(), j.n, j.err, ui.ColorReset())
+		}
+	}
+}
+
+// stopJobs cancels the running jobs when the session ends.
+func (r *REPL) stopJobs() {
+	for _, j := range r.jobs.all() {
+		if j.status() == jobRunning {
+			j.cancel()
+			<-j.done
+		}
+	}
+}
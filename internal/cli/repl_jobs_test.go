@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+// gatedCalculator reports half of its progress, then returns F(n) = n once
+// release is closed, or fails when its context is canceled. It sends the
+// options of each calculation to started.
+type gatedCalculator struct {
+	release chan struct{}
+	started chan fibonacci.Options
+}
+
+func (g *gatedCalculator) Name() string { return "gated" }
+
+func (g *gatedCalculator) Calculate(ctx context.Context, progressChan chan<- fibonacci.ProgressUpdate, calcIndex int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+	if progressChan != nil {
+		progressChan <- fibonacci.ProgressUpdate{CalculatorIndex: calcIndex, Value: 0.5}
+	}
+	g.started <- opts
+	select {
+	case <-g.release:
+		return new(big.Int).SetUint64(n), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// take returns the output written so far, without ANSI codes, and resets it.
+func (b *syncBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := testutil.StripAnsiCodes(b.buf.String())
+	b.buf.Reset()
+	return s
+}
+
+func newJobsREPL() (*REPL, *gatedCalculator, *syncBuffer) {
+	calc := &gatedCalculator{release: make(chan struct{}), started: make(chan fibonacci.Options, 10)}
+	repl := NewREPL(map[string]fibonacci.Calculator{"gated": calc}, REPLConfig{DefaultAlgo: "gated", Timeout: time.Minute})
+	out := &syncBuffer{}
+	repl.SetOutput(out)
+	return repl, calc, out
+}
+
+func TestREPLJobs(t *testing.T) {
+	t.Parallel()
+	repl, calc, out := newJobsREPL()
+
+	repl.processCommand("calc 100 &")
+	repl.processCommand("200&")
+	<-calc.started
+	<-calc.started
+	if got := out.take(); !strings.Contains(got, "[1] F(100) with gated started in the background") ||
+		!strings.Contains(got, "[2] F(200)") {
+		t.Fatalf("Unexpected start output %q", got)
+	}
+
+	// Progress is reported asynchronously
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		repl.processCommand("jobs")
+		got := out.take()
+		if strings.Contains(got, "[1] running") && strings.Contains(got, "50.00%") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected running jobs with progress, got %q", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	repl.processCommand("cancel 2")
+	if got := out.take(); !strings.Contains(got, "[2] canceled") {
+		t.Errorf("Expected job 2 to be canceled, got %q", got)
+	}
+	repl.processCommand("cancel 2")
+	if got := out.take(); !strings.Contains(got, "Job 2 is not running (canceled)") {
+		t.Errorf("Unexpected output %q", got)
+	}
+
+	close(calc.release)
+	repl.processCommand("wait 1")
+	if got := out.take(); !strings.Contains(got, "[1] F(100) with gated") || !strings.Contains(got, "Result:") {
+		t.Errorf("Expected the result of job 1, got %q", got)
+	}
+
+	// Jobs displayed by cancel or wait are not reported again
+	repl.reportJobs()
+	if got := out.take(); got != "" {
+		t.Errorf("Unexpected report %q", got)
+	}
+
+	for input, want := range map[string]string{
+		"wait":      "Usage: wait <job>",
+		"cancel x":  "Invalid job: x",
+		"wait 9":    "No such job: 9",
+		"calc &":    "Usage: calc <n> &",
+		"calc -1 &": "Invalid value: -1",
+	} {
+		repl.processCommand(input)
+		if got := out.take(); !strings.Contains(got, want) {
+			t.Errorf("%q: expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestREPLJobs_Report(t *testing.T) {
+	t.Parallel()
+	repl, calc, out := newJobsREPL()
+
+	repl.processCommand("calc 7 &")
+	<-calc.started
+	close(calc.release)
+	j := repl.jobs.get(1)
+	<-j.done
+	out.take()
+
+	repl.reportJobs()
+	if got := out.take(); !strings.Contains(got, "[1] done: F(7)") || !strings.Contains(got, "wait 1") {
+		t.Errorf("Expected a done notification, got %q", got)
+	}
+	repl.reportJobs()
+	if got := out.take(); got != "" {
+		t.Errorf("A job must be reported once, got %q", got)
+	}
+}
+
+func TestREPLJobs_Options(t *testing.T) {
+	t.Parallel()
+	repl, calc, _ := newJobsREPL()
+	repl.processCommand("set threshold 1000")
+
+	// Settings changed after the start do not affect the job
+	repl.processCommand("calc 5 &")
+	repl.processCommand("set threshold 2000")
+	if opts := <-calc.started; opts.ParallelThreshold != 1000 {
+		t.Errorf("Expected the job to use the threshold at its start, got %d", opts.ParallelThreshold)
+	}
+	close(calc.release)
+	<-repl.jobs.get(1).done
+}
+
+func TestREPLJobs_StopOnExit(t *testing.T) {
+	t.Parallel()
+	repl, calc, out := newJobsREPL()
+	repl.SetInput(strings.NewReader("calc 5 &\nexit\n"))
+
+	repl.Start()
+	<-calc.started
+	if j := repl.jobs.get(1); j.status() != jobCanceled {
+		t.Errorf("Expected the job to be canceled on exit, got %s", j.status())
+	}
+	if got := out.take(); !strings.Contains(got, "Goodbye!") {
+		t.Errorf("Unexpected output %q", got)
+	}
+}
+
+func TestREPLInterrupt(t *testing.T) {
+	t.Parallel()
+	repl, calc, out := newJobsREPL()
+	interrupts := make(chan os.Signal, 1)
+	repl.interrupts = func() (<-chan os.Signal, func()) { return interrupts, func() {} }
+
+	// Ctrl-C cancels the foreground calculation and keeps the session alive
+	go func() {
+		<-calc.started
+		interrupts <- os.Interrupt
+	}()
+	if !repl.processCommand("calc 10") {
+		t.Fatal("The session must continue after Ctrl-C")
+	}
+	if got := out.take(); !strings.Contains(got, "Calculation canceled.") {
+		t.Errorf("Expected the calculation to be canceled, got %q", got)
+	}
+
+	// Waiting brings a job to the foreground
+	repl.processCommand("calc 20 &")
+	<-calc.started
+	interrupts <- os.Interrupt
+	repl.processCommand("wait 1")
+	if got := out.take(); !strings.Contains(got, "[1] canceled") {
+		t.Errorf("Expected the job to be canceled, got %q", got)
+	}
+
+	go func() {
+		<-calc.started
+		interrupts <- os.Interrupt
+	}()
+	repl.processCommand("fib(3) + 1")
+	if got := out.take(); !strings.Contains(got, "Error: evaluation canceled") {
+		t.Errorf("Expected the evaluation to be canceled, got %q", got)
+	}
+}
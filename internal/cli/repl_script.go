@@ -0,0 +1,306 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// Variables describing the last calculation, set by "calc" and "wait" so
+// that scripts can assert on results, sizes and durations.
+const (
+	lastResultVar   = "last"    // F(n)
+	lastIndexVar    = "last_n"  // n
+	lastDurationVar = "last_ms" // duration in milliseconds
+)
+
+// ─── Scripts ───
+
+// ScriptFailure is a failed command or assertion of a REPL script.
+type ScriptFailure struct {
+	// Line is the 1-based line number of the command in the script.
+	Line int
+	// Command is the text of the command.
+	Command string
+	// Reason describes the failure.
+	Reason string
+	// Assertion is true for a failed assertion, false for a failed command.
+	Assertion bool
+}
+
+// ScriptError reports the failures of a REPL script.
+type ScriptError struct {
+	// Name identifies the script in the report, e.g. its file path.
+	Name string
+	// Failures are the failed assertions, followed by the failed command
+	// that stopped the script, if any.
+	Failures []ScriptFailure
+	// Assertions is the number of assertions evaluated.
+	Assertions int
+}
+
+// Error returns one "name:line: command: reason" line per failure, and a
+// summary line.
+func (e *ScriptError) Error() string {
+	var b strings.Builder
+	failed := 0
+	for _, f := range e.Failures {
+		fmt.Fprintf(&b, "%s:%d: %s: %s\n", e.Name, f.Line, f.Command, f.Reason)
+		if f.Assertion {
+			failed++
+		}
+	}
+	if e.Aborted() {
+		fmt.Fprintf(&b, "%s: stopped at line %d, %d of %d assertions failed",
+			e.Name, e.Failures[len(e.Failures)-1].Line, failed, e.Assertions)
+	} else {
+		fmt.Fprintf(&b, "%s: %d of %d assertions failed", e.Name, failed, e.Assertions)
+	}
+	return b.String()
+}
+
+// Aborted reports whether the script stopped on a failed command, rather
+// than completing with failed assertions.
+func (e *ScriptError) Aborted() bool {
+	return len(e.Failures) > 0 && !e.Failures[len(e.Failures)-1].Assertion
+}
+
+// RunScript executes the REPL commands read from the input (see SetInput)
+// non-interactively, echoing each command before its output. Blank lines
+// and lines starting with "#" are ignored. A failed assertion is reported
+// and the script continues; a failed command stops it, as does "exit".
+// Background jobs still running at the end are canceled.
+//
+// Parameters:
+//   - name: The name of the script in the failure report, e.g. its path.
+//
+// Returns:
+//   - error: A *ScriptError if a command or assertion failed, or an error
+//     reading the script.
+func (r *REPL) RunScript(name string) error {
+	defer r.stopJobs()
+
+	report := &ScriptError{Name: name}
+	passed := 0
+	scanner := bufio.NewScanner(r.in)
+	for line := 1; scanner.Scan(); line++ {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" || strings.HasPrefix(input, "#") {
+			continue
+		}
+		r.reportJobs()
+		fmt.Fprintf(r.out, "%sfib>%s %s\n", ui.ColorGreen(), ui.ColorReset(), input)
+
+		assertion := strings.EqualFold(strings.Fields(input)[0], "assert")
+		if assertion {
+			report.Assertions++
+		}
+		more := r.processCommand(input)
+		if f := r.failure; f != nil {
+			report.Failures = append(report.Failures, ScriptFailure{
+				Line: line, Command: input, Reason: f.reason, Assertion: f.assertion,
+			})
+			if !f.assertion {
+				break
+			}
+		} else if assertion {
+			passed++
+		}
+		if !more {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+
+	fmt.Fprintf(r.out, "\n%s%d passed%s, ", ui.ColorGreen(), passed, ui.ColorReset())
+	if failed := report.Assertions - passed; failed > 0 {
+		fmt.Fprintf(r.out, "%s%d failed%s\n", ui.ColorRed(), failed, ui.ColorReset())
+	} else {
+		fmt.Fprintln(r.out, "0 failed")
+	}
+	if len(report.Failures) > 0 {
+		return report
+	}
+	return nil
+}
+
+// ─── Failures ───
+
+// commandFailure records why the last command failed.
+type commandFailure struct {
+	reason    string
+	assertion bool
+}
+
+// errorf displays an error message and records it as the failure of the
+// current command.
+func (r *REPL) errorf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(r.out, "%s%s%s\n", ui.ColorRed(), msg, ui.ColorReset())
+	r.fail(msg)
+}
+
+// fail records the failure of the current command, whose output was
+// already displayed.
+func (r *REPL) fail(reason string) {
+	r.failure = &commandFailure{reason: reason}
+}
+
+// setLast records the last calculation in the lastResultVar,
+// lastIndexVar and lastDurationVar variables.
+func (r *REPL) setLast(n uint64, result *big.Int, duration time.Duration) {
+	r.vars[lastResultVar] = intValue(result)
+	r.vars[lastIndexVar] = intValue(new(big.Int).SetUint64(n))
+	r.vars[lastDurationVar] = intValue(big.NewInt(duration.Milliseconds()))
+}
+
+// ─── Assertions ───
+
+// valueNode is an already evaluated operand.
+type valueNode struct{ v exprValue }
+
+func (n valueNode) eval(*evaluator) (exprValue, error) { return n.v, nil }
+
+// assertion evaluates an asserted expression. For a comparison, it records
+// the values of both operands to explain a failure.
+type assertion struct {
+	node        exprNode
+	op          string
+	left, right exprValue
+}
+
+func (a *assertion) eval(ev *evaluator) (exprValue, error) {
+	cmp, ok := a.node.(binaryNode)
+	if !ok {
+		return a.node.eval(ev)
+	}
+	switch cmp.op {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return a.node.eval(ev)
+	}
+	x, err := cmp.x.eval(ev)
+	if err != nil {
+		return exprValue{}, err
+	}
+	y, err := cmp.y.eval(ev)
+	if err != nil {
+		return exprValue{}, err
+	}
+	a.op, a.left, a.right = cmp.op, x, y
+	return binaryNode{cmp.op, valueNode{x}, valueNode{y}}.eval(ev)
+}
+
+// cmdAssert handles the "assert" command: the expression must be true. An
+// expression that cannot be evaluated fails the assertion.
+func (r *REPL) cmdAssert(input string) {
+	defer func() {
+		if r.failure != nil {
+			r.failure.assertion = true
+		}
+	}()
+
+	if input == "" {
+		r.errorf("Usage: assert <expression>")
+		return
+	}
+	name, node, err := parseStatement(input)
+	if err == nil && name != "" {
+		err = errors.New("cannot assert an assignment")
+	}
+	if err != nil {
+		r.errorf("Syntax error: %v", err)
+		return
+	}
+
+	check := &assertion{node: node}
+	v, err := r.evaluate(check)
+	if err != nil {
+		r.errorf("Error: %v", err)
+		return
+	}
+	if v.n != nil {
+		text, size := r.exprText(v)
+		r.errorf("Error: assertion must be a comparison or truth value, got %s%s", text, size)
+		return
+	}
+	if v.truth {
+		fmt.Fprintf(r.out, "%s✓%s %s\n", ui.ColorGreen(), ui.ColorReset(), input)
+		return
+	}
+
+	reason := "assertion failed"
+	if check.op != "" {
+		left, leftSize := r.exprText(check.left)
+		right, rightSize := r.exprText(check.right)
+		reason = fmt.Sprintf("assertion failed: got %s%s %s %s%s", left, leftSize, check.op, right, rightSize)
+	}
+	fmt.Fprintf(r.out, "%s✗ %s: %s%s\n", ui.ColorRed(), input, reason, ui.ColorReset())
+	r.fail(reason)
+}
+
+// ─── Settings ───
+
+// cmdSet handles the "set" command, which changes an option of the session.
+// Without arguments, it displays the current configuration.
+func (r *REPL) cmdSet(args []string) {
+	if len(args) == 0 {
+		r.cmdStatus()
+		return
+	}
+	if len(args) != 2 {
+		r.errorf("Usage: set <option> <value>")
+		fmt.Fprintln(r.out, "Options: algo, timeout, threshold, fft-threshold, strassen-threshold, hex")
+		return
+	}
+
+	option, value := strings.ToLower(args[0]), args[1]
+	switch option {
+	case "algo":
+		r.cmdAlgo(args[1:])
+		return
+	case "timeout":
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			r.errorf("Invalid timeout: %s", value)
+			return
+		}
+		r.config.Timeout = d
+	case "threshold", "fft-threshold", "strassen-threshold":
+		bits, err := strconv.Atoi(value)
+		if err != nil || bits < 0 {
+			r.errorf("Invalid threshold: %s", value)
+			return
+		}
+		switch option {
+		case "threshold":
+			r.config.Threshold = bits
+		case "fft-threshold":
+			r.config.FFTThreshold = bits
+		default:
+			r.config.StrassenThreshold = bits
+		}
+	case "hex":
+		switch strings.ToLower(value) {
+		case "on", "true", "yes", "1":
+			r.config.HexOutput = true
+		case "off", "false", "no", "0":
+			r.config.HexOutput = false
+		default:
+			r.errorf("Invalid value for hex: %s (expected on or off)", value)
+			return
+		}
+	default:
+		r.errorf("Unknown option: %s", option)
+		return
+	}
+	fmt.Fprintf(r.out, "%s set to %s%s%s\n", option, ui.ColorGreen(), value, ui.ColorReset())
+}
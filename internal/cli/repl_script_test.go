@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/testutil"
+)
+
+func TestREPLRunScript(t *testing.T) {
+	t.Parallel()
+	repl, out := newExprREPL(time.Second)
+	repl.SetInput(strings.NewReader(`# Regression checks
+calc 100
+assert last == fib(100)
+assert digits(last) == 21
+assert last_n == 100 and more
+
+assert last_ms >= 0
+x = lucas(10)
+assert x == 124
+assert x
+set hex on
+assert hex(255) == 255
+`))
+
+	err := repl.RunScript("checks.fib")
+	var scriptErr *ScriptError
+	if !errors.As(err, &scriptErr) {
+		t.Fatalf("Expected a *ScriptError, got %v", err)
+	}
+	if scriptErr.Aborted() || scriptErr.Assertions != 7 {
+		t.Errorf("Unexpected report %+v", scriptErr)
+	}
+	want := `checks.fib:5: assert last_n == 100 and more: Syntax error: unexpected "and" at column 15
+checks.fib:9: assert x == 124: assertion failed: got 123 == 124
+checks.fib:10: assert x: Error: assertion must be a comparison or truth value, got 123
+checks.fib: 3 of 7 assertions failed`
+	if got := err.Error(); got != want {
+		t.Errorf("Unexpected report:\n%s\nwant:\n%s", got, want)
+	}
+	if !repl.config.HexOutput {
+		t.Error("Expected set to enable hexadecimal output")
+	}
+
+	got := testutil.StripAnsiCodes(out.String())
+	for _, want := range []string{"fib> calc 100", "✓ digits(last) == 21", "4 passed, 3 failed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Regression checks") {
+		t.Error("Comments must not be echoed")
+	}
+}
+
+func TestREPLRunScript_Abort(t *testing.T) {
+	t.Parallel()
+	repl, out := newExprREPL(time.Second)
+	repl.SetInput(strings.NewReader("assert 1 == 2\nalgo nope\nassert 1 == 1\n"))
+
+	err := repl.RunScript("stdin")
+	var scriptErr *ScriptError
+	if !errors.As(err, &scriptErr) || !scriptErr.Aborted() {
+		t.Fatalf("Expected an aborted script, got %v", err)
+	}
+	want := `stdin:1: assert 1 == 2: assertion failed: got 1 == 2
+stdin:2: algo nope: Unknown algorithm: nope
+stdin: stopped at line 2, 1 of 1 assertions failed`
+	if got := err.Error(); got != want {
+		t.Errorf("Unexpected report:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(out.String(), "assert 1 == 1") {
+		t.Error("The script must stop at the failed command")
+	}
+
+	// exit ends a script successfully
+	repl, _ = newExprREPL(time.Second)
+	repl.SetInput(strings.NewReader("assert 1 == 1\nexit\nassert 1 == 2\n"))
+	if err := repl.RunScript("stdin"); err != nil {
+		t.Errorf("Expected success, got %v", err)
+	}
+}
+
+func TestREPLSet(t *testing.T) {
+	t.Parallel()
+	repl, out := newExprREPL(time.Second)
+
+	for _, input := range []string{"set timeout 2s", "set threshold 1024", "set fft-threshold 2048", "set strassen-threshold 512", "set hex yes"} {
+		repl.processCommand(input)
+		if repl.failure != nil {
+			t.Errorf("%q failed: %s", input, repl.failure.reason)
+		}
+	}
+	cfg := repl.config
+	if cfg.Timeout != 2*time.Second || cfg.Threshold != 1024 || cfg.FFTThreshold != 2048 || cfg.StrassenThreshold != 512 || !cfg.HexOutput {
+		t.Errorf("Unexpected configuration %+v", cfg)
+	}
+
+	for input, want := range map[string]string{
+		"set timeout soon": "Invalid timeout: soon",
+		"set threshold -1": "Invalid threshold: -1",
+		"set hex maybe":    "Invalid value for hex: maybe",
+		"set color on":     "Unknown option: color",
+		"set algo":         "Usage: set <option> <value>",
+		"set algo nope":    "Unknown algorithm: nope",
+	} {
+		out.Reset()
+		repl.processCommand(input)
+		if got := testutil.StripAnsiCodes(out.String()); !strings.Contains(got, want) || repl.failure == nil {
+			t.Errorf("%q: expected failure %q, got %q", input, want, got)
+		}
+	}
+}
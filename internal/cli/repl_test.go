@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"math/big"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/resultfile"
 	"github.com/agbru/fibcalc/internal/testutil"
 )
 
@@ -141,6 +143,47 @@ func TestProcessCommand(t *testing.T) {
 		out.Reset()
 	})
 
+	t.Run("explain", func(t *testing.T) {
+		repl.config.Estimator = func(n uint64, algo string) (time.Duration, string, bool) {
+			return 3 * time.Second, "default", true
+		}
+		repl.processCommand("explain 100000")
+		output := strip(out.String())
+		if !strings.Contains(output, "Execution Plan: F(100000) with 'mock'") {
+			t.Errorf("Expected execution plan output, got %s", output)
+		}
+		if !strings.Contains(output, "(default cost model)") {
+			t.Errorf("Expected estimate from estimator, got %s", output)
+		}
+		out.Reset()
+
+		repl.processCommand("explain")
+		if !strings.Contains(out.String(), "Usage: explain <n>") {
+			t.Error("Expected usage message")
+		}
+		out.Reset()
+	})
+
+	t.Run("load", func(t *testing.T) {
+		repl.config.HexOutput = false
+		path := filepath.Join(t.TempDir(), "f12.fib")
+		if err := resultfile.WriteFile(path, big.NewInt(144), resultfile.Header{N: 12, Algorithm: "mock"}, resultfile.CompressionNone); err != nil {
+			t.Fatal(err)
+		}
+		repl.processCommand("load " + path)
+		output := strip(out.String())
+		if !strings.Contains(output, "F(12) = 144") || !strings.Contains(output, "(verified)") {
+			t.Errorf("Expected the loaded value and its checksum, got %s", output)
+		}
+		out.Reset()
+
+		repl.processCommand("load " + filepath.Join(t.TempDir(), "missing.fib"))
+		if !strings.Contains(out.String(), "Error:") {
+			t.Error("Expected an error for a missing file")
+		}
+		out.Reset()
+	})
+
 	t.Run("help", func(t *testing.T) {
 		repl.processCommand("help")
 		if !strings.Contains(out.String(), "Available commands") {
@@ -224,6 +224,9 @@ func DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressU
 	ticker := time.NewTicker(ProgressRefreshRate)
 	defer ticker.Stop()
 
+	// The step detail is only meaningful when a single calculation is running
+	var lastEvent *fibonacci.ProgressEvent
+
 	for {
 		select {
 		case update, ok := <-progressChan:
@@ -245,6 +248,9 @@ func DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressU
 				return
 			}
 			state.UpdateWithETA(update.CalculatorIndex, update.Value)
+			if update.Event != nil && numCalculators == 1 {
+				lastEvent = update.Event
+			}
 		case <-ticker.C:
 			avgProgress := state.CalculateAverage()
 			eta := state.GetETA()
@@ -254,11 +260,53 @@ func DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressU
 				label = "Avg progress"
 			}
 			etaStr := FormatETA(eta)
-			s.UpdateSuffix(fmt.Sprintf(" %s: %6.2f%% [%s] ETA: %s", label, avgProgress*100, bar, etaStr))
+			detail := ""
+			if lastEvent != nil {
+				detail = " | " + FormatStepDetail(*lastEvent)
+			}
+			s.UpdateSuffix(fmt.Sprintf(" %s: %6.2f%% [%s] ETA: %s%s", label, avgProgress*100, bar, etaStr, detail))
 		}
 	}
 }
 
+// FormatStepDetail formats the details of a calculation step for display
+// next to the progress bar, e.g.
+// "step 18/24, 1,048,576-bit operands, fft (parallel), 12ms, 48.0 MiB".
+//
+// Parameters:
+//   - ev: The step event.
+//
+// Returns:
+//   - string: The formatted step detail.
+func FormatStepDetail(ev fibonacci.ProgressEvent) string {
+	method := ev.Method
+	if ev.Parallel {
+		method += " (parallel)"
+	}
+	return fmt.Sprintf("step %d/%d, %s-bit operands, %s, %s, %s",
+		ev.Step, ev.TotalSteps,
+		formatNumberString(fmt.Sprintf("%d", ev.OperandBits)),
+		method,
+		FormatExecutionDuration(ev.StepDuration),
+		FormatStepAllocated(ev))
+}
+
+// FormatStepAllocated formats the bytes allocated by a calculation step, or
+// "n/a" when other calculations overlapped it: the allocation counter is
+// process-wide and would include theirs.
+//
+// Parameters:
+//   - ev: The step event.
+//
+// Returns:
+//   - string: The formatted size.
+func FormatStepAllocated(ev fibonacci.ProgressEvent) string {
+	if ev.Overlapped {
+		return "n/a"
+	}
+	return FormatBytes(ev.BytesAllocated)
+}
+
 // displayResultHeader prints the binary size of the result.
 //
 // Parameters:
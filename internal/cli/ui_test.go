@@ -53,6 +53,33 @@ func TestFormatExecutionDuration(t *testing.T) {
 	}
 }
 
+func TestFormatStepDetail(t *testing.T) {
+	t.Parallel()
+	ev := fibonacci.ProgressEvent{
+		Step:           18,
+		TotalSteps:     24,
+		OperandBits:    1048576,
+		Method:         fibonacci.MethodFFT,
+		Parallel:       true,
+		StepDuration:   12 * time.Millisecond,
+		BytesAllocated: 48 << 20,
+	}
+	want := "step 18/24, 1,048,576-bit operands, fft (parallel), 12ms, 48.0 MiB"
+	if got := FormatStepDetail(ev); got != want {
+		t.Errorf("FormatStepDetail() = %q; want %q", got, want)
+	}
+
+	ev.Parallel = false
+	if got := FormatStepDetail(ev); strings.Contains(got, "parallel") {
+		t.Errorf("FormatStepDetail() = %q; should not mention parallel", got)
+	}
+
+	ev.BytesAllocated, ev.Overlapped = 0, true
+	if got := FormatStepDetail(ev); !strings.HasSuffix(got, ", 12ms, n/a") {
+		t.Errorf("FormatStepDetail() = %q; expected the allocations of an overlapped step not available", got)
+	}
+}
+
 func TestProgressBar(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
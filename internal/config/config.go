@@ -8,11 +8,13 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
 	"time"
 
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/resultfile"
 )
 
 const (
@@ -33,12 +35,46 @@ const (
 	DefaultPort = "8080"
 	// DefaultAlgo is the default algorithm selection.
 	DefaultAlgo = "all"
+	// AutoAlgo is the pseudo-algorithm that picks the fastest calculator for N
+	// from the calibration cost model.
+	AutoAlgo = "auto"
 	// DefaultThreshold is the default parallelism threshold in bits.
 	DefaultThreshold = 4096
 	// DefaultFFTThreshold is the default FFT multiplication threshold in bits.
 	DefaultFFTThreshold = 500_000
 	// DefaultStrassenThreshold is the default Strassen algorithm threshold in bits.
 	DefaultStrassenThreshold = 3072
+	// DefaultProgressFD is the default file descriptor for machine-readable
+	// progress output (stderr).
+	DefaultProgressFD = 2
+	// DefaultBenchRuns is the default number of measured benchmark runs.
+	DefaultBenchRuns = 5
+	// DefaultBenchWarmup is the default number of benchmark warm-up runs.
+	DefaultBenchWarmup = 1
+	// DefaultBenchTolerance is the default regression tolerance, in percent.
+	DefaultBenchTolerance = 5.0
+	// DefaultIntegrityInterval is the default number of steps between
+	// integrity checks (every step).
+	DefaultIntegrityInterval = 1
+	// DefaultReplaySpeed replays a session in real time.
+	DefaultReplaySpeed = 1.0
+)
+
+// SelfTestCommand is the subcommand that runs the arithmetic self-test
+// (`fibcalc selftest`). It may appear before or after the flags.
+const SelfTestCommand = "selftest"
+
+// InspectCommand is the subcommand that prints the header of a result file
+// and verifies its checksum (`fibcalc inspect <file>`). The file must
+// immediately follow the subcommand.
+const InspectCommand = "inspect"
+
+// Progress output formats.
+const (
+	// ProgressFormatText draws an interactive progress bar (default).
+	ProgressFormatText = "text"
+	// ProgressFormatJSON writes newline-delimited JSON progress records.
+	ProgressFormatJSON = "json"
 )
 
 // AppConfig aggregates the application's configuration parameters, parsed from
@@ -53,7 +89,8 @@ type AppConfig struct {
 	Details bool
 	// Timeout sets the maximum duration for the calculation.
 	Timeout time.Duration
-	// Algo specifies the algorithm to use ("all", "fast", "matrix", etc.).
+	// Algo specifies the algorithm to use ("all", "auto", "fast", "matrix", etc.).
+	// "auto" selects the algorithm predicted to be fastest for N.
 	Algo string
 	// Threshold determines the bit size at which multiplications are parallelized.
 	Threshold int
@@ -61,6 +98,10 @@ type AppConfig struct {
 	FFTThreshold int
 	// StrassenThreshold controls when matrix multiplication switches to Strassen.
 	StrassenThreshold int
+	// ThresholdsSet records which thresholds the user set explicitly, by flag
+	// or environment variable, so that the range thresholds of a calibration
+	// profile do not override them.
+	ThresholdsSet ThresholdsSet
 	// Calibrate, if true, runs the application in calibration mode to find the
 	// optimal parallelism threshold.
 	Calibrate bool
@@ -88,8 +129,29 @@ type AppConfig struct {
 	Quiet bool
 	// HexOutput, if true, displays the result in hexadecimal format.
 	HexOutput bool
+	// OutputFormat is the format of the result file: "dec", "hex" or "bin".
+	// When empty, the file is decimal, or hexadecimal with HexOutput.
+	OutputFormat string
+	// OutputCompress is the compression of the payload of binary result
+	// files: "gzip", "zstd", or empty for none.
+	OutputCompress string
+	// Report, if specified, saves an HTML report of the run to this file
+	// path.
+	Report string
+	// Record, if specified, records the progress events, timings and result
+	// summaries of the run to this JSONL session file.
+	Record string
+	// Replay, if specified, replays the session recorded in this file through
+	// the CLI progress bar, or the TUI with TUIMode, instead of calculating.
+	Replay string
+	// ReplaySpeed is the speed factor of a replay: 1 replays in real time,
+	// 10 ten times faster and 0 without delays.
+	ReplaySpeed float64
 	// Interactive, if true, starts the application in REPL mode.
 	Interactive bool
+	// Script, if specified, runs the REPL commands of this file
+	// non-interactively; "-" reads them from standard input.
+	Script string
 	// Completion, if set, generates shell completion script for the specified shell.
 	// Valid values are: "bash", "zsh", "fish", "powershell".
 	Completion string
@@ -100,15 +162,104 @@ type AppConfig struct {
 	// The TUI provides a rich terminal interface with navigation, progress bars,
 	// and interactive algorithm selection.
 	TUIMode bool
+	// Remote is the base URL of a fibcalc server (e.g. "http://host:8080")
+	// on which the TUI runs the calculations instead of locally.
+	Remote string
+	// Explain, if true, prints the execution plan for F(N) (step schedule,
+	// multiplication methods, switch points, peak memory and estimated
+	// duration) without performing the calculation.
+	Explain bool
+	// MaxMemory is the memory budget for calculations (e.g. "2GiB"). When
+	// empty, GOMEMLIMIT is honoured. Calculations whose estimated peak exceeds
+	// the budget are downgraded to low-memory mode or refused.
+	MaxMemory string
+	// LowMemory trades speed for a smaller memory footprint.
+	LowMemory bool
+	// DynamicThresholds lets the doubling loop adjust the FFT and parallel
+	// thresholds from the timing of its own steps.
+	DynamicThresholds bool
+	// ProgressFormat selects how progress is reported: "text" (progress bar)
+	// or "json" (NDJSON records for scripts and job runners).
+	ProgressFormat string
+	// ProgressFD is the file descriptor receiving JSON progress records
+	// (2 = stderr, 1 = stdout, or any descriptor inherited from the parent).
+	ProgressFD int
+	// TraceOut, if set, enables OpenTelemetry tracing and writes the spans as
+	// JSON to this file ("stdout" and "stderr" select the streams).
+	TraceOut string
+	// CPUProfile, if set, writes a CPU profile of the run to this file.
+	CPUProfile string
+	// MemProfile, if set, writes a heap profile to this file at exit.
+	MemProfile string
+	// BlockProfile, if set, writes a goroutine blocking profile to this file at exit.
+	BlockProfile string
+	// ExecTrace, if set, writes a runtime execution trace to this file.
+	ExecTrace string
+	// ServerPprof, if true, exposes the /debug/pprof endpoints in server mode.
+	// The endpoints require PprofToken as a bearer token.
+	ServerPprof bool
+	// PprofToken is the bearer token protecting the /debug/pprof endpoints.
+	PprofToken string
+	// Race, if true, runs the selected calculators concurrently and keeps the
+	// first successful result, canceling the others.
+	Race bool
+	// Sequential, if true, runs the calculators of a comparison one after
+	// another, so that the resources of each are measured alone.
+	Sequential bool
+	// Bench, if true, runs the statistical benchmark mode: each calculator
+	// is run sequentially with warm-up runs and repetitions.
+	Bench bool
+	// BenchRuns is the number of measured repetitions per calculator.
+	BenchRuns int
+	// BenchWarmup is the number of unmeasured warm-up runs per calculator.
+	BenchWarmup int
+	// BenchProcs is a comma-separated list of GOMAXPROCS values to benchmark
+	// under (e.g. "1,4,8"). Empty means the current setting.
+	BenchProcs string
+	// BenchOut, if set, exports the benchmark report to this file (CSV for a
+	// ".csv" extension, JSON otherwise).
+	BenchOut string
+	// BenchBaseline, if set, is a JSON benchmark report to compare against.
+	BenchBaseline string
+	// BenchTolerance is the accepted slowdown of the median against the
+	// baseline, in percent, before a regression is reported.
+	BenchTolerance float64
+	// IntegrityCheck, if true, shadows the calculation modulo a random 61-bit
+	// prime and aborts as soon as the big-integer state diverges from it.
+	IntegrityCheck bool
+	// IntegrityInterval is the number of steps between integrity checks.
+	IntegrityInterval int
+	// SelfTest, if true, runs the known-answer self-test of the arithmetic
+	// instead of a calculation (set by the "selftest" subcommand).
+	SelfTest bool
+	// InspectFile is the result file to inspect instead of running a
+	// calculation (set by the "inspect" subcommand).
+	InspectFile string
+}
+
+// ThresholdsSet records which thresholds of an AppConfig were set
+// explicitly.
+type ThresholdsSet struct {
+	// Parallel is true if --threshold or FIBCALC_THRESHOLD was set.
+	Parallel bool
+	// FFT is true if --fft-threshold or FIBCALC_FFT_THRESHOLD was set.
+	FFT bool
+	// Strassen is true if --strassen-threshold or
+	// FIBCALC_STRASSEN_THRESHOLD was set.
+	Strassen bool
 }
 
 // ToCalculationOptions converts the application configuration into
 // fibonacci.Options for use by the calculators.
 func (c AppConfig) ToCalculationOptions() fibonacci.Options {
 	return fibonacci.Options{
-		ParallelThreshold: c.Threshold,
-		FFTThreshold:      c.FFTThreshold,
-		StrassenThreshold: c.StrassenThreshold,
+		ParallelThreshold:       c.Threshold,
+		FFTThreshold:            c.FFTThreshold,
+		StrassenThreshold:       c.StrassenThreshold,
+		LowMemory:               c.LowMemory,
+		EnableDynamicThresholds: c.DynamicThresholds,
+		IntegrityCheck:          c.IntegrityCheck,
+		IntegrityInterval:       c.IntegrityInterval,
 	}
 }
 
@@ -133,6 +284,73 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 	if c.FFTThreshold < 0 {
 		return apperrors.NewConfigError("FFT threshold cannot be negative: %d", c.FFTThreshold)
 	}
+	if c.StrassenThreshold < 0 {
+		return apperrors.NewConfigError("Strassen threshold cannot be negative: %d", c.StrassenThreshold)
+	}
+	if c.MaxMemory != "" {
+		if _, err := ParseByteSize(c.MaxMemory); err != nil {
+			return err
+		}
+	}
+	if c.ProgressFormat != "" && c.ProgressFormat != ProgressFormatText && c.ProgressFormat != ProgressFormatJSON {
+		return apperrors.NewConfigError("unrecognized progress format: '%s'. Valid formats are: '%s', '%s'", c.ProgressFormat, ProgressFormatText, ProgressFormatJSON)
+	}
+	if c.ProgressFD < 0 {
+		return apperrors.NewConfigError("progress file descriptor cannot be negative: %d", c.ProgressFD)
+	}
+	if c.ProgressFD == 0 && c.ProgressFormat == ProgressFormatJSON {
+		return apperrors.NewConfigError("progress file descriptor 0 is standard input")
+	}
+	if c.Bench {
+		if c.BenchRuns <= 0 {
+			return apperrors.NewConfigError("benchmark runs must be strictly positive: %d", c.BenchRuns)
+		}
+		if c.BenchWarmup < 0 {
+			return apperrors.NewConfigError("benchmark warm-up runs cannot be negative: %d", c.BenchWarmup)
+		}
+		if c.BenchTolerance < 0 {
+			return apperrors.NewConfigError("benchmark tolerance cannot be negative: %g", c.BenchTolerance)
+		}
+	}
+	if c.Sequential && c.Race {
+		return apperrors.NewConfigError("--sequential cannot be combined with --race")
+	}
+	if c.IntegrityCheck && c.IntegrityInterval < 1 {
+		return apperrors.NewConfigError("integrity check interval must be strictly positive: %d", c.IntegrityInterval)
+	}
+	switch c.OutputFormat {
+	case "", resultfile.FormatDecimal, resultfile.FormatHex, resultfile.FormatBinary:
+	default:
+		return apperrors.NewConfigError("unrecognized output format: '%s'. Valid formats are: '%s', '%s', '%s'", c.OutputFormat, resultfile.FormatDecimal, resultfile.FormatHex, resultfile.FormatBinary)
+	}
+	switch c.OutputCompress {
+	case "", resultfile.CompressionGzip, resultfile.CompressionZstd:
+	default:
+		return apperrors.NewConfigError("unrecognized output compression: '%s'. Valid compressions are: '%s', '%s'", c.OutputCompress, resultfile.CompressionGzip, resultfile.CompressionZstd)
+	}
+	if c.OutputCompress != "" && c.OutputFormat != resultfile.FormatBinary {
+		return apperrors.NewConfigError("--output-compress requires --output-format %s", resultfile.FormatBinary)
+	}
+	if c.Remote != "" {
+		if !c.TUIMode {
+			return apperrors.NewConfigError("--remote requires --tui")
+		}
+		if u, err := url.Parse(c.Remote); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return apperrors.NewConfigError("invalid remote server URL: '%s'. Expected http://host:port or https://host:port", c.Remote)
+		}
+	}
+	if c.Replay != "" && (c.Record != "" || c.Remote != "") {
+		return apperrors.NewConfigError("--replay cannot be combined with --record or --remote")
+	}
+	if c.Record != "" && (c.TUIMode || c.ServerMode || c.Interactive || c.Script != "") {
+		return apperrors.NewConfigError("--record records command-line calculations and cannot be used with --tui, --server, --interactive or --script")
+	}
+	if c.ReplaySpeed < 0 {
+		return apperrors.NewConfigError("replay speed cannot be negative: %g", c.ReplaySpeed)
+	}
+	if c.ServerPprof && c.PprofToken == "" {
+		return apperrors.NewConfigError("--server-pprof requires an access token (--pprof-token or FIBCALC_PPROF_TOKEN)")
+	}
 	isAlgoAvailable := false
 	for _, a := range availableAlgos {
 		if a == c.Algo {
@@ -140,8 +358,8 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 			break
 		}
 	}
-	if c.Algo != "all" && !isAlgoAvailable {
-		return apperrors.NewConfigError("unrecognized algorithm: '%s'. Valid algorithms are: 'all' or [%s]", c.Algo, strings.Join(availableAlgos, ", "))
+	if c.Algo != "all" && c.Algo != AutoAlgo && !isAlgoAvailable {
+		return apperrors.NewConfigError("unrecognized algorithm: '%s'. Valid algorithms are: 'all', 'auto' or [%s]", c.Algo, strings.Join(availableAlgos, ", "))
 	}
 	return nil
 }
@@ -168,7 +386,7 @@ func (c AppConfig) Validate(availableAlgos []string) error {
 func ParseConfig(programName string, args []string, errorWriter io.Writer, availableAlgos []string) (AppConfig, error) {
 	fs := flag.NewFlagSet(programName, flag.ContinueOnError)
 	fs.SetOutput(errorWriter)
-	algoHelp := fmt.Sprintf("Algorithm to use: 'all' (default) or one of [%s].", strings.Join(availableAlgos, ", "))
+	algoHelp := fmt.Sprintf("Algorithm to use: 'all' (default), 'auto' or one of [%s].", strings.Join(availableAlgos, ", "))
 
 	config := AppConfig{}
 	fs.Uint64Var(&config.N, "n", DefaultN, "Index n of the Fibonacci number to calculate.")
@@ -195,22 +413,62 @@ func ParseConfig(programName string, args []string, errorWriter io.Writer, avail
 	fs.BoolVar(&config.Quiet, "quiet", false, "Quiet mode - minimal output for scripts.")
 	fs.BoolVar(&config.Quiet, "q", false, "Quiet mode (shorthand).")
 	fs.BoolVar(&config.HexOutput, "hex", false, "Display result in hexadecimal format.")
+	fs.StringVar(&config.OutputFormat, "output-format", "", "Result file format: 'dec', 'hex' or 'bin' (default: dec, or hex with --hex).")
+	fs.StringVar(&config.OutputCompress, "output-compress", "", "Compress the payload of binary result files: 'gzip' or 'zstd'.")
+	fs.StringVar(&config.Report, "report", "", "Save a self-contained HTML report of the run to this file path.")
+	fs.StringVar(&config.Record, "record", "", "Record the progress events, timings and results of the run to this JSONL session file.")
+	fs.StringVar(&config.Replay, "replay", "", "Replay a recorded session file through the progress bar (or the TUI with --tui) instead of calculating.")
+	fs.Float64Var(&config.ReplaySpeed, "replay-speed", DefaultReplaySpeed, "Speed factor of --replay: 1 is real time, 10 ten times faster, 0 without delays.")
 	fs.BoolVar(&config.Interactive, "interactive", false, "Start in interactive REPL mode.")
+	fs.StringVar(&config.Script, "script", "", "Run the REPL commands and assertions of this file ('-' for stdin) and exit.")
 	fs.StringVar(&config.Completion, "completion", "", "Generate shell completion script (bash, zsh, fish, powershell).")
 	fs.BoolVar(&config.Concise, "calculate", false, "Display the calculated value (disabled by default).")
 	fs.BoolVar(&config.Concise, "c", false, "Display the calculated value (shorthand).")
 	fs.BoolVar(&config.TUIMode, "tui", false, "Start in interactive TUI mode with rich terminal interface.")
+	fs.StringVar(&config.Remote, "remote", "", "Run the TUI calculations on the fibcalc server at this URL, e.g. http://host:8080.")
+	fs.BoolVar(&config.Explain, "explain", false, "Print the execution plan for F(n) without computing it.")
+	fs.StringVar(&config.MaxMemory, "max-memory", "", "Memory budget for calculations, e.g. 2GiB (default: GOMEMLIMIT if set).")
+	fs.BoolVar(&config.LowMemory, "low-memory", false, "Trade speed for a smaller memory footprint.")
+	fs.BoolVar(&config.DynamicThresholds, "dynamic-thresholds", false, "Adjust the FFT and parallel thresholds during the calculation from measured step times.")
+	fs.StringVar(&config.ProgressFormat, "progress-format", ProgressFormatText, "Progress output format: 'text' (progress bar) or 'json' (NDJSON records).")
+	fs.IntVar(&config.ProgressFD, "progress-fd", DefaultProgressFD, "File descriptor for JSON progress records (default: stderr).")
+	fs.StringVar(&config.TraceOut, "trace-out", "", "Write OpenTelemetry spans as JSON to this file ('stdout' or 'stderr' for streams).")
+	fs.StringVar(&config.CPUProfile, "cpuprofile", "", "Write a CPU profile to this file.")
+	fs.StringVar(&config.MemProfile, "memprofile", "", "Write a heap profile to this file at exit.")
+	fs.StringVar(&config.BlockProfile, "blockprofile", "", "Write a goroutine blocking profile to this file at exit.")
+	fs.StringVar(&config.ExecTrace, "exec-trace", "", "Write a runtime execution trace to this file (see 'go tool trace').")
+	fs.BoolVar(&config.ServerPprof, "server-pprof", false, "Expose /debug/pprof in server mode (requires --pprof-token).")
+	fs.StringVar(&config.PprofToken, "pprof-token", "", "Bearer token required to access /debug/pprof.")
+	fs.BoolVar(&config.Race, "race", false, "Run the calculators concurrently and keep the first successful result.")
+	fs.BoolVar(&config.Sequential, "sequential", false, "Run the compared calculators one after another, measuring the resources of each alone.")
+	fs.BoolVar(&config.Bench, "bench", false, "Run the statistical benchmark: calculators run sequentially with warm-up and repetitions.")
+	fs.IntVar(&config.BenchRuns, "bench-runs", DefaultBenchRuns, "Number of measured runs per calculator in benchmark mode.")
+	fs.IntVar(&config.BenchWarmup, "bench-warmup", DefaultBenchWarmup, "Number of warm-up runs per calculator in benchmark mode.")
+	fs.StringVar(&config.BenchProcs, "bench-procs", "", "Comma-separated GOMAXPROCS values to benchmark under, e.g. 1,4,8.")
+	fs.StringVar(&config.BenchOut, "bench-out", "", "Export the benchmark report to this file (.csv for CSV, JSON otherwise).")
+	fs.StringVar(&config.BenchBaseline, "bench-baseline", "", "JSON benchmark report to compare against; regressions exit with status 5.")
+	fs.Float64Var(&config.BenchTolerance, "bench-tolerance", DefaultBenchTolerance, "Accepted median slowdown against the baseline, in percent.")
+	fs.BoolVar(&config.IntegrityCheck, "integrity", false, "Shadow the calculation modulo a random 61-bit prime and abort on divergence.")
+	fs.IntVar(&config.IntegrityInterval, "integrity-interval", DefaultIntegrityInterval, "Number of steps between integrity checks (the last step is always checked).")
 
 	setCustomUsage(fs)
 
-	if err := fs.Parse(args); err != nil {
+	if err := parseArgs(fs, args, &config); err != nil {
 		return AppConfig{}, err
 	}
 
 	// Apply environment variable overrides for flags not explicitly set
 	applyEnvOverrides(&config, fs)
+	config.ThresholdsSet = ThresholdsSet{
+		Parallel: isSet(fs, "threshold", "THRESHOLD"),
+		FFT:      isSet(fs, "fft-threshold", "FFT_THRESHOLD"),
+		Strassen: isSet(fs, "strassen-threshold", "STRASSEN_THRESHOLD"),
+	}
 
 	config.Algo = strings.ToLower(config.Algo)
+	config.ProgressFormat = strings.ToLower(config.ProgressFormat)
+	config.OutputFormat = strings.ToLower(config.OutputFormat)
+	config.OutputCompress = strings.ToLower(config.OutputCompress)
 	if err := config.Validate(availableAlgos); err != nil {
 		fmt.Fprintln(errorWriter, "Configuration error:", err)
 		fs.Usage()
@@ -218,3 +476,30 @@ func ParseConfig(programName string, args []string, errorWriter io.Writer, avail
 	}
 	return config, nil
 }
+
+// parseArgs parses the flags and the subcommands, which may appear before
+// or after the flags. Parsing stops at the first unknown positional
+// argument.
+func parseArgs(fs *flag.FlagSet, args []string, config *AppConfig) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for fs.NArg() > 0 {
+		rest := fs.Args()[1:]
+		switch fs.Arg(0) {
+		case SelfTestCommand:
+			config.SelfTest = true
+		case InspectCommand:
+			if len(rest) == 0 {
+				return apperrors.NewConfigError("%s requires a result file", InspectCommand)
+			}
+			config.InspectFile, rest = rest[0], rest[1:]
+		default:
+			return nil
+		}
+		if err := fs.Parse(rest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
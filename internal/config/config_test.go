@@ -3,6 +3,7 @@ package config
 import (
 	"io"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -90,6 +91,26 @@ func TestParseConfig(t *testing.T) {
 			"FIBCALC_OUTPUT":              "out.txt",
 			"FIBCALC_CALIBRATION_PROFILE": "prof.json",
 			"FIBCALC_JSON":                "true",
+			"FIBCALC_EXPLAIN":             "true",
+			"FIBCALC_PROGRESS_FORMAT":     "JSON",
+			"FIBCALC_PROGRESS_FD":         "3",
+			"FIBCALC_TRACE_OUT":           "trace.json",
+			"FIBCALC_CPUPROFILE":          "cpu.pprof",
+			"FIBCALC_MEMPROFILE":          "mem.pprof",
+			"FIBCALC_BLOCKPROFILE":        "block.pprof",
+			"FIBCALC_EXEC_TRACE":          "exec.trace",
+			"FIBCALC_SERVER_PPROF":        "true",
+			"FIBCALC_PPROF_TOKEN":         "secret",
+			"FIBCALC_RACE":                "true",
+			"FIBCALC_BENCH":               "true",
+			"FIBCALC_BENCH_RUNS":          "9",
+			"FIBCALC_BENCH_WARMUP":        "2",
+			"FIBCALC_BENCH_PROCS":         "1,4",
+			"FIBCALC_BENCH_OUT":           "bench.csv",
+			"FIBCALC_BENCH_BASELINE":      "base.json",
+			"FIBCALC_BENCH_TOLERANCE":     "7.5",
+			"FIBCALC_INTEGRITY":           "true",
+			"FIBCALC_INTEGRITY_INTERVAL":  "4",
 		}
 
 		for k, v := range env {
@@ -164,6 +185,39 @@ func TestParseConfig(t *testing.T) {
 		if !cfg.JSONOutput {
 			t.Error("Expected JSONOutput true")
 		}
+		if !cfg.Explain {
+			t.Error("Expected Explain true")
+		}
+		if cfg.ProgressFormat != ProgressFormatJSON {
+			t.Errorf("Expected ProgressFormat json, got %s", cfg.ProgressFormat)
+		}
+		if cfg.ProgressFD != 3 {
+			t.Errorf("Expected ProgressFD 3, got %d", cfg.ProgressFD)
+		}
+		if cfg.TraceOut != "trace.json" {
+			t.Errorf("Expected TraceOut trace.json, got %s", cfg.TraceOut)
+		}
+		if cfg.CPUProfile != "cpu.pprof" || cfg.MemProfile != "mem.pprof" ||
+			cfg.BlockProfile != "block.pprof" || cfg.ExecTrace != "exec.trace" {
+			t.Errorf("Unexpected profile paths: %q %q %q %q", cfg.CPUProfile, cfg.MemProfile, cfg.BlockProfile, cfg.ExecTrace)
+		}
+		if !cfg.ServerPprof || cfg.PprofToken != "secret" {
+			t.Errorf("Expected ServerPprof with token, got %v %q", cfg.ServerPprof, cfg.PprofToken)
+		}
+		if !cfg.Race {
+			t.Error("Expected Race true")
+		}
+		if !cfg.Bench || cfg.BenchRuns != 9 || cfg.BenchWarmup != 2 || cfg.BenchProcs != "1,4" ||
+			cfg.BenchOut != "bench.csv" || cfg.BenchBaseline != "base.json" || cfg.BenchTolerance != 7.5 {
+			t.Errorf("Unexpected benchmark settings: %v %d %d %q %q %q %g", cfg.Bench, cfg.BenchRuns, cfg.BenchWarmup,
+				cfg.BenchProcs, cfg.BenchOut, cfg.BenchBaseline, cfg.BenchTolerance)
+		}
+		if !cfg.IntegrityCheck || cfg.IntegrityInterval != 4 {
+			t.Errorf("Expected integrity checks every 4 steps, got %v %d", cfg.IntegrityCheck, cfg.IntegrityInterval)
+		}
+		if opts := cfg.ToCalculationOptions(); !opts.IntegrityCheck || opts.IntegrityInterval != 4 {
+			t.Errorf("Integrity settings not passed to the calculation options: %+v", opts)
+		}
 	})
 
 	t.Run("FlagPrecedenceOverEnv", func(t *testing.T) {
@@ -181,6 +235,91 @@ func TestParseConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("ThresholdsSet", func(t *testing.T) {
+		os.Setenv("FIBCALC_STRASSEN_THRESHOLD", "2048")
+		defer os.Unsetenv("FIBCALC_STRASSEN_THRESHOLD")
+
+		cfg, err := ParseConfig("fibcalc", []string{"--fft-threshold", "1000000"}, io.Discard, availableAlgos)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := (ThresholdsSet{FFT: true, Strassen: true}); cfg.ThresholdsSet != want {
+			t.Errorf("Expected %+v, got %+v", want, cfg.ThresholdsSet)
+		}
+	})
+
+	t.Run("SelfTestCommand", func(t *testing.T) {
+		t.Parallel()
+		for _, args := range [][]string{
+			{"selftest", "-json"},
+			{"-json", "selftest"},
+		} {
+			cfg, err := ParseConfig("fibcalc", args, io.Discard, availableAlgos)
+			if err != nil {
+				t.Fatalf("Unexpected error for %v: %v", args, err)
+			}
+			if !cfg.SelfTest || !cfg.JSONOutput {
+				t.Errorf("Expected SelfTest and JSONOutput for %v, got %v %v", args, cfg.SelfTest, cfg.JSONOutput)
+			}
+		}
+		cfg, err := ParseConfig("fibcalc", []string{"-n", "10"}, io.Discard, availableAlgos)
+		if err != nil || cfg.SelfTest {
+			t.Errorf("Expected SelfTest false without the subcommand, got %v (err %v)", cfg.SelfTest, err)
+		}
+	})
+
+	t.Run("InspectCommand", func(t *testing.T) {
+		t.Parallel()
+		for _, args := range [][]string{
+			{"inspect", "result.fib", "-json"},
+			{"-json", "inspect", "result.fib"},
+		} {
+			cfg, err := ParseConfig("fibcalc", args, io.Discard, availableAlgos)
+			if err != nil {
+				t.Fatalf("Unexpected error for %v: %v", args, err)
+			}
+			if cfg.InspectFile != "result.fib" || !cfg.JSONOutput {
+				t.Errorf("Expected InspectFile and JSONOutput for %v, got %q %v", args, cfg.InspectFile, cfg.JSONOutput)
+			}
+		}
+		if _, err := ParseConfig("fibcalc", []string{"inspect"}, io.Discard, availableAlgos); err == nil {
+			t.Error("Expected an error for inspect without a file")
+		}
+	})
+
+	t.Run("OutputFormat", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := ParseConfig("fibcalc", []string{"-o", "f.bin", "--output-format", "BIN", "--output-compress", "ZSTD"}, io.Discard, availableAlgos)
+		if err != nil || cfg.OutputFormat != "bin" || cfg.OutputCompress != "zstd" {
+			t.Errorf("Expected a zstd-compressed binary output, got %q %q (err %v)", cfg.OutputFormat, cfg.OutputCompress, err)
+		}
+		for _, args := range [][]string{
+			{"--output-format", "xml"},
+			{"--output-format", "dec", "--output-compress", "gzip"},
+			{"--output-format", "bin", "--output-compress", "lz4"},
+		} {
+			if _, err := ParseConfig("fibcalc", args, io.Discard, availableAlgos); err == nil {
+				t.Errorf("Expected an error for %v", args)
+			}
+		}
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := ParseConfig("fibcalc", []string{"--algo", "all", "--report", "out.html"}, io.Discard, availableAlgos)
+		if err != nil || cfg.Report != "out.html" {
+			t.Errorf("Expected the report path, got %q (err %v)", cfg.Report, err)
+		}
+	})
+
+	t.Run("Script", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := ParseConfig("fibcalc", []string{"--script", "checks.fib"}, io.Discard, availableAlgos)
+		if err != nil || cfg.Script != "checks.fib" {
+			t.Errorf("Expected the script path, got %q (err %v)", cfg.Script, err)
+		}
+	})
+
 	t.Run("InvalidFlags", func(t *testing.T) {
 		t.Parallel()
 		// Unknown flag
@@ -212,6 +351,14 @@ func TestConfigValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("ValidAuto", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Threshold: 10, FFTThreshold: 10, Algo: AutoAlgo}
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("Unexpected validation error for auto: %v", err)
+		}
+	})
+
 	t.Run("InvalidTimeout", func(t *testing.T) {
 		t.Parallel()
 		c := AppConfig{Timeout: 0, Threshold: 10, FFTThreshold: 10, Algo: "fast"}
@@ -236,6 +383,76 @@ func TestConfigValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("InvalidStrassenThreshold", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, StrassenThreshold: -1, Algo: "fast"}
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for negative Strassen threshold")
+		}
+	})
+
+	t.Run("ProgressFDStdin", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "fast", ProgressFormat: ProgressFormatJSON, ProgressFD: 0}
+		if err := c.Validate(availableAlgos); err == nil || !strings.Contains(err.Error(), "standard input") {
+			t.Errorf("Expected error for progress records on stdin, got %v", err)
+		}
+	})
+
+	t.Run("SequentialRace", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "all", Sequential: true}
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("Unexpected error for a sequential comparison: %v", err)
+		}
+		c.Race = true
+		if err := c.Validate(availableAlgos); err == nil || !strings.Contains(err.Error(), "--sequential") {
+			t.Errorf("Expected error for --sequential with --race, got %v", err)
+		}
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "fast", TUIMode: true, Remote: "http://localhost:8080"}
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("Unexpected error for a remote server: %v", err)
+		}
+		for _, remote := range []string{"localhost:8080", "ftp://host", "http://"} {
+			c.Remote = remote
+			if err := c.Validate(availableAlgos); err == nil {
+				t.Errorf("Expected error for remote URL %q", remote)
+			}
+		}
+		c.Remote, c.TUIMode = "http://localhost:8080", false
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for --remote without --tui")
+		}
+	})
+
+	t.Run("RecordReplay", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "fast", Record: "session.jsonl"}
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("Unexpected error for a recorded run: %v", err)
+		}
+		c.TUIMode = true
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for --record with --tui")
+		}
+		c = AppConfig{Timeout: 1 * time.Second, Algo: "fast", TUIMode: true, Replay: "session.jsonl", ReplaySpeed: 4}
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("Unexpected error for a TUI replay: %v", err)
+		}
+		c.Record = "other.jsonl"
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for --replay with --record")
+		}
+		c.Record, c.ReplaySpeed = "", -1
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for a negative replay speed")
+		}
+	})
+
 	t.Run("InvalidAlgo", func(t *testing.T) {
 		t.Parallel()
 		c := AppConfig{Timeout: 1 * time.Second, Threshold: 10, FFTThreshold: 10, Algo: "unknown"}
@@ -244,6 +461,50 @@ func TestConfigValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("InvalidProgressFormat", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "fast", ProgressFormat: "xml"}
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for unknown progress format")
+		}
+	})
+
+	t.Run("ServerPprofWithoutToken", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "fast", ServerPprof: true}
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for --server-pprof without token")
+		}
+		c.PprofToken = "secret"
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("Unexpected error with token: %v", err)
+		}
+	})
+
+	t.Run("InvalidBenchRuns", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "fast", Bench: true, BenchRuns: 0}
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for zero benchmark runs")
+		}
+		c.BenchRuns, c.BenchTolerance = 3, -1
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for negative benchmark tolerance")
+		}
+	})
+
+	t.Run("InvalidIntegrityInterval", func(t *testing.T) {
+		t.Parallel()
+		c := AppConfig{Timeout: 1 * time.Second, Algo: "fast", IntegrityCheck: true}
+		if err := c.Validate(availableAlgos); err == nil {
+			t.Error("Expected error for a zero integrity check interval")
+		}
+		c.IntegrityInterval = 1
+		if err := c.Validate(availableAlgos); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
 	t.Run("AlgoAll", func(t *testing.T) {
 		t.Parallel()
 		c := AppConfig{Timeout: 1 * time.Second, Threshold: 10, FFTThreshold: 10, Algo: "all"}
@@ -311,6 +572,15 @@ func TestEnvHelpers(t *testing.T) {
 		}
 	})
 
+	t.Run("getEnvFloat64", func(t *testing.T) {
+		key := "TEST_FLOAT"
+		os.Setenv(prefix+key, "2.5")
+		defer os.Unsetenv(prefix + key)
+		if val := getEnvFloat64(key, 0); val != 2.5 {
+			t.Errorf("Expected 2.5, got %g", val)
+		}
+	})
+
 	t.Run("getEnvDuration", func(t *testing.T) {
 		key := "TEST_DURATION"
 		os.Setenv(prefix+key, "1h")
@@ -47,6 +47,18 @@ func getEnvInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+// getEnvFloat64 returns the value of the environment variable with the given
+// key (prefixed with EnvPrefix) parsed as float64, or the default value if not
+// set or invalid.
+func getEnvFloat64(key string, defaultVal float64) float64 {
+	if val := os.Getenv(EnvPrefix + key); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultVal
+}
+
 // getEnvBool returns the value of the environment variable with the given key
 // (prefixed with EnvPrefix) parsed as bool, or the default value if not set.
 // Accepts "true", "1", "yes" as true; "false", "0", "no" as false (case-insensitive).
@@ -97,6 +109,12 @@ func isFlagSetAny(fs *flag.FlagSet, names ...string) bool {
 	return false
 }
 
+// isSet checks if a flag was explicitly set on the command line, or its
+// environment variable (without the FIBCALC_ prefix) is set.
+func isSet(fs *flag.FlagSet, name, key string) bool {
+	return isFlagSet(fs, name) || os.Getenv(EnvPrefix+key) != ""
+}
+
 // applyEnvOverrides applies environment variable values to the configuration
 // for any flags that were not explicitly set on the command line.
 // This implements the priority: CLI flags > Environment variables > Defaults.
@@ -115,9 +133,41 @@ func isFlagSetAny(fs *flag.FlagSet, names ...string) bool {
 //   - FIBCALC_QUIET: Enable quiet mode (bool)
 //   - FIBCALC_HEX: Enable hexadecimal output (bool)
 //   - FIBCALC_INTERACTIVE: Enable interactive REPL mode (bool)
+//   - FIBCALC_SCRIPT: REPL script file path, or "-" for stdin (string)
 //   - FIBCALC_NO_COLOR: Disable colored output (bool)
 //   - FIBCALC_OUTPUT: Output file path (string)
+//   - FIBCALC_OUTPUT_FORMAT: Result file format (string: dec, hex, bin)
+//   - FIBCALC_OUTPUT_COMPRESS: Binary result file compression (string: gzip, zstd)
+//   - FIBCALC_REPORT: HTML report file path (string)
+//   - FIBCALC_RECORD: Session file recording the run (string)
+//   - FIBCALC_REPLAY: Session file to replay (string)
+//   - FIBCALC_REPLAY_SPEED: Speed factor of the replay (float)
 //   - FIBCALC_CALIBRATION_PROFILE: Path to calibration profile (string)
+//   - FIBCALC_EXPLAIN: Print the execution plan without computing (bool)
+//   - FIBCALC_MAX_MEMORY: Memory budget, e.g. "2GiB" (string)
+//   - FIBCALC_LOW_MEMORY: Enable low-memory mode (bool)
+//   - FIBCALC_DYNAMIC_THRESHOLDS: Adjust thresholds during the calculation (bool)
+//   - FIBCALC_PROGRESS_FORMAT: Progress output format (string: text, json)
+//   - FIBCALC_PROGRESS_FD: File descriptor for JSON progress records (int)
+//   - FIBCALC_REMOTE: fibcalc server URL for the TUI (string)
+//   - FIBCALC_TRACE_OUT: Destination of OpenTelemetry spans (string: path, stdout, stderr)
+//   - FIBCALC_CPUPROFILE: CPU profile output path (string)
+//   - FIBCALC_MEMPROFILE: Heap profile output path (string)
+//   - FIBCALC_BLOCKPROFILE: Blocking profile output path (string)
+//   - FIBCALC_EXEC_TRACE: Execution trace output path (string)
+//   - FIBCALC_SERVER_PPROF: Expose /debug/pprof in server mode (bool)
+//   - FIBCALC_PPROF_TOKEN: Bearer token for /debug/pprof (string)
+//   - FIBCALC_RACE: Keep the first successful result (bool)
+//   - FIBCALC_SEQUENTIAL: Run the compared calculators one after another (bool)
+//   - FIBCALC_BENCH: Run the statistical benchmark mode (bool)
+//   - FIBCALC_BENCH_RUNS: Measured runs per calculator (int)
+//   - FIBCALC_BENCH_WARMUP: Warm-up runs per calculator (int)
+//   - FIBCALC_BENCH_PROCS: GOMAXPROCS values to benchmark under (string: "1,4,8")
+//   - FIBCALC_BENCH_OUT: Benchmark report output path (string)
+//   - FIBCALC_BENCH_BASELINE: Baseline benchmark report path (string)
+//   - FIBCALC_BENCH_TOLERANCE: Regression tolerance in percent (float)
+//   - FIBCALC_INTEGRITY: Shadow the calculation modulo a random prime (bool)
+//   - FIBCALC_INTEGRITY_INTERVAL: Steps between integrity checks (int)
 func applyEnvOverrides(config *AppConfig, fs *flag.FlagSet) {
 	applyNumericOverrides(config, fs)
 	applyDurationOverrides(config, fs)
@@ -138,6 +188,24 @@ func applyNumericOverrides(config *AppConfig, fs *flag.FlagSet) {
 	if !isFlagSet(fs, "strassen-threshold") {
 		config.StrassenThreshold = getEnvInt("STRASSEN_THRESHOLD", config.StrassenThreshold)
 	}
+	if !isFlagSet(fs, "progress-fd") {
+		config.ProgressFD = getEnvInt("PROGRESS_FD", config.ProgressFD)
+	}
+	if !isFlagSet(fs, "bench-runs") {
+		config.BenchRuns = getEnvInt("BENCH_RUNS", config.BenchRuns)
+	}
+	if !isFlagSet(fs, "bench-warmup") {
+		config.BenchWarmup = getEnvInt("BENCH_WARMUP", config.BenchWarmup)
+	}
+	if !isFlagSet(fs, "bench-tolerance") {
+		config.BenchTolerance = getEnvFloat64("BENCH_TOLERANCE", config.BenchTolerance)
+	}
+	if !isFlagSet(fs, "replay-speed") {
+		config.ReplaySpeed = getEnvFloat64("REPLAY_SPEED", config.ReplaySpeed)
+	}
+	if !isFlagSet(fs, "integrity-interval") {
+		config.IntegrityInterval = getEnvInt("INTEGRITY_INTERVAL", config.IntegrityInterval)
+	}
 }
 
 func applyDurationOverrides(config *AppConfig, fs *flag.FlagSet) {
@@ -156,9 +224,63 @@ func applyStringOverrides(config *AppConfig, fs *flag.FlagSet) {
 	if !isFlagSetAny(fs, "output", "o") {
 		config.OutputFile = getEnvString("OUTPUT", config.OutputFile)
 	}
+	if !isFlagSet(fs, "output-format") {
+		config.OutputFormat = getEnvString("OUTPUT_FORMAT", config.OutputFormat)
+	}
+	if !isFlagSet(fs, "output-compress") {
+		config.OutputCompress = getEnvString("OUTPUT_COMPRESS", config.OutputCompress)
+	}
+	if !isFlagSet(fs, "report") {
+		config.Report = getEnvString("REPORT", config.Report)
+	}
+	if !isFlagSet(fs, "record") {
+		config.Record = getEnvString("RECORD", config.Record)
+	}
+	if !isFlagSet(fs, "replay") {
+		config.Replay = getEnvString("REPLAY", config.Replay)
+	}
+	if !isFlagSet(fs, "script") {
+		config.Script = getEnvString("SCRIPT", config.Script)
+	}
 	if !isFlagSet(fs, "calibration-profile") {
 		config.CalibrationProfile = getEnvString("CALIBRATION_PROFILE", config.CalibrationProfile)
 	}
+	if !isFlagSet(fs, "max-memory") {
+		config.MaxMemory = getEnvString("MAX_MEMORY", config.MaxMemory)
+	}
+	if !isFlagSet(fs, "progress-format") {
+		config.ProgressFormat = getEnvString("PROGRESS_FORMAT", config.ProgressFormat)
+	}
+	if !isFlagSet(fs, "remote") {
+		config.Remote = getEnvString("REMOTE", config.Remote)
+	}
+	if !isFlagSet(fs, "trace-out") {
+		config.TraceOut = getEnvString("TRACE_OUT", config.TraceOut)
+	}
+	if !isFlagSet(fs, "cpuprofile") {
+		config.CPUProfile = getEnvString("CPUPROFILE", config.CPUProfile)
+	}
+	if !isFlagSet(fs, "memprofile") {
+		config.MemProfile = getEnvString("MEMPROFILE", config.MemProfile)
+	}
+	if !isFlagSet(fs, "blockprofile") {
+		config.BlockProfile = getEnvString("BLOCKPROFILE", config.BlockProfile)
+	}
+	if !isFlagSet(fs, "exec-trace") {
+		config.ExecTrace = getEnvString("EXEC_TRACE", config.ExecTrace)
+	}
+	if !isFlagSet(fs, "pprof-token") {
+		config.PprofToken = getEnvString("PPROF_TOKEN", config.PprofToken)
+	}
+	if !isFlagSet(fs, "bench-procs") {
+		config.BenchProcs = getEnvString("BENCH_PROCS", config.BenchProcs)
+	}
+	if !isFlagSet(fs, "bench-out") {
+		config.BenchOut = getEnvString("BENCH_OUT", config.BenchOut)
+	}
+	if !isFlagSet(fs, "bench-baseline") {
+		config.BenchBaseline = getEnvString("BENCH_BASELINE", config.BenchBaseline)
+	}
 }
 
 func applyBooleanOverrides(config *AppConfig, fs *flag.FlagSet) {
@@ -183,6 +305,15 @@ func applyBooleanOverrides(config *AppConfig, fs *flag.FlagSet) {
 	if !isFlagSet(fs, "interactive") {
 		config.Interactive = getEnvBool("INTERACTIVE", config.Interactive)
 	}
+	if !isFlagSet(fs, "low-memory") {
+		config.LowMemory = getEnvBool("LOW_MEMORY", config.LowMemory)
+	}
+	if !isFlagSet(fs, "dynamic-thresholds") {
+		config.DynamicThresholds = getEnvBool("DYNAMIC_THRESHOLDS", config.DynamicThresholds)
+	}
+	if !isFlagSet(fs, "explain") {
+		config.Explain = getEnvBool("EXPLAIN", config.Explain)
+	}
 	if !isFlagSet(fs, "no-color") {
 		config.NoColor = getEnvBool("NO_COLOR", config.NoColor)
 	}
@@ -195,4 +326,19 @@ func applyBooleanOverrides(config *AppConfig, fs *flag.FlagSet) {
 	if !isFlagSetAny(fs, "calculate", "c") {
 		config.Concise = getEnvBool("CALCULATE", config.Concise)
 	}
+	if !isFlagSet(fs, "server-pprof") {
+		config.ServerPprof = getEnvBool("SERVER_PPROF", config.ServerPprof)
+	}
+	if !isFlagSet(fs, "race") {
+		config.Race = getEnvBool("RACE", config.Race)
+	}
+	if !isFlagSet(fs, "sequential") {
+		config.Sequential = getEnvBool("SEQUENTIAL", config.Sequential)
+	}
+	if !isFlagSet(fs, "bench") {
+		config.Bench = getEnvBool("BENCH", config.Bench)
+	}
+	if !isFlagSet(fs, "integrity") {
+		config.IntegrityCheck = getEnvBool("INTEGRITY", config.IntegrityCheck)
+	}
 }
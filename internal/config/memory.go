@@ -0,0 +1,71 @@
+// Package config provides the configuration management for the fibcalc application.
+// This file contains the memory budget settings.
+package config
+
+import (
+	"math"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+)
+
+// byteSizeUnits maps the accepted size suffixes to their multipliers.
+// Both binary (KiB) and short (K, KB) forms are powers of 1024.
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier uint64
+}{
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30}, {"TIB", 1 << 40},
+	{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"TB", 1 << 40},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"T", 1 << 40},
+	{"B", 1},
+}
+
+// ParseByteSize parses a memory size such as "512MiB", "2G" or "1048576".
+// Suffixes are case-insensitive and use powers of 1024.
+//
+// Parameters:
+//   - s: The size to parse.
+//
+// Returns:
+//   - uint64: The size in bytes.
+//   - error: A ConfigError if the size is malformed.
+func ParseByteSize(s string) (uint64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := uint64(1)
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 || math.IsInf(number, 0) {
+		return 0, apperrors.NewConfigError("invalid memory size: '%s' (expected e.g. 512MiB, 2G or a byte count)", s)
+	}
+	bytes := number * float64(multiplier)
+	if bytes >= math.MaxInt64 {
+		return 0, apperrors.NewConfigError("memory size too large: '%s'", s)
+	}
+	return uint64(bytes), nil
+}
+
+// MemoryBudget returns the memory budget for calculations in bytes: the
+// --max-memory value if set, otherwise the Go runtime soft memory limit
+// (GOMEMLIMIT) if one is configured, otherwise 0 (no budget).
+func (c AppConfig) MemoryBudget() uint64 {
+	if c.MaxMemory != "" {
+		if budget, err := ParseByteSize(c.MaxMemory); err == nil {
+			return budget
+		}
+		return 0
+	}
+	// A negative input reads the current limit without changing it
+	if limit := debug.SetMemoryLimit(-1); limit > 0 && limit < math.MaxInt64 {
+		return uint64(limit)
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"io"
+	"math"
+	"runtime/debug"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"1048576", 1 << 20, false},
+		{"512MiB", 512 << 20, false},
+		{"2G", 2 << 30, false},
+		{"1.5gb", 3 << 29, false},
+		{"64 KiB", 64 << 10, false},
+		{"100B", 100, false},
+		{"", 0, true},
+		{"-1G", 0, true},
+		{"lots", 0, true},
+		{"0", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseByteSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryBudget(t *testing.T) {
+	// Not parallel: reads and changes the runtime memory limit.
+	previous := debug.SetMemoryLimit(-1)
+	defer debug.SetMemoryLimit(previous)
+
+	if got := (AppConfig{MaxMemory: "1GiB"}).MemoryBudget(); got != 1<<30 {
+		t.Errorf("MemoryBudget() = %d, want %d", got, 1<<30)
+	}
+
+	debug.SetMemoryLimit(math.MaxInt64)
+	if got := (AppConfig{}).MemoryBudget(); got != 0 {
+		t.Errorf("MemoryBudget() without limit = %d, want 0", got)
+	}
+
+	debug.SetMemoryLimit(256 << 20)
+	if got := (AppConfig{}).MemoryBudget(); got != 256<<20 {
+		t.Errorf("MemoryBudget() with GOMEMLIMIT = %d, want %d", got, 256<<20)
+	}
+}
+
+func TestParseConfigMemoryFlags(t *testing.T) {
+	algos := []string{"fast"}
+
+	cfg, err := ParseConfig("fibcalc", []string{"--max-memory", "2GiB", "--low-memory"}, io.Discard, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MaxMemory != "2GiB" || !cfg.LowMemory {
+		t.Errorf("unexpected memory settings: %q, %v", cfg.MaxMemory, cfg.LowMemory)
+	}
+	if !cfg.ToCalculationOptions().LowMemory {
+		t.Error("LowMemory should be passed to the calculation options")
+	}
+
+	if _, err := ParseConfig("fibcalc", []string{"--max-memory", "plenty"}, io.Discard, algos); err == nil {
+		t.Error("expected an error for an invalid memory size")
+	}
+
+	t.Setenv("FIBCALC_MAX_MEMORY", "512M")
+	t.Setenv("FIBCALC_LOW_MEMORY", "true")
+	cfg, err = ParseConfig("fibcalc", nil, io.Discard, algos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MaxMemory != "512M" || !cfg.LowMemory {
+		t.Errorf("env overrides not applied: %q, %v", cfg.MaxMemory, cfg.LowMemory)
+	}
+}
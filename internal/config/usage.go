@@ -22,7 +22,7 @@ func setCustomUsage(fs *flag.FlagSet) {
 		// Header
 		fmt.Fprintf(out, "\n%sFibonacci Calculator%s\n", t.Bold, t.Reset)
 		fmt.Fprintf(out, "High-performance modular Fibonacci calculator.\n\n")
-		fmt.Fprintf(out, "%sUsage:%s\n  %s [flags]\n\n%sFlags:%s\n", t.Warning, t.Reset, fs.Name(), t.Warning, t.Reset)
+		fmt.Fprintf(out, "%sUsage:%s\n  %s [flags]\n  %s %s [flags]\n  %s %s <file> [flags]\n\n%sFlags:%s\n", t.Warning, t.Reset, fs.Name(), fs.Name(), SelfTestCommand, fs.Name(), InspectCommand, t.Warning, t.Reset)
 
 		fs.VisitAll(func(f *flag.Flag) {
 			name, usage := flag.UnquoteUsage(f)
@@ -21,6 +21,7 @@ const (
 	ExitErrorTimeout  = 2   // Indicates the operation timed out.
 	ExitErrorMismatch = 3   // Indicates a result mismatch between algorithms.
 	ExitErrorConfig   = 4   // Indicates a configuration error.
+	ExitErrorRegress  = 5   // Indicates a benchmark regression against the baseline.
 	ExitErrorCanceled = 130 // Indicates the operation was canceled (e.g., SIGINT).
 )
 
@@ -10,13 +10,16 @@ package fibonacci
 import (
 	"context"
 	"math/big"
+	"runtime/pprof"
 	"time"
 
 	"github.com/agbru/fibcalc/internal/bigfft"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog/log"
-	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MaxFibUint64 = 93 because F(93) is the largest Fibonacci number that fits in a uint64,
@@ -162,9 +165,18 @@ func (c *FibCalculator) Calculate(ctx context.Context, progressChan chan<- Progr
 //   - *big.Int: The calculated Fibonacci number.
 //   - error: An error if one occurred.
 func (c *FibCalculator) CalculateWithObservers(ctx context.Context, subject *ProgressSubject, calcIndex int, n uint64, opts Options) (result *big.Int, err error) {
-	tracer := otel.Tracer("fibonacci")
-	ctx, span := tracer.Start(ctx, "Calculate")
+	ctx, span := tracer().Start(ctx, "Calculate", trace.WithAttributes(
+		attribute.String("fib.algorithm", c.core.Name()),
+		attribute.Int64("fib.n", int64(n)),
+	))
 	defer span.End()
+	defer trackCalculation()()
+
+	// Label the profile samples of this calculation with its algorithm
+	parentCtx := ctx
+	ctx = pprof.WithLabels(ctx, pprof.Labels(LabelAlgorithm, c.core.Name()))
+	pprof.SetGoroutineLabels(ctx)
+	defer pprof.SetGoroutineLabels(parentCtx)
 
 	start := time.Now()
 	defer func() {
@@ -172,6 +184,8 @@ func (c *FibCalculator) CalculateWithObservers(ctx context.Context, subject *Pro
 		status := "success"
 		if err != nil {
 			status = "error"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 		}
 		algoName := c.core.Name()
 		calculationsTotal.WithLabelValues(algoName, status).Inc()
@@ -189,6 +203,8 @@ func (c *FibCalculator) CalculateWithObservers(ctx context.Context, subject *Pro
 	var reporter ProgressReporter
 	if subject != nil {
 		reporter = subject.AsProgressReporter(calcIndex)
+		// Loops that support it report detailed step events instead
+		ctx = withEventReporter(ctx, subject.AsEventReporter(calcIndex))
 	} else {
 		reporter = func(float64) {} // No-op reporter
 	}
@@ -35,8 +35,9 @@ const MaxPooledBitLen = 4_000_000
 
 // checkLimit checks if a big.Int exceeds the maximum pooled bit length.
 // This is used to prevent the pool from holding onto excessively large objects.
+// The limit drops to LowMemoryMaxPooledBitLen in low-memory mode.
 func checkLimit(z *big.Int) bool {
-	return z != nil && z.BitLen() > MaxPooledBitLen
+	return z != nil && z.BitLen() > pooledBitLenLimit()
 }
 
 // task defines a common interface for executable tasks.
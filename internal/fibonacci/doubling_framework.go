@@ -146,22 +146,34 @@ func executeDoublingStepMultiplications(strategy MultiplicationStrategy, s *Calc
 func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter ProgressReporter, n uint64, opts Options, s *CalculationState, useParallel bool) (*big.Int, error) {
 	numBits := bits.Len64(n)
 
-	// Calculate total work for progress reporting via common utility
-	totalWork := CalcTotalWork(numBits)
-	// Pre-compute powers of 4 for O(1) progress calculation
-	powers := PrecomputePowers4(numBits)
-	workDone := 0.0
-	lastReportedProgress := -1.0
+	// Progress reporting (fractional or detailed step events) via common utility
+	tracker := newStepTracker(ctx, "DoublingStep", PhaseDoublingStep, reporter, numBits)
+	defer func() { s.stepCtx = nil }()
 
 	// Normalize options to ensure consistent default threshold handling
 	currentOpts := normalizeOptions(opts)
 	dtm := f.dynamicThreshold
+	if dtm != nil {
+		defer recordThresholdStats(ctx, dtm)
+	}
+
+	// Shadow F(k) and F(k+1) modulo a random prime in integrity mode
+	var shadow *doublingShadow
+	if currentOpts.IntegrityCheck {
+		var err error
+		if shadow, err = newDoublingShadow(currentOpts.IntegrityInterval); err != nil {
+			return nil, err
+		}
+	}
 
 	for i := numBits - 1; i >= 0; i-- {
 		if err := ctx.Err(); err != nil {
 			return nil, fmt.Errorf("fast doubling calculation canceled at bit %d/%d: %w", i, numBits-1, err)
 		}
 
+		tracker.begin()
+		s.stepCtx = tracker.context()
+
 		// Track iteration timing for dynamic threshold adjustment
 		var iterStart time.Time
 		if dtm != nil {
@@ -204,7 +216,7 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 			usedParallel = true
 		}
 		if err := f.strategy.ExecuteStep(s, currentOpts, shouldParallel); err != nil {
-			return nil, fmt.Errorf("doubling step failed at bit %d/%d: %w", i, numBits-1, err)
+			return nil, tracker.fail(fmt.Errorf("doubling step failed at bit %d/%d: %w", i, numBits-1, err))
 		}
 
 		// F(2k+1) = F(k+1)² + F(k)².
@@ -233,6 +245,23 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 			s.FK, s.FK1, s.T4 = s.FK1, s.T4, s.FK
 		}
 
+		method := f.stepMethod(fk1BitLen, currentOpts)
+		if shadow != nil {
+			shadow.step((n>>uint(i))&1 == 1)
+			if step := numBits - i; shadow.m.due(step, i == 0) {
+				diag := IntegrityError{Loop: "doubling", Bit: i, Step: step, BitLen: fk1BitLen, Method: method}
+				if err := shadow.verify(s.FK, s.FK1, diag); err != nil {
+					return nil, tracker.fail(err)
+				}
+			}
+		}
+
+		// In low-memory mode, drop the temporaries that no longer hold live
+		// values so the GC can reclaim them before the next, larger step.
+		if currentOpts.LowMemory {
+			s.T1, s.T2, s.T3, s.T4 = new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+		}
+
 		// Record metrics and check for threshold adjustments
 		if dtm != nil {
 			iterDuration := time.Since(iterStart)
@@ -246,8 +275,22 @@ func (f *DoublingFramework) ExecuteDoublingLoop(ctx context.Context, reporter Pr
 			}
 		}
 
-		// Harmonized reporting via common utility function
-		workDone = ReportStepProgress(reporter, &lastReportedProgress, totalWork, workDone, i, numBits, powers)
+		// Harmonized reporting via common utility
+		tracker.end(i, stepInfo{
+			bitIndex:    i,
+			operandBits: fk1BitLen,
+			method:      method,
+			parallel:    shouldParallel,
+		})
+	}
+	return detachResult(&s.FK, currentOpts.LowMemory), nil
+}
+
+// stepMethod returns the multiplication method a doubling step uses for
+// operands of the given bit length.
+func (f *DoublingFramework) stepMethod(bitLen int, opts Options) string {
+	if _, ok := f.strategy.(*FFTOnlyStrategy); ok {
+		return MethodFFT
 	}
-	return new(big.Int).Set(s.FK), nil
+	return multiplicationMethod(bitLen, opts)
 }
@@ -3,6 +3,7 @@
 package fibonacci
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -392,3 +393,33 @@ func (m *DynamicThresholdManager) Reset() {
 	m.metricsHead = 0
 	m.iterationCount = 0
 }
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Recording the Final Thresholds
+// ─────────────────────────────────────────────────────────────────────────────
+
+// thresholdStatsKey is the context key under which the destination of the
+// final statistics travels from the caller down to the doubling loop.
+type thresholdStatsKey struct{}
+
+// WithThresholdStats returns a context under which a calculation adjusting
+// its thresholds dynamically stores the final statistics of its manager in
+// stats. Calculations that do not adjust their thresholds leave stats zero.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - stats: The destination of the statistics.
+//
+// Returns:
+//   - context.Context: The context to calculate with.
+func WithThresholdStats(ctx context.Context, stats *ThresholdStats) context.Context {
+	return context.WithValue(ctx, thresholdStatsKey{}, stats)
+}
+
+// recordThresholdStats stores the statistics of m in the destination carried
+// by ctx, if any.
+func recordThresholdStats(ctx context.Context, m *DynamicThresholdManager) {
+	if stats, ok := ctx.Value(thresholdStatsKey{}).(*ThresholdStats); ok && stats != nil {
+		*stats = m.GetStats()
+	}
+}
@@ -96,7 +96,7 @@ func (fd *OptimizedFastDoubling) CalculateCore(ctx context.Context, reporter Pro
 
 	// Normalize options to ensure consistent default threshold handling
 	normalizedOpts := normalizeOptions(opts)
-	useParallel := runtime.GOMAXPROCS(0) > 1 && normalizedOpts.ParallelThreshold > 0
+	useParallel := runtime.GOMAXPROCS(0) > 1 && normalizedOpts.ParallelThreshold > 0 && !normalizedOpts.LowMemory
 
 	// Use framework with adaptive strategy for the main loop
 	strategy := &AdaptiveStrategy{}
@@ -196,6 +196,11 @@ func shouldParallelizeMultiplicationCached(opts Options, fkBitLen, fk1BitLen int
 // algorithm, allowing efficient management via an object pool.
 type CalculationState struct {
 	FK, FK1, T1, T2, T3, T4 *big.Int
+
+	// stepCtx is the context of the doubling step in progress. It carries
+	// the step span and pprof labels that the FFT and Karatsuba phases
+	// build on. It is nil outside the loop.
+	stepCtx context.Context
 }
 
 // Reset prepares the state for a new calculation.
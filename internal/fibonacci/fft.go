@@ -108,23 +108,33 @@ func executeDoublingStepFFT(s *CalculationState, opts Options, inParallel bool)
 	nWords := bigfft.ValueSize(k, m, 2)
 	n := nWords
 
+	_, restoreLabels := withPhase(s.stepCtx, PhaseFFTTransform)
+	defer func() { restoreLabels() }()
+	transformSpan := startFFTSpan(s.stepCtx, "FFTTransform", k, n, false)
 	pFk := bigfft.PolyFromInt(s.FK, k, m)
 	fkPoly, err := pFk.Transform(n)
 	if err != nil {
-		return err
+		return endSpan(transformSpan, err)
 	}
 
 	pFk1 := bigfft.PolyFromInt(s.FK1, k, m)
 	fk1Poly, err := pFk1.Transform(n)
 	if err != nil {
-		return err
+		return endSpan(transformSpan, err)
 	}
 
 	pT4 := bigfft.PolyFromInt(s.T4, k, m)
 	t4Poly, err := pT4.Transform(n)
 	if err != nil {
-		return err
+		return endSpan(transformSpan, err)
 	}
+	endSpan(transformSpan, nil)
+	restoreLabels()
+
+	// Pointwise multiplications and inverse transforms
+	_, restoreLabels = withPhase(s.stepCtx, PhaseFFTMultiply)
+	multiplySpan := startFFTSpan(s.stepCtx, "FFTMultiply", k, n, inParallel)
+	defer multiplySpan.End()
 
 	if inParallel {
 		// Clone fkPoly to avoid data race between goroutines
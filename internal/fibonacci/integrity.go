@@ -0,0 +1,205 @@
+// Package fibonacci provides implementations for calculating Fibonacci numbers.
+// This file implements the integrity mode: the doubling and matrix loops
+// shadow their big-integer state modulo a random 61-bit prime and compare
+// both after each step, so that a silent arithmetic fault (bad SIMD path,
+// bit flip, pool reuse bug) aborts the calculation instead of producing a
+// wrong answer.
+package fibonacci
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// shadowPrimeBits is the size of the shadow modulus. A 61-bit prime keeps
+// residues below 2^61, so sums of two residues cannot overflow a uint64.
+const shadowPrimeBits = 61
+
+// IntegrityError reports that the big-integer state of a calculation
+// diverged from its shadow residues. A random fault goes unnoticed only if
+// it happens to preserve the residue modulo the prime, with probability
+// about 2^-60 per check.
+type IntegrityError struct {
+	// Loop is the loop that detected the divergence ("doubling" or "matrix").
+	Loop string
+	// Bit is the bit of n (or n-1 for the matrix loop) processed by the step.
+	Bit int
+	// Step is the 1-based index of the step in the loop.
+	Step int
+	// BitLen is the operand bit length of the step.
+	BitLen int
+	// Method is the multiplication method of the step (see PlanCalculation).
+	Method string
+	// Value names the diverging value, e.g. "F(k+1)" or "res[0][1]".
+	Value string
+	// Prime is the shadow modulus.
+	Prime uint64
+	// Got is the residue of the big-integer value, Want the shadow residue.
+	Got, Want uint64
+}
+
+// Error returns the diagnostic of the divergence.
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("integrity check failed in %s step %d (bit %d, %d-bit operands, %s multiplication): %s mod %d = %d, shadow computation expects %d",
+		e.Loop, e.Step, e.Bit, e.BitLen, e.Method, e.Value, e.Prime, e.Got, e.Want)
+}
+
+// shadowModulus performs arithmetic modulo a random 61-bit prime and
+// schedules the checks.
+type shadowModulus struct {
+	p        uint64
+	bigP     big.Int
+	scratch  big.Int
+	interval int
+}
+
+// newShadowModulus draws a random 61-bit prime. The state is compared with
+// the shadow every interval steps (every step if interval <= 1) and after
+// the last step.
+func newShadowModulus(interval int) (*shadowModulus, error) {
+	p, err := rand.Prime(rand.Reader, shadowPrimeBits)
+	if err != nil {
+		return nil, fmt.Errorf("cannot draw the integrity check modulus: %w", err)
+	}
+	m := &shadowModulus{p: p.Uint64(), interval: max(interval, 1)}
+	m.bigP.SetUint64(m.p)
+	return m, nil
+}
+
+// mul returns a*b mod p.
+func (m *shadowModulus) mul(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m.p)
+}
+
+// add returns a+b mod p.
+func (m *shadowModulus) add(a, b uint64) uint64 {
+	if s := a + b; s < m.p {
+		return s
+	}
+	return a + b - m.p
+}
+
+// sub returns a-b mod p.
+func (m *shadowModulus) sub(a, b uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+	return a + m.p - b
+}
+
+// residue returns x mod p.
+func (m *shadowModulus) residue(x *big.Int) uint64 {
+	return m.scratch.Mod(x, &m.bigP).Uint64()
+}
+
+// due reports whether the state must be compared after the given step.
+func (m *shadowModulus) due(step int, last bool) bool {
+	return last || step%m.interval == 0
+}
+
+// compare returns an IntegrityError filled from diag if x diverges from the
+// shadow residue want.
+func (m *shadowModulus) compare(x *big.Int, want uint64, value string, diag IntegrityError) error {
+	if got := m.residue(x); got != want {
+		diag.Value, diag.Prime, diag.Got, diag.Want = value, m.p, got, want
+		return &diag
+	}
+	return nil
+}
+
+// doublingShadow tracks F(k) and F(k+1) modulo the shadow prime alongside
+// the doubling loop.
+type doublingShadow struct {
+	m       *shadowModulus
+	fk, fk1 uint64
+}
+
+// newDoublingShadow returns a shadow of the initial state F(0), F(1).
+func newDoublingShadow(interval int) (*doublingShadow, error) {
+	m, err := newShadowModulus(interval)
+	if err != nil {
+		return nil, err
+	}
+	return &doublingShadow{m: m, fk: 0, fk1: 1}, nil
+}
+
+// step applies a doubling step, followed by an addition step if addBit is set.
+func (s *doublingShadow) step(addBit bool) {
+	m := s.m
+	// F(2k) = F(k) * (2*F(k+1) - F(k)), F(2k+1) = F(k+1)^2 + F(k)^2
+	f2k := m.mul(s.fk, m.sub(m.add(s.fk1, s.fk1), s.fk))
+	f2k1 := m.add(m.mul(s.fk1, s.fk1), m.mul(s.fk, s.fk))
+	s.fk, s.fk1 = f2k, f2k1
+	if addBit {
+		s.fk, s.fk1 = s.fk1, m.add(s.fk, s.fk1)
+	}
+}
+
+// verify compares F(k) and F(k+1) with the shadow.
+func (s *doublingShadow) verify(fk, fk1 *big.Int, diag IntegrityError) error {
+	if err := s.m.compare(fk, s.fk, "F(k)", diag); err != nil {
+		return err
+	}
+	return s.m.compare(fk1, s.fk1, "F(k+1)", diag)
+}
+
+// shadowMatrix is a 2x2 matrix of residues {a, b, c, d}.
+type shadowMatrix [4]uint64
+
+// matrixShadow tracks the result and power matrices of the matrix loop
+// modulo the shadow prime.
+type matrixShadow struct {
+	m      *shadowModulus
+	res, p shadowMatrix
+}
+
+// newMatrixShadow returns a shadow of the initial state res = I, p = Q.
+func newMatrixShadow(interval int) (*matrixShadow, error) {
+	m, err := newShadowModulus(interval)
+	if err != nil {
+		return nil, err
+	}
+	return &matrixShadow{m: m, res: shadowMatrix{1, 0, 0, 1}, p: shadowMatrix{1, 1, 1, 0}}, nil
+}
+
+// mul returns x*y modulo the shadow prime.
+func (s *matrixShadow) mul(x, y shadowMatrix) shadowMatrix {
+	m := s.m
+	return shadowMatrix{
+		m.add(m.mul(x[0], y[0]), m.mul(x[1], y[2])),
+		m.add(m.mul(x[0], y[1]), m.mul(x[1], y[3])),
+		m.add(m.mul(x[2], y[0]), m.mul(x[3], y[2])),
+		m.add(m.mul(x[2], y[1]), m.mul(x[3], y[3])),
+	}
+}
+
+// step applies a step of the binary exponentiation: res *= p if multiply
+// is set, then p *= p if square is set.
+func (s *matrixShadow) step(multiply, square bool) {
+	if multiply {
+		s.res = s.mul(s.res, s.p)
+	}
+	if square {
+		s.p = s.mul(s.p, s.p)
+	}
+}
+
+// verify compares the result and power matrices with the shadow.
+func (s *matrixShadow) verify(res, p *matrix, diag IntegrityError) error {
+	for _, mat := range []struct {
+		name   string
+		big    *matrix
+		shadow shadowMatrix
+	}{{"res", res, s.res}, {"p", p, s.p}} {
+		for i, x := range []*big.Int{mat.big.a, mat.big.b, mat.big.c, mat.big.d} {
+			value := fmt.Sprintf("%s[%d][%d]", mat.name, i/2, i%2)
+			if err := s.m.compare(x, mat.shadow[i], value, diag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
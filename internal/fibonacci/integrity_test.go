@@ -0,0 +1,158 @@
+package fibonacci
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// faultyStrategy corrupts F(2k) after the given number of doubling steps.
+type faultyStrategy struct {
+	AdaptiveStrategy
+	faultAt int
+	steps   int
+}
+
+func (s *faultyStrategy) ExecuteStep(state *CalculationState, opts Options, inParallel bool) error {
+	if err := s.AdaptiveStrategy.ExecuteStep(state, opts, inParallel); err != nil {
+		return err
+	}
+	s.steps++
+	if s.steps == s.faultAt {
+		// T3 becomes F(k) at the end of the step
+		state.T3.SetBit(state.T3, 0, state.T3.Bit(0)^1)
+	}
+	return nil
+}
+
+// TestShadowModulus checks the modular arithmetic against math/big.
+func TestShadowModulus(t *testing.T) {
+	t.Parallel()
+
+	m, err := newShadowModulus(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if big.NewInt(0).SetUint64(m.p).BitLen() != shadowPrimeBits || !m.bigP.ProbablyPrime(20) {
+		t.Fatalf("modulus %d is not a %d-bit prime", m.p, shadowPrimeBits)
+	}
+	p := new(big.Int).SetUint64(m.p)
+	for _, pair := range [][2]uint64{{0, 0}, {1, m.p - 1}, {m.p - 1, m.p - 1}, {m.p / 3, m.p / 2}} {
+		a, b := pair[0], pair[1]
+		x, y := new(big.Int).SetUint64(a), new(big.Int).SetUint64(b)
+		if want := new(big.Int).Mod(new(big.Int).Mul(x, y), p).Uint64(); m.mul(a, b) != want {
+			t.Errorf("mul(%d, %d) = %d, want %d", a, b, m.mul(a, b), want)
+		}
+		if want := new(big.Int).Mod(new(big.Int).Add(x, y), p).Uint64(); m.add(a, b) != want {
+			t.Errorf("add(%d, %d) = %d, want %d", a, b, m.add(a, b), want)
+		}
+		if want := new(big.Int).Mod(new(big.Int).Sub(x, y), p).Uint64(); m.sub(a, b) != want {
+			t.Errorf("sub(%d, %d) = %d, want %d", a, b, m.sub(a, b), want)
+		}
+	}
+}
+
+// TestIntegrityCheck verifies that a correct calculation passes the
+// integrity checks in both loops, at several check intervals.
+func TestIntegrityCheck(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []uint64{1, 2, 3, 94, 1000, 100_000} {
+		want := calculateReference(n)
+		for _, interval := range []int{0, 1, 7} {
+			opts := Options{IntegrityCheck: true, IntegrityInterval: interval}
+
+			s := AcquireState()
+			got, err := NewDoublingFramework(&AdaptiveStrategy{}).ExecuteDoublingLoop(context.Background(), func(float64) {}, n, opts, s, false)
+			ReleaseState(s)
+			if err != nil || got.Cmp(want) != 0 {
+				t.Errorf("doubling F(%d), interval %d: err %v, correct %v", n, interval, err, err == nil && got.Cmp(want) == 0)
+			}
+
+			state := acquireMatrixState()
+			got, err = NewMatrixFramework().ExecuteMatrixLoop(context.Background(), func(float64) {}, n, opts, state)
+			releaseMatrixState(state)
+			if err != nil || got.Cmp(want) != 0 {
+				t.Errorf("matrix F(%d), interval %d: err %v, correct %v", n, interval, err, err == nil && got.Cmp(want) == 0)
+			}
+		}
+	}
+}
+
+// TestIntegrityCheck_DoublingFault verifies that a corrupted doubling step
+// aborts the calculation with a diagnostic naming the step.
+func TestIntegrityCheck_DoublingFault(t *testing.T) {
+	t.Parallel()
+
+	const n = 100_000 // 17 bits
+	run := func(opts Options) (*big.Int, error) {
+		s := AcquireState()
+		defer ReleaseState(s)
+		return NewDoublingFramework(&faultyStrategy{faultAt: 5}).ExecuteDoublingLoop(context.Background(), func(float64) {}, n, opts, s, false)
+	}
+	_, err := run(Options{IntegrityCheck: true})
+
+	var ie *IntegrityError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected an IntegrityError, got %v", err)
+	}
+	if ie.Loop != "doubling" || ie.Step != 5 || ie.Bit != 12 || ie.Value != "F(k)" || ie.Method == "" {
+		t.Errorf("unexpected diagnostic %+v", ie)
+	}
+	if !strings.Contains(err.Error(), "step 5 (bit 12") {
+		t.Errorf("diagnostic lacks the step: %v", err)
+	}
+
+	// With a check every 4 steps, the fault is detected at step 8
+	if _, err := run(Options{IntegrityCheck: true, IntegrityInterval: 4}); !errors.As(err, &ie) || ie.Step != 8 {
+		t.Errorf("expected detection at step 8, got %v", err)
+	}
+
+	// Without integrity mode, the fault goes unnoticed
+	got, err := run(Options{})
+	if err != nil || got.Cmp(calculateReference(n)) == 0 {
+		t.Errorf("expected a silently wrong result, got err %v", err)
+	}
+}
+
+// TestIntegrityCheck_MatrixFault verifies that a corrupted matrix squaring
+// aborts the calculation. It replaces squareSymmetricMatrixFunc and so does
+// not run in parallel.
+func TestIntegrityCheck_MatrixFault(t *testing.T) {
+	original := squareSymmetricMatrixFunc
+	defer func() { squareSymmetricMatrixFunc = original }()
+	calls := 0
+	squareSymmetricMatrixFunc = func(dest, mat *matrix, state *matrixState, inParallel bool, fftThreshold int) error {
+		if err := original(dest, mat, state, inParallel, fftThreshold); err != nil {
+			return err
+		}
+		if calls++; calls == 3 {
+			dest.d.Add(dest.d, big.NewInt(1))
+		}
+		return nil
+	}
+
+	state := acquireMatrixState()
+	defer releaseMatrixState(state)
+	_, err := NewMatrixFramework().ExecuteMatrixLoop(context.Background(), func(float64) {}, 1000, Options{IntegrityCheck: true}, state)
+
+	var ie *IntegrityError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected an IntegrityError, got %v", err)
+	}
+	if ie.Loop != "matrix" || ie.Step != 3 || ie.Value != "p[1][1]" {
+		t.Errorf("unexpected diagnostic %+v", ie)
+	}
+}
+
+// calculateReference computes F(n) by iterated additions.
+func calculateReference(n uint64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := uint64(0); i < n; i++ {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
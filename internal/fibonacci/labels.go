@@ -0,0 +1,46 @@
+// Package fibonacci provides implementations for calculating Fibonacci numbers.
+// This file contains the pprof labels that let profiles be sliced by
+// algorithm and calculation phase (e.g. `go tool pprof -tagfocus=phase=karatsuba`).
+package fibonacci
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// pprof label keys.
+const (
+	// LabelAlgorithm is the label holding the calculator name.
+	LabelAlgorithm = "algorithm"
+	// LabelPhase is the label holding the calculation phase.
+	LabelPhase = "phase"
+)
+
+// Calculation phases used as values of the LabelPhase label.
+const (
+	PhaseDoublingStep      = "doubling_step"
+	PhaseMatrixStep        = "matrix_step"
+	PhaseFFTTransform      = "fft_transform"
+	PhaseFFTMultiply       = "fft_pointwise_multiply"
+	PhaseKaratsuba         = "karatsuba"
+	PhaseDecimalConversion = "decimal_conversion"
+)
+
+// withPhase labels the current goroutine with phase on top of the labels of
+// ctx. Goroutines started while the label is set inherit it.
+//
+// Parameters:
+//   - ctx: The context carrying the enclosing labels; nil means none.
+//   - phase: The phase label value.
+//
+// Returns:
+//   - context.Context: The labelled context.
+//   - func(): Restores the labels of ctx on the current goroutine.
+func withPhase(ctx context.Context, phase string) (context.Context, func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	labelled := pprof.WithLabels(ctx, pprof.Labels(LabelPhase, phase))
+	pprof.SetGoroutineLabels(labelled)
+	return labelled, func() { pprof.SetGoroutineLabels(ctx) }
+}
@@ -0,0 +1,53 @@
+package fibonacci
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+// labelCapturingStrategy records the phase label of the step context.
+type labelCapturingStrategy struct {
+	AdaptiveStrategy
+	phases map[string]int
+}
+
+func (s *labelCapturingStrategy) ExecuteStep(state *CalculationState, opts Options, inParallel bool) error {
+	phase, _ := pprof.Label(state.stepCtx, LabelPhase)
+	algo, _ := pprof.Label(state.stepCtx, LabelAlgorithm)
+	s.phases[phase+"/"+algo]++
+	return s.AdaptiveStrategy.ExecuteStep(state, opts, inParallel)
+}
+
+// TestStepLabels verifies that doubling steps carry the phase label on top
+// of the enclosing labels.
+func TestStepLabels(t *testing.T) {
+	t.Parallel()
+
+	strategy := &labelCapturingStrategy{phases: make(map[string]int)}
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels(LabelAlgorithm, "test"))
+	s := AcquireState()
+	defer ReleaseState(s)
+
+	const n = 1000
+	if _, err := NewDoublingFramework(strategy).ExecuteDoublingLoop(ctx, func(float64) {}, n, Options{}, s, false); err != nil {
+		t.Fatalf("loop failed: %v", err)
+	}
+	if got := strategy.phases[PhaseDoublingStep+"/test"]; got != 10 {
+		t.Errorf("expected 10 labelled steps, got %v", strategy.phases)
+	}
+	if s.stepCtx != nil {
+		t.Error("step context should be cleared after the loop")
+	}
+}
+
+// TestWithPhase verifies the labelled context.
+func TestWithPhase(t *testing.T) {
+	t.Parallel()
+
+	ctx, restore := withPhase(nil, PhaseFFTTransform)
+	defer restore()
+	if phase, ok := pprof.Label(ctx, LabelPhase); !ok || phase != PhaseFFTTransform {
+		t.Errorf("phase label = %q, %v", phase, ok)
+	}
+}
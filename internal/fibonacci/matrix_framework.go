@@ -48,43 +48,81 @@ func (f *MatrixFramework) ExecuteMatrixLoop(ctx context.Context, reporter Progre
 	numBits := bits.Len64(exponent)
 	// Normalize options to ensure consistent default threshold handling
 	normalizedOpts := normalizeOptions(opts)
-	useParallel := runtime.NumCPU() > 1 && normalizedOpts.ParallelThreshold > 0
+	useParallel := runtime.NumCPU() > 1 && normalizedOpts.ParallelThreshold > 0 && !normalizedOpts.LowMemory
 
-	// Calculate total work for progress reporting via common utility
-	totalWork := CalcTotalWork(numBits)
-	// Pre-compute powers of 4 for O(1) progress calculation
-	powers := PrecomputePowers4(numBits)
-	workDone := 0.0
-	lastReportedProgress := -1.0
+	// Shadow the result and power matrices modulo a random prime in integrity mode
+	var shadow *matrixShadow
+	if normalizedOpts.IntegrityCheck {
+		var err error
+		if shadow, err = newMatrixShadow(normalizedOpts.IntegrityInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	// Progress reporting (fractional or detailed step events) via common utility
+	tracker := newStepTracker(ctx, "MatrixStep", PhaseMatrixStep, reporter, numBits)
 
 	for i := 0; i < numBits; i++ {
 		if err := ctx.Err(); err != nil {
 			return nil, fmt.Errorf("matrix exponentiation calculation canceled at bit %d/%d: %w", i, numBits-1, err)
 		}
 
-		if (exponent>>uint(i))&1 == 1 {
+		tracker.begin()
+		// Operand size at the start of the step, before p is squared
+		pBits := maxBitLenMatrix(state.p)
+		bit := (exponent >> uint(i)) & 1
+		stepParallel := false
+		method := multiplicationMethod(pBits, normalizedOpts)
+		if bit == 1 && pBits > normalizedOpts.StrassenThreshold {
+			method = MethodStrassen
+		}
+		restoreLabels := func() {}
+		if method == MethodKaratsuba {
+			_, restoreLabels = withPhase(tracker.context(), PhaseKaratsuba)
+		}
+
+		if bit == 1 {
 			// Decide on parallelism based on the max size of the operands involved
 			inParallel := useParallel && maxBitLenMatrix(state.p) > normalizedOpts.ParallelThreshold
+			stepParallel = inParallel
 			if err := multiplyMatricesFunc(state.tempMatrix, state.res, state.p, state, inParallel, normalizedOpts.FFTThreshold, normalizedOpts.StrassenThreshold); err != nil {
-				return nil, fmt.Errorf("matrix multiplication failed at bit %d/%d: %w", i, numBits-1, err)
+				return nil, tracker.fail(fmt.Errorf("matrix multiplication failed at bit %d/%d: %w", i, numBits-1, err))
 			}
 			state.res, state.tempMatrix = state.tempMatrix, state.res
 		}
 
 		if i < numBits-1 {
 			inParallel := useParallel && maxBitLenMatrix(state.p) > normalizedOpts.ParallelThreshold
+			stepParallel = stepParallel || inParallel
 			if err := squareSymmetricMatrixFunc(state.tempMatrix, state.p, state, inParallel, normalizedOpts.FFTThreshold); err != nil {
-				return nil, fmt.Errorf("matrix squaring failed at bit %d/%d: %w", i, numBits-1, err)
+				return nil, tracker.fail(fmt.Errorf("matrix squaring failed at bit %d/%d: %w", i, numBits-1, err))
 			}
 			state.p, state.tempMatrix = state.tempMatrix, state.p
 		}
 
+		restoreLabels()
+
+		if shadow != nil {
+			shadow.step(bit == 1, i < numBits-1)
+			if shadow.m.due(i+1, i == numBits-1) {
+				diag := IntegrityError{Loop: "matrix", Bit: i, Step: i + 1, BitLen: pBits, Method: method}
+				if err := shadow.verify(state.res, state.p, diag); err != nil {
+					return nil, tracker.fail(err)
+				}
+			}
+		}
+
 		// Harmonized reporting via common utility function
 		// For Matrix Exponentiation, we iterate from LSB (small work) to MSB (large work).
 		// However, ReportStepProgress assumes `i` counts down from MSB (large work) to LSB.
-		// To correct this, we invert the index passed to ReportStepProgress so that
+		// To correct this, we invert the index passed to the tracker so that
 		// stepIndex becomes `i`, resulting in increasing work values.
-		workDone = ReportStepProgress(reporter, &lastReportedProgress, totalWork, workDone, numBits-1-i, numBits, powers)
+		tracker.end(numBits-1-i, stepInfo{
+			bitIndex:    i,
+			operandBits: pBits,
+			method:      method,
+			parallel:    stepParallel,
+		})
 	}
-	return new(big.Int).Set(state.res.a), nil
+	return detachResult(&state.res.a, normalizedOpts.LowMemory), nil
 }
@@ -0,0 +1,165 @@
+// Package fibonacci provides implementations for calculating Fibonacci numbers.
+// This file contains the memory budget checks and the low-memory mode.
+package fibonacci
+
+import (
+	"math/big"
+	"runtime"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Low-Memory Mode
+// ─────────────────────────────────────────────────────────────────────────────
+
+// LowMemoryMaxPooledBitLen is the maximum size (in bits) of a big.Int
+// accepted into the pools while the low-memory mode is active.
+const LowMemoryMaxPooledBitLen = 262_144
+
+// pooledBitLenLimit returns the current maximum pooled bit length.
+func pooledBitLenLimit() int {
+	if bigfft.LowMemoryMode() {
+		return LowMemoryMaxPooledBitLen
+	}
+	return MaxPooledBitLen
+}
+
+// ConfigureMemoryMode sets the low-memory mode of the pools, bump allocators
+// and FFT transform cache. They are shared by every calculation of the
+// process, so the mode is set once before the calculations start rather than
+// from the options of each calculation, which would let concurrent
+// calculations switch it for each other. Options.LowMemory only governs the
+// behaviour of its own calculation.
+//
+// Parameters:
+//   - lowMemory: Whether the process runs in low-memory mode.
+func ConfigureMemoryMode(lowMemory bool) {
+	bigfft.SetLowMemoryMode(lowMemory)
+	if lowMemory {
+		bigfft.GetTransformCache().Clear()
+	}
+}
+
+// detachResult returns the result held by *z. In low-memory mode the value
+// itself is handed over and *z is replaced so the pooled state does not keep
+// it alive; otherwise a copy is returned and the state keeps its buffer.
+func detachResult(z **big.Int, lowMemory bool) *big.Int {
+	if lowMemory {
+		result := *z
+		*z = new(big.Int)
+		return result
+	}
+	return new(big.Int).Set(*z)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Memory Budget
+// ─────────────────────────────────────────────────────────────────────────────
+
+// EstimatePeakMemory estimates the peak working set of computing F(n) with a
+// registered algorithm and the given options, accounting for the state held
+// by the algorithm, parallel multiplication branches and the FFT transform
+// cache.
+//
+// Parameters:
+//   - n: The Fibonacci index.
+//   - algorithm: The registered algorithm name ("fast", "fft", "matrix", ...).
+//   - opts: The calculation options.
+//
+// Returns:
+//   - uint64: The estimated peak in bytes (0 for precomputed values).
+func EstimatePeakMemory(n uint64, algorithm string, opts Options) uint64 {
+	if n <= MaxFibUint64 {
+		return 0
+	}
+	normalized := normalizeOptions(opts)
+	parallel := !normalized.LowMemory && runtime.GOMAXPROCS(0) > 1 &&
+		EstimateFibBitLen(n/2) > normalized.ParallelThreshold
+
+	peak := bigfft.DefaultPeakMemoryOptions()
+	switch algorithm {
+	case "matrix":
+		// Result, base and temporary matrices plus the Strassen products
+		peak.StateOperands = 16
+		if parallel {
+			peak.ParallelBranches = 7
+		}
+	case "fft":
+		// Sequential doubling loop
+	default:
+		if parallel {
+			peak.ParallelBranches = 3
+		}
+	}
+
+	if normalized.LowMemory {
+		// No returned copy, temporaries released after each step
+		peak.StateOperands -= 2
+	} else if fftCacheEnabled(normalized) && EstimateFibBitLen(n/2) >= cacheMinBitLen(normalized) {
+		peak.CacheEntries = normalized.FFTCacheMaxEntries
+		if peak.CacheEntries == 0 {
+			peak.CacheEntries = bigfft.DefaultTransformCacheConfig().MaxEntries
+		}
+	}
+	return bigfft.EstimatePeakBytes(n, peak)
+}
+
+// fftCacheEnabled reports whether the options leave the transform cache on.
+func fftCacheEnabled(opts Options) bool {
+	if opts.LowMemory {
+		return false
+	}
+	if opts.FFTCacheEnabled != nil {
+		return *opts.FFTCacheEnabled
+	}
+	return bigfft.DefaultTransformCacheConfig().Enabled
+}
+
+// cacheMinBitLen returns the smallest operand cached by the transform cache.
+func cacheMinBitLen(opts Options) int {
+	if opts.FFTCacheMinBitLen > 0 {
+		return opts.FFTCacheMinBitLen
+	}
+	return bigfft.DefaultTransformCacheConfig().MinBitLen
+}
+
+// MemoryCheck is the outcome of checking calculations against a memory budget.
+type MemoryCheck struct {
+	// Budget is the memory budget in bytes.
+	Budget uint64
+	// Estimate is the estimated peak with the requested options.
+	Estimate uint64
+	// LowMemoryEstimate is the estimated peak in low-memory mode.
+	LowMemoryEstimate uint64
+	// Fits is true when the requested options stay within the budget.
+	Fits bool
+	// Downgrade is true when only the low-memory mode stays within the budget.
+	Downgrade bool
+}
+
+// CheckMemoryBudget pre-flights calculations of F(n) against a memory budget.
+// The algorithms are assumed to run concurrently, so their estimates add up.
+//
+// Parameters:
+//   - n: The Fibonacci index.
+//   - algorithms: The registered algorithm names that will run.
+//   - opts: The calculation options.
+//   - budget: The memory budget in bytes.
+//
+// Returns:
+//   - MemoryCheck: The estimates and the decision. When neither Fits nor
+//     Downgrade is set, the calculation should be refused.
+func CheckMemoryBudget(n uint64, algorithms []string, opts Options, budget uint64) MemoryCheck {
+	lowOpts := opts
+	lowOpts.LowMemory = true
+
+	check := MemoryCheck{Budget: budget}
+	for _, algo := range algorithms {
+		check.Estimate += EstimatePeakMemory(n, algo, opts)
+		check.LowMemoryEstimate += EstimatePeakMemory(n, algo, lowOpts)
+	}
+	check.Fits = check.Estimate <= budget
+	check.Downgrade = !check.Fits && !opts.LowMemory && check.LowMemoryEstimate <= budget
+	return check
+}
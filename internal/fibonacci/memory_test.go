@@ -0,0 +1,89 @@
+package fibonacci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+)
+
+func TestEstimatePeakMemory(t *testing.T) {
+	t.Parallel()
+	const n = 10_000_000
+
+	if got := EstimatePeakMemory(50, "fast", Options{}); got != 0 {
+		t.Errorf("precomputed values need no memory, got %d", got)
+	}
+
+	for _, algo := range []string{"fast", "fft", "matrix"} {
+		normal := EstimatePeakMemory(n, algo, Options{})
+		low := EstimatePeakMemory(n, algo, Options{LowMemory: true})
+		if normal == 0 || low == 0 {
+			t.Fatalf("%s: expected non-zero estimates, got %d and %d", algo, normal, low)
+		}
+		if low >= normal {
+			t.Errorf("%s: low-memory estimate %d should be below %d", algo, low, normal)
+		}
+	}
+
+	if EstimatePeakMemory(n, "matrix", Options{}) <= EstimatePeakMemory(n, "fast", Options{}) {
+		t.Error("matrix exponentiation should need more memory than fast doubling")
+	}
+}
+
+func TestCheckMemoryBudget(t *testing.T) {
+	t.Parallel()
+	const n = 10_000_000
+	algos := []string{"fast"}
+	normal := EstimatePeakMemory(n, "fast", Options{})
+	low := EstimatePeakMemory(n, "fast", Options{LowMemory: true})
+
+	if check := CheckMemoryBudget(n, algos, Options{}, normal); !check.Fits || check.Downgrade {
+		t.Errorf("expected the calculation to fit: %+v", check)
+	}
+	if check := CheckMemoryBudget(n, algos, Options{}, low); check.Fits || !check.Downgrade {
+		t.Errorf("expected a downgrade to low-memory mode: %+v", check)
+	}
+	if check := CheckMemoryBudget(n, algos, Options{}, low-1); check.Fits || check.Downgrade {
+		t.Errorf("expected the calculation to be refused: %+v", check)
+	}
+	both := CheckMemoryBudget(n, []string{"fast", "fft"}, Options{}, 1<<40)
+	if both.Estimate <= normal {
+		t.Errorf("concurrent algorithms should add up: %d <= %d", both.Estimate, normal)
+	}
+}
+
+func TestLowMemoryCalculation(t *testing.T) {
+	defer ConfigureMemoryMode(false)
+
+	const n = 200_000
+	ctx := context.Background()
+	for _, core := range []coreCalculator{&OptimizedFastDoubling{}, &MatrixExponentiation{}, &FFTBasedCalculator{}} {
+		calc := NewCalculator(core)
+		want, err := calc.Calculate(ctx, nil, 0, n, Options{})
+		if err != nil {
+			t.Fatalf("%s: %v", calc.Name(), err)
+		}
+
+		// The option of one calculation leaves the shared pools alone
+		got, err := calc.Calculate(ctx, nil, 0, n, Options{LowMemory: true, FFTThreshold: 10_000})
+		if err != nil {
+			t.Fatalf("%s (low memory): %v", calc.Name(), err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("%s: low-memory result differs", calc.Name())
+		}
+		if bigfft.LowMemoryMode() {
+			t.Errorf("%s: expected the process-wide mode unchanged", calc.Name())
+		}
+
+		ConfigureMemoryMode(true)
+		if got, err = calc.Calculate(ctx, nil, 0, n, Options{LowMemory: true, FFTThreshold: 10_000}); err != nil || got.Cmp(want) != 0 {
+			t.Errorf("%s: low-memory result differs in low-memory mode (%v)", calc.Name(), err)
+		}
+		if got := pooledBitLenLimit(); got != LowMemoryMaxPooledBitLen {
+			t.Errorf("pooled bit length limit = %d, want %d", got, LowMemoryMaxPooledBitLen)
+		}
+		ConfigureMemoryMode(false)
+	}
+}
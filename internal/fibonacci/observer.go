@@ -22,6 +22,33 @@ type ProgressObserver interface {
 	Update(calcIndex int, progress float64)
 }
 
+// ProgressEventObserver is implemented by observers that want the detailed
+// per-step ProgressEvent instead of the bare progress fraction. Observers that
+// only implement ProgressObserver keep receiving Update calls.
+type ProgressEventObserver interface {
+	// OnProgressEvent is called after each step of a calculation loop.
+	//
+	// Parameters:
+	//   - event: The step event, including the normalized progress.
+	OnProgressEvent(event ProgressEvent)
+}
+
+// FloatObserverAdapter adapts a float-based ProgressObserver to the
+// ProgressEventObserver interface by forwarding only the progress fraction.
+type FloatObserverAdapter struct {
+	Observer ProgressObserver
+}
+
+// OnProgressEvent implements ProgressEventObserver.
+//
+// Parameters:
+//   - event: The step event; only CalculatorIndex and Progress are forwarded.
+func (a FloatObserverAdapter) OnProgressEvent(event ProgressEvent) {
+	if a.Observer != nil {
+		a.Observer.Update(event.CalculatorIndex, event.Progress)
+	}
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Progress Subject (Observable)
 // ─────────────────────────────────────────────────────────────────────────────
@@ -97,6 +124,25 @@ func (s *ProgressSubject) Notify(calcIndex int, progress float64) {
 	}
 }
 
+// NotifyEvent sends a step event to all registered observers. Observers that
+// implement ProgressEventObserver receive the full event; the others receive
+// the progress fraction through a FloatObserverAdapter.
+//
+// Parameters:
+//   - event: The step event.
+func (s *ProgressSubject) NotifyEvent(event ProgressEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, observer := range s.observers {
+		if eo, ok := observer.(ProgressEventObserver); ok {
+			eo.OnProgressEvent(event)
+		} else {
+			FloatObserverAdapter{Observer: observer}.OnProgressEvent(event)
+		}
+	}
+}
+
 // ObserverCount returns the number of registered observers.
 // This is primarily useful for testing and diagnostics.
 //
@@ -122,3 +168,18 @@ func (s *ProgressSubject) AsProgressReporter(calcIndex int) ProgressReporter {
 		s.Notify(calcIndex, progress)
 	}
 }
+
+// AsEventReporter returns a ProgressEventReporter that stamps events with
+// calcIndex and notifies all observers.
+//
+// Parameters:
+//   - calcIndex: The calculator instance identifier to include in events.
+//
+// Returns:
+//   - ProgressEventReporter: A function that receives step events.
+func (s *ProgressSubject) AsEventReporter(calcIndex int) ProgressEventReporter {
+	return func(event ProgressEvent) {
+		event.CalculatorIndex = calcIndex
+		s.NotifyEvent(event)
+	}
+}
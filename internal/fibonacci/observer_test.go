@@ -2,6 +2,7 @@ package fibonacci
 
 import (
 	"bytes"
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -437,3 +438,103 @@ func TestMultipleObserversIntegration(t *testing.T) {
 	// Verify no panics occurred (implicit)
 	_ = countingObs
 }
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Progress Event Tests
+// ─────────────────────────────────────────────────────────────────────────────
+
+// eventRecorder collects step events.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []ProgressEvent
+}
+
+func (r *eventRecorder) Update(int, float64) {}
+
+func (r *eventRecorder) OnProgressEvent(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// TestProgressSubject_NotifyEvent verifies that event observers receive the
+// full event and float observers receive the fraction through the adapter.
+func TestProgressSubject_NotifyEvent(t *testing.T) {
+	t.Parallel()
+
+	subject := NewProgressSubject()
+	floatObs := newMockObserver()
+	eventObs := &eventRecorder{}
+	subject.Register(floatObs)
+	subject.Register(eventObs)
+
+	report := subject.AsEventReporter(3)
+	report(ProgressEvent{Progress: 0.25, Step: 1, Method: MethodFFT})
+
+	if floatObs.updateCount() != 1 {
+		t.Fatalf("float observer got %d updates, want 1", floatObs.updateCount())
+	}
+	if got := floatObs.updates[0]; got.calcIndex != 3 || got.progress != 0.25 {
+		t.Errorf("float observer got %+v, want index 3 progress 0.25", got)
+	}
+	if len(eventObs.events) != 1 {
+		t.Fatalf("event observer got %d events, want 1", len(eventObs.events))
+	}
+	if ev := eventObs.events[0]; ev.CalculatorIndex != 3 || ev.Method != MethodFFT {
+		t.Errorf("event observer got %+v", ev)
+	}
+}
+
+// TestChannelObserver_OnProgressEvent verifies that channel updates carry the event.
+func TestChannelObserver_OnProgressEvent(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan ProgressUpdate, 1)
+	NewChannelObserver(ch).OnProgressEvent(ProgressEvent{CalculatorIndex: 1, Progress: 1.5, Step: 4})
+
+	update := <-ch
+	if update.Value != 1.0 || update.Event == nil || update.Event.Step != 4 {
+		t.Errorf("unexpected update %+v", update)
+	}
+}
+
+// TestCalculateWithObservers_StepEvents verifies that the doubling and matrix
+// loops emit one event per bit with monotonic progress.
+func TestCalculateWithObservers_StepEvents(t *testing.T) {
+	t.Parallel()
+
+	const n = 1 << 16
+	cores := []coreCalculator{&OptimizedFastDoubling{}, &MatrixExponentiation{}, &FFTBasedCalculator{}}
+	for _, core := range cores {
+		t.Run(core.Name(), func(t *testing.T) {
+			t.Parallel()
+			subject := NewProgressSubject()
+			rec := &eventRecorder{}
+			subject.Register(rec)
+
+			calc := &FibCalculator{core: core}
+			if _, err := calc.CalculateWithObservers(context.Background(), subject, 0, n, Options{}); err != nil {
+				t.Fatalf("calculation failed: %v", err)
+			}
+			if len(rec.events) == 0 {
+				t.Fatal("no step events emitted")
+			}
+			last := 0.0
+			for i, ev := range rec.events {
+				if ev.Step != i+1 || ev.TotalSteps != len(rec.events) {
+					t.Errorf("event %d: step %d/%d", i, ev.Step, ev.TotalSteps)
+				}
+				if ev.Progress < last {
+					t.Errorf("event %d: progress decreased from %f to %f", i, last, ev.Progress)
+				}
+				if ev.Method == "" {
+					t.Errorf("event %d: empty method", i)
+				}
+				last = ev.Progress
+			}
+			if last < 0.999 {
+				t.Errorf("final progress = %f, want ~1.0", last)
+			}
+		})
+	}
+}
@@ -50,9 +50,26 @@ func (o *ChannelObserver) Update(calcIndex int, progress float64) {
 		progress = 1.0
 	}
 
-	update := ProgressUpdate{CalculatorIndex: calcIndex, Value: progress}
+	o.send(ProgressUpdate{CalculatorIndex: calcIndex, Value: progress})
+}
+
+// OnProgressEvent implements ProgressEventObserver by sending an update that
+// carries the step event alongside its progress value.
+//
+// Parameters:
+//   - event: The step event.
+func (o *ChannelObserver) OnProgressEvent(event ProgressEvent) {
+	if o.channel == nil {
+		return
+	}
+	if event.Progress > 1.0 {
+		event.Progress = 1.0
+	}
+	o.send(ProgressUpdate{CalculatorIndex: event.CalculatorIndex, Value: event.Progress, Event: &event})
+}
 
-	// Non-blocking send to avoid deadlocks
+// send performs a non-blocking send to avoid deadlocks.
+func (o *ChannelObserver) send(update ProgressUpdate) {
 	select {
 	case o.channel <- update:
 	default:
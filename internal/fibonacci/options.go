@@ -36,6 +36,27 @@ type Options struct {
 	// DynamicAdjustmentInterval is the number of iterations between threshold checks.
 	// If 0, uses the default (5 iterations). Only used when EnableDynamicThresholds is true.
 	DynamicAdjustmentInterval int
+	// LowMemory trades speed for a smaller footprint: it disables parallel
+	// multiplication branches and releases temporaries after each step. The
+	// pools, bump allocators and FFT transform cache are shared by the whole
+	// process and shrink with ConfigureMemoryMode instead.
+	LowMemory bool
+	// IntegrityCheck maintains the loop state modulo a random 61-bit prime
+	// alongside the big-integer computation and aborts with an
+	// *IntegrityError as soon as they diverge.
+	IntegrityCheck bool
+	// IntegrityInterval is the number of steps between integrity checks; the
+	// last step is always checked. If 0 or 1, every step is checked.
+	IntegrityInterval int
+}
+
+// Normalized returns a copy of the options with the default thresholds
+// filled in for zero values: the thresholds the calculators actually use.
+//
+// Returns:
+//   - Options: The normalized options.
+func (o Options) Normalized() Options {
+	return normalizeOptions(o)
 }
 
 // normalizeOptions returns a copy of opts with default values filled in for zero values.
@@ -85,6 +106,9 @@ func configureFFTCache(opts Options) {
 	if opts.FFTCacheEnabled != nil {
 		config.Enabled = *opts.FFTCacheEnabled
 	}
+	if bigfft.LowMemoryMode() {
+		config.Enabled = false
+	}
 
 	// Apply configuration to global cache
 	bigfft.SetTransformCacheConfig(config)
@@ -0,0 +1,64 @@
+// Package fibonacci provides implementations for calculating Fibonacci numbers.
+// This file tracks the calculations running concurrently in the process.
+package fibonacci
+
+import "sync/atomic"
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Overlapping Calculations
+// ─────────────────────────────────────────────────────────────────────────────
+
+// Process-wide counters of the calculations: those running, and those
+// started since the process began. A calculation increments the first
+// before the second, so that a watcher reading them in the opposite order
+// never misses it.
+var (
+	activeCalculations  atomic.Int64
+	startedCalculations atomic.Uint64
+)
+
+// trackCalculation counts a calculation as running until the returned
+// function is called.
+func trackCalculation() (done func()) {
+	activeCalculations.Add(1)
+	startedCalculations.Add(1)
+	return func() { activeCalculations.Add(-1) }
+}
+
+// OverlapWatch detects calculations overlapping a measurement. Allocation,
+// CPU and GC counters are process-wide, so a measurement taken while other
+// calculations run also counts their work.
+type OverlapWatch struct {
+	started uint64
+	alone   bool
+	own     uint64
+}
+
+// WatchOverlap starts watching for overlapping calculations.
+//
+// Parameters:
+//   - inside: True if the caller runs within the calculation it measures (a
+//     step of its loop), false if it runs around it (a meter started before
+//     the calculation and stopped after it).
+//
+// Returns:
+//   - OverlapWatch: The watch.
+func WatchOverlap(inside bool) OverlapWatch {
+	w := OverlapWatch{started: startedCalculations.Load(), own: 1}
+	if inside {
+		w.own = 0
+		w.alone = activeCalculations.Load() == 1
+	} else {
+		w.alone = activeCalculations.Load() == 0
+	}
+	return w
+}
+
+// Overlapped reports whether another calculation ran at some point since
+// WatchOverlap.
+//
+// Returns:
+//   - bool: True if the measurement may include the work of others.
+func (w OverlapWatch) Overlapped() bool {
+	return !w.alone || startedCalculations.Load()-w.started > w.own
+}
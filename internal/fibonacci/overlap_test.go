@@ -0,0 +1,30 @@
+package fibonacci
+
+import "testing"
+
+func TestWatchOverlap(t *testing.T) {
+	// Around a calculation: its own start is expected
+	w := WatchOverlap(false)
+	done := trackCalculation()
+	if w.Overlapped() {
+		t.Error("expected a calculation alone not to overlap")
+	}
+
+	// Within it, another calculation starting is detected
+	inner := WatchOverlap(true)
+	if inner.Overlapped() {
+		t.Error("expected no overlap before another calculation starts")
+	}
+	other := trackCalculation()
+	if !inner.Overlapped() || !w.Overlapped() {
+		t.Error("expected the other calculation to be detected")
+	}
+
+	// A step starting while the other one runs overlaps it, even after it ends
+	inner = WatchOverlap(true)
+	other()
+	if !inner.Overlapped() {
+		t.Error("expected a step started during another calculation to overlap")
+	}
+	done()
+}
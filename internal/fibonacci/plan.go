@@ -0,0 +1,234 @@
+// Package fibonacci provides implementations for calculating Fibonacci numbers.
+// This file builds execution plans: a dry run of the step schedule that
+// describes what a calculation would do without performing it.
+package fibonacci
+
+import (
+	"math"
+	"math/bits"
+	"runtime"
+	"time"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Execution Plan Types
+// ─────────────────────────────────────────────────────────────────────────────
+
+// Multiplication method names used in plans and step reports.
+const (
+	MethodStandard  = "math/big"
+	MethodKaratsuba = "karatsuba"
+	MethodFFT       = "fft"
+	MethodStrassen  = "strassen"
+	MethodLookup    = "lookup"
+)
+
+// log2Phi is log2 of the golden ratio, used to estimate the bit length of F(k).
+const log2Phi = 0.6942419136306174
+
+// log2Sqrt5 is log2(sqrt(5)), the constant term in the bit length of F(k).
+const log2Sqrt5 = 1.1609640474436813
+
+// PlanStep describes a single iteration of the calculation loop.
+type PlanStep struct {
+	// Step is the 0-based iteration number.
+	Step int `json:"step"`
+	// BitIndex is the bit of n (or n-1 for matrix) processed by this step.
+	BitIndex int `json:"bit_index"`
+	// Bit is the value of that bit (1 triggers an addition or a multiply).
+	Bit uint `json:"bit"`
+	// OperandBits is the estimated bit length of the operands multiplied.
+	OperandBits int `json:"operand_bits"`
+	// Method is the multiplication method selected for these operands.
+	Method string `json:"method"`
+	// Parallel indicates whether the step's multiplications run in parallel.
+	Parallel bool `json:"parallel"`
+}
+
+// PlanSwitch records the first step at which a method or parallelism engages.
+type PlanSwitch struct {
+	// Feature is the method name or "parallel".
+	Feature string `json:"feature"`
+	// Step is the first step using the feature.
+	Step int `json:"step"`
+	// OperandBits is the operand size at that step.
+	OperandBits int `json:"operand_bits"`
+}
+
+// PlanThresholds are the normalized thresholds the plan was built with.
+type PlanThresholds struct {
+	Parallel  int `json:"parallel"`
+	FFT       int `json:"fft"`
+	Karatsuba int `json:"karatsuba"`
+	Strassen  int `json:"strassen"`
+}
+
+// ExecutionPlan describes how F(N) would be computed by an algorithm.
+type ExecutionPlan struct {
+	N          uint64         `json:"n"`
+	Algorithm  string         `json:"algorithm"`
+	ResultBits int            `json:"result_bits"`
+	Thresholds PlanThresholds `json:"thresholds"`
+	Steps      []PlanStep     `json:"steps"`
+	Switches   []PlanSwitch   `json:"switches"`
+	// PeakMemoryBytes is the expected peak working set (EstimatePeakMemory).
+	PeakMemoryBytes uint64 `json:"peak_memory_bytes"`
+	// LowMemory indicates that the plan runs in low-memory mode.
+	LowMemory bool `json:"low_memory,omitempty"`
+	// EstimatedDuration is filled in by callers that have a cost model.
+	EstimatedDuration time.Duration `json:"estimated_duration_ns,omitempty"`
+	// EstimateSource describes where EstimatedDuration comes from.
+	EstimateSource string `json:"estimate_source,omitempty"`
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Plan Construction
+// ─────────────────────────────────────────────────────────────────────────────
+
+// EstimateFibBitLen returns the approximate bit length of F(k), using
+// F(k) ≈ φ^k / √5.
+//
+// Parameters:
+//   - k: The Fibonacci index.
+//
+// Returns:
+//   - int: The estimated bit length (exact to within one bit).
+func EstimateFibBitLen(k uint64) int {
+	if k == 0 {
+		return 0
+	}
+	if k <= 2 {
+		return 1
+	}
+	return int(math.Floor(float64(k)*log2Phi-log2Sqrt5)) + 1
+}
+
+// PlanCalculation builds the execution plan of F(n) for a registered
+// algorithm without performing the calculation. The step schedule follows
+// the same decisions as the calculation loops: the multiplication tier of
+// smartMultiply, shouldParallelizeMultiplicationCached for the doubling
+// loop, and the Strassen switch of the matrix loop.
+//
+// Parameters:
+//   - n: The Fibonacci index.
+//   - algorithm: The registered algorithm name ("fast", "fft", "matrix", ...).
+//   - opts: The calculation options (thresholds).
+//
+// Returns:
+//   - ExecutionPlan: The plan.
+func PlanCalculation(n uint64, algorithm string, opts Options) ExecutionPlan {
+	normalized := normalizeOptions(opts)
+	plan := ExecutionPlan{
+		N:          n,
+		Algorithm:  algorithm,
+		ResultBits: EstimateFibBitLen(n),
+		Thresholds: PlanThresholds{
+			Parallel:  normalized.ParallelThreshold,
+			FFT:       normalized.FFTThreshold,
+			Karatsuba: normalized.KaratsubaThreshold,
+			Strassen:  normalized.StrassenThreshold,
+		},
+		PeakMemoryBytes: EstimatePeakMemory(n, algorithm, opts),
+		LowMemory:       opts.LowMemory,
+	}
+
+	if n <= MaxFibUint64 {
+		plan.Steps = []PlanStep{{Method: MethodLookup}}
+		return plan
+	}
+
+	switch algorithm {
+	case "matrix":
+		plan.Steps = planMatrixSteps(n, normalized)
+	case "fft":
+		plan.Steps = planDoublingSteps(n, normalized, false, true)
+	default:
+		plan.Steps = planDoublingSteps(n, normalized, runtime.GOMAXPROCS(0) > 1 && !normalized.LowMemory, false)
+	}
+	plan.Switches = planSwitches(plan.Steps)
+	return plan
+}
+
+// planDoublingSteps mirrors DoublingFramework.ExecuteDoublingLoop.
+func planDoublingSteps(n uint64, opts Options, useParallel, fftOnly bool) []PlanStep {
+	numBits := bits.Len64(n)
+	steps := make([]PlanStep, 0, numBits)
+	var k uint64
+	for i := numBits - 1; i >= 0; i-- {
+		fkBits := EstimateFibBitLen(k)
+		fk1Bits := EstimateFibBitLen(k + 1)
+		method := multiplicationMethod(fk1Bits, opts)
+		if fftOnly {
+			method = MethodFFT
+		}
+		bit := uint((n >> uint(i)) & 1)
+		steps = append(steps, PlanStep{
+			Step:        len(steps),
+			BitIndex:    i,
+			Bit:         bit,
+			OperandBits: fk1Bits,
+			Method:      method,
+			Parallel:    useParallel && shouldParallelizeMultiplicationCached(opts, fkBits, fk1Bits),
+		})
+		k = 2*k + uint64(bit)
+	}
+	return steps
+}
+
+// planMatrixSteps mirrors MatrixFramework.ExecuteMatrixLoop, which walks the
+// bits of n-1 from least to most significant while squaring the base matrix.
+func planMatrixSteps(n uint64, opts Options) []PlanStep {
+	exponent := n - 1
+	numBits := bits.Len64(exponent)
+	useParallel := runtime.NumCPU() > 1 && opts.ParallelThreshold > 0 && !opts.LowMemory
+	steps := make([]PlanStep, 0, numBits)
+	for i := 0; i < numBits; i++ {
+		// The base matrix holds F(2^i + 1) as its largest element
+		pBits := EstimateFibBitLen(uint64(1)<<uint(i) + 1)
+		bit := uint((exponent >> uint(i)) & 1)
+		method := multiplicationMethod(pBits, opts)
+		if bit == 1 && pBits > opts.StrassenThreshold {
+			method = MethodStrassen
+		}
+		steps = append(steps, PlanStep{
+			Step:        i,
+			BitIndex:    i,
+			Bit:         bit,
+			OperandBits: pBits,
+			Method:      method,
+			Parallel:    useParallel && pBits > opts.ParallelThreshold,
+		})
+	}
+	return steps
+}
+
+// multiplicationMethod mirrors the tier selection of smartMultiply.
+func multiplicationMethod(bitLen int, opts Options) string {
+	switch {
+	case opts.FFTThreshold > 0 && bitLen > opts.FFTThreshold:
+		return MethodFFT
+	case opts.KaratsubaThreshold > 0 && bitLen > opts.KaratsubaThreshold:
+		return MethodKaratsuba
+	default:
+		return MethodStandard
+	}
+}
+
+// planSwitches records the first step at which each method and parallelism engage.
+func planSwitches(steps []PlanStep) []PlanSwitch {
+	var switches []PlanSwitch
+	seen := make(map[string]bool)
+	record := func(feature string, st PlanStep) {
+		if !seen[feature] {
+			seen[feature] = true
+			switches = append(switches, PlanSwitch{Feature: feature, Step: st.Step, OperandBits: st.OperandBits})
+		}
+	}
+	for _, st := range steps {
+		record(st.Method, st)
+		if st.Parallel {
+			record("parallel", st)
+		}
+	}
+	return switches
+}
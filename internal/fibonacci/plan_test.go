@@ -0,0 +1,97 @@
+package fibonacci
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEstimateFibBitLen(t *testing.T) {
+	t.Parallel()
+	a, b := big.NewInt(0), big.NewInt(1)
+	for k := uint64(0); k <= 10_000; k++ {
+		// a holds F(k)
+		got, want := EstimateFibBitLen(k), a.BitLen()
+		if diff := got - want; diff < -1 || diff > 1 {
+			t.Fatalf("EstimateFibBitLen(%d) = %d, want %d±1", k, got, want)
+		}
+		a.Add(a, b)
+		a, b = b, a
+	}
+}
+
+func TestPlanCalculation(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{ParallelThreshold: 4096, FFTThreshold: 500_000, StrassenThreshold: 3072}
+
+	t.Run("Small n uses lookup", func(t *testing.T) {
+		t.Parallel()
+		plan := PlanCalculation(50, "fast", opts)
+		if len(plan.Steps) != 1 || plan.Steps[0].Method != MethodLookup {
+			t.Errorf("expected a single lookup step, got %+v", plan.Steps)
+		}
+	})
+
+	t.Run("Fast doubling", func(t *testing.T) {
+		t.Parallel()
+		n := uint64(10_000_000)
+		plan := PlanCalculation(n, "fast", opts)
+		if len(plan.Steps) != 24 { // bits.Len64(10_000_000)
+			t.Fatalf("expected 24 steps, got %d", len(plan.Steps))
+		}
+		last := plan.Steps[len(plan.Steps)-1]
+		if last.Method != MethodFFT {
+			t.Errorf("last step method = %s, want %s", last.Method, MethodFFT)
+		}
+		if plan.Steps[2].Method != MethodStandard {
+			t.Errorf("early step method = %s, want %s", plan.Steps[2].Method, MethodStandard)
+		}
+		for i := 1; i < len(plan.Steps); i++ {
+			if plan.Steps[i].OperandBits < plan.Steps[i-1].OperandBits {
+				t.Fatalf("operand sizes must not decrease: step %d", i)
+			}
+		}
+		var sawFFT bool
+		for _, sw := range plan.Switches {
+			if sw.Feature == MethodFFT {
+				sawFFT = true
+				if sw.OperandBits <= opts.FFTThreshold {
+					t.Errorf("FFT switch at %d bits, below threshold", sw.OperandBits)
+				}
+			}
+		}
+		if !sawFFT {
+			t.Errorf("expected an FFT switch point, got %+v", plan.Switches)
+		}
+		if plan.PeakMemoryBytes == 0 {
+			t.Error("expected a peak memory estimate")
+		}
+	})
+
+	t.Run("FFT only", func(t *testing.T) {
+		t.Parallel()
+		plan := PlanCalculation(1_000_000, "fft", opts)
+		for _, st := range plan.Steps {
+			if st.Method != MethodFFT || st.Parallel {
+				t.Fatalf("fft plan step %+v: want sequential FFT", st)
+			}
+		}
+	})
+
+	t.Run("Matrix uses Strassen", func(t *testing.T) {
+		t.Parallel()
+		plan := PlanCalculation(1_000_001, "matrix", opts)
+		var sawStrassen bool
+		for _, st := range plan.Steps {
+			if st.Method == MethodStrassen {
+				sawStrassen = true
+				if st.Bit != 1 || st.OperandBits <= opts.StrassenThreshold {
+					t.Errorf("unexpected Strassen step %+v", st)
+				}
+			}
+		}
+		if !sawStrassen {
+			t.Error("expected Strassen steps in the matrix plan")
+		}
+	})
+}
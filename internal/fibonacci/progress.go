@@ -2,7 +2,14 @@
 // This file contains progress reporting types and utilities used by calculators.
 package fibonacci
 
-import "math"
+import (
+	"context"
+	"math"
+	"runtime/metrics"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 // ProgressUpdate is a data transfer object (DTO) that encapsulates the
 // progress state of a calculation. It is sent over a channel from the
@@ -13,6 +20,62 @@ type ProgressUpdate struct {
 	CalculatorIndex int
 	// Value represents the normalized progress of the calculation, ranging from 0.0 to 1.0.
 	Value float64
+	// Event carries the detailed step event that produced this update, or nil
+	// when the update only reports a fraction (e.g. the final 1.0).
+	Event *ProgressEvent
+}
+
+// ProgressEvent describes one completed step of a calculation loop. Besides
+// the normalized progress it records where the loop is in the bits of n, how
+// large the operands were, which multiplication method and parallelism the
+// step used, how long it took and how many bytes it allocated.
+type ProgressEvent struct {
+	// CalculatorIndex identifies the calculator instance.
+	CalculatorIndex int `json:"calculator"`
+	// Progress is the normalized progress after this step (0.0 to 1.0).
+	Progress float64 `json:"progress"`
+	// Step is the 1-based index of the completed step.
+	Step int `json:"step"`
+	// TotalSteps is the number of steps of the loop.
+	TotalSteps int `json:"total_steps"`
+	// BitIndex is the bit of n (or n-1 for the matrix method) processed by this step.
+	BitIndex int `json:"bit_index"`
+	// OperandBits is the bit length of the largest operand multiplied in this step.
+	OperandBits int `json:"operand_bits"`
+	// Method is the multiplication method used (see the Method* constants).
+	Method string `json:"method"`
+	// Parallel reports whether the step's multiplications ran in parallel.
+	Parallel bool `json:"parallel"`
+	// StepDuration is the wall-clock time spent in the step.
+	StepDuration time.Duration `json:"step_duration_ns"`
+	// BytesAllocated is the number of heap bytes allocated in the process
+	// during the step. It is 0 when the step is Overlapped.
+	BytesAllocated uint64 `json:"bytes_allocated"`
+	// Overlapped reports that other calculations ran in the process during
+	// the step, so that its allocations could not be told apart from theirs.
+	Overlapped bool `json:"overlapped,omitempty"`
+}
+
+// ProgressEventReporter is the callback type for detailed step events. It is
+// the event counterpart of ProgressReporter.
+//
+// Parameters:
+//   - event: The step event.
+type ProgressEventReporter func(event ProgressEvent)
+
+// eventReporterKey is the context key under which the event reporter travels
+// from CalculateWithObservers down to the calculation loops.
+type eventReporterKey struct{}
+
+// withEventReporter returns a context carrying the given event reporter.
+func withEventReporter(ctx context.Context, reporter ProgressEventReporter) context.Context {
+	return context.WithValue(ctx, eventReporterKey{}, reporter)
+}
+
+// eventReporterFrom returns the event reporter carried by ctx, or nil.
+func eventReporterFrom(ctx context.Context) ProgressEventReporter {
+	reporter, _ := ctx.Value(eventReporterKey{}).(ProgressEventReporter)
+	return reporter
 }
 
 // ProgressReporter defines the functional type for a progress reporting
@@ -126,3 +189,154 @@ func ReportStepProgress(progressReporter ProgressReporter, lastReported *float64
 	}
 	return currentTotalDone
 }
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Step Tracking
+// ─────────────────────────────────────────────────────────────────────────────
+
+// heapAllocsMetric is the runtime metric holding the cumulative bytes
+// allocated on the heap.
+const heapAllocsMetric = "/gc/heap/allocs:bytes"
+
+// stepInfo describes the work a loop step performed.
+type stepInfo struct {
+	bitIndex    int
+	operandBits int
+	method      string
+	parallel    bool
+}
+
+// stepTracker drives progress reporting and tracing for the calculation
+// loops. Each step runs in its own span. When the context carries an event
+// reporter, every step is reported as a ProgressEvent (timed, and with its
+// allocations measured unless other calculations overlap it); otherwise it
+// falls back to throttled fractional reporting through ReportStepProgress.
+type stepTracker struct {
+	ctx          context.Context
+	spanName     string
+	phase        string
+	span         trace.Span
+	stepCtx      context.Context
+	restore      func()
+	reporter     ProgressReporter
+	events       ProgressEventReporter
+	numBits      int
+	totalWork    float64
+	workDone     float64
+	lastReported float64
+	powers       []float64
+	step         int
+
+	start  time.Time
+	sample []metrics.Sample
+	allocs uint64
+	watch  OverlapWatch
+}
+
+// newStepTracker creates a tracker for a loop of numBits steps whose spans
+// are named spanName and whose profile samples are labelled with phase.
+func newStepTracker(ctx context.Context, spanName, phase string, reporter ProgressReporter, numBits int) *stepTracker {
+	t := &stepTracker{
+		ctx:          ctx,
+		spanName:     spanName,
+		phase:        phase,
+		stepCtx:      ctx,
+		reporter:     reporter,
+		events:       eventReporterFrom(ctx),
+		numBits:      numBits,
+		totalWork:    CalcTotalWork(numBits),
+		lastReported: -1.0,
+		powers:       PrecomputePowers4(numBits),
+	}
+	if t.events != nil {
+		t.sample = []metrics.Sample{{Name: heapAllocsMetric}}
+	}
+	return t
+}
+
+// begin marks the start of a step, opens its span and sets its phase label.
+func (t *stepTracker) begin() {
+	t.stepCtx, t.span = tracer().Start(t.ctx, t.spanName)
+	t.stepCtx, t.restore = withPhase(t.stepCtx, t.phase)
+	if t.events == nil {
+		return
+	}
+	t.watch = WatchOverlap(true)
+	t.allocs = t.readAllocs()
+	t.start = time.Now()
+}
+
+// end marks the completion of a step and reports it.
+//
+// Parameters:
+//   - i: The bit index in the countdown convention of ReportStepProgress
+//     (numBits-1 for the smallest step, 0 for the largest).
+//   - info: What the step did.
+func (t *stepTracker) end(i int, info stepInfo) {
+	t.step++
+	if t.span != nil {
+		if t.span.IsRecording() {
+			t.span.SetAttributes(stepAttributes(t.step, info)...)
+		}
+		t.span.End()
+		t.finish()
+	}
+
+	if t.events == nil {
+		t.workDone = ReportStepProgress(t.reporter, &t.lastReported, t.totalWork, t.workDone, i, t.numBits, t.powers)
+		return
+	}
+	elapsed := time.Since(t.start)
+	allocated := t.readAllocs() - t.allocs
+	overlapped := t.watch.Overlapped()
+	if overlapped {
+		allocated = 0
+	}
+
+	t.workDone += t.powers[t.numBits-1-i]
+	progress := 0.0
+	if t.totalWork > 0 {
+		progress = t.workDone / t.totalWork
+	}
+	t.events(ProgressEvent{
+		Progress:       progress,
+		Step:           t.step,
+		TotalSteps:     t.numBits,
+		BitIndex:       info.bitIndex,
+		OperandBits:    info.operandBits,
+		Method:         info.method,
+		Parallel:       info.parallel,
+		StepDuration:   elapsed,
+		BytesAllocated: allocated,
+		Overlapped:     overlapped,
+	})
+}
+
+// fail ends the span of the current step with err and returns err.
+func (t *stepTracker) fail(err error) error {
+	if t.span != nil {
+		endSpan(t.span, err)
+		t.finish()
+	}
+	return err
+}
+
+// finish restores the labels of the loop and clears the step state.
+func (t *stepTracker) finish() {
+	t.restore()
+	t.span, t.stepCtx, t.restore = nil, t.ctx, nil
+}
+
+// context returns the context of the current step, carrying its span.
+func (t *stepTracker) context() context.Context {
+	return t.stepCtx
+}
+
+// readAllocs returns the cumulative heap allocation counter.
+func (t *stepTracker) readAllocs() uint64 {
+	metrics.Read(t.sample)
+	if t.sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return t.sample[0].Value.Uint64()
+}
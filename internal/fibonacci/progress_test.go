@@ -1,6 +1,8 @@
 package fibonacci
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -194,3 +196,19 @@ func TestReportStepProgressMonotonic(t *testing.T) {
 		workDone = ReportStepProgress(reporter, &lastReported, totalWork, workDone, i, numBits, powers)
 	}
 }
+
+// TestProgressEvent_JSON verifies the wire names of ProgressEvent fields.
+func TestProgressEvent_JSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(ProgressEvent{Step: 2, Method: MethodKaratsuba, StepDuration: 5})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	for _, key := range []string{`"calculator"`, `"progress"`, `"step":2`, `"total_steps"`, `"bit_index"`,
+		`"operand_bits"`, `"method":"karatsuba"`, `"parallel"`, `"step_duration_ns":5`, `"bytes_allocated"`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("JSON %s missing %s", data, key)
+		}
+	}
+}
@@ -100,6 +100,10 @@ func (s *AdaptiveStrategy) ExecuteStep(state *CalculationState, opts Options, in
 		return executeDoublingStepFFT(state, opts, inParallel)
 	}
 	// Fallback to standard doubling step multiplication
+	if opts.KaratsubaThreshold > 0 && state.FK1.BitLen() > opts.KaratsubaThreshold {
+		_, restoreLabels := withPhase(state.stepCtx, PhaseKaratsuba)
+		defer restoreLabels()
+	}
 	return executeDoublingStepMultiplications(s, state, opts, inParallel)
 }
 
@@ -0,0 +1,77 @@
+// Package fibonacci provides implementations for calculating Fibonacci numbers.
+// This file contains the OpenTelemetry span helpers used by the calculators.
+package fibonacci
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the calculation spans.
+const tracerName = "fibonacci"
+
+// Span attribute keys.
+const (
+	attrBitIndex    = attribute.Key("fib.bit_index")
+	attrOperandBits = attribute.Key("fib.operand_bits")
+	attrMethod      = attribute.Key("fib.method")
+	attrParallel    = attribute.Key("fib.parallel")
+	attrStep        = attribute.Key("fib.step")
+	attrFFTK        = attribute.Key("fft.k")
+	attrFFTWords    = attribute.Key("fft.words")
+)
+
+// tracer returns the calculation tracer. It is looked up on each use so that
+// a tracer provider installed after package initialization is honoured.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// stepAttributes returns the span attributes describing a loop step.
+func stepAttributes(step int, info stepInfo) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attrStep.Int(step),
+		attrBitIndex.Int(info.bitIndex),
+		attrOperandBits.Int(info.operandBits),
+		attrMethod.String(info.method),
+		attrParallel.Bool(info.parallel),
+	}
+}
+
+// startFFTSpan starts a span for an FFT phase of a doubling step.
+//
+// Parameters:
+//   - ctx: The parent context (the step context); nil means no parent.
+//   - name: The span name.
+//   - k: The FFT size exponent.
+//   - words: The coefficient length in words.
+//   - parallel: Whether the phase runs its operations in parallel.
+//
+// Returns:
+//   - trace.Span: The started span.
+func startFFTSpan(ctx context.Context, name string, k uint, words int, parallel bool) trace.Span {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := tracer().Start(ctx, name, trace.WithAttributes(
+		attrMethod.String(MethodFFT),
+		attrFFTK.Int(int(k)),
+		attrFFTWords.Int(words),
+		attrParallel.Bool(parallel),
+	))
+	return span
+}
+
+// endSpan ends span, recording err on it if non-nil, and returns err.
+func endSpan(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	return err
+}
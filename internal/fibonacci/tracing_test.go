@@ -0,0 +1,63 @@
+package fibonacci
+
+import (
+	"context"
+	"math/bits"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestCalculationSpans verifies the calculation, step and FFT spans.
+// It is not parallel because it replaces the global tracer provider.
+func TestCalculationSpans(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	const n = 200_000
+	opts := Options{ParallelThreshold: 4096, FFTThreshold: 20_000, KaratsubaThreshold: 2048}
+	calc := &FibCalculator{core: &OptimizedFastDoubling{}}
+	if _, err := calc.CalculateWithObservers(context.Background(), nil, 0, n, opts); err != nil {
+		t.Fatalf("calculation failed: %v", err)
+	}
+
+	counts := make(map[string]int)
+	var root sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		counts[span.Name()]++
+		if span.Name() == "Calculate" {
+			root = span
+		}
+	}
+	if root == nil {
+		t.Fatal("missing Calculate span")
+	}
+	if got, want := counts["DoublingStep"], bits.Len64(n); got != want {
+		t.Errorf("DoublingStep spans = %d, want %d", got, want)
+	}
+	if counts["FFTTransform"] == 0 || counts["FFTTransform"] != counts["FFTMultiply"] {
+		t.Errorf("expected matching FFT spans, got %v", counts)
+	}
+
+	for _, span := range recorder.Ended() {
+		if span.Name() != "DoublingStep" {
+			continue
+		}
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("step span not parented to Calculate")
+		}
+		attrs := make(map[string]bool)
+		for _, kv := range span.Attributes() {
+			attrs[string(kv.Key)] = true
+		}
+		for _, key := range []string{"fib.bit_index", "fib.operand_bits", "fib.method", "fib.parallel"} {
+			if !attrs[key] {
+				t.Errorf("step span missing attribute %s", key)
+			}
+		}
+	}
+}
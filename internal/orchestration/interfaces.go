@@ -51,6 +51,34 @@ func (NullProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-c
 	}
 }
 
+// TeeProgressReporter is a ProgressReporter that passes each update to a
+// function before forwarding it to another reporter, so that the updates
+// can be recorded without changing the display.
+type TeeProgressReporter struct {
+	// Next is the reporter that displays the progress.
+	Next ProgressReporter
+	// Record is called with each update, in order of arrival, before the
+	// update is forwarded.
+	Record func(update fibonacci.ProgressUpdate)
+}
+
+// DisplayProgress records and forwards each update, and returns once the
+// forwarded reporter is done.
+func (t TeeProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressUpdate, numCalculators int, out io.Writer) {
+	defer wg.Done()
+	forward := make(chan fibonacci.ProgressUpdate, cap(progressChan))
+	var nextWg sync.WaitGroup
+	nextWg.Add(1)
+	go t.Next.DisplayProgress(&nextWg, forward, numCalculators, out)
+
+	for update := range progressChan {
+		t.Record(update)
+		forward <- update
+	}
+	close(forward)
+	nextWg.Wait()
+}
+
 // ResultPresenter defines the interface for presenting calculation results.
 // This interface decouples the orchestration layer from presentation concerns,
 // allowing different output formats (CLI, JSON, etc.) without modifying
@@ -75,6 +103,15 @@ type ResultPresenter interface {
 	//   - out: The writer for output.
 	PresentResult(result CalculationResult, n uint64, verbose, details, concise bool, out io.Writer)
 
+	// PresentRaceSummary displays the winner of a race (--race) and by how
+	// much it beat the other calculations.
+	//
+	// Parameters:
+	//   - winner: The first successful result.
+	//   - others: The other results, finished or canceled.
+	//   - out: The writer for output.
+	PresentRaceSummary(winner CalculationResult, others []CalculationResult, out io.Writer)
+
 	// FormatDuration formats a duration for display.
 	//
 	// Parameters:
@@ -0,0 +1,52 @@
+package orchestration
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// TestTeeProgressReporter verifies that every update is recorded and
+// forwarded in order, and that the reporter returns once the forwarded
+// reporter is done.
+func TestTeeProgressReporter(t *testing.T) {
+	t.Parallel()
+	var recorded, forwarded []float64
+	forwardDone := false
+	tee := TeeProgressReporter{
+		Next: ProgressReporterFunc(func(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressUpdate, _ int, _ io.Writer) {
+			defer wg.Done()
+			for update := range progressChan {
+				forwarded = append(forwarded, update.Value)
+			}
+			forwardDone = true
+		}),
+		Record: func(update fibonacci.ProgressUpdate) {
+			recorded = append(recorded, update.Value)
+		},
+	}
+
+	progressChan := make(chan fibonacci.ProgressUpdate, 3)
+	for _, v := range []float64{0.25, 0.5, 1} {
+		progressChan <- fibonacci.ProgressUpdate{Value: v}
+	}
+	close(progressChan)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	tee.DisplayProgress(&wg, progressChan, 1, io.Discard)
+	wg.Wait()
+
+	if !forwardDone {
+		t.Fatal("DisplayProgress returned before the forwarded reporter")
+	}
+	if len(recorded) != 3 || len(forwarded) != 3 {
+		t.Fatalf("recorded %v, forwarded %v; want 3 updates each", recorded, forwarded)
+	}
+	for i := range recorded {
+		if recorded[i] != forwarded[i] {
+			t.Errorf("update %d: recorded %g, forwarded %g", i, recorded[i], forwarded[i])
+		}
+	}
+}
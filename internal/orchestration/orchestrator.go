@@ -2,9 +2,12 @@ package orchestration
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"math/big"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -28,6 +31,35 @@ type CalculationResult struct {
 	Duration time.Duration
 	// Err contains any error that occurred during the calculation.
 	Err error
+	// Resources describes the CPU time, memory and GC activity of the
+	// calculation.
+	Resources ResourceUsage
+	// Progress is the fraction of the calculation completed, recorded in
+	// race mode: 1 for finished calculations, the last reported progress for
+	// canceled ones.
+	Progress float64
+	// Thresholds are the final statistics of the dynamic thresholds, or nil
+	// if the calculation did not adjust its thresholds.
+	Thresholds *fibonacci.ThresholdStats
+	// Digest is the digest of the result (see Digest), set by the results
+	// that may lack their value: the results of a replayed session, whose
+	// values over session.MaxValueBits are not recorded.
+	Digest string
+}
+
+// Digest returns the hexadecimal SHA-256 of the big-endian magnitude of x.
+// Results carry it wherever they are recorded (JSON output, reports,
+// sessions, TUI history, self-test known answers), so that they can be
+// compared without their digits.
+//
+// Parameters:
+//   - x: The value to digest.
+//
+// Returns:
+//   - string: The hexadecimal digest.
+func Digest(x *big.Int) string {
+	sum := sha256.Sum256(x.Bytes())
+	return hex.EncodeToString(sum[:])
 }
 
 // ProgressBufferMultiplier defines the buffer size multiplier for the progress
@@ -35,6 +67,73 @@ type CalculationResult struct {
 // goroutines when the UI is slow to consume updates.
 const ProgressBufferMultiplier = 5
 
+// progressObserversKey is the context key of WithProgressObserver.
+type progressObserversKey struct{}
+
+// WithProgressObserver returns a context under which ExecuteCalculations
+// also registers observer on the progress subject of each calculator. The
+// subject notifies it synchronously, as each update is emitted, so that it
+// receives every update; the progress channel of the reporters drops the
+// updates it has no room for.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - observer: The observer, added to those of ctx.
+//
+// Returns:
+//   - context.Context: The context carrying the observers.
+func WithProgressObserver(ctx context.Context, observer fibonacci.ProgressObserver) context.Context {
+	observers := slices.Clip(progressObserversFrom(ctx))
+	return context.WithValue(ctx, progressObserversKey{}, append(observers, observer))
+}
+
+// progressObserversFrom returns the observers carried by ctx.
+func progressObserversFrom(ctx context.Context) []fibonacci.ProgressObserver {
+	observers, _ := ctx.Value(progressObserversKey{}).([]fibonacci.ProgressObserver)
+	return observers
+}
+
+// observableCalculator is implemented by calculators that report progress to
+// a fibonacci.ProgressSubject, such as fibonacci.FibCalculator.
+type observableCalculator interface {
+	CalculateWithObservers(ctx context.Context, subject *fibonacci.ProgressSubject, calcIndex int, n uint64, opts fibonacci.Options) (*big.Int, error)
+}
+
+// calculate runs calc, reporting its progress to progressChan and to the
+// observers of ctx. The updates of calculators without a progress subject
+// are relayed to the observers.
+func calculate(ctx context.Context, calc fibonacci.Calculator, progressChan chan<- fibonacci.ProgressUpdate, calcIndex int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+	observers := progressObserversFrom(ctx)
+	if len(observers) == 0 {
+		return calc.Calculate(ctx, progressChan, calcIndex, n, opts)
+	}
+	subject := fibonacci.NewProgressSubject()
+	subject.Register(fibonacci.NewChannelObserver(progressChan))
+	for _, observer := range observers {
+		subject.Register(observer)
+	}
+	if oc, ok := calc.(observableCalculator); ok {
+		return oc.CalculateWithObservers(ctx, subject, calcIndex, n, opts)
+	}
+
+	relayChan := make(chan fibonacci.ProgressUpdate, cap(progressChan))
+	relayed := make(chan struct{})
+	go func() {
+		defer close(relayed)
+		for update := range relayChan {
+			if update.Event != nil {
+				subject.NotifyEvent(*update.Event)
+			} else {
+				subject.Notify(update.CalculatorIndex, update.Value)
+			}
+		}
+	}()
+	result, err := calc.Calculate(ctx, relayChan, calcIndex, n, opts)
+	close(relayChan)
+	<-relayed
+	return result, err
+}
+
 // ExecuteCalculations orchestrates the concurrent execution of one or more
 // Fibonacci calculations.
 //
@@ -42,10 +141,18 @@ const ProgressBufferMultiplier = 5
 // and coordinates the display of progress updates. This function is the core of
 // the application's concurrency model.
 //
+// With cfg.Sequential, the calculators run one after another, so that the
+// resources of each are measured alone (see ResourceUsage).
+//
+// In race mode (cfg.Race), the first successful calculation cancels the
+// others through the context and ExecuteCalculations returns at once: the
+// calculations still running are reported as canceled at the last progress
+// they reported, and finish in the background.
+//
 // Parameters:
 //   - ctx: The context for managing cancellation and deadlines.
 //   - calculators: A slice of calculators to execute.
-//   - cfg: The application configuration (N, thresholds, etc.).
+//   - cfg: The application configuration (N, thresholds, race mode, etc.).
 //   - progressReporter: The progress reporter for displaying updates (use NullProgressReporter for quiet mode).
 //   - out: The io.Writer for displaying progress updates.
 //
@@ -53,6 +160,9 @@ const ProgressBufferMultiplier = 5
 //   - []CalculationResult: A slice containing the results of each calculation.
 func ExecuteCalculations(ctx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, progressReporter ProgressReporter, out io.Writer) []CalculationResult {
 	g, ctx := errgroup.WithContext(ctx)
+	ctx, cancelRace := context.WithCancel(ctx)
+	defer cancelRace()
+
 	results := make([]CalculationResult, len(calculators))
 	progressChan := make(chan fibonacci.ProgressUpdate, len(calculators)*ProgressBufferMultiplier)
 
@@ -60,30 +170,206 @@ func ExecuteCalculations(ctx context.Context, calculators []fibonacci.Calculator
 	displayWg.Add(1)
 	go progressReporter.DisplayProgress(&displayWg, progressChan, len(calculators), out)
 
+	// In race mode, progress is relayed to record how far the losers got
+	calcChan := progressChan
+	if cfg.Race {
+		calcChan = make(chan fibonacci.ProgressUpdate, cap(progressChan))
+	}
+
+	if cfg.Sequential && !cfg.Race {
+		g.SetLimit(1)
+	}
+	start := time.Now()
+	finished := make(chan indexedResult, len(calculators))
 	for i, calc := range calculators {
 		idx, calculator := i, calc
 		g.Go(func() error {
+			var stats fibonacci.ThresholdStats
+			calcCtx := fibonacci.WithThresholdStats(ctx, &stats)
 			startTime := time.Now()
-			res, err := calculator.Calculate(ctx, progressChan, idx, cfg.N, cfg.ToCalculationOptions())
-			results[idx] = CalculationResult{
+			meter := StartResourceMeter()
+			res, err := calculate(calcCtx, calculator, calcChan, idx, cfg.N, cfg.ToCalculationOptions())
+			result := CalculationResult{
 				Name: calculator.Name(), Result: res, Duration: time.Since(startTime), Err: err,
+				Resources: meter.Stop(),
+			}
+			if stats != (fibonacci.ThresholdStats{}) {
+				result.Thresholds = &stats
 			}
+			if cfg.Race && err == nil {
+				cancelRace()
+			}
+			finished <- indexedResult{index: idx, result: result}
 			return nil
 		})
 	}
 
-	g.Wait()
+	if cfg.Race {
+		collectRace(calculators, start, finished, calcChan, progressChan, results)
+		// The losers finish in the background; their updates are discarded
+		go func() {
+			g.Wait()
+			close(calcChan)
+		}()
+		go func() {
+			for range calcChan {
+			}
+		}()
+	} else {
+		for range calculators {
+			r := <-finished
+			results[r.index] = r.result
+		}
+		g.Wait()
+	}
 	close(progressChan)
 	displayWg.Wait()
 
 	return results
 }
 
+// indexedResult is the result of the calculator of the given index.
+type indexedResult struct {
+	index  int
+	result CalculationResult
+}
+
+// errRaceLost is the error of the calculations still running when the race
+// was won.
+var errRaceLost = fmt.Errorf("canceled by the winner of the race: %w", context.Canceled)
+
+// collectRace collects the results of a race into results until the first
+// success, or until every calculation failed, while relaying the progress
+// updates from calcChan to progressChan and recording the last progress of
+// each calculator. The calculations still running once the race is won are
+// reported as canceled at their last progress, without waiting for them.
+func collectRace(calculators []fibonacci.Calculator, start time.Time, finished <-chan indexedResult, calcChan <-chan fibonacci.ProgressUpdate, progressChan chan<- fibonacci.ProgressUpdate, results []CalculationResult) {
+	progress := make([]float64, len(calculators))
+	done := make([]bool, len(calculators))
+	relay := func(update fibonacci.ProgressUpdate) {
+		if update.CalculatorIndex >= 0 && update.CalculatorIndex < len(progress) {
+			progress[update.CalculatorIndex] = update.Value
+		}
+		progressChan <- update
+	}
+
+	for pending := len(calculators); pending > 0; {
+		select {
+		case update := <-calcChan:
+			relay(update)
+		case r := <-finished:
+			results[r.index], done[r.index] = r.result, true
+			pending--
+			if r.result.Err == nil {
+				pending = 0
+			}
+		}
+	}
+	// Relay the updates sent before the last result
+	for {
+		select {
+		case update := <-calcChan:
+			relay(update)
+			continue
+		default:
+		}
+		break
+	}
+
+	for i := range results {
+		switch {
+		case !done[i]:
+			results[i] = CalculationResult{
+				Name: calculators[i].Name(), Duration: time.Since(start), Err: errRaceLost, Progress: progress[i],
+			}
+		case results[i].Err == nil:
+			results[i].Progress = 1
+		default:
+			results[i].Progress = progress[i]
+		}
+	}
+}
+
+// RaceWinner returns the index of the winner of a race: the successful
+// result with the shortest duration (all calculations start together).
+//
+// Parameters:
+//   - results: The results of a race.
+//
+// Returns:
+//   - int: The index of the winner, or -1 if every calculation failed.
+func RaceWinner(results []CalculationResult) int {
+	winner := -1
+	for i, res := range results {
+		if res.Err == nil && (winner < 0 || res.Duration < results[winner].Duration) {
+			winner = i
+		}
+	}
+	return winner
+}
+
+// RaceMargin returns by how much the winner beat another calculation: the
+// difference of durations if the other finished, otherwise an estimate from
+// the progress it reported before being canceled, assuming it would have
+// continued at the same pace.
+//
+// Parameters:
+//   - winner: The winning result.
+//   - other: Another result of the race.
+//
+// Returns:
+//   - time.Duration: The margin.
+//   - bool: False if the margin cannot be estimated (no progress reported).
+func RaceMargin(winner, other CalculationResult) (time.Duration, bool) {
+	if other.Err == nil {
+		return other.Duration - winner.Duration, true
+	}
+	if other.Progress <= 0 {
+		return 0, false
+	}
+	estimated := time.Duration(float64(other.Duration) / other.Progress)
+	return estimated - winner.Duration, true
+}
+
+// AnalyzeRaceResults reports the outcome of a race: the winner, the margin
+// over the other calculations, and the winning result.
+//
+// Parameters:
+//   - results: The results of the race.
+//   - cfg: The application configuration.
+//   - presenter: The result presenter for display formatting.
+//   - out: The io.Writer for the report.
+//
+// Returns:
+//   - int: An exit code indicating success (0) or the type of failure.
+func AnalyzeRaceResults(results []CalculationResult, cfg config.AppConfig, presenter ResultPresenter, out io.Writer) int {
+	winner := RaceWinner(results)
+	if winner < 0 {
+		var firstError error
+		if len(results) > 0 {
+			firstError = results[0].Err
+		}
+		fmt.Fprintf(out, "\nGlobal Status: Failure. No algorithm could complete the calculation.\n")
+		return presenter.HandleError(firstError, 0, out)
+	}
+
+	others := make([]CalculationResult, 0, len(results)-1)
+	for i, res := range results {
+		if i != winner {
+			others = append(others, res)
+		}
+	}
+	presenter.PresentRaceSummary(results[winner], others, out)
+	presenter.PresentResult(results[winner], cfg.N, cfg.Verbose, cfg.Details, cfg.Concise, out)
+	return apperrors.ExitSuccess
+}
+
 // AnalyzeComparisonResults processes the results from multiple algorithms and
 // generates a summary report.
 //
 // It sorts the results by execution time, validates consistency across
-// successful calculations, and displays a comparative table. It handles the
+// successful calculations (by value, or by digest when a value is missing),
+// and displays a comparative table. It handles the
 // logic for determining global success or failure based on the individual
 // outcomes.
 //
@@ -130,7 +416,7 @@ func AnalyzeComparisonResults(results []CalculationResult, cfg config.AppConfig,
 
 	mismatch := false
 	for _, res := range results {
-		if res.Err == nil && res.Result.Cmp(firstValidResult.Result) != 0 {
+		if res.Err == nil && !sameResult(&res, firstValidResult) {
 			mismatch = true
 			break
 		}
@@ -144,3 +430,12 @@ func AnalyzeComparisonResults(results []CalculationResult, cfg config.AppConfig,
 	presenter.PresentResult(*firstValidResult, cfg.N, cfg.Verbose, cfg.Details, cfg.Concise, out)
 	return apperrors.ExitSuccess
 }
+
+// sameResult reports whether two successful results are equal: by value
+// when both values are known, by digest otherwise.
+func sameResult(a, b *CalculationResult) bool {
+	if a.Result != nil && b.Result != nil {
+		return a.Result.Cmp(b.Result) == 0
+	}
+	return a.Digest == b.Digest
+}
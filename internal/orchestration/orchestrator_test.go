@@ -19,6 +19,8 @@ type MockResultPresenter struct{}
 func (MockResultPresenter) PresentComparisonTable(results []CalculationResult, out io.Writer) {}
 func (MockResultPresenter) PresentResult(result CalculationResult, n uint64, verbose, details, concise bool, out io.Writer) {
 }
+func (MockResultPresenter) PresentRaceSummary(winner CalculationResult, others []CalculationResult, out io.Writer) {
+}
 func (MockResultPresenter) FormatDuration(d time.Duration) string { return d.String() }
 func (MockResultPresenter) HandleError(err error, duration time.Duration, out io.Writer) int {
 	return apperrors.ExitErrorGeneric
@@ -109,6 +111,28 @@ func TestExecuteCalculations(t *testing.T) {
 	}
 }
 
+// TestExecuteCalculationsDynamicThresholds verifies that the final dynamic
+// thresholds are attached to the results only when they are enabled.
+func TestExecuteCalculationsDynamicThresholds(t *testing.T) {
+	t.Parallel()
+	calculators := []fibonacci.Calculator{fibonacci.NewCalculator(&fibonacci.OptimizedFastDoubling{})}
+
+	for _, dynamic := range []bool{false, true} {
+		cfg := config.AppConfig{N: 100_000, DynamicThresholds: dynamic}
+		results := ExecuteCalculations(context.Background(), calculators, cfg, NullProgressReporter{}, io.Discard)
+		if results[0].Err != nil {
+			t.Fatalf("dynamic=%v: %v", dynamic, results[0].Err)
+		}
+		stats := results[0].Thresholds
+		if (stats != nil) != dynamic {
+			t.Errorf("dynamic=%v: thresholds = %+v", dynamic, stats)
+		}
+		if stats != nil && (stats.IterationsProcessed == 0 || stats.OriginalFFT == 0 || stats.CurrentFFT == 0) {
+			t.Errorf("incomplete thresholds: %+v", stats)
+		}
+	}
+}
+
 // TestAnalyzeComparisonResults verifies the logic for comparing results from
 // multiple algorithms. It checks for consistent results, handling of failures,
 // and detection of mismatches.
@@ -151,6 +175,22 @@ func TestAnalyzeComparisonResults(t *testing.T) {
 			},
 			expectedStatus: apperrors.ExitSuccess,
 		},
+		{
+			name: "Digests without values",
+			results: []CalculationResult{
+				{Name: "A", Digest: "ab", Duration: time.Millisecond},
+				{Name: "B", Result: big.NewInt(5), Digest: "ab", Duration: time.Millisecond},
+			},
+			expectedStatus: apperrors.ExitSuccess,
+		},
+		{
+			name: "Digest mismatch",
+			results: []CalculationResult{
+				{Name: "A", Digest: "ab", Duration: time.Millisecond},
+				{Name: "B", Digest: "cd", Duration: time.Millisecond},
+			},
+			expectedStatus: apperrors.ExitErrorMismatch,
+		},
 	}
 
 	for _, tt := range tests {
@@ -170,3 +210,17 @@ type DiscardWriter struct{}
 func (d *DiscardWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
+func TestDigest(t *testing.T) {
+	// The magnitude of 0 has no bytes, and the sign is ignored
+	for _, tc := range []struct {
+		x    *big.Int
+		want string
+	}{
+		{big.NewInt(0), "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{big.NewInt(-256), Digest(big.NewInt(256))},
+	} {
+		if got := Digest(tc.x); got != tc.want {
+			t.Errorf("Digest(%s) = %s, want %s", tc.x, got, tc.want)
+		}
+	}
+}
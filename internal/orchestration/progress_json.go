@@ -0,0 +1,118 @@
+package orchestration
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// ProgressRecord is one line of the NDJSON progress stream written by
+// JSONProgressReporter.
+type ProgressRecord struct {
+	// Type is "progress" for progress updates and "done" once all
+	// calculations have finished.
+	Type string `json:"type"`
+	// Timestamp is the time the record was written (RFC 3339, UTC).
+	Timestamp time.Time `json:"timestamp"`
+	// Calculator is the index of the calculator that reported the update.
+	Calculator int `json:"calculator"`
+	// Algorithm is the display name of the calculator.
+	Algorithm string `json:"algorithm,omitempty"`
+	// Progress is the normalized progress of the calculator (0.0 to 1.0).
+	Progress float64 `json:"progress"`
+	// ETAMillis is the estimated time remaining for the calculator in
+	// milliseconds, or -1 when it cannot be estimated yet.
+	ETAMillis int64 `json:"eta_ms"`
+	// Step holds the details of the step that produced the update, if any.
+	Step *fibonacci.ProgressEvent `json:"step,omitempty"`
+}
+
+// JSONProgressReporter is a ProgressReporter that writes progress as
+// newline-delimited JSON (one ProgressRecord per line) instead of drawing a
+// progress bar, so that wrapper scripts and job runners can track progress.
+type JSONProgressReporter struct {
+	// Names are the calculator display names, indexed by calculator index.
+	Names []string
+	// Now returns the current time; time.Now is used when nil.
+	Now func() time.Time
+
+	// err is the first write error, after which no record is written.
+	err error
+}
+
+// Verify that JSONProgressReporter implements ProgressReporter.
+var _ ProgressReporter = (*JSONProgressReporter)(nil)
+
+// DisplayProgress writes one record per progress update and a final "done"
+// record when the channel is closed. A failed write does not stall the
+// calculation: the updates are still drained, and the error is kept for Err.
+//
+// Parameters:
+//   - wg: A WaitGroup to signal when display is complete.
+//   - progressChan: Channel receiving progress updates from calculators.
+//   - numCalculators: The number of concurrent calculators being tracked.
+//   - out: The writer receiving the NDJSON stream.
+func (r *JSONProgressReporter) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressUpdate, numCalculators int, out io.Writer) {
+	defer wg.Done()
+	now := r.Now
+	if now == nil {
+		now = time.Now
+	}
+	enc := json.NewEncoder(out)
+	start := now()
+
+	for update := range progressChan {
+		t := now()
+		rec := ProgressRecord{
+			Type:       "progress",
+			Timestamp:  t.UTC(),
+			Calculator: update.CalculatorIndex,
+			Algorithm:  r.name(update.CalculatorIndex),
+			Progress:   update.Value,
+			ETAMillis:  estimateETA(t.Sub(start), update.Value).Milliseconds(),
+			Step:       update.Event,
+		}
+		if update.Value <= 0 {
+			rec.ETAMillis = -1
+		}
+		r.encode(enc, rec)
+	}
+	r.encode(enc, ProgressRecord{Type: "done", Timestamp: now().UTC(), Calculator: -1, Progress: 1.0})
+}
+
+// encode writes rec unless a previous write failed.
+func (r *JSONProgressReporter) encode(enc *json.Encoder, rec ProgressRecord) {
+	if r.err == nil {
+		r.err = enc.Encode(rec)
+	}
+}
+
+// Err returns the first error writing the records (e.g. a closed pipe or an
+// invalid file descriptor), or nil. It is valid once DisplayProgress has
+// returned.
+//
+// Returns:
+//   - error: The first write error.
+func (r *JSONProgressReporter) Err() error {
+	return r.err
+}
+
+// name returns the display name of calculator idx, if known.
+func (r *JSONProgressReporter) name(idx int) string {
+	if idx >= 0 && idx < len(r.Names) {
+		return r.Names[idx]
+	}
+	return ""
+}
+
+// estimateETA extrapolates the remaining time linearly from the elapsed time
+// and the progress fraction.
+func estimateETA(elapsed time.Duration, progress float64) time.Duration {
+	if progress <= 0 || progress >= 1 {
+		return 0
+	}
+	return time.Duration(float64(elapsed) * (1 - progress) / progress)
+}
@@ -0,0 +1,112 @@
+package orchestration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// TestJSONProgressReporter verifies the NDJSON progress stream.
+func TestJSONProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tick := 0
+	reporter := JSONProgressReporter{
+		Names: []string{"fast", "matrix"},
+		Now: func() time.Time {
+			t := base.Add(time.Duration(tick) * time.Second)
+			tick++
+			return t
+		},
+	}
+
+	ch := make(chan fibonacci.ProgressUpdate, 3)
+	ch <- fibonacci.ProgressUpdate{CalculatorIndex: 0, Value: 0}
+	ch <- fibonacci.ProgressUpdate{CalculatorIndex: 1, Value: 0.5, Event: &fibonacci.ProgressEvent{Step: 3, Method: fibonacci.MethodFFT}}
+	ch <- fibonacci.ProgressUpdate{CalculatorIndex: 0, Value: 1.0}
+	close(ch)
+
+	var buf bytes.Buffer
+	var wg sync.WaitGroup
+	wg.Add(1)
+	reporter.DisplayProgress(&wg, ch, 2, &buf)
+	wg.Wait()
+
+	var records []ProgressRecord
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var rec ProgressRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(records))
+	}
+	if records[0].ETAMillis != -1 || records[0].Algorithm != "fast" {
+		t.Errorf("first record = %+v, want unknown ETA for 'fast'", records[0])
+	}
+	// Half done after 2s: 2s remaining
+	if rec := records[1]; rec.Algorithm != "matrix" || rec.ETAMillis != 2000 || rec.Step == nil || rec.Step.Method != fibonacci.MethodFFT {
+		t.Errorf("second record = %+v", rec)
+	}
+	if !records[1].Timestamp.Equal(base.Add(2 * time.Second)) {
+		t.Errorf("second record timestamp = %v", records[1].Timestamp)
+	}
+	if records[2].ETAMillis != 0 {
+		t.Errorf("completed record ETA = %d, want 0", records[2].ETAMillis)
+	}
+	if records[3].Type != "done" {
+		t.Errorf("last record type = %q, want done", records[3].Type)
+	}
+}
+
+// failingWriter fails every write after the first n bytes.
+type failingWriter struct {
+	n      int
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if len(p) > w.n {
+		return 0, errors.New("broken pipe")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+// TestJSONProgressReporterWriteError verifies that the first write error is
+// kept and the updates are still drained.
+func TestJSONProgressReporterWriteError(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan fibonacci.ProgressUpdate, 3)
+	for _, v := range []float64{0.25, 0.5, 1.0} {
+		ch <- fibonacci.ProgressUpdate{Value: v}
+	}
+	close(ch)
+
+	var reporter JSONProgressReporter
+	w := &failingWriter{n: 0}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	reporter.DisplayProgress(&wg, ch, 1, w)
+	wg.Wait()
+
+	if err := reporter.Err(); err == nil || err.Error() != "broken pipe" {
+		t.Errorf("Err() = %v, want the write error", err)
+	}
+	if w.writes != 1 || len(ch) != 0 {
+		t.Errorf("expected a single write attempt and the channel drained, got %d writes and %d updates left", w.writes, len(ch))
+	}
+}
@@ -0,0 +1,156 @@
+package orchestration
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// racePresenter records the race summary it is asked to present.
+type racePresenter struct {
+	MockResultPresenter
+	winner CalculationResult
+	others []CalculationResult
+}
+
+func (p *racePresenter) PresentRaceSummary(winner CalculationResult, others []CalculationResult, _ io.Writer) {
+	p.winner, p.others = winner, others
+}
+
+// TestExecuteCalculationsRace verifies that the first success cancels the
+// other calculations and that their progress is recorded.
+func TestExecuteCalculationsRace(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	fast := &MockCalculator{
+		NameFunc: func() string { return "fast" },
+		CalculateFunc: func(ctx context.Context, reporter fibonacci.ProgressReporter, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			<-started
+			return big.NewInt(55), nil
+		},
+	}
+	slow := &MockCalculator{
+		NameFunc: func() string { return "slow" },
+		CalculateFunc: func(ctx context.Context, reporter fibonacci.ProgressReporter, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			reporter(0.25)
+			close(started)
+			<-ctx.Done()
+			return nil, fmt.Errorf("slow canceled: %w", ctx.Err())
+		},
+	}
+
+	cfg := config.AppConfig{N: 10, Race: true}
+	results := ExecuteCalculations(context.Background(), []fibonacci.Calculator{fast, slow}, cfg, NullProgressReporter{}, nil)
+
+	if results[0].Err != nil || results[0].Progress != 1 {
+		t.Errorf("winner: unexpected result %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("loser should be canceled, got %v", results[1].Err)
+	}
+	if results[1].Progress != 0.25 {
+		t.Errorf("loser progress = %v, want 0.25", results[1].Progress)
+	}
+	if w := RaceWinner(results); w != 0 {
+		t.Errorf("RaceWinner = %d, want 0", w)
+	}
+
+	p := &racePresenter{}
+	if code := AnalyzeRaceResults(results, cfg, p, &bytes.Buffer{}); code != apperrors.ExitSuccess {
+		t.Errorf("expected success, got %d", code)
+	}
+	if p.winner.Name != "fast" || len(p.others) != 1 || p.others[0].Name != "slow" {
+		t.Errorf("unexpected race summary: %+v / %+v", p.winner, p.others)
+	}
+}
+
+// TestExecuteCalculationsRaceStubbornLoser verifies that a race returns
+// once it is won, without waiting for a loser that ignores the
+// cancellation, and reports the loser as canceled at its last progress.
+func TestExecuteCalculationsRaceStubbornLoser(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	fast := &MockCalculator{
+		NameFunc: func() string { return "fast" },
+		CalculateFunc: func(ctx context.Context, reporter fibonacci.ProgressReporter, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			<-started
+			return big.NewInt(55), nil
+		},
+	}
+	stubborn := &MockCalculator{
+		NameFunc: func() string { return "stubborn" },
+		CalculateFunc: func(ctx context.Context, reporter fibonacci.ProgressReporter, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			reporter(0.5)
+			close(started)
+			<-release
+			reporter(0.75)
+			return big.NewInt(55), nil
+		},
+	}
+
+	cfg := config.AppConfig{N: 10, Race: true}
+	returned := make(chan []CalculationResult)
+	go func() {
+		returned <- ExecuteCalculations(context.Background(), []fibonacci.Calculator{fast, stubborn}, cfg, NullProgressReporter{}, nil)
+	}()
+	var results []CalculationResult
+	select {
+	case results = <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the race waited for the loser")
+	}
+
+	if results[0].Err != nil || results[0].Progress != 1 {
+		t.Errorf("winner: unexpected result %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, context.Canceled) || results[1].Result != nil {
+		t.Errorf("loser should be reported as canceled, got %+v", results[1])
+	}
+	if results[1].Name != "stubborn" || results[1].Progress != 0.5 {
+		t.Errorf("loser: unexpected result %+v", results[1])
+	}
+}
+
+// TestAnalyzeRaceResultsFailure verifies the exit code when nobody wins.
+func TestAnalyzeRaceResultsFailure(t *testing.T) {
+	t.Parallel()
+
+	results := []CalculationResult{{Name: "a", Err: errors.New("boom")}}
+	if RaceWinner(results) != -1 {
+		t.Error("expected no winner")
+	}
+	if code := AnalyzeRaceResults(results, config.AppConfig{}, MockResultPresenter{}, &bytes.Buffer{}); code == apperrors.ExitSuccess {
+		t.Error("expected failure exit code")
+	}
+}
+
+func TestRaceMargin(t *testing.T) {
+	t.Parallel()
+
+	winner := CalculationResult{Duration: 100 * time.Millisecond}
+	finished := CalculationResult{Duration: 250 * time.Millisecond}
+	if m, ok := RaceMargin(winner, finished); !ok || m != 150*time.Millisecond {
+		t.Errorf("finished margin = %v, %v", m, ok)
+	}
+	// Canceled at 50% after 100ms: estimated total 200ms, margin 100ms
+	canceled := CalculationResult{Duration: 100 * time.Millisecond, Progress: 0.5, Err: context.Canceled}
+	if m, ok := RaceMargin(winner, canceled); !ok || m != 100*time.Millisecond {
+		t.Errorf("canceled margin = %v, %v", m, ok)
+	}
+	if _, ok := RaceMargin(winner, CalculationResult{Err: context.Canceled}); ok {
+		t.Error("margin without progress should be unknown")
+	}
+}
@@ -0,0 +1,154 @@
+package orchestration
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// ResourceUsage describes the resources consumed by a calculation, so that
+// algorithms can be compared on more than wall-clock time.
+//
+// The figures are process-wide deltas taken around the calculation, so they
+// describe the calculation alone only if it ran alone. When other
+// calculations overlap it (concurrent calculators, server requests, REPL
+// jobs), they include the resources of the others and are marked as Shared;
+// a comparison run with config.AppConfig.Sequential measures each
+// calculator alone.
+type ResourceUsage struct {
+	// UserCPU is the CPU time spent in user mode.
+	UserCPU time.Duration `json:"user_cpu_ns"`
+	// SystemCPU is the CPU time spent in the kernel.
+	SystemCPU time.Duration `json:"system_cpu_ns"`
+	// PeakRSSDelta is the growth of the peak resident set size, in bytes.
+	PeakRSSDelta int64 `json:"peak_rss_delta_bytes"`
+	// BytesAllocated is the total number of bytes allocated on the heap.
+	BytesAllocated uint64 `json:"bytes_allocated"`
+	// GCCycles is the number of completed garbage collection cycles.
+	GCCycles uint64 `json:"gc_cycles"`
+	// GCPause is the estimated total stop-the-world pause time of the GC.
+	GCPause time.Duration `json:"gc_pause_ns"`
+	// ParallelEfficiency is the CPU time divided by the wall-clock time
+	// times GOMAXPROCS: 1 means every processor was busy for the whole
+	// calculation, 1/GOMAXPROCS a single busy core.
+	ParallelEfficiency float64 `json:"parallel_efficiency"`
+	// Shared is true if other calculations ran during the calculation: the
+	// figures are then process-wide totals, which include their resources.
+	Shared bool `json:"shared,omitempty"`
+}
+
+// CPUTime returns the total (user + system) CPU time.
+func (u ResourceUsage) CPUTime() time.Duration {
+	return u.UserCPU + u.SystemCPU
+}
+
+// Available reports whether the usage was measured: it is not for the
+// calculations run on a server, nor for the losers of a race.
+func (u ResourceUsage) Available() bool {
+	return u != ResourceUsage{}
+}
+
+// Names of the runtime/metrics samples read by the resource meter.
+const (
+	metricAllocBytes = "/gc/heap/allocs:bytes"
+	metricGCCycles   = "/gc/cycles/total:gc-cycles"
+	metricGCPauses   = "/sched/pauses/total/gc:seconds"
+)
+
+// resourceSample is a snapshot of the process counters.
+type resourceSample struct {
+	wall       time.Time
+	userCPU    time.Duration
+	systemCPU  time.Duration
+	maxRSS     int64
+	allocBytes uint64
+	gcCycles   uint64
+	gcPause    time.Duration
+}
+
+// ResourceMeter measures the resources consumed between its creation and a
+// call to Stop.
+type ResourceMeter struct {
+	start resourceSample
+	watch fibonacci.OverlapWatch
+}
+
+// StartResourceMeter takes the initial snapshot of the process counters. It
+// is started before the calculation it measures.
+//
+// Returns:
+//   - ResourceMeter: The running meter.
+func StartResourceMeter() ResourceMeter {
+	return ResourceMeter{watch: fibonacci.WatchOverlap(false), start: takeResourceSample()}
+}
+
+// Stop takes the final snapshot and returns the resources consumed since
+// StartResourceMeter, marked as Shared if other calculations ran meanwhile.
+//
+// Returns:
+//   - ResourceUsage: The consumed resources.
+func (m ResourceMeter) Stop() ResourceUsage {
+	end := takeResourceSample()
+	usage := ResourceUsage{
+		UserCPU:        end.userCPU - m.start.userCPU,
+		SystemCPU:      end.systemCPU - m.start.systemCPU,
+		PeakRSSDelta:   end.maxRSS - m.start.maxRSS,
+		BytesAllocated: end.allocBytes - m.start.allocBytes,
+		GCCycles:       end.gcCycles - m.start.gcCycles,
+		GCPause:        end.gcPause - m.start.gcPause,
+		Shared:         m.watch.Overlapped(),
+	}
+	if wall := end.wall.Sub(m.start.wall); wall > 0 {
+		capacity := float64(wall) * float64(runtime.GOMAXPROCS(0))
+		usage.ParallelEfficiency = float64(usage.CPUTime()) / capacity
+	}
+	return usage
+}
+
+// takeResourceSample reads the rusage and runtime/metrics counters.
+func takeResourceSample() resourceSample {
+	s := resourceSample{wall: time.Now()}
+	s.userCPU, s.systemCPU, s.maxRSS = readRusage()
+
+	samples := []metrics.Sample{
+		{Name: metricAllocBytes},
+		{Name: metricGCCycles},
+		{Name: metricGCPauses},
+	}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		s.allocBytes = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		s.gcCycles = samples[1].Value.Uint64()
+	}
+	if samples[2].Value.Kind() == metrics.KindFloat64Histogram {
+		s.gcPause = histogramTotal(samples[2].Value.Float64Histogram())
+	}
+	return s
+}
+
+// histogramTotal estimates the sum of the durations recorded in a
+// runtime/metrics histogram of seconds, using the midpoint of each bucket
+// (or its finite edge for the open-ended buckets).
+func histogramTotal(h *metrics.Float64Histogram) time.Duration {
+	var total float64
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		mid := (lo + hi) / 2
+		switch {
+		case math.IsInf(lo, -1):
+			mid = hi
+		case math.IsInf(hi, 1):
+			mid = lo
+		}
+		total += float64(count) * mid
+	}
+	return time.Duration(total * float64(time.Second))
+}
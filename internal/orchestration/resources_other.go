@@ -0,0 +1,11 @@
+//go:build !unix
+
+package orchestration
+
+import "time"
+
+// readRusage is not available on this platform: CPU time and peak RSS are
+// reported as zero.
+func readRusage() (user, system time.Duration, maxRSS int64) {
+	return 0, 0, 0
+}
@@ -0,0 +1,131 @@
+package orchestration
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"runtime"
+	"runtime/metrics"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// TestResourceMeter verifies that allocations and CPU time are accounted.
+// The resource tests are not parallel: calculations of other tests would
+// overlap them.
+func TestResourceMeter(t *testing.T) {
+	meter := StartResourceMeter()
+	x := big.NewInt(3)
+	for i := 0; i < 16; i++ {
+		x.Mul(x, x)
+	}
+	runtime.GC()
+	usage := meter.Stop()
+
+	if usage.BytesAllocated == 0 {
+		t.Error("expected allocations to be accounted")
+	}
+	if usage.GCCycles == 0 {
+		t.Error("expected at least one GC cycle")
+	}
+	if usage.CPUTime() != usage.UserCPU+usage.SystemCPU {
+		t.Error("CPUTime should be user + system time")
+	}
+	if usage.ParallelEfficiency < 0 || usage.UserCPU < 0 || usage.SystemCPU < 0 {
+		t.Errorf("negative resource figures: %+v", usage)
+	}
+}
+
+// TestExecuteCalculationsResources verifies that results carry their
+// resource usage.
+func TestExecuteCalculationsResources(t *testing.T) {
+	calc := &MockCalculator{
+		CalculateFunc: func(ctx context.Context, reporter fibonacci.ProgressReporter, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+			// Large objects are accounted immediately by the runtime metrics
+			x := big.NewInt(7)
+			for i := 0; i < 20; i++ {
+				x.Mul(x, x)
+			}
+			return x, nil
+		},
+	}
+	cfg := config.AppConfig{N: 10, Timeout: time.Minute}
+	results := ExecuteCalculations(context.Background(), []fibonacci.Calculator{calc}, cfg, NullProgressReporter{}, io.Discard)
+	if results[0].Resources.BytesAllocated == 0 {
+		t.Errorf("expected allocations in result resources, got %+v", results[0].Resources)
+	}
+}
+
+// TestResourceMeterOverlap verifies that the usage of overlapped
+// calculations is marked as shared.
+func TestResourceMeterOverlap(t *testing.T) {
+	calc := fibonacci.NewCalculator(&fibonacci.OptimizedFastDoubling{})
+	ctx := context.Background()
+
+	meter := StartResourceMeter()
+	if _, err := calc.Calculate(ctx, nil, 0, 10_000, fibonacci.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if usage := meter.Stop(); !usage.Available() || usage.Shared {
+		t.Errorf("expected the usage of a calculation alone, got %+v", usage)
+	}
+
+	meter = StartResourceMeter()
+	for i := 0; i < 2; i++ {
+		if _, err := calc.Calculate(ctx, nil, i, 10_000, fibonacci.Options{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if usage := meter.Stop(); !usage.Available() || !usage.Shared {
+		t.Errorf("expected the shared usage of overlapped calculations, got %+v", usage)
+	}
+}
+
+// TestExecuteCalculationsSequential verifies that a sequential comparison
+// runs one calculator at a time and measures each alone.
+func TestExecuteCalculationsSequential(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	calculators := make([]fibonacci.Calculator, 3)
+	for i := range calculators {
+		core := fibonacci.NewCalculator(&fibonacci.OptimizedFastDoubling{})
+		calculators[i] = &MockCalculator{
+			CalculateFunc: func(ctx context.Context, reporter fibonacci.ProgressReporter, index int, n uint64, opts fibonacci.Options) (*big.Int, error) {
+				if r := running.Add(1); r > maxRunning.Load() {
+					maxRunning.Store(r)
+				}
+				defer running.Add(-1)
+				time.Sleep(10 * time.Millisecond)
+				return core.Calculate(ctx, nil, index, n, opts)
+			},
+		}
+	}
+
+	cfg := config.AppConfig{N: 100_000, Timeout: time.Minute, Sequential: true}
+	results := ExecuteCalculations(context.Background(), calculators, cfg, NullProgressReporter{}, io.Discard)
+	if maxRunning.Load() != 1 {
+		t.Errorf("%d calculators ran at once, want 1", maxRunning.Load())
+	}
+	for i, res := range results {
+		if res.Err != nil || !res.Resources.Available() || res.Resources.Shared {
+			t.Errorf("result %d: expected a usage measured alone, got %+v", i, res)
+		}
+	}
+}
+
+// TestHistogramTotal verifies the histogram sum estimate.
+func TestHistogramTotal(t *testing.T) {
+	t.Parallel()
+
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 1},
+		Buckets: []float64{0.001, 0.003, 0.005, 0.007},
+	}
+	// 1*2ms + 2*4ms + 1*6ms = 16ms
+	if got := histogramTotal(h); got != 16*time.Millisecond {
+		t.Errorf("histogramTotal = %v, want 16ms", got)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package orchestration
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// readRusage returns the user and system CPU time of the process and its
+// peak resident set size in bytes.
+func readRusage() (user, system time.Duration, maxRSS int64) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, 0, 0
+	}
+	maxRSS = int64(ru.Maxrss)
+	// Maxrss is reported in bytes on Darwin and in kilobytes elsewhere
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		maxRSS *= 1024
+	}
+	return time.Duration(ru.Utime.Nano()), time.Duration(ru.Stime.Nano()), maxRSS
+}
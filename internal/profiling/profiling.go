@@ -0,0 +1,124 @@
+// Package profiling captures CPU, heap and block profiles and execution traces
+// for a run of the application, so that slow runs can be diagnosed with
+// `go tool pprof` and `go tool trace` without patching the code.
+package profiling
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// Options selects the profiles to capture. Empty paths disable the
+// corresponding profile.
+type Options struct {
+	// CPUProfile is the output path of the CPU profile.
+	CPUProfile string
+	// MemProfile is the output path of the heap profile, written at stop.
+	MemProfile string
+	// BlockProfile is the output path of the blocking profile, written at stop.
+	BlockProfile string
+	// ExecTrace is the output path of the runtime execution trace.
+	ExecTrace string
+}
+
+// Enabled reports whether any profile is requested.
+func (o Options) Enabled() bool {
+	return o.CPUProfile != "" || o.MemProfile != "" || o.BlockProfile != "" || o.ExecTrace != ""
+}
+
+// Session is a running profiling capture.
+type Session struct {
+	opts      Options
+	cpuFile   *os.File
+	traceFile *os.File
+}
+
+// Start begins the captures selected by opts. CPU profiling and the execution
+// trace start immediately; the heap and block profiles are written by Stop.
+//
+// Parameters:
+//   - opts: The profiles to capture.
+//
+// Returns:
+//   - *Session: The running session; Stop must be called to write the files.
+//   - error: An error if a capture could not be started.
+func Start(opts Options) (*Session, error) {
+	s := &Session{opts: opts}
+
+	if opts.CPUProfile != "" {
+		f, err := os.Create(opts.CPUProfile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("cannot start CPU profile: %w", err)
+		}
+		s.cpuFile = f
+	}
+
+	if opts.ExecTrace != "" {
+		f, err := os.Create(opts.ExecTrace)
+		if err != nil {
+			s.Stop()
+			return nil, fmt.Errorf("cannot create execution trace: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			s.Stop()
+			return nil, fmt.Errorf("cannot start execution trace: %w", err)
+		}
+		s.traceFile = f
+	}
+
+	if opts.BlockProfile != "" {
+		runtime.SetBlockProfileRate(1)
+	}
+	return s, nil
+}
+
+// Stop ends the captures and writes the heap and block profiles.
+//
+// Returns:
+//   - error: The errors encountered while writing the profiles, if any.
+func (s *Session) Stop() error {
+	var errs []error
+
+	if s.cpuFile != nil {
+		pprof.StopCPUProfile()
+		errs = append(errs, s.cpuFile.Close())
+		s.cpuFile = nil
+	}
+	if s.traceFile != nil {
+		trace.Stop()
+		errs = append(errs, s.traceFile.Close())
+		s.traceFile = nil
+	}
+	if s.opts.MemProfile != "" {
+		// Up-to-date allocation statistics
+		runtime.GC()
+		errs = append(errs, writeProfile("heap", s.opts.MemProfile))
+	}
+	if s.opts.BlockProfile != "" {
+		errs = append(errs, writeProfile("block", s.opts.BlockProfile))
+		runtime.SetBlockProfileRate(0)
+	}
+	return errors.Join(errs...)
+}
+
+// writeProfile writes the named runtime profile to path.
+func writeProfile(name, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create %s profile: %w", name, err)
+	}
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot write %s profile: %w", name, err)
+	}
+	return f.Close()
+}
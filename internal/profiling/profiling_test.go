@@ -0,0 +1,55 @@
+package profiling
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSession verifies that every requested profile is written.
+// It is not parallel because CPU profiling and tracing are process-wide.
+func TestSession(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{
+		CPUProfile:   filepath.Join(dir, "cpu.pprof"),
+		MemProfile:   filepath.Join(dir, "mem.pprof"),
+		BlockProfile: filepath.Join(dir, "block.pprof"),
+		ExecTrace:    filepath.Join(dir, "exec.trace"),
+	}
+	if !opts.Enabled() {
+		t.Fatal("expected options to be enabled")
+	}
+
+	s, err := Start(opts)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	x := big.NewInt(3)
+	for i := 0; i < 12; i++ {
+		x.Mul(x, x)
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	for _, path := range []string{opts.CPUProfile, opts.MemProfile, opts.BlockProfile, opts.ExecTrace} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("profile %s not written: %v", filepath.Base(path), err)
+		} else if info.Size() == 0 {
+			t.Errorf("profile %s is empty", filepath.Base(path))
+		}
+	}
+}
+
+// TestStartError verifies that an unwritable path is reported.
+func TestStartError(t *testing.T) {
+	if (Options{}).Enabled() {
+		t.Error("empty options should not be enabled")
+	}
+	_, err := Start(Options{CPUProfile: filepath.Join(t.TempDir(), "missing", "cpu.pprof")})
+	if err == nil {
+		t.Error("expected error for unwritable CPU profile path")
+	}
+}
@@ -0,0 +1,170 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+)
+
+// Chart geometry, in SVG user units.
+const (
+	chartWidth  = 720
+	chartHeight = 320
+	chartLeft   = 70
+	chartRight  = 20
+	chartTop    = 20
+	chartBottom = 45
+)
+
+// palette is the series colors, cycled if there are more series.
+var palette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f"}
+
+// seriesColor returns the color of the i-th series.
+func seriesColor(i int) string {
+	return palette[i%len(palette)]
+}
+
+// tick is an axis graduation at a position of the plot area.
+type tick struct {
+	Pos   float64
+	Label string
+}
+
+// series is a polyline of a chart.
+type series struct {
+	Name    string
+	Color   string
+	Points  string
+	LegendY float64
+}
+
+// chart is the view model of an inline SVG line chart.
+type chart struct {
+	Width, Height         int
+	Left, Top             float64
+	PlotWidth, PlotHeight float64
+	Bottom, Right         float64
+	TickEnd               float64
+	XLabel, YLabel        string
+	XTicks, YTicks        []tick
+	Series                []series
+}
+
+// point is a data point of a series.
+type point struct{ x, y float64 }
+
+// newChart returns an empty chart with the plot area laid out.
+func newChart(xLabel, yLabel string) *chart {
+	c := &chart{
+		Width: chartWidth, Height: chartHeight,
+		Left: chartLeft, Top: chartTop,
+		PlotWidth:  chartWidth - chartLeft - chartRight,
+		PlotHeight: chartHeight - chartTop - chartBottom,
+		XLabel:     xLabel, YLabel: yLabel,
+	}
+	c.Bottom = c.Top + c.PlotHeight
+	c.Right = c.Left + c.PlotWidth
+	c.TickEnd = c.Bottom + 5
+	return c
+}
+
+// addSeries scales the points from [xMin, xMax] x [yMin, yMax] to the plot
+// area and adds them as a series.
+func (c *chart) addSeries(name, color string, points []point, xMin, xMax, yMin, yMax float64) {
+	var b strings.Builder
+	for i, p := range points {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%.1f,%.1f", c.scaleX(p.x, xMin, xMax), c.scaleY(p.y, yMin, yMax))
+	}
+	legendY := c.Top + 12 + 14*float64(len(c.Series))
+	c.Series = append(c.Series, series{Name: name, Color: color, Points: b.String(), LegendY: legendY})
+}
+
+// scaleX maps x from [min, max] to the plot area.
+func (c *chart) scaleX(x, min, max float64) float64 {
+	return c.Left + (x-min)/(max-min)*c.PlotWidth
+}
+
+// scaleY maps y from [min, max] to the plot area, upwards.
+func (c *chart) scaleY(y, min, max float64) float64 {
+	return c.Bottom - (y-min)/(max-min)*c.PlotHeight
+}
+
+// progressChart plots the progress of each calculator against the elapsed
+// time. It returns nil if no progress was recorded.
+func progressChart(samples []Sample, names []string) *chart {
+	perCalc := make([][]point, len(names))
+	var xMax float64
+	for _, s := range samples {
+		if s.Calculator < 0 || s.Calculator >= len(names) {
+			continue
+		}
+		x := s.Elapsed.Seconds()
+		perCalc[s.Calculator] = append(perCalc[s.Calculator], point{x, s.Progress * 100})
+		xMax = math.Max(xMax, x)
+	}
+	if xMax == 0 {
+		return nil
+	}
+
+	c := newChart("Elapsed time", "Progress")
+	for i := 0; i <= 4; i++ {
+		x := xMax * float64(i) / 4
+		c.XTicks = append(c.XTicks, tick{c.scaleX(x, 0, xMax), cli.FormatExecutionDuration(time.Duration(x * float64(time.Second)))})
+		c.YTicks = append(c.YTicks, tick{c.scaleY(float64(i*25), 0, 100), fmt.Sprintf("%d%%", i*25)})
+	}
+	for i, points := range perCalc {
+		if len(points) == 0 {
+			continue
+		}
+		c.addSeries(names[i], seriesColor(i), append([]point{{0, 0}}, points...), 0, xMax, 0, 100)
+	}
+	return c
+}
+
+// stepChart plots the duration of each step of each calculator, on a
+// logarithmic scale since step durations grow geometrically. It returns nil
+// if no step event was recorded.
+func stepChart(steps [][]stepRow, names []string) *chart {
+	xMax := 1.0
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	for _, rows := range steps {
+		for _, r := range rows {
+			y := math.Log10(float64(max(r.duration, 1)))
+			yMin, yMax = math.Min(yMin, y), math.Max(yMax, y)
+			xMax = math.Max(xMax, float64(r.Step))
+		}
+	}
+	if math.IsInf(yMin, 1) {
+		return nil
+	}
+	yMin, yMax = math.Floor(yMin), math.Ceil(yMax)
+	if yMax == yMin {
+		yMax++
+	}
+
+	c := newChart("Step", "Step duration")
+	for i := 0; i <= 4; i++ {
+		x := 1 + (xMax-1)*float64(i)/4
+		c.XTicks = append(c.XTicks, tick{c.scaleX(x, 1, max(xMax, 2)), fmt.Sprintf("%.0f", x)})
+	}
+	for e := yMin; e <= yMax; e++ {
+		c.YTicks = append(c.YTicks, tick{c.scaleY(e, yMin, yMax), cli.FormatExecutionDuration(time.Duration(math.Pow(10, e)))})
+	}
+	for i, rows := range steps {
+		if len(rows) == 0 {
+			continue
+		}
+		points := make([]point, len(rows))
+		for j, r := range rows {
+			points[j] = point{float64(r.Step), math.Log10(float64(max(r.duration, 1)))}
+		}
+		c.addSeries(names[i], seriesColor(i), points, 1, max(xMax, 2), yMin, yMax)
+	}
+	return c
+}
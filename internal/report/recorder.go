@@ -0,0 +1,74 @@
+package report
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// Sample is a progress update recorded with the time elapsed since the
+// calculations started.
+type Sample struct {
+	// Calculator is the index of the calculator that sent the update.
+	Calculator int
+	// Elapsed is the time elapsed since the calculations started.
+	Elapsed time.Duration
+	// Progress is the normalized progress (0.0 to 1.0).
+	Progress float64
+	// Event is the step event of the update, or nil.
+	Event *fibonacci.ProgressEvent
+}
+
+// ProgressRecorder is an orchestration.ProgressReporter that records the
+// progress updates for the report and forwards them to another reporter, so
+// that the terminal display is unchanged.
+type ProgressRecorder struct {
+	next orchestration.ProgressReporter
+
+	mu      sync.Mutex
+	samples []Sample
+}
+
+// NewProgressRecorder returns a recorder forwarding the updates to next.
+//
+// Parameters:
+//   - next: The reporter that displays the progress.
+//
+// Returns:
+//   - *ProgressRecorder: The recorder.
+func NewProgressRecorder(next orchestration.ProgressReporter) *ProgressRecorder {
+	return &ProgressRecorder{next: next}
+}
+
+// DisplayProgress implements orchestration.ProgressReporter. It records each
+// update through an orchestration.TeeProgressReporter, which forwards it and
+// returns once the forwarded reporter is done.
+func (r *ProgressRecorder) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressUpdate, numCalculators int, out io.Writer) {
+	start := time.Now()
+	orchestration.TeeProgressReporter{Next: r.next, Record: func(update fibonacci.ProgressUpdate) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.samples = append(r.samples, Sample{
+			Calculator: update.CalculatorIndex,
+			Elapsed:    time.Since(start),
+			Progress:   update.Value,
+			Event:      update.Event,
+		})
+	}}.DisplayProgress(wg, progressChan, numCalculators, out)
+}
+
+// Samples returns the recorded updates, in order of arrival.
+//
+// Returns:
+//   - []Sample: A copy of the recorded updates.
+func (r *ProgressRecorder) Samples() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Sample(nil), r.samples...)
+}
+
+// Verify interface compliance
+var _ orchestration.ProgressReporter = (*ProgressRecorder)(nil)
@@ -0,0 +1,342 @@
+// Package report renders self-contained HTML reports of calculation runs.
+//
+// The report is produced by HTMLPresenter, an orchestration.ResultPresenter
+// fed by the same analysis as the terminal output, and by ProgressRecorder,
+// which records the progress updates of the run for the progress and
+// per-step timing charts. Charts are inline SVG and styles are inline, so
+// the file can be attached to a document or a ticket as is.
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	apperrors "github.com/agbru/fibcalc/internal/errors"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+//go:embed report.html.tmpl
+var reportTemplate string
+
+// tmpl is the parsed report template.
+var tmpl = template.Must(template.New("report").Parse(reportTemplate))
+
+// Metadata describes the run a report is about.
+type Metadata struct {
+	// N is the index of the calculated Fibonacci number.
+	N uint64
+	// Strategy is the algorithm run ("all" or a calculator name).
+	Strategy string
+	// Race reports whether the run was a race (--race).
+	Race bool
+	// Thresholds are the thresholds the calculators used.
+	Thresholds Thresholds
+	// LowMemory and IntegrityCheck are the memory and integrity modes.
+	LowMemory      bool
+	IntegrityCheck bool
+	// Machine describes the machine the run happened on.
+	Machine Machine
+	// Profile is the calibration profile, or nil if there is none.
+	Profile *Profile
+	// Generated is the time of the report.
+	Generated time.Time
+}
+
+// Thresholds are the effective thresholds of a run, in bits.
+type Thresholds struct {
+	Parallel, FFT, Karatsuba, Strassen int
+	// Dynamic reports whether the thresholds could change during the run.
+	Dynamic bool
+}
+
+// Machine describes the machine and binary of a run.
+type Machine struct {
+	Version, GoVersion string
+	GOOS, GOARCH       string
+	NumCPU, GOMAXPROCS int
+	CPUFeatures        []string
+	SIMD               string
+}
+
+// Profile describes the calibration profile of the machine.
+type Profile struct {
+	// Path is the profile file and Source how it was used (see the
+	// manifest of the JSON output).
+	Path, Source string
+	// CPUModel and NumCPU identify the calibrated machine.
+	CPUModel string
+	NumCPU   int
+	// CalibratedAt and CalibrationN describe the calibration run.
+	CalibratedAt time.Time
+	CalibrationN uint64
+	// Parallel, FFT and Strassen are the calibrated thresholds.
+	Parallel, FFT, Strassen int
+}
+
+// HTMLPresenter implements orchestration.ResultPresenter by collecting the
+// results of a run; Render then writes them as an HTML report.
+type HTMLPresenter struct {
+	meta     Metadata
+	names    []string
+	recorder *ProgressRecorder
+
+	results []orchestration.CalculationResult
+	best    *orchestration.CalculationResult
+	winner  *orchestration.CalculationResult
+	err     error
+}
+
+// NewHTMLPresenter returns a presenter for a run.
+//
+// Parameters:
+//   - meta: The description of the run.
+//   - names: The names of the calculators, by calculator index.
+//   - recorder: The progress recorder of the run, or nil.
+//
+// Returns:
+//   - *HTMLPresenter: The presenter.
+func NewHTMLPresenter(meta Metadata, names []string, recorder *ProgressRecorder) *HTMLPresenter {
+	return &HTMLPresenter{meta: meta, names: names, recorder: recorder}
+}
+
+// PresentComparisonTable implements orchestration.ResultPresenter by
+// recording the results.
+func (p *HTMLPresenter) PresentComparisonTable(results []orchestration.CalculationResult, _ io.Writer) {
+	p.results = append([]orchestration.CalculationResult(nil), results...)
+}
+
+// PresentResult implements orchestration.ResultPresenter by recording the
+// result summarized by the report.
+func (p *HTMLPresenter) PresentResult(result orchestration.CalculationResult, _ uint64, _, _, _ bool, _ io.Writer) {
+	p.best = &result
+}
+
+// PresentRaceSummary implements orchestration.ResultPresenter by recording
+// the winner and the other results of the race.
+func (p *HTMLPresenter) PresentRaceSummary(winner orchestration.CalculationResult, others []orchestration.CalculationResult, _ io.Writer) {
+	p.winner = &winner
+	p.results = append([]orchestration.CalculationResult{winner}, others...)
+}
+
+// FormatDuration implements orchestration.ResultPresenter.
+func (p *HTMLPresenter) FormatDuration(d time.Duration) string {
+	return cli.FormatExecutionDuration(d)
+}
+
+// HandleError implements orchestration.ResultPresenter by recording the
+// error, which the report shows as the status of the run.
+func (p *HTMLPresenter) HandleError(err error, duration time.Duration, out io.Writer) int {
+	p.err = err
+	return apperrors.HandleCalculationError(err, duration, out, nil)
+}
+
+// stepRow is a row of the per-step timing table.
+type stepRow struct {
+	Step, Bit, OperandBits int
+	Method                 string
+	Parallel               bool
+	Duration, Allocated    string
+	duration               time.Duration
+}
+
+// resultRow is a row of the comparison table.
+type resultRow struct {
+	Name, Color                string
+	Duration, Relative, Margin string
+	CPU, Efficiency, PeakRSS   string
+	Allocated, GCCycles        string
+	OK                         bool
+	Status                     string
+}
+
+// summary describes the result of the run.
+type summary struct {
+	Algorithm, Duration string
+	Bits, Digits        int
+	SHA256              string
+}
+
+// stepTable is the per-step timing table of a calculator.
+type stepTable struct {
+	Name, Color string
+	Rows        []stepRow
+}
+
+// view is the data of the report template.
+type view struct {
+	Meta          Metadata
+	Status        string
+	StatusClass   string
+	Summary       *summary
+	Rows          []resultRow
+	ProgressChart *chart
+	StepChart     *chart
+	StepTables    []stepTable
+}
+
+// Render writes the report.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if writing fails.
+func (p *HTMLPresenter) Render(w io.Writer) error {
+	v := view{Meta: p.meta}
+	v.Status, v.StatusClass = p.status()
+	if p.best != nil && p.best.Result != nil {
+		bits := p.best.Result.BitLen()
+		v.Summary = &summary{
+			Algorithm: p.best.Name,
+			Duration:  cli.FormatExecutionDuration(p.best.Duration),
+			Bits:      bits,
+			Digits:    int(float64(bits)*math.Log10(2)) + 1,
+			SHA256:    orchestration.Digest(p.best.Result),
+		}
+	}
+	v.Rows = p.rows()
+
+	var samples []Sample
+	if p.recorder != nil {
+		samples = p.recorder.Samples()
+	}
+	v.ProgressChart = progressChart(samples, p.names)
+	steps := make([][]stepRow, len(p.names))
+	for _, s := range samples {
+		if e := s.Event; e != nil && s.Calculator >= 0 && s.Calculator < len(p.names) {
+			steps[s.Calculator] = append(steps[s.Calculator], stepRow{
+				Step: e.Step, Bit: e.BitIndex, OperandBits: e.OperandBits,
+				Method: e.Method, Parallel: e.Parallel,
+				Duration:  cli.FormatExecutionDuration(e.StepDuration),
+				Allocated: cli.FormatStepAllocated(*e),
+				duration:  e.StepDuration,
+			})
+		}
+	}
+	v.StepChart = stepChart(steps, p.names)
+	for i, rows := range steps {
+		if len(rows) > 0 {
+			v.StepTables = append(v.StepTables, stepTable{Name: p.names[i], Color: seriesColor(i), Rows: rows})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, v); err != nil {
+		return fmt.Errorf("cannot render the report: %w", err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// WriteFile writes the report to path, creating the parent directory if
+// needed.
+//
+// Parameters:
+//   - path: The destination file.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func (p *HTMLPresenter) WriteFile(path string) error {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	if err := p.Render(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// status returns the status of the run and its CSS class.
+func (p *HTMLPresenter) status() (string, string) {
+	if p.err != nil || p.best == nil {
+		if p.err != nil {
+			return fmt.Sprintf("Failure: %v", p.err), "failure"
+		}
+		return "Failure: no algorithm completed the calculation", "failure"
+	}
+	for _, res := range p.results {
+		if res.Err == nil && res.Result.Cmp(p.best.Result) != 0 {
+			return "Critical error: the algorithms returned different results", "failure"
+		}
+	}
+	if p.winner != nil {
+		return fmt.Sprintf("Race won by %s", p.winner.Name), "success"
+	}
+	return "Success: all valid results are consistent", "success"
+}
+
+// rows returns the comparison table, with the duration of each result
+// relative to the fastest one.
+func (p *HTMLPresenter) rows() []resultRow {
+	var fastest time.Duration
+	for _, res := range p.results {
+		if res.Err == nil && (fastest == 0 || res.Duration < fastest) {
+			fastest = res.Duration
+		}
+	}
+	rows := make([]resultRow, 0, len(p.results))
+	for _, res := range p.results {
+		r := resultRow{
+			Name:       res.Name,
+			Color:      p.color(res.Name),
+			Duration:   cli.FormatExecutionDuration(res.Duration),
+			CPU:        "n/a",
+			Efficiency: cli.FormatEfficiency(res.Resources),
+			PeakRSS:    "n/a",
+			Allocated:  "n/a",
+			GCCycles:   "n/a",
+			OK:         res.Err == nil,
+			Status:     "Success",
+		}
+		if u := res.Resources; u.Available() {
+			r.CPU = cli.FormatExecutionDuration(u.CPUTime())
+			if u.Shared {
+				r.CPU += " (shared)"
+			}
+			r.PeakRSS = cli.FormatBytes(uint64(max(u.PeakRSSDelta, 0)))
+			r.Allocated = cli.FormatBytes(u.BytesAllocated)
+			r.GCCycles = strconv.FormatUint(u.GCCycles, 10)
+		}
+		if res.Err != nil {
+			r.Status = fmt.Sprintf("Failure (%v)", res.Err)
+		} else if fastest > 0 {
+			r.Relative = fmt.Sprintf("×%.2f", float64(res.Duration)/float64(fastest))
+		}
+		if p.winner != nil && res.Name != p.winner.Name {
+			if margin, ok := orchestration.RaceMargin(*p.winner, res); ok {
+				r.Margin = "+" + cli.FormatExecutionDuration(margin)
+			}
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+// color returns the chart color of the named calculator.
+func (p *HTMLPresenter) color(name string) string {
+	for i, n := range p.names {
+		if n == name {
+			return seriesColor(i)
+		}
+	}
+	return "#333333"
+}
+
+// Verify interface compliance
+var _ orchestration.ResultPresenter = (*HTMLPresenter)(nil)
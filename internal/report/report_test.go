@@ -0,0 +1,95 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// record feeds updates to a recorder as ExecuteCalculations would.
+func record(t *testing.T, updates []fibonacci.ProgressUpdate) *ProgressRecorder {
+	t.Helper()
+	rec := NewProgressRecorder(orchestration.NullProgressReporter{})
+	ch := make(chan fibonacci.ProgressUpdate, len(updates))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go rec.DisplayProgress(&wg, ch, 2, &bytes.Buffer{})
+	for _, u := range updates {
+		time.Sleep(time.Millisecond)
+		ch <- u
+	}
+	close(ch)
+	wg.Wait()
+	if got := len(rec.Samples()); got != len(updates) {
+		t.Fatalf("Recorded %d samples, want %d", got, len(updates))
+	}
+	return rec
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	rec := record(t, []fibonacci.ProgressUpdate{
+		{CalculatorIndex: 0, Value: 0.5, Event: &fibonacci.ProgressEvent{Step: 1, TotalSteps: 2, Method: "karatsuba", StepDuration: time.Microsecond}},
+		{CalculatorIndex: 1, Value: 0.5},
+		{CalculatorIndex: 0, Value: 1, Event: &fibonacci.ProgressEvent{Step: 2, TotalSteps: 2, Method: "fft", Parallel: true, StepDuration: time.Millisecond}},
+		{CalculatorIndex: 1, Value: 1},
+	})
+	meta := Metadata{
+		N:          10,
+		Strategy:   "all",
+		Thresholds: Thresholds{Parallel: 4096, FFT: 500000},
+		Machine:    Machine{Version: "dev", CPUFeatures: []string{"AVX2"}, SIMD: "AVX2"},
+		Profile:    &Profile{Path: "/tmp/profile.json", CPUModel: "Test <CPU>"},
+		Generated:  time.Now(),
+	}
+	p := NewHTMLPresenter(meta, []string{"Fast Doubling", "Matrix"}, rec)
+	results := []orchestration.CalculationResult{
+		{Name: "Fast Doubling", Result: big.NewInt(55), Duration: time.Millisecond},
+		{Name: "Matrix", Result: big.NewInt(55), Duration: 2 * time.Millisecond},
+	}
+	orchestration.AnalyzeComparisonResults(results, config.AppConfig{N: 10}, p, &bytes.Buffer{})
+
+	var buf bytes.Buffer
+	if err := p.Render(&buf); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"<svg", "<polyline", "Fast Doubling", "Matrix", "fft", "4096", "Test &lt;CPU&gt;", "AVX2", "success", "×2.00"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Report does not contain %q", want)
+		}
+	}
+	for _, external := range []string{"src=", "href=", "@import", "url("} {
+		if strings.Contains(html, external) {
+			t.Errorf("Report references an external asset (%q)", external)
+		}
+	}
+}
+
+func TestRender_Failure(t *testing.T) {
+	t.Parallel()
+
+	p := NewHTMLPresenter(Metadata{N: 10}, []string{"Fast Doubling"}, nil)
+	results := []orchestration.CalculationResult{{Name: "Fast Doubling", Err: errors.New("boom")}}
+	orchestration.AnalyzeComparisonResults(results, config.AppConfig{N: 10}, p, &bytes.Buffer{})
+
+	var buf bytes.Buffer
+	if err := p.Render(&buf); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"status failure", "No result.", "No progress was recorded.", "No calibration profile."} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Report does not contain %q", want)
+		}
+	}
+}
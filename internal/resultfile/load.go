@@ -0,0 +1,114 @@
+package resultfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Result file formats, as reported by Loaded.Format and accepted by
+// --output-format.
+const (
+	FormatDecimal = "dec"
+	FormatHex     = "hex"
+	FormatBinary  = "bin"
+)
+
+// Loaded is a result read back by Load.
+type Loaded struct {
+	// Value is the stored Fibonacci number.
+	Value *big.Int
+	// N is the index of the Fibonacci number, or 0 if the file does not say.
+	N uint64
+	// Algorithm is the producing calculator, if recorded.
+	Algorithm string
+	// Format is FormatBinary, FormatDecimal or FormatHex.
+	Format string
+	// Info describes a binary file; nil for text files.
+	Info *Info
+}
+
+// Load reads a result file written in any of the supported formats: the
+// binary format, whose checksum is verified, or the decimal and hexadecimal
+// text files with their "# Key: value" header. A bare decimal or
+// 0x-prefixed hexadecimal number is also accepted.
+//
+// Parameters:
+//   - path: The file to read.
+//
+// Returns:
+//   - *Loaded: The result and its metadata.
+//   - error: An error if the file cannot be read or parsed.
+func Load(path string) (*Loaded, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	size := int64(-1)
+	if fi, err := f.Stat(); err == nil && fi.Mode().IsRegular() {
+		size = fi.Size()
+	}
+	br := bufio.NewReaderSize(f, 1<<16)
+	if prefix, _ := br.Peek(len(Magic)); string(prefix) == Magic {
+		x, info, err := read(br, size)
+		if err != nil {
+			return nil, err
+		}
+		return &Loaded{Value: x, N: info.Header.N, Algorithm: info.Header.Algorithm, Format: FormatBinary, Info: info}, nil
+	}
+	return loadText(br)
+}
+
+// loadText parses a text result file.
+func loadText(br *bufio.Reader) (*Loaded, error) {
+	loaded := &Loaded{Format: FormatDecimal}
+	for {
+		line, err := br.ReadString('\n')
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#"):
+			key, value, ok := strings.Cut(strings.TrimSpace(line[1:]), ":")
+			if !ok {
+				break
+			}
+			switch strings.TrimSpace(key) {
+			case "N":
+				n, perr := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+				if perr != nil {
+					return nil, fmt.Errorf("invalid N in result file: %w", perr)
+				}
+				loaded.N = n
+			case "Algorithm":
+				loaded.Algorithm = strings.TrimSpace(value)
+			}
+		case strings.HasSuffix(line, "="):
+			// "F(n) =" or "F(n) [hex] =" introduces the value
+		default:
+			return loaded, loaded.parseValue(line)
+		}
+		if err != nil {
+			return nil, errors.New("result file contains no value")
+		}
+	}
+}
+
+// parseValue sets the value from its decimal or 0x-prefixed hexadecimal text.
+func (l *Loaded) parseValue(text string) error {
+	digits, base := text, 10
+	if rest, ok := strings.CutPrefix(text, "0x"); ok {
+		digits, base, l.Format = rest, 16, FormatHex
+	}
+	x, ok := new(big.Int).SetString(digits, base)
+	if !ok || x.Sign() < 0 {
+		return fmt.Errorf("invalid %s value in result file", l.Format)
+	}
+	l.Value = x
+	return nil
+}
@@ -0,0 +1,438 @@
+// Package resultfile implements the versioned binary container for
+// calculation results, and a loader that also reads the decimal and
+// hexadecimal text files written by earlier versions.
+//
+// A binary result file is laid out as follows (integers are little-endian):
+//
+//	magic    [8]byte  "FIBCALC\x1a"
+//	version  uint16   FormatVersion
+//	flags    uint16   FlagGzip or FlagZstd if the payload is compressed
+//	hdrLen   uint32   length of the header
+//	header   [hdrLen]byte JSON-encoded Header
+//	payload  Header.Limbs 64-bit limbs of F(n), least significant first,
+//	         compressed as the flags say
+//	trailer  [32]byte SHA-256 of everything before it
+//
+// Storing the limbs avoids the decimal conversion, which dominates the cost
+// of writing a large result, and takes about 2.4 times less space than the
+// decimal text.
+package resultfile
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic identifies a binary result file.
+const Magic = "FIBCALC\x1a"
+
+// FormatVersion is the version of the binary format written by Write.
+const FormatVersion = 1
+
+// Flags of the binary format.
+const (
+	// FlagGzip indicates a gzip-compressed payload.
+	FlagGzip uint16 = 1 << iota
+	// FlagZstd indicates a zstd-compressed payload.
+	FlagZstd
+)
+
+// Payload compressions, as reported by Info.Compression and accepted by
+// --output-compress.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// trailerSize is the size of the SHA-256 trailer.
+const trailerSize = sha256.Size
+
+// maxHeaderSize bounds the header length accepted by Read.
+const maxHeaderSize = 1 << 20
+
+// prefixSize is the size of the magic, version, flags and header length.
+const prefixSize = len(Magic) + 8
+
+// preallocLimbs bounds the limbs allocated before the payload is read; a
+// larger result grows as its limbs arrive, so that a forged header cannot
+// make Read allocate memory the file does not fill.
+const preallocLimbs = 1 << 17
+
+// ErrChecksum is returned when the SHA-256 trailer does not match the file.
+var ErrChecksum = errors.New("result file checksum mismatch")
+
+// Thresholds are the multiplication thresholds of the calculation, in bits.
+type Thresholds struct {
+	Parallel int `json:"parallel"`
+	FFT      int `json:"fft"`
+	Strassen int `json:"strassen"`
+}
+
+// BuildInfo identifies the binary that produced a result.
+type BuildInfo struct {
+	GoVersion string `json:"go_version"`
+	Module    string `json:"module,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Revision  string `json:"revision,omitempty"`
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+}
+
+// CurrentBuild returns the build information of the running binary.
+func CurrentBuild() BuildInfo {
+	b := BuildInfo{GoVersion: runtime.Version(), GOOS: runtime.GOOS, GOARCH: runtime.GOARCH}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		b.Module, b.Version = info.Main.Path, info.Main.Version
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				b.Revision = s.Value
+			}
+		}
+	}
+	return b
+}
+
+// Header describes the result stored in a binary result file.
+type Header struct {
+	// N is the index of the Fibonacci number.
+	N uint64 `json:"n"`
+	// Algorithm is the name of the calculator that produced the result.
+	Algorithm string `json:"algorithm"`
+	// Thresholds are the thresholds of the calculation.
+	Thresholds Thresholds `json:"thresholds"`
+	// Build identifies the producing binary.
+	Build BuildInfo `json:"build"`
+	// Timestamp is the time the result was written.
+	Timestamp time.Time `json:"timestamp"`
+	// Duration is the duration of the calculation.
+	Duration time.Duration `json:"duration_ns"`
+	// Bits is the bit length of the result; set by Write.
+	Bits int `json:"bits"`
+	// Limbs is the number of 64-bit limbs of the payload; set by Write.
+	Limbs int `json:"limbs"`
+}
+
+// Write writes x and its header in the binary format. The Bits and Limbs
+// fields of the header are set from x.
+//
+// Parameters:
+//   - w: The destination writer.
+//   - x: The result; it must not be negative.
+//   - h: The header describing the result.
+//   - compression: CompressionNone (or ""), CompressionGzip or
+//     CompressionZstd.
+//
+// Returns:
+//   - error: An error if x is negative, the compression is unknown or
+//     writing fails.
+func Write(w io.Writer, x *big.Int, h Header, compression string) error {
+	if x.Sign() < 0 {
+		return errors.New("cannot store a negative result")
+	}
+	var flags uint16
+	switch compression {
+	case "", CompressionNone:
+	case CompressionGzip:
+		flags |= FlagGzip
+	case CompressionZstd:
+		flags |= FlagZstd
+	default:
+		return fmt.Errorf("unknown result file compression %q", compression)
+	}
+	h.Bits = x.BitLen()
+	h.Limbs = (h.Bits + 63) / 64
+	header, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("cannot encode result header: %w", err)
+	}
+
+	sum := sha256.New()
+	bw := bufio.NewWriterSize(io.MultiWriter(w, sum), 1<<16)
+	prefix := make([]byte, 0, prefixSize)
+	prefix = append(prefix, Magic...)
+	prefix = binary.LittleEndian.AppendUint16(prefix, FormatVersion)
+	prefix = binary.LittleEndian.AppendUint16(prefix, flags)
+	prefix = binary.LittleEndian.AppendUint32(prefix, uint32(len(header)))
+	bw.Write(prefix)
+	bw.Write(header)
+
+	switch flags {
+	case FlagGzip:
+		zw := gzip.NewWriter(bw)
+		if err := writeLimbs(zw, x); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	case FlagZstd:
+		zw, err := zstd.NewWriter(bw, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		if err := writeLimbs(zw, x); err != nil {
+			zw.Close()
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	default:
+		if err := writeLimbs(bw, x); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	_, err = w.Write(sum.Sum(nil))
+	return err
+}
+
+// writeLimbs writes the magnitude of x as 64-bit little-endian limbs.
+func writeLimbs(w io.Writer, x *big.Int) error {
+	words := x.Bits()
+	buf := make([]byte, 0, 8192)
+	flush := func() error {
+		_, err := w.Write(buf)
+		buf = buf[:0]
+		return err
+	}
+	if bits.UintSize == 64 {
+		for _, word := range words {
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(word))
+			if len(buf) == cap(buf) {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	} else {
+		for i := 0; i < len(words); i += 2 {
+			limb := uint64(words[i])
+			if i+1 < len(words) {
+				limb |= uint64(words[i+1]) << 32
+			}
+			buf = binary.LittleEndian.AppendUint64(buf, limb)
+			if len(buf) == cap(buf) {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return flush()
+}
+
+// Info describes a binary result file, as read by Read.
+type Info struct {
+	// Version is the format version of the file.
+	Version uint16 `json:"version"`
+	// Compression is CompressionNone, CompressionGzip or CompressionZstd.
+	Compression string `json:"compression"`
+	// Header is the header of the file.
+	Header Header `json:"header"`
+	// Checksum is the hexadecimal SHA-256 trailer, verified by Read.
+	Checksum string `json:"sha256"`
+}
+
+// Read reads a result in the binary format and verifies its checksum.
+//
+// Parameters:
+//   - r: The source reader, positioned at the magic.
+//
+// Returns:
+//   - *big.Int: The result.
+//   - *Info: The description of the file.
+//   - error: An error if the file is not a binary result file, is of an
+//     unsupported version, has an inconsistent header, is truncated, or
+//     fails the checksum (ErrChecksum).
+func Read(r io.Reader) (*big.Int, *Info, error) {
+	return read(r, -1)
+}
+
+// read implements Read. If size is not negative, it is the size of the
+// file, which bounds the limbs of an uncompressed payload.
+func read(r io.Reader, size int64) (*big.Int, *Info, error) {
+	tr := &trailerReader{br: bufio.NewReaderSize(r, 1<<16)}
+	sum := sha256.New()
+	body := io.TeeReader(tr, sum)
+
+	prefix := make([]byte, prefixSize)
+	if _, err := io.ReadFull(body, prefix); err != nil {
+		return nil, nil, fmt.Errorf("not a binary result file: %w", err)
+	}
+	if string(prefix[:len(Magic)]) != Magic {
+		return nil, nil, errors.New("not a binary result file: bad magic")
+	}
+	info := &Info{Version: binary.LittleEndian.Uint16(prefix[8:]), Compression: CompressionNone}
+	if info.Version != FormatVersion {
+		return nil, nil, fmt.Errorf("unsupported result file version %d (this binary reads version %d)", info.Version, FormatVersion)
+	}
+	flags := binary.LittleEndian.Uint16(prefix[10:])
+	if flags&^(FlagGzip|FlagZstd) != 0 || flags == FlagGzip|FlagZstd {
+		return nil, nil, fmt.Errorf("unsupported result file flags %#x", flags)
+	}
+	hdrLen := binary.LittleEndian.Uint32(prefix[12:])
+	if hdrLen > maxHeaderSize {
+		return nil, nil, fmt.Errorf("result file header too large: %d bytes", hdrLen)
+	}
+	header := make([]byte, hdrLen)
+	if _, err := io.ReadFull(body, header); err != nil {
+		return nil, nil, fmt.Errorf("truncated result file header: %w", err)
+	}
+	if err := json.Unmarshal(header, &info.Header); err != nil {
+		return nil, nil, fmt.Errorf("invalid result file header: %w", err)
+	}
+	h := info.Header
+	if h.Bits < 0 || h.Limbs != (h.Bits+63)/64 {
+		return nil, nil, fmt.Errorf("invalid result file header: %d limbs for %d bits", h.Limbs, h.Bits)
+	}
+	if payloadSize := size - int64(prefixSize) - int64(hdrLen) - trailerSize; size >= 0 && flags == 0 && int64(h.Limbs) > payloadSize/8 {
+		return nil, nil, fmt.Errorf("truncated result file: %d limbs do not fit in %d payload bytes", h.Limbs, max(payloadSize, 0))
+	}
+
+	payload := body
+	switch flags {
+	case FlagGzip:
+		info.Compression = CompressionGzip
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid compressed payload: %w", err)
+		}
+		defer zr.Close()
+		payload = zr
+	case FlagZstd:
+		info.Compression = CompressionZstd
+		zr, err := zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid compressed payload: %w", err)
+		}
+		defer zr.Close()
+		payload = zr
+	}
+	x, err := readLimbs(payload, h.Limbs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if flags != 0 {
+		// Drain the end of the compressed stream so that its bytes are hashed
+		if n, err := io.Copy(io.Discard, payload); err != nil || n != 0 {
+			return nil, nil, fmt.Errorf("invalid compressed payload: %d extra bytes, %v", n, err)
+		}
+	}
+	if n, err := io.Copy(io.Discard, body); err != nil || n != 0 {
+		return nil, nil, fmt.Errorf("result file has %d unexpected bytes before the checksum: %v", n, err)
+	}
+	if tr.trailer == nil {
+		return nil, nil, errors.New("truncated result file: missing checksum")
+	}
+	want := sum.Sum(nil)
+	info.Checksum = fmt.Sprintf("%x", tr.trailer)
+	if !bytes.Equal(tr.trailer, want) {
+		return nil, info, ErrChecksum
+	}
+	if x.BitLen() != info.Header.Bits {
+		return nil, info, fmt.Errorf("result has %d bits, header says %d", x.BitLen(), info.Header.Bits)
+	}
+	return x, info, nil
+}
+
+// readLimbs reads limbs 64-bit little-endian limbs. It allocates at most
+// preallocLimbs limbs ahead of the data read.
+func readLimbs(r io.Reader, limbs int) (*big.Int, error) {
+	words := make([]big.Word, 0, min(limbs, preallocLimbs)*(64/bits.UintSize))
+	buf := make([]byte, 8192)
+	for remaining := limbs; remaining > 0; {
+		chunk := buf[:min(remaining*8, len(buf))]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, fmt.Errorf("truncated result payload: %w", err)
+		}
+		for i := 0; i < len(chunk); i += 8 {
+			limb := binary.LittleEndian.Uint64(chunk[i:])
+			if bits.UintSize == 64 {
+				words = append(words, big.Word(limb))
+			} else {
+				words = append(words, big.Word(uint32(limb)), big.Word(limb>>32))
+			}
+		}
+		remaining -= len(chunk) / 8
+	}
+	return new(big.Int).SetBits(words), nil
+}
+
+// trailerReader returns the bytes of br except the last trailerSize ones,
+// which it stores in trailer once the end of br is reached.
+type trailerReader struct {
+	br      *bufio.Reader
+	trailer []byte
+}
+
+// Read implements io.Reader.
+func (t *trailerReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	buf, err := t.br.Peek(min(len(p)+trailerSize, t.br.Size()))
+	avail := len(buf) - trailerSize
+	if avail > 0 {
+		n := copy(p, buf[:avail])
+		_, _ = t.br.Discard(n)
+		return n, nil
+	}
+	if err == nil || errors.Is(err, bufio.ErrBufferFull) {
+		return 0, io.ErrNoProgress
+	}
+	if !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	if len(buf) == trailerSize {
+		t.trailer = bytes.Clone(buf)
+	}
+	return 0, io.EOF
+}
+
+// WriteFile writes x to path in the binary format, creating the parent
+// directory if needed.
+//
+// Parameters:
+//   - path: The destination file.
+//   - x: The result.
+//   - h: The header describing the result.
+//   - compression: The payload compression, as accepted by Write.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func WriteFile(path string, x *big.Int, h Header, compression string) error {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := Write(f, x, h, compression); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write result file: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,206 @@
+package resultfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fib returns F(n) by iterated additions.
+func fib(n uint64) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := uint64(0); i < n; i++ {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+func testHeader(n uint64) Header {
+	return Header{
+		N:          n,
+		Algorithm:  "Fast Doubling",
+		Thresholds: Thresholds{Parallel: 4096, FFT: 500000, Strassen: 3072},
+		Build:      CurrentBuild(),
+		Timestamp:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Duration:   1500 * time.Millisecond,
+	}
+}
+
+// TestRoundTrip writes and reads back results of various sizes, with and
+// without compression.
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []uint64{0, 1, 2, 93, 94, 1000, 50_000} {
+		for _, compress := range []string{CompressionNone, CompressionGzip, CompressionZstd} {
+			want := fib(n)
+			var buf bytes.Buffer
+			if err := Write(&buf, want, testHeader(n), compress); err != nil {
+				t.Fatalf("Write(F(%d)): %v", n, err)
+			}
+			got, info, err := Read(&buf)
+			if err != nil {
+				t.Fatalf("Read(F(%d), %s): %v", n, compress, err)
+			}
+			if got.Cmp(want) != 0 {
+				t.Errorf("F(%d), %s: value mismatch", n, compress)
+			}
+			h := info.Header
+			if h.N != n || h.Algorithm != "Fast Doubling" || h.Thresholds.FFT != 500000 || h.Bits != want.BitLen() ||
+				!h.Timestamp.Equal(testHeader(n).Timestamp) || h.Duration != 1500*time.Millisecond {
+				t.Errorf("F(%d): unexpected header %+v", n, h)
+			}
+			if info.Compression != compress {
+				t.Errorf("compression = %q, want %q", info.Compression, compress)
+			}
+			if len(info.Checksum) != 64 {
+				t.Errorf("checksum %q is not a hex SHA-256", info.Checksum)
+			}
+		}
+	}
+}
+
+// TestRead_Corruption verifies that truncated, altered and foreign files are
+// rejected.
+func TestRead_Corruption(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, fib(10_000), testHeader(10_000), CompressionNone); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	flipped := bytes.Clone(data)
+	flipped[len(flipped)/2] ^= 0x10
+	if _, _, err := Read(bytes.NewReader(flipped)); !errors.Is(err, ErrChecksum) {
+		t.Errorf("flipped payload bit: expected ErrChecksum, got %v", err)
+	}
+
+	for _, size := range []int{0, 10, len(Magic) + 20, len(data) / 2, len(data) - 1} {
+		if _, _, err := Read(bytes.NewReader(data[:size])); err == nil {
+			t.Errorf("truncated to %d bytes: expected an error", size)
+		}
+	}
+
+	if _, _, err := Read(strings.NewReader("F(10) =\n55\n")); err == nil {
+		t.Error("text file: expected an error")
+	}
+
+	future := bytes.Clone(data)
+	future[len(Magic)] = FormatVersion + 1
+	if _, _, err := Read(bytes.NewReader(future)); err == nil || !strings.Contains(err.Error(), "unsupported result file version") {
+		t.Errorf("future version: unexpected error %v", err)
+	}
+
+	if err := Write(&buf, big.NewInt(-1), testHeader(0), CompressionNone); err == nil {
+		t.Error("negative value: expected an error")
+	}
+	if err := Write(&buf, big.NewInt(1), testHeader(0), "lz4"); err == nil {
+		t.Error("unknown compression: expected an error")
+	}
+}
+
+// forge returns a binary result file with the given header, flags and
+// payload, and a valid checksum.
+func forge(t *testing.T, h Header, flags uint16, payload []byte) []byte {
+	t.Helper()
+	header, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(Magic)
+	data = binary.LittleEndian.AppendUint16(data, FormatVersion)
+	data = binary.LittleEndian.AppendUint16(data, flags)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(header)))
+	data = append(append(data, header...), payload...)
+	sum := sha256.Sum256(data)
+	return append(data, sum[:]...)
+}
+
+// TestRead_ForgedHeader verifies that a header announcing more limbs than
+// the file holds is rejected without allocating them.
+func TestRead_ForgedHeader(t *testing.T) {
+	t.Parallel()
+
+	inconsistent := Header{Bits: 64, Limbs: 1 << 40}
+	if _, _, err := Read(bytes.NewReader(forge(t, inconsistent, 0, make([]byte, 8)))); err == nil || !strings.Contains(err.Error(), "limbs for") {
+		t.Errorf("inconsistent limb count: unexpected error %v", err)
+	}
+
+	huge := Header{Bits: 1 << 46, Limbs: 1 << 40}
+	for _, flags := range []uint16{0, FlagGzip, FlagZstd} {
+		if _, _, err := Read(bytes.NewReader(forge(t, huge, flags, make([]byte, 8)))); err == nil {
+			t.Errorf("flags %#x: expected an error for a truncated payload", flags)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "forged.fib")
+	if err := os.WriteFile(path, forge(t, huge, 0, make([]byte, 8)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "do not fit") {
+		t.Errorf("Load: unexpected error %v", err)
+	}
+}
+
+// TestLoad verifies that Load reads the binary and text formats.
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	want := fib(300)
+	files := map[string]string{
+		FormatDecimal: "# Fibonacci Calculation Result\n# Algorithm: Matrix\n# N: 300\n\nF(300) =\n" + want.String() + "\n",
+		FormatHex:     "# N: 300\n\nF(300) [hex] =\n0x" + want.Text(16) + "\n",
+	}
+	for format, content := range files {
+		path := filepath.Join(dir, format+".txt")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	binPath := filepath.Join(dir, "sub", "result.fib")
+	if err := WriteFile(binPath, want, testHeader(300), CompressionZstd); err != nil {
+		t.Fatal(err)
+	}
+	files[FormatBinary] = ""
+
+	for format := range files {
+		path := filepath.Join(dir, format+".txt")
+		if format == FormatBinary {
+			path = binPath
+		}
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", format, err)
+		}
+		if loaded.Format != format || loaded.N != 300 || loaded.Value.Cmp(want) != 0 {
+			t.Errorf("Load(%s) = format %q, N %d, correct %v", format, loaded.Format, loaded.N, loaded.Value.Cmp(want) == 0)
+		}
+		if (loaded.Info != nil) != (format == FormatBinary) {
+			t.Errorf("Load(%s): Info = %v", format, loaded.Info)
+		}
+	}
+
+	bare := filepath.Join(dir, "bare.txt")
+	os.WriteFile(bare, []byte("12345\n"), 0644)
+	if loaded, err := Load(bare); err != nil || loaded.Value.Int64() != 12345 || loaded.N != 0 {
+		t.Errorf("bare number: %+v, %v", loaded, err)
+	}
+
+	empty := filepath.Join(dir, "empty.txt")
+	os.WriteFile(empty, []byte("# N: 5\n"), 0644)
+	if _, err := Load(empty); err == nil {
+		t.Error("file without value: expected an error")
+	}
+}
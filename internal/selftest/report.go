@@ -0,0 +1,53 @@
+package selftest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/ui"
+)
+
+// PrintReport prints the pass/fail table of the checks and a summary line.
+//
+// Parameters:
+//   - out: The writer for output.
+//   - r: The report to print.
+func PrintReport(out io.Writer, r *Report) {
+	fmt.Fprintf(out, "\n--- Self-test: %s/%s, %s ---\n", r.GOOS, r.GOARCH, r.CPUFeatures)
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Check\tStatus\tDuration\t\n")
+	for _, c := range r.Checks {
+		status := fmt.Sprintf("%sPASS%s", ui.ColorGreen(), ui.ColorReset())
+		if !c.Passed {
+			status = fmt.Sprintf("%sFAIL%s %s", ui.ColorRed(), ui.ColorReset(), c.Error)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", c.Name, status, cli.FormatExecutionDuration(c.Duration))
+	}
+	tw.Flush()
+
+	passed := len(r.Checks) - r.Failures()
+	if r.Passed() {
+		fmt.Fprintf(out, "\n%sSelf-test passed:%s %d/%d checks in %s\n",
+			ui.ColorGreen(), ui.ColorReset(), passed, len(r.Checks), r.Duration.Round(time.Millisecond))
+		return
+	}
+	fmt.Fprintf(out, "\n%sSelf-test FAILED:%s %d/%d checks passed in %s\n",
+		ui.ColorRed(), ui.ColorReset(), passed, len(r.Checks), r.Duration.Round(time.Millisecond))
+}
+
+// WriteJSON writes the report as an indented JSON document.
+//
+// Parameters:
+//   - w: The destination writer.
+//
+// Returns:
+//   - error: An error if encoding fails.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,274 @@
+// Package selftest implements `fibcalc selftest`, which validates the
+// arithmetic of the current machine against bundled known answers.
+//
+// The bigfft package dispatches to assembly depending on the SIMD level of
+// the CPU and draws its temporaries from pools or bump allocators, so a
+// result can be wrong on one host and right on another. The self-test
+// computes large Fibonacci numbers under every SIMD level, with every
+// multiplication strategy, compares their SHA-256 with the known answers
+// generated by cmd/generate-golden, and cross-checks FFT multiplication with
+// every allocator against math/big. The checks run sequentially because the
+// SIMD level is process-wide.
+package selftest
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"runtime"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// knownAnswersJSON is generated by:
+//
+//	go run ./cmd/generate-golden -known-answers internal/selftest/testdata/known_answers.json
+//
+//go:embed testdata/known_answers.json
+var knownAnswersJSON []byte
+
+// KnownAnswer describes F(N) by its bit length and its digest (see
+// orchestration.Digest).
+type KnownAnswer struct {
+	N      uint64 `json:"n"`
+	Bits   int    `json:"bits"`
+	SHA256 string `json:"sha256"`
+}
+
+// KnownAnswers returns the bundled known answers, in increasing order of N.
+//
+// Returns:
+//   - []KnownAnswer: The known answers.
+//   - error: An error if the bundled file is corrupt.
+func KnownAnswers() ([]KnownAnswer, error) {
+	var answers []KnownAnswer
+	if err := json.Unmarshal(knownAnswersJSON, &answers); err != nil {
+		return nil, fmt.Errorf("corrupt known answers: %w", err)
+	}
+	return answers, nil
+}
+
+// Options configures a self-test run.
+type Options struct {
+	// MaxN skips the known answers with a larger index; zero runs them all.
+	MaxN uint64
+	// AllocatorBits is the size of the operands of the allocator checks;
+	// zero uses DefaultAllocatorBits.
+	AllocatorBits int
+}
+
+// DefaultAllocatorBits is the operand size of the allocator checks, large
+// enough for FFT multiplication with a multi-level recursion.
+const DefaultAllocatorBits = 1 << 21
+
+// Check is the outcome of one self-test check.
+type Check struct {
+	// Name identifies the check, including its SIMD level.
+	Name string `json:"name"`
+	// Passed is true if the check produced the expected result.
+	Passed bool `json:"passed"`
+	// Duration is the time the check took.
+	Duration time.Duration `json:"duration_ns"`
+	// Error describes the failure, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the outcome of a self-test run.
+type Report struct {
+	// GOOS, GOARCH and CPUFeatures describe the machine.
+	GOOS        string `json:"goos"`
+	GOARCH      string `json:"goarch"`
+	CPUFeatures string `json:"cpu_features"`
+	// Checks lists the checks in run order.
+	Checks []Check `json:"checks"`
+	// Duration is the total duration of the run.
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Passed reports whether every check passed.
+func (r *Report) Passed() bool {
+	return r.Failures() == 0
+}
+
+// Failures returns the number of failed checks.
+func (r *Report) Failures() int {
+	failures := 0
+	for _, c := range r.Checks {
+		if !c.Passed {
+			failures++
+		}
+	}
+	return failures
+}
+
+// strategies returns every multiplication strategy.
+func strategies() []fibonacci.MultiplicationStrategy {
+	return []fibonacci.MultiplicationStrategy{
+		&fibonacci.AdaptiveStrategy{},
+		&fibonacci.FFTOnlyStrategy{},
+		&fibonacci.KaratsubaStrategy{},
+	}
+}
+
+// allocator is a way of multiplying with FFT that exercises one allocator.
+type allocator struct {
+	name string
+	mul  func(x, y *big.Int) (*big.Int, error)
+}
+
+// allocators returns every FFT temporary allocator: the pools, the bump
+// allocator, and the pools in low-memory mode, which stop retaining large
+// buffers.
+func allocators() []allocator {
+	return []allocator{
+		{"pool", mulWithPool},
+		{"bump", mulWithBump},
+		{"pool (low-memory)", func(x, y *big.Int) (*big.Int, error) {
+			defer bigfft.SetLowMemoryMode(bigfft.LowMemoryMode())
+			bigfft.SetLowMemoryMode(true)
+			return mulWithPool(x, y)
+		}},
+	}
+}
+
+// Run runs the self-test: for each SIMD level, the known answers with each
+// strategy, then the allocator checks. The SIMD level is restored on return.
+//
+// Parameters:
+//   - ctx: The context for cancellation.
+//   - opts: The self-test options.
+//
+// Returns:
+//   - *Report: The report of the checks run so far.
+//   - error: An error if the known answers are corrupt or the run was
+//     interrupted.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	answers, err := KnownAnswers()
+	if err != nil {
+		return nil, err
+	}
+	if opts.AllocatorBits <= 0 {
+		opts.AllocatorBits = DefaultAllocatorBits
+	}
+	x, y := operand(opts.AllocatorBits, 1), operand(opts.AllocatorBits, 2)
+	want := new(big.Int).Mul(x, y)
+
+	report := &Report{GOOS: runtime.GOOS, GOARCH: runtime.GOARCH, CPUFeatures: cpuFeatures()}
+	start := time.Now()
+	defer func() { report.Duration = time.Since(start) }()
+	defer saveSIMD()()
+
+	for _, level := range simdLevels() {
+		level.apply()
+		for _, ka := range answers {
+			if opts.MaxN > 0 && ka.N > opts.MaxN {
+				continue
+			}
+			for _, strategy := range strategies() {
+				name := fmt.Sprintf("F(%d) %s [%s]", ka.N, strategy.Name(), level.name)
+				report.Checks = append(report.Checks, runCheck(name, func() error {
+					return checkKnownAnswer(ctx, strategy, ka)
+				}))
+				if err := ctx.Err(); err != nil {
+					return report, err
+				}
+			}
+		}
+		for _, alloc := range allocators() {
+			name := fmt.Sprintf("FFT %d-bit product, %s allocator [%s]", opts.AllocatorBits, alloc.name, level.name)
+			report.Checks = append(report.Checks, runCheck(name, func() error {
+				got, err := alloc.mul(x, y)
+				if err != nil {
+					return err
+				}
+				if got.Cmp(want) != 0 {
+					return fmt.Errorf("product differs from math/big")
+				}
+				return nil
+			}))
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// runCheck times fn and records its outcome.
+func runCheck(name string, fn func() error) Check {
+	start := time.Now()
+	err := fn()
+	c := Check{Name: name, Passed: err == nil, Duration: time.Since(start)}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	return c
+}
+
+// checkKnownAnswer computes F(ka.N) with the strategy and compares it with
+// the known answer.
+func checkKnownAnswer(ctx context.Context, strategy fibonacci.MultiplicationStrategy, ka KnownAnswer) error {
+	s := fibonacci.AcquireState()
+	defer fibonacci.ReleaseState(s)
+	opts := fibonacci.Options{
+		ParallelThreshold:  fibonacci.DefaultParallelThreshold,
+		FFTThreshold:       fibonacci.DefaultFFTThreshold,
+		KaratsubaThreshold: fibonacci.DefaultKaratsubaThreshold,
+		StrassenThreshold:  fibonacci.DefaultStrassenThreshold,
+	}
+	f, err := fibonacci.NewDoublingFramework(strategy).
+		ExecuteDoublingLoop(ctx, func(float64) {}, ka.N, opts, s, runtime.GOMAXPROCS(0) > 1)
+	if err != nil {
+		return err
+	}
+	if f.BitLen() != ka.Bits {
+		return fmt.Errorf("result has %d bits, want %d", f.BitLen(), ka.Bits)
+	}
+	if got := orchestration.Digest(f); got != ka.SHA256 {
+		return fmt.Errorf("SHA-256 %s…, want %s…", got[:16], ka.SHA256[:16])
+	}
+	return nil
+}
+
+// operand returns a deterministic pseudo-random operand of the given size.
+func operand(bits int, seed uint64) *big.Int {
+	rng := rand.New(rand.NewPCG(seed, 0x5e1f7e57))
+	words := make([]big.Word, (bits+63)/64)
+	for i := range words {
+		words[i] = big.Word(rng.Uint64())
+	}
+	x := new(big.Int).SetBits(words)
+	return x.SetBit(x, bits-1, 1)
+}
+
+// mulWithPool multiplies x and y with FFT, drawing temporaries from the pools.
+func mulWithPool(x, y *big.Int) (*big.Int, error) {
+	k, m := bigfft.GetFFTParams(len(x.Bits()) + len(y.Bits()))
+	xp, yp := bigfft.PolyFromInt(x, k, m), bigfft.PolyFromInt(y, k, m)
+	rp, err := xp.Mul(&yp)
+	if err != nil {
+		return nil, err
+	}
+	return rp.IntToBigInt(new(big.Int)), nil
+}
+
+// mulWithBump multiplies x and y with FFT, drawing temporaries from a bump
+// allocator.
+func mulWithBump(x, y *big.Int) (*big.Int, error) {
+	words := len(x.Bits()) + len(y.Bits())
+	ba := bigfft.AcquireBumpAllocator(bigfft.EstimateBumpCapacity(words))
+	defer bigfft.ReleaseBumpAllocator(ba)
+	k, m := bigfft.GetFFTParams(words)
+	xp, yp := bigfft.PolyFromInt(x, k, m), bigfft.PolyFromInt(y, k, m)
+	rp, err := xp.MulWithBump(&yp, ba)
+	if err != nil {
+		return nil, err
+	}
+	return rp.IntToBigInt(new(big.Int)), nil
+}
@@ -0,0 +1,84 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestKnownAnswers(t *testing.T) {
+	answers, err := KnownAnswers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) == 0 {
+		t.Fatal("no known answers bundled")
+	}
+	for i, ka := range answers {
+		if len(ka.SHA256) != 64 || ka.Bits <= 0 {
+			t.Errorf("malformed known answer %+v", ka)
+		}
+		if i > 0 && ka.N <= answers[i-1].N {
+			t.Errorf("known answers not in increasing order at F(%d)", ka.N)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(context.Background(), Options{MaxN: 100_000, AllocatorBits: 1 << 18})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed() {
+		for _, c := range report.Checks {
+			if !c.Passed {
+				t.Errorf("%s: %s", c.Name, c.Error)
+			}
+		}
+	}
+	// Two known answers and three strategies, plus three allocators, per SIMD level.
+	if want := len(simdLevels()) * (2*3 + 3); len(report.Checks) != want {
+		t.Errorf("got %d checks, want %d", len(report.Checks), want)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Run(ctx, Options{MaxN: 10_000, AllocatorBits: 1 << 16})
+	if err == nil {
+		t.Fatal("expected an error for a canceled context")
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Passed {
+		t.Errorf("expected a single failed check, got %+v", report.Checks)
+	}
+}
+
+func TestCheckKnownAnswer_Mismatch(t *testing.T) {
+	answers, _ := KnownAnswers()
+	ka := answers[0]
+	ka.SHA256 = strings.Repeat("0", 64)
+	if err := checkKnownAnswer(context.Background(), strategies()[0], ka); err == nil {
+		t.Error("expected a mismatch error")
+	}
+	ka.Bits++
+	if err := checkKnownAnswer(context.Background(), strategies()[0], ka); err == nil || !strings.Contains(err.Error(), "bits") {
+		t.Errorf("expected a bit length error, got %v", err)
+	}
+}
+
+func TestPrintReport(t *testing.T) {
+	r := &Report{GOOS: "linux", GOARCH: "amd64", CPUFeatures: "AVX2", Checks: []Check{
+		{Name: "F(10000) FFT-Only [AVX2]", Passed: true},
+		{Name: "F(10000) Karatsuba-Only [Generic]", Error: "SHA-256 mismatch"},
+	}}
+	var buf bytes.Buffer
+	PrintReport(&buf, r)
+	out := buf.String()
+	for _, want := range []string{"F(10000) FFT-Only [AVX2]", "PASS", "FAIL", "SHA-256 mismatch", "1/2 checks passed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
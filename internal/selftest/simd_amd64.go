@@ -0,0 +1,60 @@
+//go:build amd64
+
+package selftest
+
+import "github.com/agbru/fibcalc/internal/bigfft"
+
+// simdLevel is a SIMD implementation level of bigfft.
+type simdLevel struct {
+	name  string
+	apply func()
+}
+
+// simdLevels returns the SIMD levels supported by the CPU, from the highest
+// to the generic implementation.
+func simdLevels() []simdLevel {
+	var levels []simdLevel
+	if bigfft.HasAVX512() {
+		levels = append(levels, simdLevel{bigfft.SIMDAVX512.String(), useAVX512})
+	}
+	if bigfft.HasAVX2() {
+		levels = append(levels, simdLevel{bigfft.SIMDAVX2.String(), useAVX2})
+	}
+	levels = append(levels, simdLevel{"Generic", useGeneric})
+	return levels
+}
+
+// saveSIMD returns a function restoring the active SIMD implementation, which
+// the user may have restricted.
+func saveSIMD() (restore func()) {
+	switch bigfft.GetActiveImplementation() {
+	case bigfft.SIMDAVX512:
+		return useAVX512
+	case bigfft.SIMDAVX2:
+		return useAVX2
+	default:
+		return useGeneric
+	}
+}
+
+// useAVX512 selects the AVX-512 implementation.
+func useAVX512() {
+	bigfft.EnableAllSIMD()
+}
+
+// useAVX2 selects the AVX2 implementation.
+func useAVX2() {
+	bigfft.EnableAllSIMD()
+	bigfft.DisableAVX512()
+}
+
+// useGeneric selects the generic implementation.
+func useGeneric() {
+	bigfft.DisableAVX512()
+	bigfft.DisableAVX2()
+}
+
+// cpuFeatures describes the SIMD features of the CPU.
+func cpuFeatures() string {
+	return bigfft.GetCPUFeatures().String()
+}
@@ -0,0 +1,22 @@
+//go:build amd64
+
+package selftest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+)
+
+func TestRun_RestoresSIMD(t *testing.T) {
+	defer bigfft.EnableAllSIMD()
+	useGeneric()
+
+	if _, err := Run(context.Background(), Options{MaxN: 10_000, AllocatorBits: 1 << 16}); err != nil {
+		t.Fatal(err)
+	}
+	if got := bigfft.GetActiveImplementation(); got != bigfft.SIMDNone {
+		t.Errorf("expected the generic implementation kept, got %s", got)
+	}
+}
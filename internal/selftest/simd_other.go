@@ -0,0 +1,25 @@
+//go:build !amd64
+
+package selftest
+
+// simdLevel is a SIMD implementation level of bigfft.
+type simdLevel struct {
+	name  string
+	apply func()
+}
+
+// simdLevels returns the generic implementation only: bigfft has no SIMD
+// dispatch on this architecture.
+func simdLevels() []simdLevel {
+	return []simdLevel{{"Generic", func() {}}}
+}
+
+// saveSIMD returns a no-op: there is no SIMD state on this architecture.
+func saveSIMD() (restore func()) {
+	return func() {}
+}
+
+// cpuFeatures describes the SIMD features of the CPU.
+func cpuFeatures() string {
+	return "No SIMD dispatch on this architecture"
+}
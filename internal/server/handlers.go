@@ -10,6 +10,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/agbru/fibcalc/internal/service"
 )
 
@@ -99,6 +101,43 @@ func (s *Server) handleCalculate(w http.ResponseWriter, r *http.Request) {
 	s.writeJSONResponse(w, http.StatusOK, resp)
 }
 
+// handlePlan returns the execution plan of a calculation without performing it.
+// It accepts the same 'n' and 'algo' parameters as /calculate and responds with
+// the step schedule, multiplication methods, switch points, expected peak
+// memory and estimated duration.
+//
+// Parameters:
+//   - w: The HTTP response writer.
+//   - r: The HTTP request.
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	n, algo, err := parseCalculateParams(r)
+	if err != nil {
+		if parseErr, ok := err.(CalculateParseError); ok {
+			s.writeErrorResponse(w, parseErr.StatusCode, parseErr.Message)
+		} else {
+			s.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	if _, err := s.factory.Get(algo); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unknown algorithm: %s", algo))
+		return
+	}
+
+	plan := fibonacci.PlanCalculation(n, algo, s.cfg.ToCalculationOptions())
+	if d, source, ok := calibration.EstimateDuration(n, s.profile, algo); ok {
+		plan.EstimatedDuration, plan.EstimateSource = d, source
+	}
+
+	s.writeJSONResponse(w, http.StatusOK, plan)
+}
+
 // parseCalculateParams extracts and validates the calculation parameters from the request.
 //
 // Parameters:
@@ -4,6 +4,12 @@ package server
 import (
 	"net/http"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -70,3 +76,52 @@ func (s *Server) loggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		s.logger.Printf("%s %s completed in %v", r.Method, r.URL.Path, duration)
 	}
 }
+
+// traceContext is the W3C trace-context propagator used for the traceparent
+// and tracestate headers.
+var traceContext = propagation.TraceContext{}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and forwards it.
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// tracingMiddleware wraps an http.HandlerFunc in a server span. The span
+// continues the trace of an incoming W3C traceparent header, is passed to the
+// handler (and thus to the calculation) through the request context, and its
+// traceparent is returned in the response headers.
+//
+// Parameters:
+//   - next: The next handler in the chain.
+//
+// Returns:
+//   - http.HandlerFunc: A new handler with tracing capability.
+func (s *Server) tracingMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := traceContext.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer("server").Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("url.query", r.URL.RawQuery),
+			))
+		defer span.End()
+
+		traceContext.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package server
 
 import (
+	"context"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Unit tests for middleware functions
@@ -140,3 +145,38 @@ func TestRateLimiterCleanup(t *testing.T) {
 
 	rl.Stop()
 }
+
+// traceCapturingCalculator records the trace ID of the calculation context.
+type traceCapturingCalculator struct {
+	traceID trace.TraceID
+}
+
+func (c *traceCapturingCalculator) Name() string { return "Trace" }
+
+func (c *traceCapturingCalculator) Calculate(ctx context.Context, _ chan<- fibonacci.ProgressUpdate, _ int, _ uint64, _ fibonacci.Options) (*big.Int, error) {
+	c.traceID = trace.SpanContextFromContext(ctx).TraceID()
+	return big.NewInt(55), nil
+}
+
+// TestTracingMiddleware verifies that an incoming traceparent is propagated
+// into the calculation and returned in the response.
+func TestTracingMiddleware(t *testing.T) {
+	calc := &traceCapturingCalculator{}
+	server := createTestServer(map[string]fibonacci.Calculator{"fast": calc})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/calculate?n=10&algo=fast", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := calc.traceID.String(); got != traceID {
+		t.Errorf("calculation trace ID = %s, want %s", got, traceID)
+	}
+	if tp := w.Header().Get("traceparent"); !strings.Contains(tp, traceID) {
+		t.Errorf("response traceparent = %q, want trace ID %s", tp, traceID)
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+)
+
+// pprofPrefix is the path under which the runtime profiles are exposed.
+const pprofPrefix = "/debug/pprof/"
+
+// registerPprof mounts the net/http/pprof handlers on mux behind bearer-token
+// authentication. Named profiles (heap, goroutine, block, ...) are served by
+// the index handler.
+//
+// Parameters:
+//   - mux: The request multiplexer.
+//   - token: The bearer token required to access the profiles.
+func (s *Server) registerPprof(mux *http.ServeMux, token string) {
+	protect := func(h http.HandlerFunc) http.HandlerFunc {
+		return s.loggingMiddleware(pprofAuthMiddleware(token, h))
+	}
+	mux.HandleFunc(pprofPrefix, protect(pprof.Index))
+	mux.HandleFunc(pprofPrefix+"cmdline", protect(pprof.Cmdline))
+	mux.HandleFunc(pprofPrefix+"profile", protect(pprof.Profile))
+	mux.HandleFunc(pprofPrefix+"symbol", protect(pprof.Symbol))
+	mux.HandleFunc(pprofPrefix+"trace", protect(pprof.Trace))
+}
+
+// pprofAuthMiddleware rejects requests that do not carry the expected token
+// in an "Authorization: Bearer <token>" header. The comparison runs in
+// constant time, and an empty token rejects every request.
+//
+// Parameters:
+//   - token: The expected bearer token.
+//   - next: The next handler in the chain.
+//
+// Returns:
+//   - http.HandlerFunc: A new handler with authentication.
+func pprofAuthMiddleware(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pprof"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
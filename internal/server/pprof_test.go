@@ -0,0 +1,65 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/logging"
+)
+
+// TestPprofEndpoints verifies that /debug/pprof is opt-in and authenticated.
+func TestPprofEndpoints(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(enabled bool) *Server {
+		cfg := config.AppConfig{Port: "8080", ServerPprof: enabled, PprofToken: "s3cret"}
+		return NewServer(fibonacci.NewTestFactory(nil), cfg, WithLogger(logging.NewLogger(io.Discard, "server")))
+	}
+	get := func(s *Server, auth string) int {
+		req := httptest.NewRequest("GET", "/debug/pprof/cmdline", http.NoBody)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := get(newServer(false), "Bearer s3cret"); code != http.StatusNotFound {
+		t.Errorf("disabled pprof: expected 404, got %d", code)
+	}
+
+	s := newServer(true)
+	tests := []struct {
+		auth string
+		want int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Basic s3cret", http.StatusUnauthorized},
+		{"Bearer s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := get(s, tt.auth); code != tt.want {
+			t.Errorf("Authorization %q: expected %d, got %d", tt.auth, tt.want, code)
+		}
+	}
+}
+
+// TestPprofAuthEmptyToken verifies that an empty token never authenticates.
+func TestPprofAuthEmptyToken(t *testing.T) {
+	t.Parallel()
+
+	h := pprofAuthMiddleware("", func(w http.ResponseWriter, _ *http.Request) {})
+	req := httptest.NewRequest("GET", "/debug/pprof/", http.NoBody)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+	h(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/agbru/fibcalc/internal/calibration"
 	"github.com/agbru/fibcalc/internal/config"
 	apperrors "github.com/agbru/fibcalc/internal/errors"
 	"github.com/agbru/fibcalc/internal/fibonacci"
@@ -29,6 +30,7 @@ type Server struct {
 	securityConfig SecurityConfig
 	metrics        *Metrics
 	timeouts       Timeouts
+	profile        *calibration.CalibrationProfile
 }
 
 // NewServer creates a new Server instance with the given calculator registry and configuration.
@@ -57,6 +59,11 @@ func NewServer(factory fibonacci.CalculatorFactory, cfg config.AppConfig, opts .
 		opt(s)
 	}
 
+	// Cost models for plan duration estimates (built-in defaults if absent)
+	if profile, err := calibration.LoadProfile(s.cfg.CalibrationProfile); err == nil {
+		s.profile = profile
+	}
+
 	// Initialize service if not provided
 	if s.service == nil {
 		s.service = service.NewCalculatorService(s.factory, s.cfg, s.securityConfig.MaxNValue)
@@ -69,11 +76,15 @@ func NewServer(factory fibonacci.CalculatorFactory, cfg config.AppConfig, opts .
 
 	mux := http.NewServeMux()
 
-	// Apply middleware chain: Security -> RateLimit -> Logging -> Metrics -> Handler
+	// Apply middleware chain: Tracing -> Security -> RateLimit -> Logging -> Metrics -> Handler
 	mux.HandleFunc("/calculate", s.wrapWithMiddleware(s.handleCalculate))
+	mux.HandleFunc("/calculate/plan", s.wrapWithMiddleware(s.handlePlan))
 	mux.HandleFunc("/health", s.wrapWithMiddleware(s.handleHealth))
 	mux.HandleFunc("/algorithms", s.wrapWithMiddleware(s.handleAlgorithms))
 	mux.HandleFunc("/metrics", s.wrapWithMiddleware(s.handleMetrics))
+	if cfg.ServerPprof {
+		s.registerPprof(mux, cfg.PprofToken)
+	}
 
 	s.httpServer = &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -88,11 +99,12 @@ func NewServer(factory fibonacci.CalculatorFactory, cfg config.AppConfig, opts .
 
 // wrapWithMiddleware applies the full middleware chain to a handler.
 func (s *Server) wrapWithMiddleware(handler http.HandlerFunc) http.HandlerFunc {
-	// Apply in reverse order: Security -> RateLimit -> Logging -> Metrics -> Handler
+	// Apply in reverse order: Tracing -> Security -> RateLimit -> Logging -> Metrics -> Handler
 	wrapped := s.metricsMiddleware(handler)
 	wrapped = s.loggingMiddleware(wrapped)
 	wrapped = RateLimitMiddleware(s.rateLimiter, wrapped)
 	wrapped = SecurityMiddleware(s.securityConfig, wrapped)
+	wrapped = s.tracingMiddleware(wrapped)
 	return wrapped
 }
 
@@ -116,8 +128,12 @@ func (s *Server) Start() error {
 			s.cfg.Threshold, s.cfg.FFTThreshold, s.cfg.StrassenThreshold)
 		s.logger.Println("Available endpoints:")
 		s.logger.Println("  GET /calculate?n=<number>&algo=<algorithm>")
+		s.logger.Println("  GET /calculate/plan?n=<number>&algo=<algorithm>")
 		s.logger.Println("  GET /health")
 		s.logger.Println("  GET /algorithms")
+		if s.cfg.ServerPprof {
+			s.logger.Println("  GET /debug/pprof/ (bearer token required)")
+		}
 
 		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
@@ -236,6 +236,53 @@ func TestHandleAlgorithms(t *testing.T) {
 	}
 }
 
+// TestHandlePlan verifies the execution plan endpoint.
+func TestHandlePlan(t *testing.T) {
+	mockCalc := &MockCalculator{Err: errors.New("calculation must not run")}
+	server := createTestServer(map[string]fibonacci.Calculator{"fast": mockCalc})
+
+	t.Run("Success", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/calculate/plan?n=1000000&algo=fast", http.NoBody)
+		w := httptest.NewRecorder()
+		server.handlePlan(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var plan fibonacci.ExecutionPlan
+		if err := json.NewDecoder(w.Body).Decode(&plan); err != nil {
+			t.Fatalf("Failed to decode plan: %v", err)
+		}
+		if plan.N != 1_000_000 || plan.Algorithm != "fast" || len(plan.Steps) == 0 {
+			t.Errorf("Unexpected plan: %+v", plan)
+		}
+		if plan.Thresholds.FFT != 20000 {
+			t.Errorf("Expected server FFT threshold 20000, got %d", plan.Thresholds.FFT)
+		}
+		if plan.EstimatedDuration <= 0 || plan.EstimateSource == "" {
+			t.Errorf("Expected a duration estimate, got %v (%q)", plan.EstimatedDuration, plan.EstimateSource)
+		}
+	})
+
+	t.Run("Unknown algorithm", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/calculate/plan?n=100&algo=nope", http.NoBody)
+		w := httptest.NewRecorder()
+		server.handlePlan(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Missing n", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/calculate/plan", http.NoBody)
+		w := httptest.NewRecorder()
+		server.handlePlan(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
 // TestMethodNotAllowed verifies that non-GET methods are rejected.
 func TestMethodNotAllowed(t *testing.T) {
 	server := createTestServer(nil)
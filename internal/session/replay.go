@@ -0,0 +1,226 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// Session is a loaded session file.
+type Session struct {
+	Header Header
+	// Events are the progress updates, in order of arrival.
+	Events []Event
+	// Results are the recorded results, indexed by calculator index; nil
+	// for the calculators without one.
+	Results []*Result
+	// Complete reports whether the session ends with an "end" record. The
+	// session of an interrupted run holds the updates up to the
+	// interruption.
+	Complete bool
+}
+
+// Event is a progress update with the time it arrived, relative to the
+// start of the run.
+type Event struct {
+	Elapsed time.Duration
+	Update  fibonacci.ProgressUpdate
+}
+
+// LoadFile loads the session file at path.
+//
+// Parameters:
+//   - path: The session file path.
+//
+// Returns:
+//   - *Session: The session.
+//   - error: An error if the file cannot be read or is not a session.
+func LoadFile(path string) (*Session, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open session: %w", err)
+	}
+	defer f.Close()
+	s, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Load reads a session file.
+//
+// Parameters:
+//   - r: The session file.
+//
+// Returns:
+//   - *Session: The session.
+//   - error: An error if the content is not a session.
+func Load(r io.Reader) (*Session, error) {
+	var s *Session
+	sc := bufio.NewScanner(r)
+	// A result record holds up to MaxValueBits bits of decimal digits
+	sc.Buffer(make([]byte, 0, 64*1024), 4*MaxValueBits)
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if s == nil {
+			if rec.Type != TypeHeader || rec.Session == nil {
+				return nil, fmt.Errorf("line %d: not a session file: expected a %q record", line, TypeHeader)
+			}
+			s = &Session{Header: *rec.Session, Results: make([]*Result, len(rec.Session.Algorithms))}
+			continue
+		}
+		if s.Complete {
+			return nil, fmt.Errorf("line %d: record after the end of the session", line)
+		}
+		if rec.Type == TypeEnd {
+			s.Complete = true
+			continue
+		}
+		if rec.Calculator < 0 || rec.Calculator >= len(s.Results) {
+			return nil, fmt.Errorf("line %d: unknown calculator %d", line, rec.Calculator)
+		}
+		switch rec.Type {
+		case TypeProgress:
+			s.Events = append(s.Events, Event{Elapsed: rec.Elapsed, Update: fibonacci.ProgressUpdate{
+				CalculatorIndex: rec.Calculator,
+				Value:           rec.Progress,
+				Event:           rec.Step,
+			}})
+		case TypeResult:
+			if rec.Result == nil {
+				return nil, fmt.Errorf("line %d: result record without a result", line)
+			}
+			s.Results[rec.Calculator] = rec.Result
+		default:
+			return nil, fmt.Errorf("line %d: unknown record type %q", line, rec.Type)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read session: %w", err)
+	}
+	if s == nil {
+		return nil, fmt.Errorf("empty session file")
+	}
+	return s, nil
+}
+
+// CalculationResults returns the recorded results, indexed by calculator
+// index. A calculator without a recorded result reports that the recording
+// was interrupted.
+//
+// Returns:
+//   - []orchestration.CalculationResult: The results.
+func (s *Session) CalculationResults() []orchestration.CalculationResult {
+	results := make([]orchestration.CalculationResult, len(s.Results))
+	for i, r := range s.Results {
+		if r == nil {
+			results[i] = orchestration.CalculationResult{
+				Name: s.Header.Algorithms[i],
+				Err:  fmt.Errorf("the recording ends before the result of %s", s.Header.Algorithms[i]),
+			}
+			continue
+		}
+		results[i] = r.CalculationResult()
+	}
+	return results
+}
+
+// EventsOf returns the progress updates of calculator i.
+//
+// Parameters:
+//   - i: The calculator index.
+//
+// Returns:
+//   - []Event: The updates of the calculator, in order of arrival.
+func (s *Session) EventsOf(i int) []Event {
+	var events []Event
+	for _, e := range s.Events {
+		if e.Update.CalculatorIndex == i {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// Feed sends events to progressChan at their recorded times divided by
+// speed, from now; a speed of 0 sends them without delay. The channel is
+// not closed.
+//
+// Parameters:
+//   - ctx: The context canceling the replay.
+//   - events: The updates to send.
+//   - speed: The speed factor.
+//   - progressChan: The channel receiving the updates.
+//
+// Returns:
+//   - error: The context error if the replay was canceled.
+func Feed(ctx context.Context, events []Event, speed float64, progressChan chan<- fibonacci.ProgressUpdate) error {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for _, e := range events {
+		if speed > 0 {
+			if wait := time.Duration(float64(e.Elapsed)/speed) - time.Since(start); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		select {
+		case progressChan <- e.Update:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ctx.Err()
+}
+
+// Replay feeds the progress updates of the session through a progress
+// reporter, as orchestration.ExecuteCalculations does for a run, and returns
+// the recorded results.
+//
+// Parameters:
+//   - ctx: The context canceling the replay.
+//   - s: The session.
+//   - speed: The speed factor (see Feed).
+//   - reporter: The reporter displaying the progress.
+//   - out: The writer for progress output.
+//
+// Returns:
+//   - []orchestration.CalculationResult: The recorded results.
+//   - error: The context error if the replay was canceled.
+func Replay(ctx context.Context, s *Session, speed float64, reporter orchestration.ProgressReporter, out io.Writer) ([]orchestration.CalculationResult, error) {
+	n := len(s.Header.Algorithms)
+	progressChan := make(chan fibonacci.ProgressUpdate, n*orchestration.ProgressBufferMultiplier)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go reporter.DisplayProgress(&wg, progressChan, n, out)
+
+	err := Feed(ctx, s.Events, speed, progressChan)
+	close(progressChan)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return s.CalculationResults(), nil
+}
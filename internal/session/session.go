@@ -0,0 +1,257 @@
+// Package session records calculation runs to session files and replays
+// them.
+//
+// A session file is JSONL: a "session" header describing the run, one
+// "progress" record per progress update, stamped with the time elapsed
+// since the start when the update was emitted, one "result" record per calculator and a final "end" record. The
+// records are written as they happen, so the file of a run that was killed
+// or hung holds every update up to that point. Replay feeds the updates
+// back through any orchestration.ProgressReporter, in real time or
+// accelerated, which reproduces progress reports and gives the user
+// interfaces deterministic fixtures.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// Record types.
+const (
+	TypeHeader   = "session"
+	TypeProgress = "progress"
+	TypeResult   = "result"
+	TypeEnd      = "end"
+)
+
+// MaxValueBits is the largest result, in bits, whose value is recorded
+// (about 315,000 decimal digits). Larger results are recorded by size and
+// digest only.
+const MaxValueBits = 1 << 20
+
+// Header describes the recorded run.
+type Header struct {
+	// N is the index of the calculated Fibonacci number.
+	N uint64 `json:"n"`
+	// Algorithms are the calculator names, indexed by calculator index.
+	Algorithms []string `json:"algorithms"`
+	// Race reports whether the run was a race (--race).
+	Race bool `json:"race,omitempty"`
+	// Thresholds of the run, in bits.
+	Threshold         int `json:"parallel_threshold"`
+	FFTThreshold      int `json:"fft_threshold"`
+	StrassenThreshold int `json:"strassen_threshold"`
+	// Machine that ran the calculations.
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	GoVersion  string `json:"go_version"`
+	Platform   string `json:"platform"`
+}
+
+// NewHeader describes a run of the calculators names with cfg on this
+// machine.
+//
+// Parameters:
+//   - cfg: The configuration of the run.
+//   - names: The calculator names, indexed by calculator index.
+//
+// Returns:
+//   - Header: The session header.
+func NewHeader(cfg config.AppConfig, names []string) Header {
+	return Header{
+		N:                 cfg.N,
+		Algorithms:        names,
+		Race:              cfg.Race,
+		Threshold:         cfg.Threshold,
+		FFTThreshold:      cfg.FFTThreshold,
+		StrassenThreshold: cfg.StrassenThreshold,
+		GOMAXPROCS:        runtime.GOMAXPROCS(0),
+		GoVersion:         runtime.Version(),
+		Platform:          runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+// Result summarizes the outcome of a calculator.
+type Result struct {
+	// Algorithm is the display name of the calculator.
+	Algorithm string `json:"algorithm"`
+	// Duration is the time taken by the calculation.
+	Duration time.Duration `json:"duration_ns"`
+	// Error is the error of a failed calculation.
+	Error string `json:"error,omitempty"`
+	// Bits is the bit length of the result.
+	Bits int `json:"bits,omitempty"`
+	// SHA256 is the digest of the big-endian bytes of the result.
+	SHA256 string `json:"sha256,omitempty"`
+	// Value is the result, when it has at most MaxValueBits bits.
+	Value *big.Int `json:"value,omitempty"`
+	// Progress is the last progress of the calculator (race mode).
+	Progress float64 `json:"progress,omitempty"`
+	// Resources describes the resources consumed by the calculation.
+	Resources orchestration.ResourceUsage `json:"resources"`
+}
+
+// Record is one line of a session file. The fields used depend on the
+// type.
+type Record struct {
+	// Type is the record type (see the Type* constants).
+	Type string `json:"type"`
+	// Timestamp is the time the record was written (UTC).
+	Timestamp time.Time `json:"timestamp"`
+	// Elapsed is the time elapsed since the start of the run.
+	Elapsed time.Duration `json:"elapsed_ns"`
+	// Calculator is the index of the calculator, or -1.
+	Calculator int `json:"calculator"`
+	// Session is the header of a "session" record.
+	Session *Header `json:"session,omitempty"`
+	// Progress is the normalized progress of a "progress" record.
+	Progress float64 `json:"progress,omitempty"`
+	// Step holds the step event of a "progress" record, if any.
+	Step *fibonacci.ProgressEvent `json:"step,omitempty"`
+	// Result is the summary of a "result" record.
+	Result *Result `json:"result,omitempty"`
+}
+
+// ─── Recording ───
+
+// Recorder is a fibonacci.ProgressObserver that writes the progress
+// updates of a run to a session file. It is registered on the progress
+// subject of each calculator with orchestration.WithProgressObserver, which
+// notifies it of every update as it is emitted: each record is written and
+// stamped before the calculation goes on, instead of being read from the
+// progress channel of the display, which drops updates.
+type Recorder struct {
+	now   func() time.Time
+	start time.Time
+
+	mu       sync.Mutex
+	enc      *json.Encoder
+	err      error
+	finished bool
+}
+
+// Verify interface compliance
+var (
+	_ fibonacci.ProgressObserver      = (*Recorder)(nil)
+	_ fibonacci.ProgressEventObserver = (*Recorder)(nil)
+)
+
+// NewRecorder writes the header of a session to w and returns the recorder
+// of its progress. The elapsed times of the records start now.
+//
+// Parameters:
+//   - w: The session file.
+//   - header: The description of the run.
+//
+// Returns:
+//   - *Recorder: The recorder.
+func NewRecorder(w io.Writer, header Header) *Recorder {
+	r := &Recorder{now: time.Now, enc: json.NewEncoder(w)}
+	r.start = r.now()
+	r.write(Record{Type: TypeHeader, Calculator: -1, Session: &header})
+	return r
+}
+
+// Update implements fibonacci.ProgressObserver by recording the progress
+// fraction of a calculator, such as its final 1.0.
+//
+// Parameters:
+//   - calcIndex: The calculator index.
+//   - progress: The normalized progress value (0.0 to 1.0).
+func (r *Recorder) Update(calcIndex int, progress float64) {
+	r.write(Record{Type: TypeProgress, Calculator: calcIndex, Progress: min(progress, 1)})
+}
+
+// OnProgressEvent implements fibonacci.ProgressEventObserver by recording a
+// step event.
+//
+// Parameters:
+//   - event: The step event.
+func (r *Recorder) OnProgressEvent(event fibonacci.ProgressEvent) {
+	event.Progress = min(event.Progress, 1)
+	r.write(Record{Type: TypeProgress, Calculator: event.CalculatorIndex, Progress: event.Progress, Step: &event})
+}
+
+// Finish records the results of the run and the end of the session.
+//
+// Parameters:
+//   - results: The results, indexed by calculator index.
+//
+// Returns:
+//   - error: The first error writing the session, if any.
+func (r *Recorder) Finish(results []orchestration.CalculationResult) error {
+	for i, res := range results {
+		r.write(Record{Type: TypeResult, Calculator: i, Result: summarize(res)})
+	}
+	r.write(Record{Type: TypeEnd, Calculator: -1})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = true
+	return r.err
+}
+
+// write stamps and writes a record. Write errors are kept for Finish and
+// must not stall the calculation. The updates of calculations still running
+// once the session ended, such as the canceled losers of a race, are
+// dropped.
+func (r *Recorder) write(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil || r.finished {
+		return
+	}
+	now := r.now()
+	rec.Timestamp = now.UTC()
+	rec.Elapsed = now.Sub(r.start)
+	r.err = r.enc.Encode(rec)
+}
+
+// summarize returns the summary of a result.
+func summarize(res orchestration.CalculationResult) *Result {
+	s := &Result{
+		Algorithm: res.Name,
+		Duration:  res.Duration,
+		Progress:  res.Progress,
+		Resources: res.Resources,
+	}
+	if res.Err != nil {
+		s.Error = res.Err.Error()
+		return s
+	}
+	if res.Result != nil {
+		s.Bits = res.Result.BitLen()
+		s.SHA256 = orchestration.Digest(res.Result)
+		if s.Bits <= MaxValueBits {
+			s.Value = res.Result
+		}
+	}
+	return s
+}
+
+// CalculationResult returns the result as recorded: without its value when
+// it was too large to be recorded.
+//
+// Returns:
+//   - orchestration.CalculationResult: The recorded result.
+func (s Result) CalculationResult() orchestration.CalculationResult {
+	res := orchestration.CalculationResult{
+		Name:      s.Algorithm,
+		Result:    s.Value,
+		Digest:    s.SHA256,
+		Duration:  s.Duration,
+		Progress:  s.Progress,
+		Resources: s.Resources,
+	}
+	if s.Error != "" {
+		res.Err = errors.New(s.Error)
+	}
+	return res
+}
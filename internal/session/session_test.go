@@ -0,0 +1,219 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// collector is a progress reporter collecting the updates it displays.
+type collector struct {
+	updates *[]fibonacci.ProgressUpdate
+}
+
+func (c collector) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressUpdate, _ int, _ io.Writer) {
+	defer wg.Done()
+	for u := range progressChan {
+		*c.updates = append(*c.updates, u)
+	}
+}
+
+// record records a run of two calculators with the given updates and
+// results, 10ms apart on a fake clock. Updates with a step event are
+// notified as events, the others as progress fractions.
+func record(t *testing.T, updates []fibonacci.ProgressUpdate, results []orchestration.CalculationResult) string {
+	t.Helper()
+	var buf bytes.Buffer
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	cfg := config.AppConfig{N: 1000, Threshold: 4096, FFTThreshold: 500_000}
+	r := NewRecorder(&buf, NewHeader(cfg, []string{"fast", "matrix"}))
+	r.start = clock
+	r.now = func() time.Time {
+		clock = clock.Add(10 * time.Millisecond)
+		return clock
+	}
+
+	for _, u := range updates {
+		if u.Event != nil {
+			r.OnProgressEvent(*u.Event)
+		} else {
+			r.Update(u.CalculatorIndex, u.Value)
+		}
+	}
+	if results != nil {
+		if err := r.Finish(results); err != nil {
+			t.Fatal(err)
+		}
+		// Updates after the end of the session are dropped
+		r.Update(0, 1)
+	}
+	return buf.String()
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	step := &fibonacci.ProgressEvent{CalculatorIndex: 1, Progress: 0.5, Step: 3, TotalSteps: 6, OperandBits: 512, Method: "karatsuba"}
+	updates := []fibonacci.ProgressUpdate{
+		{CalculatorIndex: 0, Value: 0.25},
+		{CalculatorIndex: 1, Value: 0.5, Event: step},
+		{CalculatorIndex: 0, Value: 1},
+	}
+	value, _ := new(big.Int).SetString("43466557686937456435688527675040625802564660517371780402481729089536555417949051890403879840079255169295922593080322634775209689623239873322471161642996440906533187938298969649928516003704476137795166849228875", 10)
+	large := new(big.Int).Lsh(big.NewInt(1), MaxValueBits)
+	content := record(t, updates, []orchestration.CalculationResult{
+		{Name: "fast", Result: value, Duration: time.Millisecond},
+		{Name: "matrix", Result: large, Duration: 2 * time.Millisecond},
+	})
+
+	s, err := Load(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Load: %v\n%s", err, content)
+	}
+	if !s.Complete || s.Header.N != 1000 || s.Header.FFTThreshold != 500_000 || len(s.Header.Algorithms) != 2 {
+		t.Errorf("Unexpected session %+v", s)
+	}
+	if len(s.Events) != 3 || s.Events[0].Elapsed != 10*time.Millisecond || s.Events[2].Elapsed != 30*time.Millisecond {
+		t.Fatalf("Unexpected events %+v", s.Events)
+	}
+	if ev := s.Events[1].Update.Event; ev == nil || *ev != *step {
+		t.Errorf("Expected the step event recorded, got %+v", ev)
+	}
+	if got := s.EventsOf(0); len(got) != 2 || got[1].Update.Value != 1 {
+		t.Errorf("Unexpected events of calculator 0: %+v", got)
+	}
+
+	results := s.CalculationResults()
+	if results[0].Result.Cmp(value) != 0 || results[0].Duration != time.Millisecond {
+		t.Errorf("Unexpected result %+v", results[0])
+	}
+	if results[1].Result != nil || results[1].Err != nil || s.Results[1].Bits != MaxValueBits+1 || len(s.Results[1].SHA256) != 64 {
+		t.Errorf("Expected the large result recorded by size and digest, got %+v", s.Results[1])
+	}
+}
+
+// slowDisplay is a progress reporter slower than the calculation, whose
+// channel drops updates.
+type slowDisplay struct{}
+
+func (slowDisplay) DisplayProgress(wg *sync.WaitGroup, progressChan <-chan fibonacci.ProgressUpdate, _ int, _ io.Writer) {
+	defer wg.Done()
+	for range progressChan {
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// TestRecorder_EveryStep verifies that a recorded run holds every step of
+// the calculation and its final progress, however slow the display.
+func TestRecorder_EveryStep(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	cfg := config.AppConfig{N: 300_000, Threshold: fibonacci.DefaultParallelThreshold, FFTThreshold: fibonacci.DefaultFFTThreshold}
+	r := NewRecorder(&buf, NewHeader(cfg, []string{"fast"}))
+	ctx := orchestration.WithProgressObserver(context.Background(), r)
+	calc := fibonacci.NewCalculator(&fibonacci.OptimizedFastDoubling{})
+	results := orchestration.ExecuteCalculations(ctx, []fibonacci.Calculator{calc}, cfg, slowDisplay{}, io.Discard)
+	if err := r.Finish(results); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := s.EventsOf(0)
+	steps := 0
+	for i, e := range events {
+		if i > 0 && e.Elapsed < events[i-1].Elapsed {
+			t.Errorf("event %d recorded before the previous one", i)
+		}
+		if e.Update.Event != nil {
+			steps++
+			if e.Update.Event.Step != steps {
+				t.Fatalf("event %d is step %d, want %d", i, e.Update.Event.Step, steps)
+			}
+		}
+	}
+	if total := 19; steps != total { // bits.Len64(300_000)
+		t.Errorf("recorded %d steps, want %d", steps, total)
+	}
+	if len(events) == 0 || events[len(events)-1].Update.Value != 1 {
+		t.Errorf("the last update is not the final 1.0: %+v", events)
+	}
+}
+
+func TestLoad_Interrupted(t *testing.T) {
+	content := record(t, []fibonacci.ProgressUpdate{{CalculatorIndex: 1, Value: 0.87}}, nil)
+	s, err := Load(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Complete || len(s.Events) != 1 {
+		t.Errorf("Expected an incomplete session with one event, got %+v", s)
+	}
+	results := s.CalculationResults()
+	if results[1].Name != "matrix" || results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "recording ends before the result") {
+		t.Errorf("Unexpected result %+v", results[1])
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	header := `{"type":"session","calculator":-1,"session":{"n":10,"algorithms":["fast"]}}` + "\n"
+	tests := []struct {
+		name, content, want string
+	}{
+		{"empty", "", "empty session file"},
+		{"no header", `{"type":"progress","calculator":0}`, "not a session file"},
+		{"not JSON", header + "progress\n", "line 2"},
+		{"unknown calculator", header + `{"type":"progress","calculator":3}`, "unknown calculator 3"},
+		{"unknown type", header + `{"type":"pause","calculator":0}`, `unknown record type "pause"`},
+		{"after end", header + `{"type":"end","calculator":-1}` + "\n" + `{"type":"progress","calculator":0}`, "after the end"},
+	}
+	for _, tt := range tests {
+		if _, err := Load(strings.NewReader(tt.content)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error with %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	s := &Session{
+		Header: Header{N: 10, Algorithms: []string{"fast"}},
+		Events: []Event{
+			{Elapsed: 20 * time.Millisecond, Update: fibonacci.ProgressUpdate{Value: 0.5}},
+			{Elapsed: 40 * time.Millisecond, Update: fibonacci.ProgressUpdate{Value: 1}},
+		},
+		Results:  []*Result{{Algorithm: "fast", Value: big.NewInt(55)}},
+		Complete: true,
+	}
+
+	// At twice the speed, the replay takes half the recorded time
+	var displayed []fibonacci.ProgressUpdate
+	start := time.Now()
+	results, err := Replay(context.Background(), s, 2, collector{&displayed}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Replay took %v, expected at least 20ms", elapsed)
+	}
+	if len(displayed) != 2 || displayed[1].Value != 1 || len(results) != 1 || results[0].Result.Int64() != 55 {
+		t.Errorf("Unexpected replay %+v %+v", displayed, results)
+	}
+
+	// A canceled replay stops
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Events[0].Elapsed = time.Hour
+	if _, err := Replay(ctx, s, 1, collector{new([]fibonacci.ProgressUpdate)}, io.Discard); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled replay, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package telemetry configures OpenTelemetry tracing for fibcalc.
+// It installs a global tracer provider backed by a span exporter and the
+// W3C trace-context propagator, so that the spans created by the calculators
+// and the HTTP server are recorded and can be correlated across processes.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ServiceName is the service name attached to every exported span.
+const ServiceName = "fibcalc"
+
+// Trace destinations understood by NewExporter besides file paths.
+const (
+	// DestStdout writes spans to standard output.
+	DestStdout = "stdout"
+	// DestStderr writes spans to standard error.
+	DestStderr = "stderr"
+)
+
+// ShutdownFunc flushes pending spans and releases the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// NewExporter creates a span exporter writing one JSON object per span to
+// the given destination: "stdout" (or "-"), "stderr", or a file path that is
+// created (or truncated).
+//
+// Parameters:
+//   - dest: The trace destination.
+//   - stdout: The writer used for "stdout".
+//   - stderr: The writer used for "stderr".
+//
+// Returns:
+//   - sdktrace.SpanExporter: The exporter.
+//   - io.Closer: Closes the destination file after shutdown; nil for streams.
+//   - error: An error if the destination cannot be opened.
+func NewExporter(dest string, stdout, stderr io.Writer) (sdktrace.SpanExporter, io.Closer, error) {
+	var w io.Writer
+	var closer io.Closer
+	switch dest {
+	case "":
+		return nil, nil, errors.New("empty trace destination")
+	case DestStdout, "-":
+		w = stdout
+	case DestStderr:
+		w = stderr
+	default:
+		f, err := os.Create(dest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot create trace file: %w", err)
+		}
+		w, closer = f, f
+	}
+
+	exp, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, nil, fmt.Errorf("cannot create trace exporter: %w", err)
+	}
+	return exp, closer, nil
+}
+
+// Setup installs a global tracer provider exporting to dest (see NewExporter)
+// together with the W3C trace-context and baggage propagators.
+//
+// Parameters:
+//   - dest: The trace destination.
+//   - stdout: The writer used for "stdout".
+//   - stderr: The writer used for "stderr".
+//
+// Returns:
+//   - ShutdownFunc: Flushes the spans and closes the destination; must be
+//     called before the program exits.
+//   - error: An error if the exporter cannot be created.
+func Setup(dest string, stdout, stderr io.Writer) (ShutdownFunc, error) {
+	exp, closer, err := NewExporter(dest, stdout, stderr)
+	if err != nil {
+		return nil, err
+	}
+	shutdown := Install(exp)
+	return func(ctx context.Context) error {
+		err := shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// Install registers a global tracer provider that batches spans to exp, and
+// the W3C trace-context and baggage propagators. It is the extension point
+// for other exporters (e.g. OTLP) built by the caller.
+//
+// Parameters:
+//   - exp: The span exporter.
+//
+// Returns:
+//   - ShutdownFunc: Flushes pending spans and shuts the exporter down.
+func Install(exp sdktrace.SpanExporter) ShutdownFunc {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown
+}
@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+// TestSetupFileExporter verifies that spans end up in the trace file as JSON.
+// It is not parallel because it replaces the global tracer provider.
+func TestSetupFileExporter(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	shutdown, err := Setup(path, nil, nil)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "TestSpan")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read trace file: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		t.Fatalf("trace file is not JSON: %v\n%s", err, data)
+	}
+	if decoded["Name"] != "TestSpan" {
+		t.Errorf("expected span name TestSpan, got %v", decoded["Name"])
+	}
+	if !strings.Contains(string(data), ServiceName) {
+		t.Errorf("expected service name %q in trace output", ServiceName)
+	}
+}
+
+// TestNewExporter verifies destination handling.
+func TestNewExporter(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	for _, dest := range []string{DestStdout, "-", DestStderr} {
+		exp, closer, err := NewExporter(dest, &stdout, &stderr)
+		if err != nil || exp == nil || closer != nil {
+			t.Errorf("NewExporter(%q) = %v, %v, %v", dest, exp, closer, err)
+		}
+	}
+
+	if _, _, err := NewExporter("", nil, nil); err == nil {
+		t.Error("expected error for empty destination")
+	}
+	if _, _, err := NewExporter(filepath.Join(t.TempDir(), "missing", "trace.json"), nil, nil); err == nil {
+		t.Error("expected error for unwritable destination")
+	}
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// Backend runs the calculations of the dashboard: the local calculators, or
+// a fibcalc server.
+type Backend interface {
+	// Describe returns a short description of where calculations run, for
+	// the header.
+	Describe() string
+	// Algorithms returns the names of the algorithms, in table order.
+	Algorithms() []string
+	// Calculate computes F(n) with the algorithm at index i. It sends the
+	// progress updates the backend reports to progressChan and closes it
+	// on return.
+	Calculate(ctx context.Context, i int, n uint64, cfg config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) orchestration.CalculationResult
+	// Compare computes F(n) with every algorithm, reporting progress like
+	// Calculate. Results are in table order.
+	Compare(ctx context.Context, n uint64, cfg config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) []orchestration.CalculationResult
+}
+
+// MetricsBackend is implemented by backends reporting the metrics of the
+// process running the calculations. The dashboard shows them instead of
+// the local system telemetry.
+type MetricsBackend interface {
+	Backend
+	// Metrics fetches the current metrics.
+	Metrics(ctx context.Context) (ServerMetrics, error)
+}
+
+// ─── Local Backend ───
+
+// localBackend runs the calculations in this process.
+type localBackend struct {
+	calculators []fibonacci.Calculator
+}
+
+// NewLocalBackend returns a backend running the calculators in this process.
+//
+// Parameters:
+//   - calculators: The calculators, in table order.
+//
+// Returns:
+//   - Backend: The local backend.
+func NewLocalBackend(calculators []fibonacci.Calculator) Backend {
+	return localBackend{calculators: calculators}
+}
+
+// Describe implements Backend.
+func (b localBackend) Describe() string {
+	return "local"
+}
+
+// Algorithms implements Backend.
+func (b localBackend) Algorithms() []string {
+	names := make([]string, len(b.calculators))
+	for i, c := range b.calculators {
+		names[i] = c.Name()
+	}
+	return names
+}
+
+// Calculate implements Backend, measuring the resources the calculation
+// consumes.
+func (b localBackend) Calculate(ctx context.Context, i int, n uint64, cfg config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) orchestration.CalculationResult {
+	defer close(progressChan)
+	calc := b.calculators[i]
+	start := time.Now()
+	meter := orchestration.StartResourceMeter()
+	result, err := calc.Calculate(ctx, progressChan, i, n, cfg.ToCalculationOptions())
+	duration := time.Since(start)
+	return orchestration.CalculationResult{
+		Name:      calc.Name(),
+		Result:    result,
+		Duration:  duration,
+		Err:       err,
+		Resources: meter.Stop(),
+	}
+}
+
+// Compare implements Backend with the orchestration layer.
+func (b localBackend) Compare(ctx context.Context, n uint64, cfg config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) []orchestration.CalculationResult {
+	// Use a custom progress reporter that forwards to our channel
+	reporter := &channelProgressReporter{ch: progressChan}
+	cfg.N = n
+	return orchestration.ExecuteCalculations(ctx, b.calculators, cfg, reporter, nil)
+}
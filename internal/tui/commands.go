@@ -10,7 +10,6 @@ import (
 
 	"github.com/agbru/fibcalc/internal/config"
 	"github.com/agbru/fibcalc/internal/fibonacci"
-	"github.com/agbru/fibcalc/internal/orchestration"
 )
 
 // listenForProgress creates a command that listens for progress updates.
@@ -32,47 +31,45 @@ func tickCmd(d time.Duration) tea.Cmd {
 	})
 }
 
-// runCalculation runs a single calculation and returns the result.
-func runCalculation(ctx context.Context, calc fibonacci.Calculator, n uint64, opts fibonacci.Options, progressChan chan<- fibonacci.ProgressUpdate, calcIndex int) tea.Cmd {
+// runCalculation runs a single calculation on the backend and returns the
+// result. A positive timeout bounds the calculation.
+func runCalculation(ctx context.Context, backend Backend, i int, n uint64, cfg config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) tea.Cmd {
 	return func() tea.Msg {
-		start := time.Now()
-		result, err := calc.Calculate(ctx, progressChan, calcIndex, n, opts)
-		duration := time.Since(start)
-
-		close(progressChan)
-
-		if err != nil {
-			return ErrorMsg{Err: err}
+		ctx, cancel := withTimeout(ctx, cfg.Timeout)
+		defer cancel()
+		result := backend.Calculate(ctx, i, n, cfg, progressChan)
+		if result.Err != nil {
+			return ErrorMsg{Err: result.Err}
 		}
-
 		return CalculationResultMsg{
-			Result: orchestration.CalculationResult{
-				Name:     calc.Name(),
-				Result:   result,
-				Duration: duration,
-				Err:      nil,
-			},
+			Result:   result,
 			N:        n,
-			Duration: duration,
+			Duration: result.Duration,
 		}
 	}
 }
 
-// runComparison runs all calculators and returns comparison results.
-func runComparison(ctx context.Context, calculators []fibonacci.Calculator, cfg config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) tea.Cmd {
+// runComparison runs all algorithms on the backend and returns comparison
+// results, within the timeout of the configuration.
+func runComparison(ctx context.Context, backend Backend, n uint64, cfg config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) tea.Cmd {
 	return func() tea.Msg {
-		// Use a custom progress reporter that forwards to our channel
-		reporter := &channelProgressReporter{ch: progressChan}
-
-		results := orchestration.ExecuteCalculations(ctx, calculators, cfg, reporter, nil)
-
+		ctx, cancel := withTimeout(ctx, cfg.Timeout)
+		defer cancel()
 		return ComparisonResultsMsg{
-			Results: results,
-			N:       cfg.N,
+			Results: backend.Compare(ctx, n, cfg, progressChan),
+			N:       n,
 		}
 	}
 }
 
+// withTimeout bounds ctx by timeout, if positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // channelProgressReporter forwards progress updates to a channel.
 type channelProgressReporter struct {
 	ch chan<- fibonacci.ProgressUpdate
@@ -18,10 +18,10 @@ import (
 // DashboardModel is the root model for the single-screen HTOP-style TUI.
 type DashboardModel struct {
 	// Configuration
-	config      config.AppConfig
-	calculators []fibonacci.Calculator
-	ctx         context.Context
-	cancel      context.CancelFunc
+	config  config.AppConfig
+	backend Backend
+	ctx     context.Context
+	cancel  context.CancelFunc
 
 	// UI Framework
 	keys   KeyMap
@@ -36,6 +36,11 @@ type DashboardModel struct {
 	algorithms  AlgorithmTableState
 	calculation CalculationState
 	results     ResultsDisplayState
+	viewer      DigitViewerState
+	telemetry   TelemetryState
+	server      ServerState
+	history     HistoryState
+	settings    SettingsFormState
 
 	// Focus and overlays
 	focusedSection Section
@@ -58,8 +63,11 @@ type AlgorithmTableState struct {
 	names      []string
 	progresses []float64
 	durations  []time.Duration
-	statuses   []AlgoStatus
-	cursor     int
+	// steps holds the most recent step event of each algorithm (nil until
+	// the calculation reports one).
+	steps    []*fibonacci.ProgressEvent
+	statuses []AlgoStatus
+	cursor   int
 }
 
 // CalculationState holds the active calculation state.
@@ -77,35 +85,54 @@ type ResultsDisplayState struct {
 	results     []orchestration.CalculationResult
 	n           uint64
 	showHex     bool
-	showFull    bool
 	showDetails bool // When false, only show bits count; when true, show full result
 	cursor      int
 	consistent  bool
 }
 
-// NewDashboardModel creates a new dashboard model.
+// clearStep forgets the last step event of algorithm i.
+func (a *AlgorithmTableState) clearStep(i int) {
+	if i < len(a.steps) {
+		a.steps[i] = nil
+	}
+}
+
+// NewDashboardModel creates a new dashboard model running the calculators
+// in this process.
 func NewDashboardModel(cfg config.AppConfig, calculators []fibonacci.Calculator) DashboardModel {
+	return NewDashboardModelWithBackend(cfg, NewLocalBackend(calculators))
+}
+
+// NewDashboardModelWithBackend creates a new dashboard model running the
+// calculations on a backend.
+//
+// Parameters:
+//   - cfg: The application configuration.
+//   - backend: The local calculators or a fibcalc server.
+//
+// Returns:
+//   - DashboardModel: The dashboard model.
+func NewDashboardModelWithBackend(cfg config.AppConfig, backend Backend) DashboardModel {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Get algorithm names
-	algoNames := make([]string, len(calculators))
-	progresses := make([]float64, len(calculators))
-	durations := make([]time.Duration, len(calculators))
-	statuses := make([]AlgoStatus, len(calculators))
+	algoNames := backend.Algorithms()
+	progresses := make([]float64, len(algoNames))
+	durations := make([]time.Duration, len(algoNames))
+	statuses := make([]AlgoStatus, len(algoNames))
 
-	for i, c := range calculators {
-		algoNames[i] = c.Name()
+	for i := range algoNames {
 		statuses[i] = StatusIdle
 	}
 
 	return DashboardModel{
-		config:      cfg,
-		calculators: calculators,
-		ctx:         ctx,
-		cancel:      cancel,
-		keys:        DefaultKeyMap(),
-		styles:      DefaultStyles(),
-		help:        help.New(),
+		config:  cfg,
+		backend: backend,
+		ctx:     ctx,
+		cancel:  cancel,
+		keys:    DefaultKeyMap(),
+		styles:  DefaultStyles(),
+		help:    help.New(),
 		input: InputState{
 			n:           fmt.Sprintf("%d", cfg.N),
 			cursorPos:   len(fmt.Sprintf("%d", cfg.N)),
@@ -115,9 +142,14 @@ func NewDashboardModel(cfg config.AppConfig, calculators []fibonacci.Calculator)
 			names:      algoNames,
 			progresses: progresses,
 			durations:  durations,
+			steps:      make([]*fibonacci.ProgressEvent, len(algoNames)),
 			statuses:   statuses,
 			cursor:     0,
 		},
+		viewer:         newDigitViewer(),
+		telemetry:      newTelemetry(len(algoNames), newSystemSampler()),
+		history:        newHistory(),
+		settings:       newSettingsForm(),
 		focusedSection: SectionInput,
 	}
 }
@@ -145,18 +177,63 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleCalculationResult(msg)
 	case ComparisonResultsMsg:
 		return m.handleComparisonResults(msg)
+	case DigitTextMsg:
+		return m.handleDigitText(msg)
+	case ClipboardMsg:
+		return m.handleClipboard(msg)
+	case HistoryRecordedMsg:
+		return m.handleHistoryRecorded(msg)
+	case HistorySavedMsg:
+		return m.handleHistorySaved(msg)
+	case HistoryExportedMsg:
+		return m.handleHistoryExported(msg)
+	case CalibrationResultMsg:
+		return m.handleCalibrationResult(msg)
+	case ProfileSavedMsg:
+		return m.handleProfileSaved(msg)
+	case ServerMetricsMsg:
+		return m.handleServerMetrics(msg)
 	case ErrorMsg:
-		m.lastError = msg.Err
-		return m, nil
+		return m.handleError(msg)
 	case ThemeChangedMsg:
 		return m.handleThemeChanged(msg)
 	case TickMsg:
+		// A remote dashboard shows the server metrics instead of this
+		// machine
+		if _, remote := m.serverBackend(); remote {
+			m, cmd := m.pollServer(msg.Time)
+			return m, tea.Batch(cmd, tickCmd(100*time.Millisecond))
+		}
+		m.telemetry.update(msg.Time, m.algorithms)
 		return m, tickCmd(100 * time.Millisecond)
 	}
 	return m, nil
 }
 
 func (m DashboardModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The digit viewer handles all keys but Ctrl+C
+	if m.viewer.active {
+		if msg.Type == tea.KeyCtrlC {
+			m.cancel()
+			return m, tea.Quit
+		}
+		return m.updateViewer(msg)
+	}
+
+	// The settings form handles all keys but Ctrl+C
+	if m.settings.active {
+		if msg.Type == tea.KeyCtrlC {
+			m.cancel()
+			return m, tea.Quit
+		}
+		return m.updateSettings(msg)
+	}
+
+	// The export prompt of the history handles all keys
+	if m.history.exporting {
+		return m.updateHistoryPrompt(msg)
+	}
+
 	// Help overlay toggle
 	if key.Matches(msg, m.keys.Help) {
 		m.helpOverlay = !m.helpOverlay
@@ -196,11 +273,15 @@ func (m DashboardModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.results.showHex = !m.results.showHex
 		return m, nil
 	case key.Matches(msg, m.keys.Full):
-		m.results.showFull = !m.results.showFull
-		return m, nil
+		return m.openViewer()
 	case key.Matches(msg, m.keys.Details):
 		m.results.showDetails = !m.results.showDetails
 		return m, nil
+	case key.Matches(msg, m.keys.Telemetry):
+		m.telemetry.visible = !m.telemetry.visible
+		return m, nil
+	case key.Matches(msg, m.keys.Settings):
+		return m.openSettings()
 	case key.Matches(msg, m.keys.Save):
 		return m.saveResult()
 	}
@@ -214,6 +295,7 @@ func (m DashboardModel) handleWindowSizeMsg(msg tea.WindowSizeMsg) (tea.Model, t
 	m.height = msg.Height
 	m.ready = true
 	m.help.Width = msg.Width
+	m.viewer.resize(msg.Width, msg.Height)
 	return m, nil
 }
 
@@ -221,6 +303,9 @@ func (m DashboardModel) handleProgressUpdate(msg ProgressMsg) (tea.Model, tea.Cm
 	idx := msg.Update.CalculatorIndex
 	if idx >= 0 && idx < len(m.algorithms.progresses) {
 		m.algorithms.progresses[idx] = msg.Update.Value
+		if msg.Update.Event != nil && idx < len(m.algorithms.steps) {
+			m.algorithms.steps[idx] = msg.Update.Event
+		}
 	}
 	// Continue listening for progress
 	if m.calculation.progressChan != nil && m.calculation.active {
@@ -251,7 +336,7 @@ func (m DashboardModel) handleCalculationResult(msg CalculationResultMsg) (tea.M
 	m.focusedSection = SectionResults
 	m.input.inputActive = false
 
-	return m, nil
+	return m, recordHistory(m.results.results, msg.N)
 }
 
 func (m DashboardModel) handleComparisonResults(msg ComparisonResultsMsg) (tea.Model, tea.Cmd) {
@@ -282,6 +367,20 @@ func (m DashboardModel) handleComparisonResults(msg ComparisonResultsMsg) (tea.M
 	m.focusedSection = SectionResults
 	m.input.inputActive = false
 
+	return m, recordHistory(msg.Results, msg.N)
+}
+
+// handleError reports an error; a failed or timed-out calculation ends.
+func (m DashboardModel) handleError(msg ErrorMsg) (tea.Model, tea.Cmd) {
+	m.lastError = msg.Err
+	if m.calculation.active {
+		m.calculation.active = false
+		for i := range m.algorithms.statuses {
+			if m.algorithms.statuses[i] == StatusRunning {
+				m.algorithms.statuses[i] = StatusError
+			}
+		}
+	}
 	return m, nil
 }
 
@@ -302,6 +401,7 @@ func (m DashboardModel) handleEscape() (tea.Model, tea.Cmd) {
 			if m.algorithms.statuses[i] == StatusRunning {
 				m.algorithms.statuses[i] = StatusIdle
 				m.algorithms.progresses[i] = 0
+				m.algorithms.clearStep(i)
 			}
 		}
 		return m, nil
@@ -332,6 +432,8 @@ func (m DashboardModel) updateFocusedSection(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		return m.updateAlgorithms(msg)
 	case SectionResults:
 		return m.updateResults(msg)
+	case SectionHistory:
+		return m.updateHistory(msg)
 	}
 	return m, nil
 }
@@ -347,6 +449,14 @@ func (m DashboardModel) View() string {
 		return "Loading..."
 	}
 
+	if m.viewer.active {
+		return m.renderViewer()
+	}
+
+	if m.settings.active {
+		return m.renderSettings()
+	}
+
 	// If help overlay is shown, render it on top
 	if m.helpOverlay {
 		return m.renderHelpOverlay()
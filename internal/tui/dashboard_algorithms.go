@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -93,9 +95,30 @@ func (m DashboardModel) renderAlgorithmTable() string {
 		b.WriteString("\n")
 	}
 
+	// Step details of the running calculations
+	for i, name := range m.algorithms.names {
+		if m.algorithms.statuses[i] != StatusRunning || i >= len(m.algorithms.steps) || m.algorithms.steps[i] == nil {
+			continue
+		}
+		line := fmt.Sprintf("  %s: %s", truncateString(name, colWidthName), formatStepDetail(*m.algorithms.steps[i]))
+		b.WriteString(m.styles.Muted.Render(line))
+		b.WriteString("\n")
+	}
+
 	return b.String()
 }
 
+// formatStepDetail formats the details of the latest calculation step.
+func formatStepDetail(ev fibonacci.ProgressEvent) string {
+	method := ev.Method
+	if ev.Parallel {
+		method += " ∥"
+	}
+	return fmt.Sprintf("step %d/%d · %s-bit operands · %s · %s · %s",
+		ev.Step, ev.TotalSteps, formatNumber(ev.OperandBits), method,
+		formatDuration(ev.StepDuration), cli.FormatStepAllocated(ev))
+}
+
 // renderAlgorithmRow renders a single algorithm row.
 func (m DashboardModel) renderAlgorithmRow(idx int, name string) string {
 	progress := m.algorithms.progresses[idx]
@@ -0,0 +1,396 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// maxHistoryRows is the number of history entries displayed at once.
+const maxHistoryRows = 6
+
+// defaultExportPath is the file proposed by the export prompt.
+const defaultExportPath = "fibcalc-history.csv"
+
+// HistoryState holds the history section state: the past calculations,
+// newest first, persisted to a file.
+type HistoryState struct {
+	path    string // history file, or "" to keep the history in memory
+	entries []HistoryEntry
+	nextID  int
+	cursor  int
+	marks   []int // IDs of the entries to compare, at most two
+
+	exporting bool // whether the export prompt is focused
+	input     textinput.Model
+	status    string
+}
+
+// newHistory returns an empty history kept in memory.
+func newHistory() HistoryState {
+	input := textinput.New()
+	input.CharLimit = 256
+	return HistoryState{input: input, nextID: 1}
+}
+
+// withHistory loads the history file and saves the calculations to it. An
+// unreadable file is reported and left untouched: the history of the
+// session is then kept in memory.
+func (m DashboardModel) withHistory(path string) DashboardModel {
+	entries, err := LoadHistory(path)
+	if err != nil {
+		m.lastError = fmt.Errorf("%w (history not saved)", err)
+		return m
+	}
+	m.history.path = path
+	m.history.entries = entries
+	for _, e := range entries {
+		m.history.nextID = max(m.history.nextID, e.ID+1)
+	}
+	return m
+}
+
+// selected returns the entry under the cursor.
+func (h *HistoryState) selected() (HistoryEntry, bool) {
+	if h.cursor < 0 || h.cursor >= len(h.entries) {
+		return HistoryEntry{}, false
+	}
+	return h.entries[h.cursor], true
+}
+
+// find returns the entry with the given ID.
+func (h *HistoryState) find(id int) (HistoryEntry, bool) {
+	for _, e := range h.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return HistoryEntry{}, false
+}
+
+// ─── Recording ───
+
+// recordHistory computes the history entries of results in the background:
+// the digit count and digest of a large value take a while.
+func recordHistory(results []orchestration.CalculationResult, n uint64) tea.Cmd {
+	return func() tea.Msg {
+		return HistoryRecordedMsg{Entries: newHistoryEntries(results, n, time.Now())}
+	}
+}
+
+// handleHistoryRecorded adds the entries of a calculation to the history
+// and saves it.
+func (m DashboardModel) handleHistoryRecorded(msg HistoryRecordedMsg) (tea.Model, tea.Cmd) {
+	if len(msg.Entries) == 0 {
+		return m, nil
+	}
+	h := &m.history
+	// Newest first: the last entry gets the highest ID and comes first
+	added := make([]HistoryEntry, len(msg.Entries))
+	for i, e := range msg.Entries {
+		e.ID = h.nextID
+		h.nextID++
+		added[len(added)-1-i] = e
+	}
+	h.entries = append(added, h.entries...)
+	if len(h.entries) > MaxHistoryEntries {
+		h.entries = h.entries[:MaxHistoryEntries]
+	}
+	h.cursor = 0
+	return m, h.save()
+}
+
+// save writes the history file in the background.
+func (h *HistoryState) save() tea.Cmd {
+	if h.path == "" {
+		return nil
+	}
+	path, entries := h.path, slices.Clone(h.entries)
+	return func() tea.Msg {
+		return HistorySavedMsg{Err: SaveHistory(path, entries)}
+	}
+}
+
+// handleHistorySaved reports a history that could not be saved.
+func (m DashboardModel) handleHistorySaved(msg HistorySavedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.history.status = fmt.Sprintf("History not saved: %v", msg.Err)
+	}
+	return m, nil
+}
+
+// handleHistoryExported reports the outcome of an export.
+func (m DashboardModel) handleHistoryExported(msg HistoryExportedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.history.status = fmt.Sprintf("Export failed: %v", msg.Err)
+	} else {
+		m.history.status = fmt.Sprintf("Exported %d entries to %s", msg.Entries, msg.Path)
+	}
+	return m, nil
+}
+
+// ─── Keys ───
+
+// updateHistory handles key messages for the history section.
+func (m DashboardModel) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	h := &m.history
+	h.status = ""
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		h.cursor = max(0, h.cursor-1)
+	case key.Matches(msg, m.keys.Down):
+		h.cursor = max(0, min(h.cursor+1, len(h.entries)-1))
+	case key.Matches(msg, m.keys.Enter), key.Matches(msg, m.keys.Rerun):
+		return m.rerunHistory()
+	case key.Matches(msg, m.keys.Mark):
+		if e, ok := h.selected(); ok {
+			h.toggleMark(e.ID)
+		}
+	case key.Matches(msg, m.keys.Delete):
+		return m, h.deleteSelected()
+	case key.Matches(msg, m.keys.Export):
+		if len(h.entries) == 0 {
+			h.status = "Nothing to export"
+			return m, nil
+		}
+		h.exporting = true
+		h.input.Prompt = "Export to (.csv or .json): "
+		h.input.SetValue(defaultExportPath)
+		h.input.CursorEnd()
+		return m, h.input.Focus()
+	}
+	return m, nil
+}
+
+// updateHistoryPrompt handles key messages while the export prompt is
+// focused.
+func (m DashboardModel) updateHistoryPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	h := &m.history
+	switch msg.Type {
+	case tea.KeyEsc:
+		h.exporting = false
+		h.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		h.exporting = false
+		h.input.Blur()
+		path := strings.TrimSpace(h.input.Value())
+		if path == "" {
+			return m, nil
+		}
+		entries := slices.Clone(h.entries)
+		return m, func() tea.Msg {
+			return HistoryExportedMsg{Path: path, Entries: len(entries), Err: ExportHistory(path, entries)}
+		}
+	}
+	var cmd tea.Cmd
+	h.input, cmd = h.input.Update(msg)
+	return m, cmd
+}
+
+// rerunHistory calculates the selected entry again, with the same index and
+// algorithm.
+func (m DashboardModel) rerunHistory() (tea.Model, tea.Cmd) {
+	e, ok := m.history.selected()
+	if !ok {
+		return m, nil
+	}
+	idx := slices.Index(m.algorithms.names, e.Algorithm)
+	if idx < 0 {
+		m.lastError = fmt.Errorf("algorithm not available: %s", e.Algorithm)
+		return m, nil
+	}
+	m.algorithms.cursor = idx
+	m.input.n = fmt.Sprintf("%d", e.N)
+	m.input.cursorPos = len(m.input.n)
+	return m.startSingleCalculation()
+}
+
+// toggleMark marks an entry for comparison, or unmarks it. Marking a third
+// entry unmarks the oldest mark.
+func (h *HistoryState) toggleMark(id int) {
+	if i := slices.Index(h.marks, id); i >= 0 {
+		h.marks = slices.Delete(h.marks, i, i+1)
+		return
+	}
+	h.marks = append(h.marks, id)
+	if len(h.marks) > 2 {
+		h.marks = h.marks[1:]
+	}
+}
+
+// deleteSelected removes the entry under the cursor and saves the history.
+func (h *HistoryState) deleteSelected() tea.Cmd {
+	e, ok := h.selected()
+	if !ok {
+		return nil
+	}
+	h.entries = slices.Delete(h.entries, h.cursor, h.cursor+1)
+	if i := slices.Index(h.marks, e.ID); i >= 0 {
+		h.marks = slices.Delete(h.marks, i, i+1)
+	}
+	h.cursor = max(0, min(h.cursor, len(h.entries)-1))
+	h.status = fmt.Sprintf("Deleted #%d", e.ID)
+	return h.save()
+}
+
+// ─── Rendering ───
+
+// Column widths of the history table.
+const (
+	colWidthHistID     = 5
+	colWidthHistTime   = 17
+	colWidthHistN      = 14
+	colWidthHistAlgo   = 22
+	colWidthHistDigits = 12
+	colWidthHistDigest = 12
+)
+
+// renderHistorySection renders the history section of the dashboard.
+func (m DashboardModel) renderHistorySection() string {
+	h := m.history
+	var b strings.Builder
+
+	titleStyle := m.styles.BoxTitle
+	if m.focusedSection == SectionHistory {
+		titleStyle = titleStyle.Foreground(m.styles.Primary.GetForeground())
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("HISTORY (%d)", len(h.entries))))
+	b.WriteString("\n\n")
+
+	if len(h.entries) == 0 {
+		b.WriteString(m.styles.Muted.Render("  No calculations yet. Results are recorded here."))
+	} else {
+		header := fmt.Sprintf("  %-*s %-*s %*s  %-*s %*s %*s  %s",
+			colWidthHistID+2, "#", colWidthHistTime, "When", colWidthHistN, "N",
+			colWidthHistAlgo, "Algorithm", colWidthDur, "Duration", colWidthHistDigits, "Digits", "SHA-256")
+		b.WriteString(m.styles.TableHeader.Render(header))
+		b.WriteString("\n")
+
+		// Window of rows around the cursor
+		start := max(0, min(h.cursor-maxHistoryRows/2, len(h.entries)-maxHistoryRows))
+		end := min(start+maxHistoryRows, len(h.entries))
+		for i := start; i < end; i++ {
+			b.WriteString(m.renderHistoryRow(i))
+			b.WriteString("\n")
+		}
+		if len(h.entries) > maxHistoryRows {
+			b.WriteString(m.styles.Muted.Render(fmt.Sprintf("  %d-%d of %d", start+1, end, len(h.entries))))
+			b.WriteString("\n")
+		}
+
+		if len(h.marks) == 2 {
+			a, okA := h.find(h.marks[0])
+			c, okC := h.find(h.marks[1])
+			if okA && okC {
+				b.WriteString("\n")
+				b.WriteString(m.renderHistoryComparison(a, c))
+			}
+		}
+	}
+
+	switch {
+	case h.exporting:
+		b.WriteString("\n\n  ")
+		b.WriteString(h.input.View())
+	case h.status != "":
+		b.WriteString("\n\n  ")
+		b.WriteString(m.styles.Info.Render(h.status))
+	case m.focusedSection == SectionHistory && len(h.entries) > 0:
+		b.WriteString("\n\n  ")
+		for _, hint := range [][2]string{{"[Enter]", " Re-run  "}, {"[Space]", " Mark for comparison  "}, {"[Del]", " Delete  "}, {"[e]", " Export"}} {
+			b.WriteString(m.styles.HelpKey.Render(hint[0]))
+			b.WriteString(m.styles.HelpDesc.Render(hint[1]))
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderHistoryRow renders the history entry at index i.
+func (m DashboardModel) renderHistoryRow(i int) string {
+	h := m.history
+	e := h.entries[i]
+
+	mark := " "
+	if slices.Contains(h.marks, e.ID) {
+		mark = "●"
+	}
+	digest := e.SHA256
+	if len(digest) > colWidthHistDigest {
+		digest = digest[:colWidthHistDigest]
+	}
+	row := fmt.Sprintf("  %s %-*s %-*s %*s  %-*s %*s %*s  %s",
+		mark, colWidthHistID, fmt.Sprintf("#%d", e.ID),
+		colWidthHistTime, e.Time.Local().Format("2006-01-02 15:04"),
+		colWidthHistN, formatNumber64(e.N),
+		colWidthHistAlgo, truncateString(e.Algorithm, colWidthHistAlgo),
+		colWidthDur, formatDuration(e.Duration),
+		colWidthHistDigits, formatNumber(e.Digits),
+		digest)
+
+	style := m.styles.TableRow
+	if i%2 == 1 {
+		style = m.styles.TableRowAlt
+	}
+	if m.focusedSection == SectionHistory && i == h.cursor {
+		style = m.styles.MenuItemActive
+	}
+	return style.Render(row)
+}
+
+// renderHistoryComparison renders two history entries side by side, with
+// whether they computed the same value and their speed ratio.
+func (m DashboardModel) renderHistoryComparison(a, b HistoryEntry) string {
+	var s strings.Builder
+	s.WriteString(m.styles.BoxTitle.Render(fmt.Sprintf("  COMPARE #%d vs #%d", a.ID, b.ID)))
+	s.WriteString("\n")
+
+	line := func(name, x, y string) {
+		s.WriteString("  ")
+		s.WriteString(m.styles.ResultLabel.Width(12).Render(name))
+		s.WriteString(m.styles.ResultValue.Width(36).Render(x))
+		s.WriteString(m.styles.ResultValue.Render(y))
+		s.WriteString("\n")
+	}
+	line("", fmt.Sprintf("#%d", a.ID), fmt.Sprintf("#%d", b.ID))
+	line("When", a.Time.Local().Format("2006-01-02 15:04:05"), b.Time.Local().Format("2006-01-02 15:04:05"))
+	line("N", formatNumber64(a.N), formatNumber64(b.N))
+	line("Algorithm", truncateString(a.Algorithm, 34), truncateString(b.Algorithm, 34))
+	line("Duration", formatDuration(a.Duration), formatDuration(b.Duration))
+	line("Digits", formatNumber(a.Digits), formatNumber(b.Digits))
+	line("SHA-256", a.SHA256[:min(len(a.SHA256), 32)], b.SHA256[:min(len(b.SHA256), 32)])
+
+	s.WriteString("  ")
+	switch {
+	case a.N != b.N:
+		s.WriteString(m.styles.Muted.Render(fmt.Sprintf("Different indices: F(%d) and F(%d)", a.N, b.N)))
+	case a.SHA256 != b.SHA256:
+		s.WriteString(m.styles.Error.Render("✗ The results differ"))
+	default:
+		s.WriteString(m.styles.Success.Render("✓ Same result"))
+	}
+	if a.N == b.N && a.Duration > 0 && b.Duration > 0 && a.Duration != b.Duration {
+		fast, slow := a, b
+		if b.Duration < a.Duration {
+			fast, slow = b, a
+		}
+		s.WriteString(m.styles.Muted.Render(fmt.Sprintf(" · #%d is %.2fx faster", fast.ID, float64(slow.Duration)/float64(fast.Duration))))
+	}
+	return s.String()
+}
+
+// formatNumber64 formats an unsigned number with thousand separators.
+func formatNumber64(n uint64) string {
+	if n > uint64(^uint(0)>>1) {
+		return fmt.Sprintf("%d", n)
+	}
+	return formatNumber(int(n))
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// newHistoryModel returns a dashboard saving its history to a file in a
+// temporary directory.
+func newHistoryModel(t *testing.T) (DashboardModel, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "history.json")
+	m := NewDashboardModel(config.AppConfig{N: 100}, newMockCalculators()).withHistory(path)
+	m.ready, m.width, m.height = true, 140, 80
+	return m, path
+}
+
+// runCmd runs a command and feeds its message back to the model.
+func runCmd(t *testing.T, m DashboardModel, cmd tea.Cmd) DashboardModel {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("Expected a command")
+	}
+	m, _ = press(t, m, cmd())
+	return m
+}
+
+func TestDashboardModel_History(t *testing.T) {
+	m, path := newHistoryModel(t)
+
+	// A comparison records its successful results, and a calculation its result
+	m, cmd := press(t, m, ComparisonResultsMsg{N: 10, Results: []orchestration.CalculationResult{
+		{Name: "fast-doubling", Result: big.NewInt(55), Duration: 2 * time.Millisecond},
+		{Name: "matrix", Result: big.NewInt(55), Duration: 5 * time.Millisecond},
+	}})
+	m, cmd = press(t, m, cmd())
+	m = runCmd(t, m, cmd)
+	m, cmd = press(t, m, CalculationResultMsg{N: 12, Result: orchestration.CalculationResult{Name: "matrix", Result: big.NewInt(144), Duration: time.Millisecond}})
+	m, cmd = press(t, m, cmd())
+	m = runCmd(t, m, cmd)
+
+	saved, err := LoadHistory(path)
+	if err != nil || len(saved) != 3 || saved[0].ID != 3 || saved[0].N != 12 || saved[2].Algorithm != "fast-doubling" {
+		t.Fatalf("Unexpected saved history %+v, %v", saved, err)
+	}
+
+	// IDs continue after a reload
+	m, _ = newHistoryModel(t)
+	m = m.withHistory(path)
+	if m.history.nextID != 4 || len(m.history.entries) != 3 {
+		t.Fatalf("Expected the history to reload, got %+v", m.history)
+	}
+
+	m.focusedSection = SectionHistory
+	view := m.View()
+	for _, want := range []string{"HISTORY (3)", "#3", "matrix", "12", "1.0ms", "7902699be42c"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View lacks %q:\n%s", want, view)
+		}
+	}
+
+	// Compare the two entries of the comparison
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyDown}, runes(" "), tea.KeyMsg{Type: tea.KeyDown}, runes(" "))
+	section := m.renderHistorySection()
+	for _, want := range []string{"COMPARE #2 vs #1", "✓ Same result", "#1 is 2.50x faster"} {
+		if !strings.Contains(section, want) {
+			t.Errorf("Comparison lacks %q:\n%s", want, section)
+		}
+	}
+
+	// Delete the selected entry, which also unmarks it
+	m, cmd = press(t, m, tea.KeyMsg{Type: tea.KeyDelete})
+	m = runCmd(t, m, cmd)
+	if len(m.history.entries) != 2 || len(m.history.marks) != 1 || !strings.Contains(m.history.status, "Deleted #1") {
+		t.Errorf("Unexpected history after delete %+v", m.history)
+	}
+	if saved, _ := LoadHistory(path); len(saved) != 2 {
+		t.Errorf("Expected the deletion to be saved, got %d entries", len(saved))
+	}
+
+	// Export, typing keys that are otherwise shortcuts
+	exportPath := filepath.Join(filepath.Dir(path), "cm.json")
+	m, _ = press(t, m, runes("e"))
+	if !m.history.exporting {
+		t.Fatal("Expected the export prompt")
+	}
+	m.history.input.SetValue(filepath.Dir(path) + string(filepath.Separator))
+	m.history.input.CursorEnd()
+	m, _ = press(t, m, runes("c"), runes("m"), runes(".json"))
+	m, cmd = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = runCmd(t, m, cmd)
+	if m.calculation.active || !strings.Contains(m.history.status, "Exported 2 entries") {
+		t.Errorf("Unexpected export status %q", m.history.status)
+	}
+	if _, err := os.Stat(exportPath); err != nil {
+		t.Errorf("Expected the export file: %v", err)
+	}
+
+	// Re-run the selected entry with its index and algorithm
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyUp})
+	m, cmd = press(t, m, runes("r"))
+	if cmd == nil || !m.calculation.active || m.input.n != "12" || m.algorithms.names[m.algorithms.cursor] != "matrix" {
+		t.Errorf("Expected a re-run of F(12) with matrix, got n=%s cursor=%d", m.input.n, m.algorithms.cursor)
+	}
+}
+
+func TestDashboardModel_HistoryInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m := NewDashboardModel(config.AppConfig{N: 100}, newMockCalculators()).withHistory(path)
+	if m.lastError == nil || m.history.path != "" {
+		t.Errorf("Expected an unsaved history with an error, got path %q, error %v", m.history.path, m.lastError)
+	}
+
+	// The history is kept in memory and the file left untouched
+	m, cmd := press(t, m, CalculationResultMsg{N: 10, Result: orchestration.CalculationResult{Name: "matrix", Result: big.NewInt(55)}})
+	if m, cmd = press(t, m, cmd()); cmd != nil || len(m.history.entries) != 1 {
+		t.Errorf("Expected an entry kept in memory, got %d", len(m.history.entries))
+	}
+	if data, _ := os.ReadFile(path); string(data) != "not json" {
+		t.Error("The invalid history file must not be overwritten")
+	}
+}
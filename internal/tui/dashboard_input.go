@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/agbru/fibcalc/internal/config"
 	"github.com/agbru/fibcalc/internal/fibonacci"
 )
 
@@ -104,11 +105,13 @@ func (m DashboardModel) startSingleCalculation() (tea.Model, tea.Cmd) {
 	}
 
 	// Get selected algorithm (use first one for single calc)
+	if m.config.Algo == config.AutoAlgo {
+		m.selectAutoAlgorithm(n)
+	}
 	selectedIdx := m.algorithms.cursor
-	if selectedIdx >= len(m.calculators) {
+	if selectedIdx >= len(m.algorithms.names) {
 		selectedIdx = 0
 	}
-	calc := m.calculators[selectedIdx]
 
 	// Reset state
 	m.lastError = nil
@@ -123,6 +126,7 @@ func (m DashboardModel) startSingleCalculation() (tea.Model, tea.Cmd) {
 		m.algorithms.statuses[i] = StatusIdle
 		m.algorithms.progresses[i] = 0
 		m.algorithms.durations[i] = 0
+		m.algorithms.clearStep(i)
 	}
 	m.algorithms.statuses[selectedIdx] = StatusRunning
 
@@ -130,11 +134,8 @@ func (m DashboardModel) startSingleCalculation() (tea.Model, tea.Cmd) {
 	m.focusedSection = SectionAlgorithms
 	m.input.inputActive = false
 
-	// Start calculation - use config's ToCalculationOptions method
-	opts := m.config.ToCalculationOptions()
-
 	return m, tea.Batch(
-		runCalculation(m.ctx, calc, n, opts, m.calculation.progressChan, selectedIdx),
+		runCalculation(m.ctx, m.backend, selectedIdx, n, m.config, m.calculation.progressChan),
 		listenForProgress(m.calculation.progressChan),
 	)
 }
@@ -154,25 +155,22 @@ func (m DashboardModel) startComparison() (tea.Model, tea.Cmd) {
 	m.calculation.n = n
 	m.calculation.mode = ModeCompare
 	m.calculation.startTime = time.Now()
-	m.calculation.progressChan = make(chan fibonacci.ProgressUpdate, len(m.calculators)*10)
+	m.calculation.progressChan = make(chan fibonacci.ProgressUpdate, len(m.algorithms.names)*10)
 
 	// Reset algorithm statuses
 	for i := range m.algorithms.statuses {
 		m.algorithms.statuses[i] = StatusRunning
 		m.algorithms.progresses[i] = 0
 		m.algorithms.durations[i] = 0
+		m.algorithms.clearStep(i)
 	}
 
 	// Focus algorithms section to show progress
 	m.focusedSection = SectionAlgorithms
 	m.input.inputActive = false
 
-	// Update config with current N
-	cfg := m.config
-	cfg.N = n
-
 	return m, tea.Batch(
-		runComparison(m.ctx, m.calculators, cfg, m.calculation.progressChan),
+		runComparison(m.ctx, m.backend, n, m.config, m.calculation.progressChan),
 		listenForProgress(m.calculation.progressChan),
 	)
 }
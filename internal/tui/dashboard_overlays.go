@@ -57,14 +57,25 @@ func (m DashboardModel) buildHelpContent() string {
 	b.WriteString(formatHelpLine(m.styles, "c", "Calculate F(N) with selected algorithm"))
 	b.WriteString(formatHelpLine(m.styles, "m", "Compare all algorithms"))
 	b.WriteString(formatHelpLine(m.styles, "x", "Toggle hexadecimal display"))
-	b.WriteString(formatHelpLine(m.styles, "v", "Toggle full value display"))
+	b.WriteString(formatHelpLine(m.styles, "v", "View all digits: page, search, jump, copy"))
 	b.WriteString(formatHelpLine(m.styles, "Ctrl+S", "Save result to file"))
 	b.WriteString("\n")
 
+	// History section
+	b.WriteString(m.styles.BoxTitle.Render("History"))
+	b.WriteString("\n")
+	b.WriteString(formatHelpLine(m.styles, "Enter / r", "Re-run the selected calculation"))
+	b.WriteString(formatHelpLine(m.styles, "Space", "Mark two entries to compare them"))
+	b.WriteString(formatHelpLine(m.styles, "Del", "Delete the selected entry"))
+	b.WriteString(formatHelpLine(m.styles, "e", "Export the history to CSV or JSON"))
+	b.WriteString("\n")
+
 	// UI section
 	b.WriteString(m.styles.BoxTitle.Render("Interface"))
 	b.WriteString("\n")
 	b.WriteString(formatHelpLine(m.styles, "t", "Cycle theme (dark/light/none)"))
+	b.WriteString(formatHelpLine(m.styles, "p", "Toggle the system telemetry panel"))
+	b.WriteString(formatHelpLine(m.styles, "s", "Settings: thresholds, strategy, timeout, calibration"))
 	b.WriteString(formatHelpLine(m.styles, "? / F1", "Toggle this help"))
 	b.WriteString(formatHelpLine(m.styles, "q / Ctrl+C", "Quit"))
 	b.WriteString("\n")
@@ -111,11 +122,25 @@ func (m DashboardModel) renderDashboard() string {
 	algoBox := m.styles.Box.Width(m.width - 4).Render(algoSection)
 	sections = append(sections, algoBox)
 
+	// System telemetry panel, or the server metrics of a remote dashboard
+	if m.telemetry.visible {
+		panel := m.renderTelemetryPanel()
+		if _, remote := m.serverBackend(); remote {
+			panel = m.renderServerPanel()
+		}
+		telemetryBox := m.styles.Box.Width(m.width - 4).Render(panel)
+		sections = append(sections, telemetryBox)
+	}
+
 	// Results section
 	resultsSection := m.renderResultsSection()
 	resultsBox := m.styles.Box.Width(m.width - 4).Render(resultsSection)
 	sections = append(sections, resultsBox)
 
+	// History section
+	historyBox := m.styles.Box.Width(m.width - 4).Render(m.renderHistorySection())
+	sections = append(sections, historyBox)
+
 	// Footer
 	footer := m.renderFooter()
 	sections = append(sections, footer)
@@ -129,6 +154,9 @@ func (m DashboardModel) renderHeader() string {
 
 	left := m.styles.Title.Render("FIBONACCI CALCULATOR")
 	rightFull := fmt.Sprintf("Theme: %s  [?] Help", theme.Name)
+	if label := m.backendLabel(); label != "" {
+		rightFull = label + "  " + rightFull
+	}
 	rightShort := "[?] Help"
 
 	// Calculate available width (accounting for header padding)
@@ -173,6 +201,18 @@ func (m DashboardModel) renderHeader() string {
 	return m.styles.Header.Width(m.width - 2).Render(header)
 }
 
+// backendLabel returns where the calculations run, for the header, or ""
+// when they run locally.
+func (m DashboardModel) backendLabel() string {
+	switch m.backend.(type) {
+	case MetricsBackend:
+		return "Remote: " + m.backend.Describe()
+	case replayBackend:
+		return "Replay: " + m.backend.Describe()
+	}
+	return ""
+}
+
 // renderFooter renders the dashboard footer with context-sensitive help.
 func (m DashboardModel) renderFooter() string {
 	var hints []string
@@ -185,14 +225,16 @@ func (m DashboardModel) renderFooter() string {
 		hints = append(hints, "Enter:Run", "Up/Down:Select", "Tab:Next")
 	case SectionResults:
 		if m.results.showDetails {
-			hints = append(hints, "d:Hide", "x:Hex", "v:Full", "Ctrl+S:Save", "Tab:Next")
+			hints = append(hints, "d:Hide", "x:Hex", "v:View", "Ctrl+S:Save", "Tab:Next")
 		} else {
 			hints = append(hints, "d:Details", "Ctrl+S:Save", "Tab:Next")
 		}
+	case SectionHistory:
+		hints = append(hints, "Enter:Re-run", "Space:Mark", "Del:Delete", "e:Export", "Tab:Next")
 	}
 
 	// Global hints
-	hints = append(hints, "c:Calc", "m:Compare", "t:Theme", "?:Help", "q:Quit")
+	hints = append(hints, "c:Calc", "m:Compare", "s:Settings", "t:Theme", "?:Help", "q:Quit")
 
 	// Join hints and truncate if too long
 	footer := strings.Join(hints, "  ")
@@ -6,6 +6,10 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/session"
 )
 
 // updateResults handles key messages for the results section.
@@ -45,29 +49,24 @@ func (m DashboardModel) renderResultsSection() string {
 	}
 
 	// Get the best result
-	var bestResult string
-	var bestAlgo string
-	var bestDuration string
-	var bitCount int
-
-	if len(m.results.results) > 0 {
+	best := m.bestResult()
+	if best == nil {
 		for _, r := range m.results.results {
-			if r.Err == nil && r.Result != nil {
-				if bestResult == "" {
-					bestResult = r.Result.String()
-					bestAlgo = r.Name
-					bestDuration = formatDuration(r.Duration)
-					bitCount = r.Result.BitLen()
-				}
-				break
+			if r.Err == nil {
+				// A replayed session holds the values up to session.MaxValueBits
+				b.WriteString(m.styles.Muted.Render(fmt.Sprintf("  The session does not include the value of F(%d): it has more than %s bits.",
+					m.results.n, formatNumber(session.MaxValueBits))))
+				return b.String()
 			}
 		}
-	}
-
-	if bestResult == "" {
 		b.WriteString(m.styles.Error.Render("  All calculations failed."))
 		return b.String()
 	}
+	bestResult := best.Result.String()
+	bestAlgo := best.Name
+	bestDuration := formatDuration(best.Duration)
+	bestResources := best.Resources
+	bitCount := best.Result.BitLen()
 
 	// Default view: show Global Status like CLI mode
 	if !m.results.showDetails {
@@ -100,6 +99,7 @@ func (m DashboardModel) renderResultsSection() string {
 			m.styles.Success.Render(bestAlgo),
 			m.styles.Muted.Render(bestDuration),
 		))
+		b.WriteString("\n  " + m.styles.Muted.Render(formatResources(bestResources)))
 
 		// Actions hint
 		b.WriteString("\n\n")
@@ -113,7 +113,7 @@ func (m DashboardModel) renderResultsSection() string {
 	}
 
 	// Detailed view: show full result
-	displayResult := formatResultValue(bestResult, m.results.showHex, m.results.showFull, m.getMaxValueLength())
+	displayResult := formatResultValue(bestResult, m.getMaxValueLength())
 	digitCount := len(bestResult)
 
 	// Result line
@@ -139,6 +139,9 @@ func (m DashboardModel) renderResultsSection() string {
 		}
 	}
 
+	// Resource usage line
+	b.WriteString("\n  " + m.styles.Muted.Render(formatResources(bestResources)))
+
 	// Actions hint
 	b.WriteString("\n\n")
 	b.WriteString("  ")
@@ -147,19 +150,43 @@ func (m DashboardModel) renderResultsSection() string {
 	b.WriteString(m.styles.HelpKey.Render("[x]"))
 	b.WriteString(m.styles.HelpDesc.Render(" Toggle hex  "))
 	b.WriteString(m.styles.HelpKey.Render("[v]"))
-	b.WriteString(m.styles.HelpDesc.Render(" Toggle full  "))
+	b.WriteString(m.styles.HelpDesc.Render(" View digits  "))
 	b.WriteString(m.styles.HelpKey.Render("[Ctrl+S]"))
 	b.WriteString(m.styles.HelpDesc.Render(" Save"))
 
 	return b.String()
 }
 
-// formatResultValue formats the result value for display.
-func formatResultValue(result string, showHex, showFull bool, maxLen int) string {
-	if showFull {
-		return result
+// bestResult returns the first successful result, or nil.
+func (m DashboardModel) bestResult() *orchestration.CalculationResult {
+	for i, r := range m.results.results {
+		if r.Err == nil && r.Result != nil {
+			return &m.results.results[i]
+		}
 	}
+	return nil
+}
+
+// formatResources summarizes the resources consumed by a calculation on one
+// line. Calculations on a server report no resource usage; those overlapped
+// by other calculations (a comparison) report shared process-wide totals.
+func formatResources(u orchestration.ResourceUsage) string {
+	if !u.Available() {
+		return "Resource usage not available"
+	}
+	prefix := ""
+	if u.Shared {
+		prefix = "Shared: "
+	}
+	return prefix + fmt.Sprintf("CPU %s (%.0f%% efficiency) | RSS +%s | Alloc %s | GC %d (%s)",
+		formatDuration(u.CPUTime()), u.ParallelEfficiency*100,
+		cli.FormatBytes(uint64(max(u.PeakRSSDelta, 0))), cli.FormatBytes(u.BytesAllocated),
+		u.GCCycles, formatDuration(u.GCPause))
+}
 
+// formatResultValue formats the result value for display, truncated to
+// maxLen characters. The digit viewer displays it in full.
+func formatResultValue(result string, maxLen int) string {
 	if len(result) <= maxLen {
 		return result
 	}
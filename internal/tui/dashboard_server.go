@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/cli"
+)
+
+// ─── Polling ───
+
+// serverPollInterval is the time between two fetches of the server metrics.
+const serverPollInterval = time.Second
+
+// serverPollTimeout bounds a fetch of the server metrics.
+const serverPollTimeout = 2 * time.Second
+
+// ServerState holds the metrics panel of a remote dashboard, which replaces
+// the system telemetry panel.
+type ServerState struct {
+	fetching bool
+	last     time.Time // start of the latest fetch
+	err      error     // error of the latest fetch
+
+	// The two latest metrics, for the rates of the counters
+	metrics, prev ServerMetrics
+	at, prevAt    time.Time
+}
+
+// serverBackend returns the backend when it reports server metrics.
+func (m DashboardModel) serverBackend() (MetricsBackend, bool) {
+	b, ok := m.backend.(MetricsBackend)
+	return b, ok
+}
+
+// pollServer fetches the server metrics on a tick, once per
+// serverPollInterval while the panel is visible.
+func (m DashboardModel) pollServer(now time.Time) (DashboardModel, tea.Cmd) {
+	backend, ok := m.serverBackend()
+	if !ok || !m.telemetry.visible || m.server.fetching || now.Sub(m.server.last) < serverPollInterval {
+		return m, nil
+	}
+	m.server.fetching = true
+	m.server.last = now
+	return m, fetchServerMetrics(backend)
+}
+
+// fetchServerMetrics returns a command fetching the metrics of the server.
+func fetchServerMetrics(backend MetricsBackend) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), serverPollTimeout)
+		defer cancel()
+		metrics, err := backend.Metrics(ctx)
+		return ServerMetricsMsg{Metrics: metrics, Time: time.Now(), Err: err}
+	}
+}
+
+// handleServerMetrics records fetched metrics; a failed fetch keeps the
+// previous ones.
+func (m DashboardModel) handleServerMetrics(msg ServerMetricsMsg) (tea.Model, tea.Cmd) {
+	m.server.fetching = false
+	m.server.err = msg.Err
+	if msg.Err == nil {
+		m.server.prev, m.server.prevAt = m.server.metrics, m.server.at
+		m.server.metrics, m.server.at = msg.Metrics, msg.Time
+	}
+	return m, nil
+}
+
+// ─── Rendering ───
+
+// renderServerPanel renders the metrics of the server.
+func (m DashboardModel) renderServerPanel() string {
+	s := m.server
+	var b strings.Builder
+	b.WriteString(m.styles.BoxTitle.Render("SERVER"))
+	b.WriteString("\n\n")
+
+	row := func(label, value string) {
+		b.WriteString("  ")
+		b.WriteString(m.styles.ResultLabel.Width(telemetryLabelWidth).Render(label))
+		b.WriteString(" ")
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+
+	switch {
+	case s.err != nil:
+		row("Status", m.styles.Error.Render(truncateString(s.err.Error(), max(20, m.width-telemetryLabelWidth-12))))
+	case s.at.IsZero():
+		row("Status", m.styles.Muted.Render("Waiting for metrics..."))
+	default:
+		row("Status", m.styles.Success.Render("connected to "+m.backend.Describe()))
+	}
+	if s.at.IsZero() {
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+
+	metric := func(name string, labels ...string) string {
+		v, ok := s.metrics.Sum(name, labels...)
+		if !ok {
+			return "n/a"
+		}
+		return fmt.Sprintf("%.0f", v)
+	}
+	row("Requests", fmt.Sprintf("%s active, %s total",
+		metric("fibcalc_active_requests"), metric("fibcalc_requests_total")))
+	row("Calculations", fmt.Sprintf("%s succeeded, %s failed",
+		metric("fibonacci_calculations_total", `status="success"`),
+		metric("fibonacci_calculations_total", `status="error"`)))
+
+	mean := "n/a"
+	sum, okSum := s.metrics.Sum("fibonacci_calculation_duration_seconds_sum")
+	count, okCount := s.metrics.Sum("fibonacci_calculation_duration_seconds_count")
+	if okSum && okCount && count > 0 {
+		mean = formatDuration(time.Duration(sum / count * float64(time.Second)))
+	}
+	row("Mean duration", mean)
+	row("CPU", m.serverCPU())
+
+	memory := "n/a"
+	if rss, ok := s.metrics.Sum("process_resident_memory_bytes"); ok {
+		memory = cli.FormatBytes(uint64(rss)) + " RSS"
+	}
+	if heap, ok := s.metrics.Sum("go_memstats_heap_alloc_bytes"); ok {
+		memory += ", heap " + cli.FormatBytes(uint64(heap))
+	}
+	row("Memory", memory)
+	row("Goroutines", metric("go_goroutines"))
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// serverCPU returns the CPU utilisation of the server between the two
+// latest fetches, in cores.
+func (m DashboardModel) serverCPU() string {
+	s := m.server
+	now, ok := s.metrics.Sum("process_cpu_seconds_total")
+	if !ok {
+		return "n/a"
+	}
+	prev, ok := s.prev.Sum("process_cpu_seconds_total")
+	elapsed := s.at.Sub(s.prevAt).Seconds()
+	if !ok || elapsed <= 0 || now < prev {
+		return fmt.Sprintf("%.1fs total", now)
+	}
+	return fmt.Sprintf("%.0f%% of a core (%.1fs total)", (now-prev)/elapsed*100, now)
+}
@@ -0,0 +1,434 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+// minCalibrationConfidence is the confidence below which the thresholds of
+// a quick calibration are not applied, as for the startup calibration.
+const minCalibrationConfidence = 0.5
+
+// settingsField identifies a field of the settings form.
+type settingsField int
+
+const (
+	fieldParallel settingsField = iota
+	fieldFFT
+	fieldStrassen
+	fieldStrategy
+	fieldDynamic
+	fieldTimeout
+	settingsFieldCount
+)
+
+// settingsLabels are the labels of the fields, in form order.
+var settingsLabels = [settingsFieldCount]string{
+	fieldParallel: "Parallel threshold (bits)",
+	fieldFFT:      "FFT threshold (bits)",
+	fieldStrassen: "Strassen threshold (bits)",
+	fieldStrategy: "Strategy",
+	fieldDynamic:  "Dynamic thresholds",
+	fieldTimeout:  "Timeout",
+}
+
+// isText reports whether the field is edited as text.
+func (f settingsField) isText() bool {
+	return f != fieldStrategy && f != fieldDynamic
+}
+
+// SettingsFormState holds the settings form: the calculation options
+// edited on a copy of the configuration, applied once validated.
+type SettingsFormState struct {
+	active bool
+	keys   SettingsKeyMap
+	focus  settingsField
+	inputs [settingsFieldCount]textinput.Model // text fields only
+	status string
+
+	strategy string // "all", "auto" or an algorithm
+	dynamic  bool
+
+	// algos are the registry names of the calculators, in table order, as
+	// accepted by config.AppConfig.Validate.
+	algos []string
+	// profile is the calibration profile used by the "auto" strategy.
+	profile *calibration.CalibrationProfile
+
+	calibrating      bool
+	calibrationStart time.Time
+}
+
+// newSettingsForm returns a closed settings form.
+func newSettingsForm() SettingsFormState {
+	s := SettingsFormState{keys: DefaultSettingsKeyMap()}
+	for f := range s.inputs {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = 20
+		s.inputs[f] = input
+	}
+	return s
+}
+
+// withSettings sets the registry names of the calculators, in table order,
+// and loads the calibration profile used by the "auto" strategy. A missing
+// or unreadable profile leaves the built-in cost models.
+func (m DashboardModel) withSettings(algos []string) DashboardModel {
+	m.settings.algos = algos
+	if profile, err := calibration.LoadProfile(m.config.CalibrationProfile); err == nil {
+		m.settings.profile = profile
+	}
+	return m
+}
+
+// algorithmNames returns the names under which the configuration refers to
+// the calculators.
+func (m DashboardModel) algorithmNames() []string {
+	if len(m.settings.algos) == len(m.algorithms.names) {
+		return m.settings.algos
+	}
+	return m.algorithms.names
+}
+
+// strategies returns the choices of the strategy field.
+func (m DashboardModel) strategies() []string {
+	return append([]string{"all", config.AutoAlgo}, m.algorithmNames()...)
+}
+
+// ─── Keys ───
+
+// openSettings opens the form with the current configuration.
+func (m DashboardModel) openSettings() (tea.Model, tea.Cmd) {
+	s := &m.settings
+	s.active = true
+	s.status = ""
+	s.inputs[fieldParallel].SetValue(strconv.Itoa(m.config.Threshold))
+	s.inputs[fieldFFT].SetValue(strconv.Itoa(m.config.FFTThreshold))
+	s.inputs[fieldStrassen].SetValue(strconv.Itoa(m.config.StrassenThreshold))
+	s.inputs[fieldTimeout].SetValue(m.config.Timeout.String())
+	s.strategy = m.config.Algo
+	if !slices.Contains(m.strategies(), s.strategy) {
+		s.strategy = "all"
+	}
+	s.dynamic = m.config.DynamicThresholds
+	return m, s.focusField(fieldParallel)
+}
+
+// focusField moves the focus to field f.
+func (s *SettingsFormState) focusField(f settingsField) tea.Cmd {
+	s.inputs[s.focus].Blur()
+	s.focus = f
+	if !f.isText() {
+		return nil
+	}
+	s.inputs[f].CursorEnd()
+	return s.inputs[f].Focus()
+}
+
+// updateSettings handles key messages while the settings form is open.
+func (m DashboardModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.settings
+	s.status = ""
+	switch {
+	case key.Matches(msg, s.keys.Close):
+		s.active = false
+		return m, nil
+	case key.Matches(msg, s.keys.Up):
+		return m, s.focusField((s.focus + settingsFieldCount - 1) % settingsFieldCount)
+	case key.Matches(msg, s.keys.Down):
+		return m, s.focusField((s.focus + 1) % settingsFieldCount)
+	case key.Matches(msg, s.keys.Apply):
+		return m.applySettings()
+	case key.Matches(msg, s.keys.Calibrate):
+		return m.startQuickCalibration()
+	case key.Matches(msg, s.keys.Save):
+		return m.saveProfile()
+	}
+
+	switch s.focus {
+	case fieldStrategy:
+		choices := m.strategies()
+		i := slices.Index(choices, s.strategy)
+		switch {
+		case key.Matches(msg, s.keys.Left):
+			s.strategy = choices[(i+len(choices)-1)%len(choices)]
+		case key.Matches(msg, s.keys.Right), key.Matches(msg, s.keys.Toggle):
+			s.strategy = choices[(i+1)%len(choices)]
+		}
+		return m, nil
+	case fieldDynamic:
+		if key.Matches(msg, s.keys.Left, s.keys.Right, s.keys.Toggle) {
+			s.dynamic = !s.dynamic
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	s.inputs[s.focus], cmd = s.inputs[s.focus].Update(msg)
+	return m, cmd
+}
+
+// formConfig returns the configuration edited by the form, validated with
+// config.AppConfig.Validate.
+func (m DashboardModel) formConfig() (config.AppConfig, error) {
+	s := &m.settings
+	cfg := m.config
+	thresholds := []struct {
+		field settingsField
+		dst   *int
+	}{
+		{fieldParallel, &cfg.Threshold},
+		{fieldFFT, &cfg.FFTThreshold},
+		{fieldStrassen, &cfg.StrassenThreshold},
+	}
+	for _, t := range thresholds {
+		value := strings.TrimSpace(s.inputs[t.field].Value())
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return cfg, fmt.Errorf("%s: not an integer: %q", settingsLabels[t.field], value)
+		}
+		*t.dst = v
+	}
+	value := strings.TrimSpace(s.inputs[fieldTimeout].Value())
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return cfg, fmt.Errorf("%s: not a duration such as 5m or 90s: %q", settingsLabels[fieldTimeout], value)
+	}
+	cfg.Timeout = timeout
+	cfg.Algo = s.strategy
+	cfg.DynamicThresholds = s.dynamic
+	if err := cfg.Validate(m.algorithmNames()); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// applySettings applies the form to the configuration of the following
+// calculations and closes it. Invalid values keep the form open.
+func (m DashboardModel) applySettings() (tea.Model, tea.Cmd) {
+	cfg, err := m.formConfig()
+	if err != nil {
+		m.settings.status = err.Error()
+		return m, nil
+	}
+	m.config = cfg
+	if i := slices.Index(m.algorithmNames(), cfg.Algo); i >= 0 {
+		m.algorithms.cursor = i
+	}
+	m.settings.active = false
+	m.settings.inputs[m.settings.focus].Blur()
+	return m, nil
+}
+
+// selectAutoAlgorithm moves the algorithm cursor to the calculator predicted
+// to be fastest for F(n) by the cost models of the calibration profile.
+func (m *DashboardModel) selectAutoAlgorithm(n uint64) {
+	names := m.algorithmNames()
+	sel := calibration.SelectAlgorithm(n, m.settings.profile, names)
+	if i := slices.Index(names, sel.Algorithm); i >= 0 {
+		m.algorithms.cursor = i
+	}
+}
+
+// ─── Calibration ───
+
+// startQuickCalibration estimates the FFT and parallel thresholds of this
+// machine with micro-benchmarks, in the background.
+func (m DashboardModel) startQuickCalibration() (tea.Model, tea.Cmd) {
+	s := &m.settings
+	if s.calibrating {
+		return m, nil
+	}
+	if m.calculation.active {
+		s.status = "Wait for the calculation to finish: it would skew the measurements"
+		return m, nil
+	}
+	if _, remote := m.serverBackend(); remote {
+		s.status = "Calibration measures this machine, not the server"
+		return m, nil
+	}
+	s.calibrating = true
+	s.calibrationStart = time.Now()
+	return m, runQuickCalibration(m.ctx)
+}
+
+// runQuickCalibration runs calibration.QuickCalibrate.
+func runQuickCalibration(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		results, err := calibration.QuickCalibrate(ctx)
+		return CalibrationResultMsg{Results: results, Err: err}
+	}
+}
+
+// handleCalibrationResult fills the form with the calibrated thresholds and
+// applies them, unless the measurements were inconclusive.
+func (m DashboardModel) handleCalibrationResult(msg CalibrationResultMsg) (tea.Model, tea.Cmd) {
+	s := &m.settings
+	s.calibrating = false
+	r := msg.Results
+	switch {
+	case msg.Err != nil:
+		s.status = fmt.Sprintf("Calibration failed: %v", msg.Err)
+		return m, nil
+	case r.Confidence < minCalibrationConfidence:
+		s.status = fmt.Sprintf("Calibration inconclusive (confidence %.0f%%): thresholds unchanged", r.Confidence*100)
+		return m, nil
+	}
+
+	m.config.Threshold = r.ParallelThreshold
+	m.config.FFTThreshold = r.FFTThreshold
+	s.inputs[fieldParallel].SetValue(strconv.Itoa(r.ParallelThreshold))
+	s.inputs[fieldFFT].SetValue(strconv.Itoa(r.FFTThreshold))
+	s.inputs[s.focus].CursorEnd()
+	s.status = fmt.Sprintf("Calibrated in %s (confidence %.0f%%): parallel %s bits, FFT %s bits applied",
+		r.Duration.Round(time.Millisecond), r.Confidence*100,
+		formatNumber(r.ParallelThreshold), formatNumber(r.FFTThreshold))
+	return m, nil
+}
+
+// saveProfile applies the form and saves its thresholds to the calibration
+// profile, keeping the cost models and ranges of a profile valid for this
+// machine.
+func (m DashboardModel) saveProfile() (tea.Model, tea.Cmd) {
+	cfg, err := m.formConfig()
+	if err != nil {
+		m.settings.status = err.Error()
+		return m, nil
+	}
+	m.config = cfg
+
+	path := cfg.CalibrationProfile
+	if path == "" {
+		path = calibration.GetDefaultProfilePath()
+	}
+	profile := calibration.NewProfile()
+	if p := m.settings.profile; p.IsValid() {
+		clone := *p
+		profile = &clone
+		profile.CalibratedAt = time.Now()
+	} else {
+		profile.CalibrationN = fibonacci.CalibrationN
+	}
+	profile.OptimalParallelThreshold = cfg.Threshold
+	profile.OptimalFFTThreshold = cfg.FFTThreshold
+	profile.OptimalStrassenThreshold = cfg.StrassenThreshold
+	return m, func() tea.Msg {
+		return ProfileSavedMsg{Path: path, Profile: profile, Err: profile.SaveProfile(path)}
+	}
+}
+
+// handleProfileSaved reports the outcome of saving the profile.
+func (m DashboardModel) handleProfileSaved(msg ProfileSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.settings.status = fmt.Sprintf("Profile not saved: %v", msg.Err)
+		return m, nil
+	}
+	m.settings.profile = msg.Profile
+	m.settings.status = "Profile saved to " + msg.Path
+	return m, nil
+}
+
+// ─── Rendering ───
+
+// settingsLabelWidth is the width of the field labels.
+const settingsLabelWidth = 28
+
+// renderSettings renders the settings form over the dashboard.
+func (m DashboardModel) renderSettings() string {
+	s := &m.settings
+	var b strings.Builder
+	b.WriteString(m.styles.Title.Render("SETTINGS"))
+	b.WriteString("\n\n")
+
+	for f := range settingsFieldCount {
+		label := fmt.Sprintf("%-*s", settingsLabelWidth, settingsLabels[f])
+		marker := "  "
+		labelStyle := m.styles.ResultLabel
+		if f == s.focus {
+			marker = m.styles.Primary.Render("▶ ")
+			labelStyle = m.styles.Primary
+		}
+		b.WriteString(marker)
+		b.WriteString(labelStyle.Render(label))
+		switch f {
+		case fieldStrategy:
+			b.WriteString(m.renderChoice(s.strategy, f == s.focus))
+		case fieldDynamic:
+			b.WriteString(m.renderChoice(onOff(s.dynamic), f == s.focus))
+		default:
+			b.WriteString(s.inputs[f].View())
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.Muted.Render(m.strategyHint()))
+	b.WriteString("\n\n")
+
+	switch {
+	case s.calibrating:
+		elapsed := time.Since(s.calibrationStart)
+		// The micro-benchmarks stop at MicroBenchTimeout
+		progress := float64(elapsed) / float64(calibration.MicroBenchTimeout)
+		if progress > 0.99 {
+			progress = 0.99
+		}
+		b.WriteString(m.styles.Info.Render("Calibrating "))
+		b.WriteString(m.renderProgressBar(progress, 30))
+	case s.status != "":
+		b.WriteString(m.styles.Info.Render(s.status))
+	}
+	b.WriteString("\n\n")
+
+	for _, hint := range [][2]string{{"[↑/↓]", " Field  "}, {"[Enter]", " Apply  "}, {"[Ctrl+R]", " Calibrate  "}, {"[Ctrl+S]", " Save profile  "}, {"[Esc]", " Cancel"}} {
+		b.WriteString(m.styles.HelpKey.Render(hint[0]))
+		b.WriteString(m.styles.HelpDesc.Render(hint[1]))
+	}
+
+	overlay := lipgloss.NewStyle().
+		Width(min(84, m.width-4)).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(m.styles.Primary.GetForeground()).
+		Padding(1, 2).
+		Render(b.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, overlay)
+}
+
+// renderChoice renders the value of a choice field.
+func (m DashboardModel) renderChoice(value string, focused bool) string {
+	if focused {
+		return m.styles.Primary.Render("◀ " + value + " ▶")
+	}
+	return m.styles.ResultValue.Render("  " + value)
+}
+
+// strategyHint explains the selected strategy.
+func (m DashboardModel) strategyHint() string {
+	switch m.settings.strategy {
+	case "all":
+		return "c calculates with the highlighted algorithm"
+	case config.AutoAlgo:
+		return "c calculates with the algorithm predicted fastest for N"
+	}
+	return "c calculates with " + m.settings.strategy
+}
+
+// onOff renders a boolean setting.
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
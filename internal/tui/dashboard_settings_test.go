@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/calibration"
+	"github.com/agbru/fibcalc/internal/config"
+)
+
+// newSettingsModel returns a dashboard whose calibration profile is a file
+// in a temporary directory, with the settings form open.
+func newSettingsModel(t *testing.T) DashboardModel {
+	t.Helper()
+	cfg := config.AppConfig{
+		N:                  10_000_000,
+		Timeout:            5 * time.Minute,
+		Algo:               "all",
+		Threshold:          config.DefaultThreshold,
+		FFTThreshold:       config.DefaultFFTThreshold,
+		StrassenThreshold:  config.DefaultStrassenThreshold,
+		CalibrationProfile: filepath.Join(t.TempDir(), "profile.json"),
+	}
+	m := NewDashboardModel(cfg, newMockCalculators()).withSettings([]string{"fft", "fast", "matrix"})
+	m, _ = press(t, m, tea.WindowSizeMsg{Width: 120, Height: 40}, runes("s"))
+	if !m.settings.active {
+		t.Fatal("Expected s to open the settings form")
+	}
+	return m
+}
+
+// erase is the key erasing a text field.
+var erase = tea.KeyMsg{Type: tea.KeyCtrlU}
+
+func TestSettingsForm_Apply(t *testing.T) {
+	m := newSettingsModel(t)
+	view := m.View()
+	for _, want := range []string{"SETTINGS", "Parallel threshold", "4096", "500000", "Strategy", "all", "5m0s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View lacks %q:\n%s", want, view)
+		}
+	}
+
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	m, _ = press(t, m, erase, runes("8192"), down, down, erase, runes("2048"), down,
+		tea.KeyMsg{Type: tea.KeyLeft}, down,
+		runes(" "), down, erase, runes("1h30s"))
+	if !strings.Contains(m.View(), "c calculates with matrix") {
+		t.Errorf("Expected the matrix strategy:\n%s", m.View())
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	c := m.config
+	if m.settings.active || c.Threshold != 8192 || c.FFTThreshold != config.DefaultFFTThreshold ||
+		c.StrassenThreshold != 2048 || c.Algo != "matrix" || !c.DynamicThresholds || c.Timeout != time.Hour+30*time.Second {
+		t.Errorf("Settings not applied: %+v", c)
+	}
+	if !c.ToCalculationOptions().EnableDynamicThresholds {
+		t.Error("Expected dynamic thresholds in the calculation options")
+	}
+	if m.algorithms.cursor != 2 {
+		t.Errorf("Expected the matrix calculator selected, got %d", m.algorithms.cursor)
+	}
+
+	// Esc discards the changes
+	m, _ = press(t, m, runes("s"), erase, runes("1"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.settings.active || m.config.Threshold != 8192 {
+		t.Errorf("Expected Esc to discard the form, threshold %d", m.config.Threshold)
+	}
+}
+
+func TestSettingsForm_Validation(t *testing.T) {
+	tests := []struct {
+		field settingsField
+		value string
+		want  string
+	}{
+		{fieldParallel, "-1", "parallelism threshold cannot be negative"},
+		{fieldFFT, "big", "FFT threshold (bits): not an integer"},
+		{fieldStrassen, "-3", "Strassen threshold cannot be negative"},
+		{fieldTimeout, "soon", "not a duration"},
+		{fieldTimeout, "0s", "timeout value must be strictly positive"},
+	}
+	for _, tt := range tests {
+		m := newSettingsModel(t)
+		m.settings.focusField(tt.field)
+		m, _ = press(t, m, erase, runes(tt.value), tea.KeyMsg{Type: tea.KeyEnter})
+		if !m.settings.active || !strings.Contains(m.settings.status, tt.want) {
+			t.Errorf("%s = %q: expected the form open with %q, got %q", settingsLabels[tt.field], tt.value, tt.want, m.settings.status)
+		}
+		if m.config.Threshold != config.DefaultThreshold || m.config.Timeout != 5*time.Minute {
+			t.Errorf("%s = %q: the configuration must not change", settingsLabels[tt.field], tt.value)
+		}
+	}
+}
+
+func TestSettingsForm_Calibration(t *testing.T) {
+	m := newSettingsModel(t)
+	m, cmd := press(t, m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	if cmd == nil || !m.settings.calibrating || !strings.Contains(m.View(), "Calibrating") {
+		t.Fatal("Expected a quick calibration in progress")
+	}
+
+	m, _ = press(t, m, CalibrationResultMsg{Results: calibration.ThresholdResults{FFTThreshold: 100_000, ParallelThreshold: 1024, Confidence: 0.2}})
+	if m.config.Threshold != config.DefaultThreshold || !strings.Contains(m.settings.status, "inconclusive") {
+		t.Errorf("Expected an inconclusive calibration, got %q", m.settings.status)
+	}
+
+	m, _ = press(t, m, CalibrationResultMsg{Results: calibration.ThresholdResults{FFTThreshold: 100_000, ParallelThreshold: 1024, Confidence: 0.8, Duration: 120 * time.Millisecond}})
+	if m.config.Threshold != 1024 || m.config.FFTThreshold != 100_000 || m.settings.inputs[fieldFFT].Value() != "100000" {
+		t.Errorf("Expected the calibrated thresholds applied, got %+v", m.config)
+	}
+	if !strings.Contains(m.settings.status, "Calibrated in 120ms (confidence 80%)") {
+		t.Errorf("Unexpected status %q", m.settings.status)
+	}
+
+	// Save the thresholds to the profile, used for automatic selection
+	m, cmd = press(t, m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("Expected the profile to be saved")
+	}
+	m, _ = press(t, m, cmd())
+	if !strings.Contains(m.settings.status, "Profile saved to") {
+		t.Fatalf("Unexpected status %q", m.settings.status)
+	}
+	profile, err := calibration.LoadProfile(m.config.CalibrationProfile)
+	if err != nil || !profile.IsValid() || profile.OptimalParallelThreshold != 1024 ||
+		profile.OptimalFFTThreshold != 100_000 || profile.OptimalStrassenThreshold != config.DefaultStrassenThreshold {
+		t.Errorf("Unexpected profile %+v, %v", profile, err)
+	}
+	if m.settings.profile == nil || m.settings.profile.OptimalParallelThreshold != 1024 {
+		t.Error("Expected the saved profile to be used")
+	}
+}
+
+func TestSettingsForm_AutoStrategy(t *testing.T) {
+	m := newSettingsModel(t)
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown},
+		runes(" "), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.config.Algo != config.AutoAlgo {
+		t.Fatalf("Expected the auto strategy, got %q", m.config.Algo)
+	}
+
+	// The default cost models predict fast doubling to be fastest
+	m, cmd := press(t, m, runes("c"))
+	if cmd == nil || !m.calculation.active || m.algorithms.cursor != 1 {
+		t.Errorf("Expected a calculation with the fast calculator, got %d", m.algorithms.cursor)
+	}
+}
@@ -0,0 +1,401 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/bigfft"
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// ─── Sampling ───
+
+// telemetryHistory is the number of samples kept for the sparklines: 30
+// seconds at the tick cadence.
+const telemetryHistory = 300
+
+// metricHeapObjects is the runtime/metrics sample of the heap size.
+const metricHeapObjects = "/memory/classes/heap/objects:bytes"
+
+// telemetrySample holds the machine figures measured over one tick.
+type telemetrySample struct {
+	// cores is the utilisation of each core from 0 to 1, or nil when the
+	// platform does not report it.
+	cores []float64
+	// process is the CPU utilisation of the process, relative to GOMAXPROCS.
+	process float64
+	// heap is the size of the heap objects, in bytes.
+	heap uint64
+	// gcPause is the stop-the-world GC pause time during the tick.
+	gcPause time.Duration
+	// poolHitRate and cacheHitRate are the hit rates of the bigfft buffer
+	// pools and transform cache during the tick, or -1 without lookups.
+	poolHitRate  float64
+	cacheHitRate float64
+}
+
+// telemetrySampler measures the machine over the time since its previous
+// call.
+type telemetrySampler func() telemetrySample
+
+// newSystemSampler returns the sampler of the per-core CPU times of the
+// machine, the CPU time and runtime/metrics of the process, and the bigfft
+// pool and cache statistics.
+func newSystemSampler() telemetrySampler {
+	meter := orchestration.StartResourceMeter()
+	cores := readCoreTimes()
+	pool := bigfft.GetPoolStats()
+	cache := bigfft.GetTransformCache().Stats()
+	heap := []metrics.Sample{{Name: metricHeapObjects}}
+
+	return func() telemetrySample {
+		usage := meter.Stop()
+		meter = orchestration.StartResourceMeter()
+		s := telemetrySample{process: usage.ParallelEfficiency, gcPause: usage.GCPause}
+
+		now := readCoreTimes()
+		if len(now) == len(cores) {
+			s.cores = make([]float64, len(now))
+			for i, c := range now {
+				if total := delta(c.total, cores[i].total); total > 0 {
+					s.cores[i] = float64(delta(c.busy, cores[i].busy)) / float64(total)
+				}
+			}
+		}
+		cores = now
+
+		metrics.Read(heap)
+		if heap[0].Value.Kind() == metrics.KindUint64 {
+			s.heap = heap[0].Value.Uint64()
+		}
+
+		p := bigfft.GetPoolStats()
+		gets, misses := delta(p.Gets, pool.Gets), delta(p.Misses, pool.Misses)
+		if misses > gets {
+			misses = gets
+		}
+		s.poolHitRate = hitRate(gets-misses, gets)
+		pool = p
+
+		c := bigfft.GetTransformCache().Stats()
+		hits := delta(c.Hits, cache.Hits)
+		s.cacheHitRate = hitRate(hits, hits+delta(c.Misses, cache.Misses))
+		cache = c
+		return s
+	}
+}
+
+// delta returns the increase of a counter, which restarts from zero when it
+// is reset.
+func delta(now, prev uint64) uint64 {
+	if now < prev {
+		return now
+	}
+	return now - prev
+}
+
+// hitRate returns hits/total, or -1 when total is zero.
+func hitRate(hits, total uint64) float64 {
+	if total == 0 {
+		return -1
+	}
+	return float64(hits) / float64(total)
+}
+
+// coreTimes holds the cumulative busy and total time of a CPU core, in
+// clock ticks.
+type coreTimes struct {
+	busy, total uint64
+}
+
+// parseProcStat reads the per-core times from the content of /proc/stat:
+// the "cpuN user nice system idle iowait irq softirq steal ..." lines. Idle
+// and I/O wait times are not busy.
+func parseProcStat(r io.Reader) []coreTimes {
+	var cores []coreTimes
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || !strings.HasPrefix(fields[0], "cpu") || fields[0] == "cpu" {
+			continue
+		}
+		var c coreTimes
+		for i, field := range fields[1:min(len(fields), 9)] {
+			v, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil
+			}
+			c.total += v
+			if i != 3 && i != 4 { // idle, iowait
+				c.busy += v
+			}
+		}
+		cores = append(cores, c)
+	}
+	return cores
+}
+
+// ─── Telemetry state ───
+
+// TelemetryState holds the history of the system telemetry panel.
+type TelemetryState struct {
+	visible bool
+	sample  telemetrySampler
+	current telemetrySample
+
+	// Histories, oldest first
+	cores     [][]float64
+	process   []float64
+	heap      []float64
+	gcPause   []float64
+	poolHits  []float64
+	cacheHits []float64
+
+	// Throughput of each algorithm: the operand size reached, when it was
+	// first seen, and the rate at which it grew from the previous one.
+	throughput [][]float64
+	bits       []int
+	seen       []time.Time
+	rates      []float64
+}
+
+// newTelemetry returns the visible telemetry panel of n algorithms.
+func newTelemetry(n int, sample telemetrySampler) TelemetryState {
+	return TelemetryState{
+		visible:    true,
+		sample:     sample,
+		throughput: make([][]float64, n),
+		bits:       make([]int, n),
+		seen:       make([]time.Time, n),
+		rates:      make([]float64, n),
+	}
+}
+
+// appendSample appends v to a history, keeping the last telemetryHistory
+// samples.
+func appendSample(history []float64, v float64) []float64 {
+	history = append(history, v)
+	if len(history) > telemetryHistory {
+		history = history[len(history)-telemetryHistory:]
+	}
+	return history
+}
+
+// update takes a sample on a tick. The throughput of an algorithm is the
+// growth of its operands, in bits, divided by the time between the ticks
+// where the two sizes were first seen; it holds until the next step.
+func (t *TelemetryState) update(now time.Time, algos AlgorithmTableState) {
+	if !t.visible || t.sample == nil {
+		return
+	}
+	s := t.sample()
+	t.current = s
+
+	if len(t.cores) != len(s.cores) {
+		t.cores = make([][]float64, len(s.cores))
+	}
+	for i, u := range s.cores {
+		t.cores[i] = appendSample(t.cores[i], u)
+	}
+	t.process = appendSample(t.process, s.process)
+	t.heap = appendSample(t.heap, float64(s.heap))
+	t.gcPause = appendSample(t.gcPause, float64(s.gcPause))
+	t.poolHits = appendSample(t.poolHits, s.poolHitRate)
+	t.cacheHits = appendSample(t.cacheHits, s.cacheHitRate)
+
+	for i := range t.throughput {
+		bits := 0
+		if i < len(algos.statuses) && algos.statuses[i] == StatusRunning && algos.steps[i] != nil {
+			bits = algos.steps[i].OperandBits
+		}
+		switch {
+		case bits == 0:
+			t.rates[i] = 0
+		case bits > t.bits[i] && t.bits[i] > 0:
+			if elapsed := now.Sub(t.seen[i]).Seconds(); elapsed > 0 {
+				t.rates[i] = float64(bits-t.bits[i]) / elapsed
+			}
+		}
+		if bits != t.bits[i] {
+			t.bits[i], t.seen[i] = bits, now
+		}
+		t.throughput[i] = appendSample(t.throughput[i], t.rates[i])
+	}
+}
+
+// ─── Rendering ───
+
+// sparkBars are the bars of a sparkline, from lowest to highest.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Layout of the telemetry panel.
+const (
+	telemetryLabelWidth = 20
+	telemetryValueWidth = 24
+	coreSparkWidth      = 10
+	maxCoreRows         = 4
+)
+
+// sparkline renders the last width values as bars, the newest on the right,
+// scaled to top or, when top is 0, to the largest value shown. Negative
+// values (no data) are blank.
+func sparkline(values []float64, width int, top float64) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if top <= 0 {
+		for _, v := range values {
+			top = math.Max(top, v)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		switch {
+		case v < 0:
+			b.WriteByte(' ')
+		case top <= 0:
+			b.WriteRune(sparkBars[0])
+		default:
+			i := int(math.Round(v / top * float64(len(sparkBars)-1)))
+			b.WriteRune(sparkBars[max(0, min(i, len(sparkBars)-1))])
+		}
+	}
+	return b.String()
+}
+
+// lastRate returns the latest hit rate of a history, or -1 without lookups
+// in the window.
+func lastRate(history []float64) float64 {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i] >= 0 {
+			return history[i]
+		}
+	}
+	return -1
+}
+
+// formatRate formats a hit rate.
+func formatRate(rate float64) string {
+	if rate < 0 {
+		return "no lookups"
+	}
+	return fmt.Sprintf("%.1f%% hits", rate*100)
+}
+
+// formatBitRate formats a throughput in bits per second using decimal units.
+func formatBitRate(bps float64) string {
+	units := []string{"bit/s", "kbit/s", "Mbit/s", "Gbit/s", "Tbit/s"}
+	i := 0
+	for bps >= 1000 && i < len(units)-1 {
+		bps /= 1000
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", bps, units[i])
+}
+
+// renderTelemetryPanel renders the system telemetry panel.
+func (m DashboardModel) renderTelemetryPanel() string {
+	t := m.telemetry
+	var b strings.Builder
+	b.WriteString(m.styles.BoxTitle.Render("SYSTEM"))
+	b.WriteString("\n\n")
+
+	content := max(40, m.width-8) // box border and padding
+	sparkWidth := max(8, content-telemetryLabelWidth-telemetryValueWidth-4)
+	row := func(label, spark, value string) {
+		b.WriteString("  ")
+		b.WriteString(m.styles.ResultLabel.Width(telemetryLabelWidth).Render(truncateString(label, telemetryLabelWidth)))
+		b.WriteString(" ")
+		b.WriteString(m.styles.Primary.Render(spark))
+		b.WriteString(" ")
+		b.WriteString(m.styles.Muted.Render(value))
+		b.WriteString("\n")
+	}
+
+	cpu := "process CPU"
+	if n := len(t.cores); n > 0 {
+		cpu = fmt.Sprintf("process, %d cores", n)
+	}
+	row("CPU", sparkline(t.process, sparkWidth, 1), fmt.Sprintf("%3.0f%% %s", t.current.process*100, cpu))
+	m.renderCores(&b, content)
+	row("Heap", sparkline(t.heap, sparkWidth, 0), cli.FormatBytes(t.current.heap))
+
+	var peak float64
+	for _, p := range t.gcPause {
+		peak = math.Max(peak, p)
+	}
+	row("GC pauses", sparkline(t.gcPause, sparkWidth, 0),
+		fmt.Sprintf("%s (peak %s)", formatDuration(t.current.gcPause), formatDuration(time.Duration(peak))))
+	row("FFT buffer pools", sparkline(t.poolHits, sparkWidth, 1), formatRate(lastRate(t.poolHits)))
+	row("FFT transform cache", sparkline(t.cacheHits, sparkWidth, 1), formatRate(lastRate(t.cacheHits)))
+
+	// Throughput of the algorithms that ran during the window
+	for i, history := range t.throughput {
+		var top float64
+		for _, v := range history {
+			top = math.Max(top, v)
+		}
+		if top == 0 || i >= len(m.algorithms.names) {
+			continue
+		}
+		row(m.algorithms.names[i], sparkline(history, sparkWidth, 0), formatBitRate(t.rates[i]))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderCores renders a sparkline per core, in columns, or the current
+// utilisation of each core on a single line when the cores do not fit in
+// maxCoreRows rows.
+func (m DashboardModel) renderCores(b *strings.Builder, width int) {
+	cores := m.telemetry.cores
+	if len(cores) == 0 {
+		return
+	}
+
+	labelWidth := len(fmt.Sprintf("cpu%d", len(cores)-1))
+	cellWidth := labelWidth + 1 + coreSparkWidth + 5 // " 100%"
+	columns := max(1, (width-2)/(cellWidth+2))
+	rows := (len(cores) + columns - 1) / columns
+	if rows > maxCoreRows {
+		current := make([]float64, len(cores))
+		for i, history := range cores {
+			current[i] = history[len(history)-1]
+		}
+		b.WriteString("  ")
+		b.WriteString(m.styles.ResultLabel.Width(telemetryLabelWidth).Render("Cores"))
+		b.WriteString(" ")
+		b.WriteString(m.styles.Primary.Render(sparkline(current, min(len(current), width-telemetryLabelWidth-3), 1)))
+		b.WriteString("\n")
+		return
+	}
+
+	for r := 0; r < rows; r++ {
+		b.WriteString("  ")
+		for c := 0; c < columns; c++ {
+			i := r*columns + c
+			if i >= len(cores) {
+				break
+			}
+			history := cores[i]
+			label := fmt.Sprintf("%-*s", labelWidth, fmt.Sprintf("cpu%d", i))
+			b.WriteString(m.styles.Muted.Render(label))
+			b.WriteString(" ")
+			b.WriteString(m.styles.Primary.Render(sparkline(history, coreSparkWidth, 1)))
+			b.WriteString(fmt.Sprintf(" %3.0f%%  ", history[len(history)-1]*100))
+		}
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+)
+
+func TestParseProcStat(t *testing.T) {
+	stat := `cpu  100 0 50 800 50 0 0 0 0 0
+cpu0 60 0 20 400 20 0 0 0 0 0
+cpu1 40 0 30 400 30 0 0 0
+intr 12345 0 0
+ctxt 6789
+`
+	cores := parseProcStat(strings.NewReader(stat))
+	want := []coreTimes{{busy: 80, total: 500}, {busy: 70, total: 500}}
+	if len(cores) != len(want) {
+		t.Fatalf("Expected %d cores, got %+v", len(want), cores)
+	}
+	for i := range want {
+		if cores[i] != want[i] {
+			t.Errorf("Core %d: expected %+v, got %+v", i, want[i], cores[i])
+		}
+	}
+	if cores := parseProcStat(strings.NewReader("cpu0 1 x 3 4 5\n")); cores != nil {
+		t.Errorf("Expected no cores for a malformed line, got %+v", cores)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		top    float64
+		want   string
+	}{
+		{[]float64{0, 0.5, 1, -1}, 6, 1, "  ▁▅█ "},
+		{[]float64{1, 2, 4}, 2, 0, "▅█"},
+		{[]float64{0, 0}, 2, 0, "▁▁"},
+		{nil, 3, 1, "   "},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values, tt.width, tt.top); got != tt.want {
+			t.Errorf("sparkline(%v, %d, %v) = %q, want %q", tt.values, tt.width, tt.top, got, tt.want)
+		}
+	}
+	if got := formatBitRate(1.5e9); got != "1.5 Gbit/s" {
+		t.Errorf("formatBitRate(1.5e9) = %q", got)
+	}
+}
+
+func TestDashboardModel_Telemetry(t *testing.T) {
+	model := NewDashboardModel(config.AppConfig{N: 100}, newMockCalculators())
+	model.ready = true
+	model.width = 120
+	model.height = 60
+
+	samples := 0
+	model.telemetry.sample = func() telemetrySample {
+		samples++
+		return telemetrySample{
+			cores: []float64{0.25, 1}, process: 0.5, heap: 64 << 20,
+			gcPause: 2 * time.Millisecond, poolHitRate: 0.9, cacheHitRate: -1,
+		}
+	}
+
+	// The operands of a running algorithm grow by 1,000 bits in 100ms
+	start := time.Now()
+	model.algorithms.statuses[0] = StatusRunning
+	for i, bits := range []int{1000, 1000, 2000} {
+		model.algorithms.steps[0] = &fibonacci.ProgressEvent{OperandBits: bits}
+		updated, _ := model.Update(TickMsg{Time: start.Add(time.Duration(i) * 50 * time.Millisecond)})
+		model = updated.(DashboardModel)
+	}
+
+	view := model.View()
+	for _, want := range []string{"SYSTEM", " 50% process, 2 cores", "cpu0", " 25%", "100%", "64.0 MiB",
+		"2.0ms (peak 2.0ms)", "90.0% hits", "no lookups", "fast-doubling", "10.0 kbit/s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View lacks %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(model.renderTelemetryPanel(), "matrix") {
+		t.Error("Algorithms without throughput must not be shown")
+	}
+
+	// p hides the panel and stops sampling
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	model = updated.(DashboardModel)
+	updated, _ = model.Update(TickMsg{Time: start.Add(time.Second)})
+	model = updated.(DashboardModel)
+	if strings.Contains(model.View(), "SYSTEM") || samples != 3 {
+		t.Errorf("Expected a hidden panel without sampling, got %d samples", samples)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/agbru/fibcalc/internal/config"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
 )
 
 // mockCalculator implements the fibonacci.Calculator interface for testing.
@@ -39,8 +40,8 @@ func TestNewDashboardModel(t *testing.T) {
 	if model.config.N != 1000 {
 		t.Errorf("expected N=1000, got %d", model.config.N)
 	}
-	if len(model.calculators) != 3 {
-		t.Errorf("expected 3 calculators, got %d", len(model.calculators))
+	if len(model.backend.Algorithms()) != 3 {
+		t.Errorf("expected 3 calculators, got %d", len(model.backend.Algorithms()))
 	}
 	if model.focusedSection != SectionInput {
 		t.Errorf("expected initial focus on SectionInput, got %v", model.focusedSection)
@@ -80,19 +81,26 @@ func TestDashboardModel_SectionNavigation(t *testing.T) {
 		t.Errorf("after 2nd Tab, expected SectionResults, got %v", model.focusedSection)
 	}
 
+	// Tab again
+	result, _ = model.Update(msg)
+	model = result.(DashboardModel)
+	if model.focusedSection != SectionHistory {
+		t.Errorf("after 3rd Tab, expected SectionHistory, got %v", model.focusedSection)
+	}
+
 	// Tab wraps around
 	result, _ = model.Update(msg)
 	model = result.(DashboardModel)
 	if model.focusedSection != SectionInput {
-		t.Errorf("after 3rd Tab, expected SectionInput (wrap), got %v", model.focusedSection)
+		t.Errorf("after 4th Tab, expected SectionInput (wrap), got %v", model.focusedSection)
 	}
 
 	// Shift+Tab goes backward
 	msg = tea.KeyMsg{Type: tea.KeyShiftTab}
 	result, _ = model.Update(msg)
 	model = result.(DashboardModel)
-	if model.focusedSection != SectionResults {
-		t.Errorf("after Shift+Tab, expected SectionResults, got %v", model.focusedSection)
+	if model.focusedSection != SectionHistory {
+		t.Errorf("after Shift+Tab, expected SectionHistory, got %v", model.focusedSection)
 	}
 }
 
@@ -160,7 +168,8 @@ func TestSection_Next(t *testing.T) {
 	}{
 		{SectionInput, SectionAlgorithms},
 		{SectionAlgorithms, SectionResults},
-		{SectionResults, SectionInput},
+		{SectionResults, SectionHistory},
+		{SectionHistory, SectionInput},
 	}
 
 	for _, tt := range tests {
@@ -176,9 +185,10 @@ func TestSection_Prev(t *testing.T) {
 		current  Section
 		expected Section
 	}{
-		{SectionInput, SectionResults},
+		{SectionInput, SectionHistory},
 		{SectionAlgorithms, SectionInput},
 		{SectionResults, SectionAlgorithms},
+		{SectionHistory, SectionResults},
 	}
 
 	for _, tt := range tests {
@@ -266,6 +276,62 @@ func TestDashboardModel_View(t *testing.T) {
 	}
 }
 
+func TestDashboardModel_StepDetail(t *testing.T) {
+	cfg := config.AppConfig{N: 100}
+	model := NewDashboardModel(cfg, newMockCalculators())
+	model.ready = true
+	model.width = 120
+	model.height = 40
+	model.algorithms.statuses[1] = StatusRunning
+
+	ev := &fibonacci.ProgressEvent{
+		CalculatorIndex: 1, Progress: 0.5, Step: 18, TotalSteps: 24,
+		OperandBits: 1048576, Method: fibonacci.MethodFFT, Parallel: true,
+		StepDuration: 12 * time.Millisecond, BytesAllocated: 48 << 20,
+	}
+	updated, _ := model.handleProgressUpdate(ProgressMsg{Update: fibonacci.ProgressUpdate{CalculatorIndex: 1, Value: 0.5, Event: ev}})
+	model = updated.(DashboardModel)
+
+	if model.algorithms.steps[1] != ev {
+		t.Fatal("expected step event to be stored")
+	}
+	table := model.renderAlgorithmTable()
+	if !containsString(table, "step 18/24 · 1,048,576-bit operands · fft ∥ · 12.0ms · 48.0 MiB") {
+		t.Errorf("table should show step detail, got:\n%s", table)
+	}
+
+	model.algorithms.statuses[1] = StatusComplete
+	if containsString(model.renderAlgorithmTable(), "step 18/24") {
+		t.Error("step detail should only be shown for running algorithms")
+	}
+}
+
+func TestDashboardModel_ResultResources(t *testing.T) {
+	cfg := config.AppConfig{N: 10}
+	model := NewDashboardModel(cfg, newMockCalculators())
+	model.ready = true
+	model.width = 120
+	model.height = 40
+
+	res := orchestration.CalculationResult{
+		Name: "Fast", Result: big.NewInt(55), Duration: 10 * time.Millisecond,
+		Resources: orchestration.ResourceUsage{
+			UserCPU: 30 * time.Millisecond, ParallelEfficiency: 0.75,
+			BytesAllocated: 3 << 20, GCCycles: 2,
+		},
+	}
+	updated, _ := model.handleCalculationResult(CalculationResultMsg{Result: res, N: 10, Duration: res.Duration})
+	model = updated.(DashboardModel)
+
+	for _, details := range []bool{false, true} {
+		model.results.showDetails = details
+		section := model.renderResultsSection()
+		if !containsString(section, "75% efficiency") || !containsString(section, "Alloc 3.0 MiB") || !containsString(section, "GC 2") {
+			t.Errorf("results (details=%v) should show resource usage, got:\n%s", details, section)
+		}
+	}
+}
+
 func TestDashboardModel_HelpOverlayView(t *testing.T) {
 	cfg := config.AppConfig{N: 100}
 	model := NewDashboardModel(cfg, newMockCalculators())
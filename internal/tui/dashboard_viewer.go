@@ -0,0 +1,591 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Digit grouping of the viewer.
+const (
+	decimalGroupSize = 10
+	hexGroupSize     = 8
+)
+
+// maxSearchMatches bounds the number of matches recorded by a search.
+const maxSearchMatches = 100000
+
+// maxCopyDigits bounds the selection copied to the clipboard, as terminals
+// limit the size of OSC 52 sequences.
+const maxCopyDigits = 1 << 20
+
+// viewerPrompt is the input line of the digit viewer.
+type viewerPrompt int
+
+const (
+	promptNone viewerPrompt = iota
+	promptJump
+	promptSearch
+)
+
+// DigitViewerState holds the state of the digit viewer, which displays a
+// result in full. The value is converted to text once per base, in the
+// background, and only the lines around the visible ones are rendered.
+type DigitViewerState struct {
+	active bool
+	n      uint64
+	value  *big.Int
+	hex    bool
+	// texts holds the decimal (0) and hexadecimal (1) digits of value, or ""
+	// until they are converted.
+	texts [2]string
+
+	keys      DigitViewerKeyMap
+	styles    Styles
+	viewport  viewport.Model
+	width     int
+	height    int
+	pageStart int // first line rendered in the viewport
+	pageEnd   int // line after the last rendered line
+	top       int // first visible line
+
+	cursor int // index of the digit under the cursor
+	mark   int // index of the start of the selection, or -1
+
+	prompt  viewerPrompt
+	input   textinput.Model
+	query   string
+	matches []int // indexes of the matches of query, in order
+	match   int   // index in matches of the current match
+	status  string
+
+	// clipboard receives the OSC 52 sequences copying selections.
+	clipboard io.Writer
+}
+
+// newDigitViewer returns a closed digit viewer.
+func newDigitViewer() DigitViewerState {
+	input := textinput.New()
+	input.CharLimit = 64
+	return DigitViewerState{
+		keys:      DefaultDigitViewerKeyMap(),
+		viewport:  viewport.New(0, 0),
+		input:     input,
+		mark:      -1,
+		clipboard: os.Stdout,
+	}
+}
+
+// openViewer opens the digit viewer on the first successful result, in the
+// base selected by the hex toggle.
+func (m DashboardModel) openViewer() (tea.Model, tea.Cmd) {
+	best := m.bestResult()
+	if best == nil {
+		return m, nil
+	}
+	v := &m.viewer
+	if v.value != best.Result {
+		v.texts = [2]string{}
+		v.cursor, v.mark, v.top = 0, -1, 0
+		v.query, v.matches = "", nil
+	}
+	v.active, v.n, v.value, v.styles = true, m.results.n, best.Result, m.styles
+	v.prompt, v.status = promptNone, ""
+	v.resize(m.width, m.height)
+	return m, v.setBase(m.results.showHex)
+}
+
+// handleDigitText stores the digits converted by convertDigits.
+func (m DashboardModel) handleDigitText(msg DigitTextMsg) (tea.Model, tea.Cmd) {
+	v := &m.viewer
+	if msg.Value != v.value {
+		return m, nil
+	}
+	v.texts[baseIndex(msg.Hex)] = msg.Text
+	if msg.Hex == v.hex {
+		if v.query != "" {
+			v.search(v.query)
+		}
+		v.refresh()
+	}
+	return m, nil
+}
+
+// handleClipboard reports the outcome of a copy to the clipboard.
+func (m DashboardModel) handleClipboard(msg ClipboardMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.viewer.status = fmt.Sprintf("Copy failed: %v", msg.Err)
+	} else {
+		m.viewer.status = fmt.Sprintf("Copied %s digits to the clipboard", formatNumber(msg.Digits))
+	}
+	return m, nil
+}
+
+// updateViewer handles key messages while the digit viewer is open.
+func (m DashboardModel) updateViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := &m.viewer
+	if v.prompt != promptNone {
+		return m, v.updatePrompt(msg)
+	}
+
+	text := v.text()
+	if key.Matches(msg, v.keys.Close) {
+		v.active = false
+		return m, nil
+	}
+	if key.Matches(msg, v.keys.Hex) {
+		return m, v.setBase(!v.hex)
+	}
+	if text == "" {
+		return m, nil
+	}
+
+	// Messages last until the next key
+	v.status = ""
+	perLine, visible := v.digitsPerLine(), v.viewport.Height
+	switch {
+	case key.Matches(msg, v.keys.Left):
+		v.moveTo(v.cursor - 1)
+	case key.Matches(msg, v.keys.Right):
+		v.moveTo(v.cursor + 1)
+	case key.Matches(msg, v.keys.Up):
+		v.moveTo(v.cursor - perLine)
+	case key.Matches(msg, v.keys.Down):
+		v.moveTo(v.cursor + perLine)
+	case key.Matches(msg, v.keys.PageUp):
+		v.moveTo(v.cursor - perLine*visible)
+	case key.Matches(msg, v.keys.PageDown):
+		v.moveTo(v.cursor + perLine*visible)
+	case key.Matches(msg, v.keys.Top):
+		v.moveTo(0)
+	case key.Matches(msg, v.keys.Bottom):
+		v.moveTo(len(text) - 1)
+	case key.Matches(msg, v.keys.Jump):
+		return m, v.startPrompt(promptJump, "Go to digit: ")
+	case key.Matches(msg, v.keys.Search):
+		return m, v.startPrompt(promptSearch, "Search: ")
+	case key.Matches(msg, v.keys.NextMatch):
+		v.nextMatch(1)
+	case key.Matches(msg, v.keys.PrevMatch):
+		v.nextMatch(-1)
+	case key.Matches(msg, v.keys.Mark):
+		if v.mark < 0 {
+			v.mark = v.cursor
+			v.status = fmt.Sprintf("Selection started at digit %s", formatNumber(v.cursor+1))
+		} else {
+			v.mark = -1
+			v.status = "Selection cleared"
+		}
+		v.refresh()
+	case key.Matches(msg, v.keys.Copy):
+		return m, v.copySelection()
+	}
+	return m, nil
+}
+
+// ─── Viewer state ───
+
+// baseIndex returns the index in DigitViewerState.texts of a base.
+func baseIndex(hex bool) int {
+	if hex {
+		return 1
+	}
+	return 0
+}
+
+// text returns the digits in the current base, or "" while converting.
+func (v *DigitViewerState) text() string {
+	return v.texts[baseIndex(v.hex)]
+}
+
+// groupSize returns the number of digits per group in the current base.
+func (v *DigitViewerState) groupSize() int {
+	if v.hex {
+		return hexGroupSize
+	}
+	return decimalGroupSize
+}
+
+// gutterWidth returns the width of the position column.
+func (v *DigitViewerState) gutterWidth() int {
+	return len(formatNumber(max(len(v.text()), 1)))
+}
+
+// digitsPerLine returns the number of digits per line: as many groups as fit
+// in the width, separated by spaces.
+func (v *DigitViewerState) digitsPerLine() int {
+	group := v.groupSize()
+	avail := v.width - v.gutterWidth() - 5
+	return max(1, (avail+1)/(group+1)) * group
+}
+
+// lineCount returns the number of lines of the digits.
+func (v *DigitViewerState) lineCount() int {
+	perLine := v.digitsPerLine()
+	return (len(v.text()) + perLine - 1) / perLine
+}
+
+// resize fits the viewer to the terminal, leaving room for the title, the
+// ruler, the status line and the key hints.
+func (v *DigitViewerState) resize(width, height int) {
+	v.width, v.height = width, height
+	v.viewport.Width = width
+	v.viewport.Height = max(1, height-6)
+	v.pageStart, v.pageEnd = 0, 0
+	v.refresh()
+}
+
+// setBase switches to decimal or hexadecimal digits. Positions differ
+// between bases, so the cursor and the selection are reset. It returns the
+// command converting the value if its digits in that base are not known yet.
+func (v *DigitViewerState) setBase(hex bool) tea.Cmd {
+	if hex != v.hex {
+		v.hex = hex
+		v.cursor, v.mark, v.top = 0, -1, 0
+		v.pageStart, v.pageEnd = 0, 0
+		v.matches = nil
+		if v.query != "" && v.text() != "" {
+			v.search(v.query)
+		}
+	}
+	v.refresh()
+	if v.text() == "" && v.value != nil {
+		return convertDigits(v.value, hex)
+	}
+	return nil
+}
+
+// moveTo moves the cursor to digit i, scrolling to keep it visible.
+func (v *DigitViewerState) moveTo(i int) {
+	v.cursor = max(0, min(i, len(v.text())-1))
+	line, visible := v.cursor/v.digitsPerLine(), v.viewport.Height
+	if line < v.top {
+		v.top = line
+	} else if line >= v.top+visible {
+		v.top = line - visible + 1
+	}
+	v.refresh()
+}
+
+// startPrompt focuses the input line.
+func (v *DigitViewerState) startPrompt(prompt viewerPrompt, label string) tea.Cmd {
+	v.prompt = prompt
+	v.input.Prompt = label
+	v.input.SetValue("")
+	return v.input.Focus()
+}
+
+// updatePrompt handles key messages while the input line is focused.
+func (v *DigitViewerState) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		v.prompt = promptNone
+		v.input.Blur()
+		return nil
+	case tea.KeyEnter:
+		prompt, value := v.prompt, strings.TrimSpace(v.input.Value())
+		v.prompt = promptNone
+		v.input.Blur()
+		if prompt == promptJump {
+			v.jump(value)
+		} else {
+			v.search(value)
+			v.nextMatch(0)
+		}
+		return nil
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return cmd
+}
+
+// jump moves the cursor to a 1-based digit position, which may contain
+// thousands separators.
+func (v *DigitViewerState) jump(value string) {
+	value = strings.NewReplacer(",", "", "_", "").Replace(value)
+	pos, err := strconv.Atoi(value)
+	if err != nil || pos < 1 || pos > len(v.text()) {
+		v.status = fmt.Sprintf("Invalid position %q: expected 1 to %s", value, formatNumber(len(v.text())))
+		return
+	}
+	v.moveTo(pos - 1)
+	v.status = fmt.Sprintf("Digit %s", formatNumber(pos))
+}
+
+// search records the positions of query in the digits, up to
+// maxSearchMatches. Overlapping matches are included.
+func (v *DigitViewerState) search(query string) {
+	query = strings.ToLower(query)
+	v.query, v.matches, v.match = query, nil, 0
+	if query == "" {
+		v.status = ""
+		v.refresh()
+		return
+	}
+	digits, base := "0123456789", "decimal"
+	if v.hex {
+		digits, base = "0123456789abcdef", "hexadecimal"
+	}
+	for _, c := range query {
+		if !strings.ContainsRune(digits, c) {
+			v.status = fmt.Sprintf("Invalid %s digits: %q", base, query)
+			v.query = ""
+			v.refresh()
+			return
+		}
+	}
+	text := v.text()
+	for i := 0; len(v.matches) < maxSearchMatches; {
+		j := strings.Index(text[i:], query)
+		if j < 0 {
+			break
+		}
+		v.matches = append(v.matches, i+j)
+		i += j + 1
+	}
+}
+
+// nextMatch moves the cursor to the next (dir 1) or previous (dir -1) match,
+// wrapping around, or with dir 0 to the first match at or after the cursor.
+func (v *DigitViewerState) nextMatch(dir int) {
+	if v.query == "" {
+		if v.status == "" {
+			v.status = "No search: press / to search"
+		}
+		return
+	}
+	if len(v.matches) == 0 {
+		v.status = fmt.Sprintf("No match for %q", v.query)
+		v.refresh()
+		return
+	}
+	switch dir {
+	case 0:
+		v.match = sort.SearchInts(v.matches, v.cursor) % len(v.matches)
+	default:
+		v.match = (v.match + dir + len(v.matches)) % len(v.matches)
+	}
+	v.moveTo(v.matches[v.match])
+	count := formatNumber(len(v.matches))
+	if len(v.matches) == maxSearchMatches {
+		count += "+"
+	}
+	v.status = fmt.Sprintf("Match %s of %s for %q", formatNumber(v.match+1), count, v.query)
+}
+
+// selection returns the selected digits, from the mark to the cursor.
+func (v *DigitViewerState) selection() (start, end int, ok bool) {
+	if v.mark < 0 {
+		return 0, 0, false
+	}
+	return min(v.mark, v.cursor), max(v.mark, v.cursor) + 1, true
+}
+
+// copySelection copies the selected digits to the clipboard.
+func (v *DigitViewerState) copySelection() tea.Cmd {
+	start, end, ok := v.selection()
+	if !ok {
+		v.status = "Nothing selected: press space to start a selection"
+		return nil
+	}
+	if end-start > maxCopyDigits {
+		v.status = fmt.Sprintf("Selection too large to copy (%s digits, at most %s)",
+			formatNumber(end-start), formatNumber(maxCopyDigits))
+		return nil
+	}
+	return copyToClipboard(v.clipboard, v.text()[start:end])
+}
+
+// ─── Rendering ───
+
+// refresh renders the lines around the visible ones into the viewport.
+// Pages of three screens are rendered, so that the viewport content stays
+// small whatever the size of the value.
+func (v *DigitViewerState) refresh() {
+	text, visible := v.text(), v.viewport.Height
+	if text == "" || v.width == 0 {
+		v.viewport.SetContent("")
+		return
+	}
+	lines := v.lineCount()
+	v.top = max(0, min(v.top, lines-1))
+	if v.top < v.pageStart || v.top+visible > v.pageEnd || v.pageEnd == 0 {
+		v.pageStart = max(0, v.top-visible)
+		v.pageEnd = min(lines, v.pageStart+3*visible)
+	}
+
+	var b strings.Builder
+	for line := v.pageStart; line < v.pageEnd; line++ {
+		if line > v.pageStart {
+			b.WriteByte('\n')
+		}
+		v.renderLine(&b, line)
+	}
+	v.viewport.SetContent(b.String())
+	v.viewport.SetYOffset(v.top - v.pageStart)
+}
+
+// digitClass is the highlighting of a digit.
+type digitClass int
+
+const (
+	classPlain digitClass = iota
+	classMatch
+	classSelected
+	classCursor
+)
+
+// renderLine writes a line of digits, preceded by the position of its first
+// digit, with the cursor, the selection and the search matches highlighted.
+func (v *DigitViewerState) renderLine(b *strings.Builder, line int) {
+	text, perLine, group := v.text(), v.digitsPerLine(), v.groupSize()
+	start := line * perLine
+	end := min(start+perLine, len(text))
+
+	classes := make([]digitClass, end-start)
+	if len(v.matches) > 0 {
+		from := sort.SearchInts(v.matches, start-len(v.query)+1)
+		for _, pos := range v.matches[from:] {
+			if pos >= end {
+				break
+			}
+			for i := max(pos, start); i < min(pos+len(v.query), end); i++ {
+				classes[i-start] = classMatch
+			}
+		}
+	}
+	if selStart, selEnd, ok := v.selection(); ok {
+		for i := max(selStart, start); i < min(selEnd, end); i++ {
+			classes[i-start] = classSelected
+		}
+	}
+	if v.cursor >= start && v.cursor < end {
+		classes[v.cursor-start] = classCursor
+	}
+
+	b.WriteString(v.styles.Muted.Render(fmt.Sprintf("%*s │", v.gutterWidth(), formatNumber(start+1))))
+	for g := start; g < end; g += group {
+		b.WriteByte(' ')
+		// Render runs of digits with the same highlighting
+		for i := g; i < min(g+group, end); {
+			j := i + 1
+			for j < min(g+group, end) && classes[j-start] == classes[i-start] {
+				j++
+			}
+			b.WriteString(digitStyle(v.styles, classes[i-start]).Render(text[i:j]))
+			i = j
+		}
+	}
+}
+
+// digitStyle returns the style of a class of digits.
+func digitStyle(styles Styles, class digitClass) lipgloss.Style {
+	switch class {
+	case classCursor:
+		return styles.DigitCursor
+	case classSelected:
+		return styles.DigitSelection
+	case classMatch:
+		return styles.DigitMatch
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// renderRuler returns the offsets of the digit groups within a line.
+func (v *DigitViewerState) renderRuler() string {
+	group := v.groupSize()
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", v.gutterWidth()+2))
+	for offset := 0; offset < v.digitsPerLine(); offset += group {
+		fmt.Fprintf(&b, " %-*s", group, "+"+strconv.Itoa(offset))
+	}
+	return b.String()
+}
+
+// renderViewer renders the digit viewer over the whole screen.
+func (m DashboardModel) renderViewer() string {
+	v := &m.viewer
+	base := "decimal"
+	if v.hex {
+		base = "hexadecimal"
+	}
+	text := v.text()
+
+	var b strings.Builder
+	b.WriteString(m.styles.Title.UnsetMarginBottom().Render("DIGIT VIEWER"))
+	b.WriteString(m.styles.Muted.Render(fmt.Sprintf("  F(%d), %s", v.n, base)))
+	if text != "" {
+		b.WriteString(m.styles.Muted.Render(fmt.Sprintf(", %s digits", formatNumber(len(text)))))
+	}
+	b.WriteString("\n\n")
+
+	if text == "" {
+		b.WriteString(m.styles.Info.Render(fmt.Sprintf("  Converting F(%d) to %s...", v.n, base)))
+		b.WriteString(strings.Repeat("\n", v.viewport.Height+1))
+	} else {
+		b.WriteString(m.styles.Muted.Render(v.renderRuler()))
+		b.WriteString("\n")
+		b.WriteString(v.viewport.View())
+		b.WriteString("\n")
+	}
+
+	// Status or input line
+	switch {
+	case v.prompt != promptNone:
+		b.WriteString(v.input.View())
+	case v.status != "":
+		b.WriteString(m.styles.Info.Render(v.status))
+	case text != "":
+		status := fmt.Sprintf("Digit %s of %s", formatNumber(v.cursor+1), formatNumber(len(text)))
+		if start, end, ok := v.selection(); ok {
+			status += fmt.Sprintf(", %s digits selected", formatNumber(end-start))
+		}
+		b.WriteString(m.styles.Muted.Render(status))
+	}
+	b.WriteString("\n")
+
+	hints := []string{"Arrows:Move", "PgUp/PgDn:Page", "g:Go to", "/:Search", "n/N:Match", "x:Hex", "Space:Select", "y:Copy", "Esc:Close"}
+	b.WriteString(m.styles.Footer.Width(m.width - 2).Render(strings.Join(hints, "  ")))
+	return b.String()
+}
+
+// ─── Commands ───
+
+// convertDigits converts value to decimal or hexadecimal digits in the
+// background: the conversion of a value with millions of digits takes a
+// while.
+func convertDigits(value *big.Int, hex bool) tea.Cmd {
+	return func() tea.Msg {
+		base := 10
+		if hex {
+			base = 16
+		}
+		return DigitTextMsg{Value: value, Hex: hex, Text: value.Text(base)}
+	}
+}
+
+// copyToClipboard copies text to the system clipboard with an OSC 52 escape
+// sequence, which terminals (and tmux) forward to the clipboard, also over
+// SSH.
+func copyToClipboard(w io.Writer, text string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		}
+		_, err := seq.WriteTo(w)
+		return ClipboardMsg{Digits: len(text), Err: err}
+	}
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// fib returns F(n), computed iteratively.
+func fib(n int) *big.Int {
+	a, b := big.NewInt(0), big.NewInt(1)
+	for i := 0; i < n; i++ {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a
+}
+
+// runes returns the key message typing s.
+func runes(s string) tea.KeyMsg {
+	if s == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(s)}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// press sends msgs to the model and returns it with the last command.
+func press(t *testing.T, m DashboardModel, msgs ...tea.Msg) (DashboardModel, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, msg := range msgs {
+		var model tea.Model
+		model, cmd = m.Update(msg)
+		m = model.(DashboardModel)
+	}
+	return m, cmd
+}
+
+// newViewerModel returns a dashboard whose result is value, with the digit
+// viewer open and the value converted.
+func newViewerModel(t *testing.T, n uint64, value *big.Int) DashboardModel {
+	t.Helper()
+	m := NewDashboardModel(config.AppConfig{N: n}, newMockCalculators())
+	m, _ = press(t, m, tea.WindowSizeMsg{Width: 80, Height: 16})
+	m, _ = press(t, m, CalculationResultMsg{Result: orchestration.CalculationResult{Name: "fast-doubling", Result: value}, N: n})
+
+	m, cmd := press(t, m, runes("v"))
+	if !m.viewer.active || cmd == nil {
+		t.Fatal("Expected the viewer to open and convert the value")
+	}
+	if view := m.View(); !strings.Contains(view, "Converting") {
+		t.Errorf("Expected a conversion message, got:\n%s", view)
+	}
+	m, _ = press(t, m, cmd())
+	return m
+}
+
+func TestDigitViewer_Navigation(t *testing.T) {
+	value := fib(1000)
+	digits := value.String()
+	m := newViewerModel(t, 1000, value)
+
+	view := m.View()
+	for _, want := range []string{"DIGIT VIEWER", "F(1000), decimal, 209 digits", "+0", "+10", digits[:10] + " " + digits[10:20], "Digit 1 of 209"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View lacks %q:\n%s", want, view)
+		}
+	}
+	perLine := m.viewer.digitsPerLine()
+	if perLine%decimalGroupSize != 0 || perLine > 80 {
+		t.Errorf("Unexpected %d digits per line", perLine)
+	}
+
+	// Moves are clamped to the digits
+	m, _ = press(t, m, runes("j"), runes("l"), tea.KeyMsg{Type: tea.KeyLeft}, runes("l"))
+	if m.viewer.cursor != perLine+1 {
+		t.Errorf("Expected the cursor at %d, got %d", perLine+1, m.viewer.cursor)
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEnd}, runes("l"))
+	if m.viewer.cursor != 208 {
+		t.Errorf("Expected the cursor on the last digit, got %d", m.viewer.cursor)
+	}
+
+	// Jump to a digit position
+	m, _ = press(t, m, runes("g"), runes("1,50"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.viewer.cursor != 149 || !strings.Contains(m.View(), "Digit 150") {
+		t.Errorf("Expected the cursor on digit 150, got %d (status %q)", m.viewer.cursor, m.viewer.status)
+	}
+	m, _ = press(t, m, runes("g"), runes("999"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.viewer.cursor != 149 || !strings.Contains(m.viewer.status, "Invalid position") {
+		t.Errorf("Expected an invalid position, got %d (status %q)", m.viewer.cursor, m.viewer.status)
+	}
+
+	// Search from the cursor, then navigate the matches with wrap-around
+	var want []int
+	for i := 0; i+2 <= len(digits); i++ {
+		if digits[i:i+2] == "42" {
+			want = append(want, i)
+		}
+	}
+	m, _ = press(t, m, runes("/"), runes("42"), tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.viewer.matches) != len(want) {
+		t.Fatalf("Expected matches %v, got %v", want, m.viewer.matches)
+	}
+	first := len(want) - 1
+	for i, pos := range want {
+		if pos >= 149 {
+			first = i
+			break
+		}
+	}
+	if m.viewer.cursor != want[first] {
+		t.Errorf("Expected the first match after the cursor at %d, got %d", want[first], m.viewer.cursor)
+	}
+	m, _ = press(t, m, runes("N"))
+	if m.viewer.cursor != want[(first+len(want)-1)%len(want)] {
+		t.Errorf("Expected the previous match, got %d", m.viewer.cursor)
+	}
+	m, _ = press(t, m, runes("/"), runes("4x"), tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(m.viewer.status, "Invalid decimal digits") {
+		t.Errorf("Unexpected status %q", m.viewer.status)
+	}
+
+	// Esc closes the viewer and keeps the dashboard running
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.viewer.active || !strings.Contains(m.View(), "RESULT") {
+		t.Error("Expected Esc to close the viewer")
+	}
+}
+
+func TestDigitViewer_HexAndCopy(t *testing.T) {
+	value := fib(1000)
+	m := newViewerModel(t, 1000, value)
+	var clipboard bytes.Buffer
+	m.viewer.clipboard = &clipboard
+
+	m, cmd := press(t, m, runes("x"))
+	if cmd == nil {
+		t.Fatal("Expected the conversion to hexadecimal")
+	}
+	m, _ = press(t, m, cmd())
+	hex := value.Text(16)
+	if !strings.Contains(m.View(), "hexadecimal, 174 digits") || !strings.Contains(m.View(), hex[:8]+" "+hex[8:16]) {
+		t.Errorf("Expected hexadecimal digits:\n%s", m.View())
+	}
+
+	// Toggling back uses the converted digits
+	if m, cmd = press(t, m, runes("x")); cmd != nil || !strings.Contains(m.View(), "decimal, 209 digits") {
+		t.Error("Expected the decimal digits to be reused")
+	}
+	m, _ = press(t, m, runes("x"))
+
+	m, cmd = press(t, m, runes("y"))
+	if cmd != nil || !strings.Contains(m.viewer.status, "Nothing selected") {
+		t.Errorf("Expected nothing to copy, got status %q", m.viewer.status)
+	}
+	m, _ = press(t, m, runes("l"), runes(" "), runes("l"), runes("l"), runes("l"))
+	if !strings.Contains(m.View(), "Digit 5 of 174, 4 digits selected") {
+		t.Errorf("Expected a selection:\n%s", m.View())
+	}
+	m, cmd = press(t, m, runes("y"))
+	if cmd == nil {
+		t.Fatal("Expected a copy command")
+	}
+	m, _ = press(t, m, cmd())
+	if want := base64.StdEncoding.EncodeToString([]byte(hex[1:5])); !strings.Contains(clipboard.String(), "\x1b]52;c;"+want) {
+		t.Errorf("Expected an OSC 52 sequence copying %q, got %q", hex[1:5], clipboard.String())
+	}
+	if !strings.Contains(m.viewer.status, "Copied 4 digits") {
+		t.Errorf("Unexpected status %q", m.viewer.status)
+	}
+}
+
+func TestDigitViewer_Paging(t *testing.T) {
+	value := new(big.Int).Lsh(big.NewInt(1), 200000) // 60,206 digits
+	m := newViewerModel(t, 0, value)
+	lines, visible := m.viewer.lineCount(), m.viewer.viewport.Height
+
+	check := func(where string) {
+		t.Helper()
+		v := m.viewer
+		if rendered := v.pageEnd - v.pageStart; rendered > 3*visible {
+			t.Errorf("%s: %d lines rendered, expected at most %d", where, rendered, 3*visible)
+		}
+		if line := v.cursor / v.digitsPerLine(); line < v.top || line >= v.top+visible {
+			t.Errorf("%s: cursor line %d not visible from line %d", where, line, v.top)
+		}
+	}
+	check("top")
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyPgDown}, tea.KeyMsg{Type: tea.KeyPgDown})
+	check("page down")
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEnd})
+	check("end")
+	if m.viewer.top != lines-visible {
+		t.Errorf("Expected the last page from line %d, got %d", lines-visible, m.viewer.top)
+	}
+	if view := m.View(); !strings.Contains(view, "Digit 60,206 of 60,206") {
+		t.Errorf("Expected the last digit:\n%s", view)
+	}
+}
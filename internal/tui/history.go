@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/cli"
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+// DefaultHistoryFileName is the name of the TUI history file in the user's
+// home directory.
+const DefaultHistoryFileName = ".fibcalc_tui_history.json"
+
+// MaxHistoryEntries bounds the number of calculations kept in the TUI
+// history; the oldest are dropped first.
+const MaxHistoryEntries = 500
+
+// HistoryEntry is a past calculation recorded in the TUI history.
+type HistoryEntry struct {
+	// ID identifies the entry in the history; it is never reused.
+	ID int `json:"id"`
+	// Time is when the calculation finished.
+	Time time.Time `json:"time"`
+	// N is the Fibonacci index.
+	N uint64 `json:"n"`
+	// Algorithm is the name of the calculator.
+	Algorithm string `json:"algorithm"`
+	// Duration is the wall-clock time of the calculation.
+	Duration time.Duration `json:"duration_ns"`
+	// Digits is the number of decimal digits of F(N).
+	Digits int `json:"digits"`
+	// SHA256 is the hexadecimal SHA-256 of the big-endian magnitude of F(N).
+	SHA256 string `json:"sha256"`
+}
+
+// DefaultHistoryPath returns the default path of the TUI history file.
+func DefaultHistoryPath() string {
+	return cli.HomeFilePath(DefaultHistoryFileName)
+}
+
+// newHistoryEntries returns the entries of the successful results of a
+// calculation or comparison, without IDs.
+//
+// Parameters:
+//   - results: The calculation results.
+//   - n: The Fibonacci index.
+//   - now: The time of the calculation.
+//
+// Returns:
+//   - []HistoryEntry: One entry per successful result.
+func newHistoryEntries(results []orchestration.CalculationResult, n uint64, now time.Time) []HistoryEntry {
+	var entries []HistoryEntry
+	for _, r := range results {
+		if r.Err != nil || r.Result == nil {
+			continue
+		}
+		entries = append(entries, HistoryEntry{
+			Time:      now,
+			N:         n,
+			Algorithm: r.Name,
+			Duration:  r.Duration,
+			Digits:    decimalDigits(r.Result),
+			SHA256:    orchestration.Digest(r.Result),
+		})
+	}
+	return entries
+}
+
+// decimalDigits returns the number of decimal digits of |x|, without
+// converting it to decimal.
+func decimalDigits(x *big.Int) int {
+	if x.Sign() == 0 {
+		return 1
+	}
+	// 2^(bits-1) <= |x| < 2^bits: |x| has the digits of 2^(bits-1), or one more
+	d := int(float64(x.BitLen()-1)*math.Log10(2)) + 1
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d)), nil)
+	if new(big.Int).Abs(x).Cmp(pow) >= 0 {
+		d++
+	}
+	return d
+}
+
+// LoadHistory reads the TUI history, newest entry first.
+//
+// Parameters:
+//   - path: The history file.
+//
+// Returns:
+//   - []HistoryEntry: The entries; none if the file does not exist.
+//   - error: An error if the file cannot be read or parsed.
+func LoadHistory(path string) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read history: %w", err)
+	}
+	var entries []HistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid history %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// SaveHistory writes the TUI history.
+//
+// Parameters:
+//   - path: The history file.
+//   - entries: The entries, newest first.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func SaveHistory(path string, entries []HistoryEntry) error {
+	if entries == nil {
+		entries = []HistoryEntry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode history: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("cannot write history: %w", err)
+	}
+	return nil
+}
+
+// ExportHistory writes history entries as CSV, for a path with a ".csv"
+// extension, or as JSON otherwise.
+//
+// Parameters:
+//   - path: The destination file.
+//   - entries: The entries to export.
+//
+// Returns:
+//   - error: An error if the file cannot be written.
+func ExportHistory(path string, entries []HistoryEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create history export: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = writeHistoryCSV(f, entries)
+	} else {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entries)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("cannot write history export: %w", err)
+	}
+	return f.Close()
+}
+
+// writeHistoryCSV writes the entries as CSV with a header row.
+func writeHistoryCSV(w io.Writer, entries []HistoryEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "time", "n", "algorithm", "duration_ns", "digits", "sha256"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			strconv.Itoa(e.ID),
+			e.Time.Format(time.RFC3339),
+			strconv.FormatUint(e.N, 10),
+			e.Algorithm,
+			strconv.FormatInt(int64(e.Duration), 10),
+			strconv.Itoa(e.Digits),
+			e.SHA256,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
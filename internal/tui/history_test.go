@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"encoding/csv"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/orchestration"
+)
+
+func TestDecimalDigits(t *testing.T) {
+	for _, s := range []string{"0", "1", "9", "10", "-99", "100", "999999999999", "1000000000000", "18446744073709551616"} {
+		x, _ := new(big.Int).SetString(s, 10)
+		if got, want := decimalDigits(x), len(strings.TrimPrefix(s, "-")); got != want {
+			t.Errorf("decimalDigits(%s) = %d, want %d", s, got, want)
+		}
+	}
+	if got := decimalDigits(fib(10000)); got != len(fib(10000).String()) {
+		t.Errorf("decimalDigits(F(10000)) = %d", got)
+	}
+}
+
+func TestNewHistoryEntries(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	results := []orchestration.CalculationResult{
+		{Name: "fast", Result: big.NewInt(55), Duration: time.Millisecond},
+		{Name: "broken", Err: errors.New("boom")},
+	}
+	entries := newHistoryEntries(results, 10, now)
+	if len(entries) != 1 {
+		t.Fatalf("Expected one entry, got %+v", entries)
+	}
+	e := entries[0]
+	// SHA-256 of the single byte 0x37 (55)
+	if e.N != 10 || e.Algorithm != "fast" || e.Digits != 2 || !e.Time.Equal(now) ||
+		e.SHA256 != "7902699be42c8a8e46fbbb4501726517e86b22c56a189f7625a6da49081b2451" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.json")
+
+	// A missing file is an empty history
+	if entries, err := LoadHistory(path); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected an empty history, got %v, %v", entries, err)
+	}
+
+	want := []HistoryEntry{
+		{ID: 2, Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), N: 1000, Algorithm: "matrix", Duration: 2 * time.Millisecond, Digits: 209, SHA256: "ab"},
+		{ID: 1, Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), N: 10, Algorithm: "fast, \"doubling\"", Duration: time.Microsecond, Digits: 2, SHA256: "cd"},
+	}
+	if err := SaveHistory(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadHistory(path)
+	if err != nil || len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Round trip failed: %+v, %v", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHistory(path); err == nil || !strings.Contains(err.Error(), "invalid history") {
+		t.Errorf("Expected an invalid history, got %v", err)
+	}
+
+	// Export to CSV and JSON
+	csvPath := filepath.Join(dir, "export.CSV")
+	if err := ExportHistory(csvPath, want); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("Expected a header and 2 records, got %v, %v", records, err)
+	}
+	if strings.Join(records[0], ",") != "id,time,n,algorithm,duration_ns,digits,sha256" ||
+		strings.Join(records[2], ",") != "1,2026-01-01T00:00:00Z,10,fast, \"doubling\",1000,2,cd" {
+		t.Errorf("Unexpected CSV %q", records)
+	}
+
+	jsonPath := filepath.Join(dir, "export.json")
+	if err := ExportHistory(jsonPath, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadHistory(jsonPath); err != nil || len(got) != 2 || got[1] != want[1] {
+		t.Errorf("Expected the JSON export to read back, got %+v, %v", got, err)
+	}
+	if err := ExportHistory(filepath.Join(dir, "missing", "x.csv"), want); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
@@ -27,6 +27,13 @@ type KeyMap struct {
 	Hex       key.Binding
 	Full      key.Binding
 	Details   key.Binding
+	Telemetry key.Binding
+
+	// History section
+	Rerun  key.Binding
+	Mark   key.Binding
+	Delete key.Binding
+	Export key.Binding
 
 	// Legacy aliases (for backward compatibility)
 	NewCalc    key.Binding
@@ -107,12 +114,32 @@ func DefaultKeyMap() KeyMap {
 		Hex:  hexBinding,
 		Full: key.NewBinding(
 			key.WithKeys("v"),
-			key.WithHelp("v", "full value"),
+			key.WithHelp("v", "view digits"),
 		),
 		Details: key.NewBinding(
 			key.WithKeys("d"),
 			key.WithHelp("d", "details"),
 		),
+		Telemetry: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "system panel"),
+		),
+		Rerun: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "re-run"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark for comparison"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("delete", "backspace"),
+			key.WithHelp("del", "delete"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "export"),
+		),
 		// Legacy aliases
 		NewCalc:    calcBinding,
 		SaveResult: saveBinding,
@@ -134,3 +161,76 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.SaveResult, k.HexToggle, k.Help, k.Quit},
 	}
 }
+
+// SettingsKeyMap defines the key bindings of the settings form. Letters
+// are typed into the fields, so navigation uses arrows and Tab only.
+type SettingsKeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Toggle    key.Binding
+	Apply     key.Binding
+	Calibrate key.Binding
+	Save      key.Binding
+	Close     key.Binding
+}
+
+// DefaultSettingsKeyMap returns the default key bindings of the settings
+// form.
+func DefaultSettingsKeyMap() SettingsKeyMap {
+	return SettingsKeyMap{
+		Up:        key.NewBinding(key.WithKeys("up", "shift+tab"), key.WithHelp("up", "previous field")),
+		Down:      key.NewBinding(key.WithKeys("down", "tab"), key.WithHelp("down/tab", "next field")),
+		Left:      key.NewBinding(key.WithKeys("left"), key.WithHelp("left", "previous choice")),
+		Right:     key.NewBinding(key.WithKeys("right"), key.WithHelp("right", "next choice")),
+		Toggle:    key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+		Apply:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "apply")),
+		Calibrate: key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "quick calibration")),
+		Save:      key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save profile")),
+		Close:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+	}
+}
+
+// DigitViewerKeyMap defines the key bindings of the digit viewer.
+type DigitViewerKeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	PageUp    key.Binding
+	PageDown  key.Binding
+	Top       key.Binding
+	Bottom    key.Binding
+	Jump      key.Binding
+	Search    key.Binding
+	NextMatch key.Binding
+	PrevMatch key.Binding
+	Hex       key.Binding
+	Mark      key.Binding
+	Copy      key.Binding
+	Close     key.Binding
+}
+
+// DefaultDigitViewerKeyMap returns the default key bindings of the digit
+// viewer.
+func DefaultDigitViewerKeyMap() DigitViewerKeyMap {
+	return DigitViewerKeyMap{
+		Up:        key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("up/k", "line up")),
+		Down:      key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("down/j", "line down")),
+		Left:      key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("left/h", "previous digit")),
+		Right:     key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("right/l", "next digit")),
+		PageUp:    key.NewBinding(key.WithKeys("pgup", "b"), key.WithHelp("pgup", "page up")),
+		PageDown:  key.NewBinding(key.WithKeys("pgdown", "f"), key.WithHelp("pgdn", "page down")),
+		Top:       key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "first digit")),
+		Bottom:    key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "last digit")),
+		Jump:      key.NewBinding(key.WithKeys("g", ":"), key.WithHelp("g", "go to digit")),
+		Search:    key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		NextMatch: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next match")),
+		PrevMatch: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "previous match")),
+		Hex:       key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "decimal/hex")),
+		Mark:      key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "start/clear selection")),
+		Copy:      key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy selection")),
+		Close:     key.NewBinding(key.WithKeys("esc", "q", "v"), key.WithHelp("esc/q", "close")),
+	}
+}
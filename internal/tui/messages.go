@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/agbru/fibcalc/internal/calibration"
 	"github.com/agbru/fibcalc/internal/fibonacci"
 	"github.com/agbru/fibcalc/internal/orchestration"
 )
@@ -50,6 +51,57 @@ type ComparisonResultsMsg struct {
 	N       uint64
 }
 
+// DigitTextMsg carries the digits of a result converted for the digit viewer.
+type DigitTextMsg struct {
+	Value *big.Int
+	Hex   bool
+	Text  string
+}
+
+// ClipboardMsg reports the copy of digits to the clipboard.
+type ClipboardMsg struct {
+	Digits int
+	Err    error
+}
+
+// HistoryRecordedMsg carries the history entries of finished calculations.
+type HistoryRecordedMsg struct {
+	Entries []HistoryEntry
+}
+
+// HistorySavedMsg reports the write of the history file.
+type HistorySavedMsg struct {
+	Err error
+}
+
+// HistoryExportedMsg reports the export of the history.
+type HistoryExportedMsg struct {
+	Path    string
+	Entries int
+	Err     error
+}
+
+// CalibrationResultMsg carries the thresholds estimated by a quick
+// calibration.
+type CalibrationResultMsg struct {
+	Results calibration.ThresholdResults
+	Err     error
+}
+
+// ProfileSavedMsg reports the saving of the calibration profile.
+type ProfileSavedMsg struct {
+	Path    string
+	Profile *calibration.CalibrationProfile
+	Err     error
+}
+
+// ServerMetricsMsg carries the metrics of the server of a remote dashboard.
+type ServerMetricsMsg struct {
+	Metrics ServerMetrics
+	Time    time.Time
+	Err     error
+}
+
 // ErrorMsg carries an error that occurred during operation.
 type ErrorMsg struct {
 	Err error
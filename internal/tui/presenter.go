@@ -60,6 +60,12 @@ func (p *ResultPresenter) PresentResult(_ orchestration.CalculationResult, _ uin
 	// No-op in TUI mode - handled by viewResults
 }
 
+// PresentRaceSummary implements orchestration.ResultPresenter.
+// In TUI mode, this is a no-op as results are shown via the TUI view.
+func (p *ResultPresenter) PresentRaceSummary(_ orchestration.CalculationResult, _ []orchestration.CalculationResult, _ io.Writer) {
+	// No-op in TUI mode - handled by viewResults
+}
+
 // FormatDuration formats a duration for display.
 func (p *ResultPresenter) FormatDuration(d time.Duration) string {
 	if d < time.Millisecond {
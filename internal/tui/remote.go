@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/server"
+)
+
+// remoteBackend runs the calculations on a fibcalc server through its HTTP
+// API. The server calculates with its own thresholds and does not report
+// progress or resource usage.
+type remoteBackend struct {
+	baseURL    string
+	client     *http.Client
+	algorithms []string
+}
+
+// NewRemoteBackend connects to the fibcalc server at baseURL and lists its
+// algorithms.
+//
+// Parameters:
+//   - ctx: The context of the connection.
+//   - baseURL: The server URL, such as "http://host:8080".
+//   - client: The HTTP client, or nil for http.DefaultClient.
+//
+// Returns:
+//   - MetricsBackend: The backend, also reporting the server metrics.
+//   - error: An error if the server cannot be reached or has no algorithm.
+func NewRemoteBackend(ctx context.Context, baseURL string, client *http.Client) (MetricsBackend, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	b := &remoteBackend{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+
+	var resp struct {
+		Algorithms []string `json:"algorithms"`
+	}
+	if err := b.getJSON(ctx, "/algorithms", nil, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Algorithms) == 0 {
+		return nil, fmt.Errorf("server %s has no algorithm", b.baseURL)
+	}
+	b.algorithms = resp.Algorithms
+	return b, nil
+}
+
+// Describe implements Backend.
+func (b *remoteBackend) Describe() string {
+	return b.baseURL
+}
+
+// Algorithms implements Backend.
+func (b *remoteBackend) Algorithms() []string {
+	return b.algorithms
+}
+
+// Calculate implements Backend with GET /calculate.
+func (b *remoteBackend) Calculate(ctx context.Context, i int, n uint64, _ config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) orchestration.CalculationResult {
+	defer close(progressChan)
+	return b.calculate(ctx, i, n)
+}
+
+// Compare implements Backend, with one request per algorithm in parallel.
+func (b *remoteBackend) Compare(ctx context.Context, n uint64, _ config.AppConfig, _ chan<- fibonacci.ProgressUpdate) []orchestration.CalculationResult {
+	results := make([]orchestration.CalculationResult, len(b.algorithms))
+	var wg sync.WaitGroup
+	for i := range b.algorithms {
+		wg.Go(func() {
+			results[i] = b.calculate(ctx, i, n)
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// calculate requests F(n) with algorithm i. The duration is the one the
+// server measured, without the network.
+func (b *remoteBackend) calculate(ctx context.Context, i int, n uint64) orchestration.CalculationResult {
+	name := b.algorithms[i]
+	result := orchestration.CalculationResult{Name: name}
+
+	var resp server.Response
+	query := url.Values{"n": {strconv.FormatUint(n, 10)}, "algo": {name}}
+	if err := b.getJSON(ctx, "/calculate", query, &resp); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Duration, _ = time.ParseDuration(resp.Duration)
+	switch {
+	case resp.Error != "":
+		result.Err = errors.New(resp.Error)
+	case resp.Result == nil:
+		result.Err = errors.New("the server returned no result")
+	default:
+		result.Result = resp.Result
+	}
+	return result
+}
+
+// Metrics implements MetricsBackend with GET /metrics.
+func (b *remoteBackend) Metrics(ctx context.Context) (ServerMetrics, error) {
+	resp, err := b.get(ctx, "/metrics", nil)
+	if err != nil {
+		return ServerMetrics{}, err
+	}
+	defer resp.Body.Close()
+	return parseMetrics(resp.Body)
+}
+
+// get sends a GET request to the server. Responses other than 200 OK are
+// returned as errors with the message of the server.
+func (b *remoteBackend) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	target := b.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach server: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var errResp server.ErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Message != "" {
+			return nil, fmt.Errorf("server returned %s: %s", resp.Status, errResp.Message)
+		}
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// getJSON sends a GET request to the server and decodes the JSON response
+// into v.
+func (b *remoteBackend) getJSON(ctx context.Context, path string, query url.Values, v any) error {
+	resp, err := b.get(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// ─── Server Metrics ───
+
+// ServerMetrics holds the samples of the Prometheus metrics of a server.
+type ServerMetrics struct {
+	samples []metricSample
+}
+
+// metricSample is one line of the Prometheus text format.
+type metricSample struct {
+	name   string
+	labels string
+	value  float64
+}
+
+// Sum returns the sum of the samples of a metric whose labels include all
+// of labels, each written as in the text format, e.g. `status="error"`.
+//
+// Parameters:
+//   - name: The metric name.
+//   - labels: The labels the samples must have.
+//
+// Returns:
+//   - float64: The sum of the samples.
+//   - bool: True if the metric has any sample.
+func (s ServerMetrics) Sum(name string, labels ...string) (float64, bool) {
+	var sum float64
+	found := false
+	for _, sample := range s.samples {
+		if sample.name != name || !hasLabels(sample.labels, labels) {
+			continue
+		}
+		sum += sample.value
+		found = true
+	}
+	return sum, found
+}
+
+// hasLabels reports whether the label set of a sample includes all labels.
+func hasLabels(set string, labels []string) bool {
+	for _, l := range labels {
+		if !strings.Contains(set, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMetrics reads metrics in the Prometheus text format.
+func parseMetrics(r io.Reader) (ServerMetrics, error) {
+	var m ServerMetrics
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var s metricSample
+		var rest string
+		if open := strings.IndexByte(line, '{'); open >= 0 {
+			end := strings.LastIndexByte(line, '}')
+			if end < open {
+				return m, fmt.Errorf("invalid metric line: %q", line)
+			}
+			s.name, s.labels, rest = line[:open], line[open+1:end], line[end+1:]
+		} else {
+			s.name, rest, _ = strings.Cut(line, " ")
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return m, fmt.Errorf("invalid metric line: %q", line)
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return m, fmt.Errorf("invalid metric line: %q", line)
+		}
+		s.value = v
+		m.samples = append(m.samples, s)
+	}
+	if err := sc.Err(); err != nil {
+		return m, fmt.Errorf("cannot read metrics: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/server"
+)
+
+// testMetrics is an excerpt of the /metrics output of a server.
+const testMetrics = `# HELP fibcalc_active_requests Current number of active requests
+# TYPE fibcalc_active_requests gauge
+fibcalc_active_requests 1
+fibcalc_requests_total 42
+fibonacci_calculations_total{algorithm="fast",status="success"} 30
+fibonacci_calculations_total{algorithm="matrix",status="success"} 9
+fibonacci_calculations_total{algorithm="matrix",status="error"} 2
+fibonacci_calculation_duration_seconds_bucket{algorithm="fast",le="+Inf"} 30
+fibonacci_calculation_duration_seconds_sum{algorithm="fast"} 1.5
+fibonacci_calculation_duration_seconds_count{algorithm="fast"} 30
+go_goroutines 12
+process_cpu_seconds_total 3.25
+process_resident_memory_bytes 5.24288e+07
+`
+
+// newTestServer returns a server with the API of fibcalc: F(n) for n up
+// to 100 with the fast and matrix algorithms.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/algorithms", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string][]string{"algorithms": {"fast", "matrix"}})
+	})
+	mux.HandleFunc("/calculate", func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(r.URL.Query().Get("n"))
+		if err != nil || n > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(server.ErrorResponse{Error: "Bad Request", Message: "n is out of range"})
+			return
+		}
+		resp := server.Response{N: uint64(n), Algorithm: r.URL.Query().Get("algo"), Duration: "1.5ms", Result: fib(n)}
+		if resp.Algorithm == "matrix" && n == 13 {
+			resp.Result, resp.Error = nil, "calculation failed"
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testMetrics))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteBackend_Calculate(t *testing.T) {
+	srv := newTestServer(t)
+	backend, err := NewRemoteBackend(context.Background(), srv.URL+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.Describe() != srv.URL || strings.Join(backend.Algorithms(), ",") != "fast,matrix" {
+		t.Fatalf("Unexpected backend %s %v", backend.Describe(), backend.Algorithms())
+	}
+
+	progress := make(chan fibonacci.ProgressUpdate, 1)
+	r := backend.Calculate(context.Background(), 1, 90, config.AppConfig{}, progress)
+	if r.Err != nil || r.Name != "matrix" || r.Result.Cmp(fib(90)) != 0 || r.Duration != 1500*time.Microsecond {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if _, open := <-progress; open {
+		t.Error("Expected the progress channel closed")
+	}
+
+	r = backend.Calculate(context.Background(), 0, 1000, config.AppConfig{}, make(chan fibonacci.ProgressUpdate))
+	if r.Err == nil || !strings.Contains(r.Err.Error(), "400 Bad Request: n is out of range") {
+		t.Errorf("Expected the error of the server, got %v", r.Err)
+	}
+
+	results := backend.Compare(context.Background(), 13, config.AppConfig{}, nil)
+	if len(results) != 2 || results[0].Name != "fast" || results[0].Result.Cmp(fib(13)) != 0 ||
+		results[1].Name != "matrix" || results[1].Err == nil || results[1].Err.Error() != "calculation failed" {
+		t.Errorf("Unexpected comparison %+v", results)
+	}
+}
+
+func TestRemoteBackend_Unreachable(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Close()
+	if _, err := NewRemoteBackend(context.Background(), srv.URL, nil); err == nil || !strings.Contains(err.Error(), "cannot reach server") {
+		t.Errorf("Expected a connection error, got %v", err)
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	m, err := parseMetrics(strings.NewReader(testMetrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		labels []string
+		want   float64
+	}{
+		{"fibcalc_requests_total", nil, 42},
+		{"fibonacci_calculations_total", nil, 41},
+		{"fibonacci_calculations_total", []string{`status="error"`}, 2},
+		{"fibonacci_calculations_total", []string{`algorithm="matrix"`, `status="success"`}, 9},
+		{"process_resident_memory_bytes", nil, 52428800},
+	}
+	for _, tt := range tests {
+		if got, ok := m.Sum(tt.name, tt.labels...); !ok || got != tt.want {
+			t.Errorf("Sum(%s, %v) = %v, %v; want %v", tt.name, tt.labels, got, ok, tt.want)
+		}
+	}
+	if _, ok := m.Sum("go_threads"); ok {
+		t.Error("Expected no go_threads sample")
+	}
+
+	if _, err := parseMetrics(strings.NewReader("broken{le=\"1\" 2\n")); err == nil {
+		t.Error("Expected an error for an unterminated label set")
+	}
+}
+
+func TestDashboardModel_RemotePanel(t *testing.T) {
+	srv := newTestServer(t)
+	backend, err := NewRemoteBackend(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewDashboardModelWithBackend(config.AppConfig{N: 100}, backend)
+	m.ready, m.width, m.height = true, 140, 80
+	if !strings.Contains(m.View(), "Remote: "+srv.URL) || !strings.Contains(m.View(), "Waiting for metrics") {
+		t.Fatalf("Expected the remote header and the server panel:\n%s", m.View())
+	}
+
+	// A tick polls the metrics, at most once per interval
+	start := time.Now()
+	m, _ = press(t, m, TickMsg{Time: start})
+	if !m.server.fetching {
+		t.Fatal("Expected a fetch of the server metrics")
+	}
+	m, _ = press(t, m, fetchServerMetrics(backend)())
+	if m, _ = press(t, m, TickMsg{Time: start.Add(serverPollInterval / 2)}); m.server.fetching {
+		t.Error("Expected no fetch before the poll interval")
+	}
+
+	view := m.View()
+	for _, want := range []string{"SERVER", "connected to", "1 active, 42 total", "39 succeeded, 2 failed", "50.0ms", "50.0 MiB RSS", "12"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Server panel lacks %q:\n%s", want, view)
+		}
+	}
+
+	// Remote results report no resource usage
+	m, _ = press(t, m, CalculationResultMsg{N: 10, Result: backend.Compare(context.Background(), 10, m.config, nil)[0]})
+	if !strings.Contains(m.View(), "Resource usage not available") {
+		t.Errorf("Expected no resource usage:\n%s", m.View())
+	}
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/orchestration"
+	"github.com/agbru/fibcalc/internal/session"
+)
+
+// replayBackend replays a recorded session (--replay) instead of
+// calculating: the progress bars follow the recorded updates and the
+// results are the recorded ones.
+type replayBackend struct {
+	session *session.Session
+	path    string
+	speed   float64
+}
+
+// NewReplayBackend returns a backend replaying a session.
+//
+// Parameters:
+//   - s: The recorded session.
+//   - path: The session file, for the header.
+//   - speed: The speed factor (see session.Feed).
+//
+// Returns:
+//   - Backend: The replay backend.
+func NewReplayBackend(s *session.Session, path string, speed float64) Backend {
+	return replayBackend{session: s, path: path, speed: speed}
+}
+
+// Describe implements Backend.
+func (b replayBackend) Describe() string {
+	return b.path
+}
+
+// Algorithms implements Backend.
+func (b replayBackend) Algorithms() []string {
+	return b.session.Header.Algorithms
+}
+
+// Calculate implements Backend, replaying the updates of algorithm i.
+func (b replayBackend) Calculate(ctx context.Context, i int, n uint64, _ config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) orchestration.CalculationResult {
+	defer close(progressChan)
+	if err := b.check(n); err != nil {
+		return orchestration.CalculationResult{Name: b.session.Header.Algorithms[i], Err: err}
+	}
+	if err := session.Feed(ctx, b.session.EventsOf(i), b.speed, progressChan); err != nil {
+		return orchestration.CalculationResult{Name: b.session.Header.Algorithms[i], Err: err}
+	}
+	return b.session.CalculationResults()[i]
+}
+
+// Compare implements Backend, replaying the whole session.
+func (b replayBackend) Compare(ctx context.Context, n uint64, _ config.AppConfig, progressChan chan<- fibonacci.ProgressUpdate) []orchestration.CalculationResult {
+	err := b.check(n)
+	if err == nil {
+		err = session.Feed(ctx, b.session.Events, b.speed, progressChan)
+	}
+	if err != nil {
+		results := make([]orchestration.CalculationResult, len(b.session.Header.Algorithms))
+		for i, name := range b.session.Header.Algorithms {
+			results[i] = orchestration.CalculationResult{Name: name, Err: err}
+		}
+		return results
+	}
+	return b.session.CalculationResults()
+}
+
+// check reports an error if n is not the index of the recorded run.
+func (b replayBackend) check(n uint64) error {
+	if n != b.session.Header.N {
+		return fmt.Errorf("the session recorded F(%d), not F(%d)", b.session.Header.N, n)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/agbru/fibcalc/internal/config"
+	"github.com/agbru/fibcalc/internal/session"
+)
+
+// sessionFixture is a recorded comparison of the three algorithms for
+// F(10000).
+const sessionFixture = "testdata/session.jsonl"
+
+// newReplayModel returns a dashboard replaying the session fixture without
+// delays.
+func newReplayModel(t *testing.T) (DashboardModel, *session.Session) {
+	t.Helper()
+	s, err := session.LoadFile(sessionFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewDashboardModelWithBackend(config.AppConfig{N: s.Header.N}, NewReplayBackend(s, sessionFixture, 0))
+	m.ready, m.width, m.height = true, 140, 80
+	return m, s
+}
+
+func TestDashboardModel_Replay(t *testing.T) {
+	m, s := newReplayModel(t)
+	if !strings.Contains(m.View(), "Replay: "+sessionFixture) {
+		t.Errorf("Expected the session in the header:\n%s", m.View())
+	}
+
+	m, cmd := press(t, m, runes("m"))
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("Expected the comparison and progress commands, got %T", cmd())
+	}
+
+	// Feed the replayed updates to the dashboard as they arrive
+	done := make(chan tea.Msg, 1)
+	go func() { done <- batch[0]() }()
+	var result tea.Msg
+	updates := 0
+	for result == nil || len(m.calculation.progressChan) > 0 {
+		select {
+		case u := <-m.calculation.progressChan:
+			m, _ = press(t, m, ProgressMsg{Update: u})
+			updates++
+		case result = <-done:
+		}
+	}
+	if updates != len(s.Events) {
+		t.Fatalf("Expected %d updates, got %d", len(s.Events), updates)
+	}
+
+	// Every algorithm shows its last recorded step
+	for i, step := range m.algorithms.steps {
+		if step == nil || step.Step != step.TotalSteps || m.algorithms.progresses[i] != 1 {
+			t.Errorf("%s: expected the last step, got %+v at %v", m.algorithms.names[i], step, m.algorithms.progresses[i])
+		}
+	}
+
+	m, cmd = press(t, m, result)
+	m = runCmd(t, m, cmd)
+	view := m.View()
+	for _, want := range []string{"Global Status: Success.", "6,942", "Fast Doubling"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View lacks %q:\n%s", want, view)
+		}
+	}
+	if len(m.history.entries) != 3 || m.history.path != "" {
+		t.Errorf("Expected the results in the in-memory history, got %d entries", len(m.history.entries))
+	}
+}
+
+func TestDashboardModel_ReplayOtherN(t *testing.T) {
+	m, _ := newReplayModel(t)
+	m.input.n = "500"
+	m, cmd := press(t, m, runes("c"))
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("Expected a batch of commands, got %T", cmd())
+	}
+	m, _ = press(t, m, batch[0]())
+	if m.calculation.active || m.lastError == nil || !strings.Contains(m.lastError.Error(), "the session recorded F(10000), not F(500)") {
+		t.Errorf("Expected a replay error, got %v", m.lastError)
+	}
+}
@@ -7,6 +7,7 @@ const (
 	SectionInput Section = iota
 	SectionAlgorithms
 	SectionResults
+	SectionHistory
 )
 
 // String returns the string representation of the section.
@@ -18,6 +19,8 @@ func (s Section) String() string {
 		return "Algorithms"
 	case SectionResults:
 		return "Results"
+	case SectionHistory:
+		return "History"
 	default:
 		return "Unknown"
 	}
@@ -31,6 +34,8 @@ func (s Section) Next() Section {
 	case SectionAlgorithms:
 		return SectionResults
 	case SectionResults:
+		return SectionHistory
+	case SectionHistory:
 		return SectionInput
 	default:
 		return SectionInput
@@ -41,11 +46,13 @@ func (s Section) Next() Section {
 func (s Section) Prev() Section {
 	switch s {
 	case SectionInput:
-		return SectionResults
+		return SectionHistory
 	case SectionAlgorithms:
 		return SectionInput
 	case SectionResults:
 		return SectionAlgorithms
+	case SectionHistory:
+		return SectionResults
 	default:
 		return SectionInput
 	}
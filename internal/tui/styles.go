@@ -46,6 +46,9 @@ type Styles struct {
 	Box            lipgloss.Style
 	BoxTitle       lipgloss.Style
 	Highlight      lipgloss.Style
+	DigitCursor    lipgloss.Style
+	DigitSelection lipgloss.Style
+	DigitMatch     lipgloss.Style
 	Muted          lipgloss.Style
 	Bold           lipgloss.Style
 }
@@ -206,6 +209,9 @@ func (s *Styles) initComponentStyles(colors themeColors) {
 	s.BoxTitle = lipgloss.NewStyle().Bold(true).Foreground(colors.primary)
 
 	s.Highlight = lipgloss.NewStyle().Foreground(colors.accent).Bold(true)
+	s.DigitCursor = lipgloss.NewStyle().Reverse(true).Bold(true)
+	s.DigitSelection = lipgloss.NewStyle().Foreground(colors.primary).Background(colors.bgHighlight).Underline(true)
+	s.DigitMatch = lipgloss.NewStyle().Foreground(colors.accent).Bold(true)
 	s.Muted = lipgloss.NewStyle().Foreground(colors.secondary)
 	s.Bold = lipgloss.NewStyle().Bold(true)
 }
@@ -0,0 +1,16 @@
+//go:build linux
+
+package tui
+
+import "os"
+
+// readCoreTimes returns the cumulative times of each CPU core from
+// /proc/stat, or nil when it cannot be read.
+func readCoreTimes() []coreTimes {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	return parseProcStat(f)
+}
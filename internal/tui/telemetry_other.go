@@ -0,0 +1,9 @@
+//go:build !linux
+
+package tui
+
+// readCoreTimes is not available on this platform: the telemetry panel only
+// shows the CPU utilisation of the process.
+func readCoreTimes() []coreTimes {
+	return nil
+}
@@ -5,30 +5,69 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/agbru/fibcalc/internal/config"
 	"github.com/agbru/fibcalc/internal/fibonacci"
+	"github.com/agbru/fibcalc/internal/session"
 )
 
+// remoteConnectTimeout bounds the connection to the server of --remote.
+const remoteConnectTimeout = 10 * time.Second
+
 // Run starts the TUI application with the given configuration.
 // It returns an exit code (0 for success, non-zero for errors).
 func Run(cfg config.AppConfig, calculatorMap map[string]fibonacci.Calculator) int {
-	// Convert map to slice for internal use
+	// Convert map to slice for internal use, in a stable order
+	names := slices.Sorted(maps.Keys(calculatorMap))
 	calculators := make([]fibonacci.Calculator, 0, len(calculatorMap))
-	for _, c := range calculatorMap {
-		calculators = append(calculators, c)
+	for _, name := range names {
+		calculators = append(calculators, calculatorMap[name])
 	}
 
 	// In TUI mode, disable detailed calculation by default
 	cfg.Details = false
 
-	// Use the new HTOP-style dashboard model
-	model := NewDashboardModel(cfg, calculators)
+	// With --remote, the calculations run on a fibcalc server
+	backend := NewLocalBackend(calculators)
+	if cfg.Remote != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteConnectTimeout)
+		remote, err := NewRemoteBackend(ctx, cfg.Remote, nil)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error connecting to %s: %v\n", cfg.Remote, err)
+			return 1
+		}
+		backend = remote
+	}
+
+	// With --replay, the dashboard replays a recorded session; its results
+	// are not added to the persistent history
+	if cfg.Replay != "" {
+		s, err := session.LoadFile(cfg.Replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		backend = NewReplayBackend(s, cfg.Replay, cfg.ReplaySpeed)
+		cfg.N = s.Header.N
+	}
+
+	// Use the new HTOP-style dashboard model, with the persistent history
+	// and the settings form
+	model := NewDashboardModelWithBackend(cfg, backend)
+	if cfg.Replay == "" {
+		model = model.withHistory(DefaultHistoryPath())
+	}
+	model = model.withSettings(backend.Algorithms())
 
 	p := tea.NewProgram(
 		model,